@@ -0,0 +1,107 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TestingT is the subset of testing.TB the assertions of an AccessRecorder report failures to
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AccessRecorder captures the settings read with Set.Get while it is attached, see Set.RecordAccess
+type AccessRecorder struct {
+	mu     sync.Mutex
+	reads  map[string]int
+	paths  map[string]string
+	handle *NotifyHandle
+}
+
+// RecordAccess attaches an AccessRecorder to the Set tree capturing every setting read with Set.Get (or Set.GetContext) until it is closed. Tests use it to verify that new configuration actually influences the behavior under test. Fields of bound structs are read directly and are not seen by the recorder.
+func (s *Set) RecordAccess() *AccessRecorder {
+	r := &AccessRecorder{reads: map[string]int{}, paths: map[string]string{}}
+	r.handle = s.Hook(HookFunc(r.event))
+
+	return r
+}
+
+// event records the setting of a successful OpGet
+func (r *AccessRecorder) event(e Event) {
+	if e.Op != OpGet || e.Err != nil {
+		return
+	}
+
+	key := strings.ToLower(e.Path)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reads[key]++
+	r.paths[key] = e.Path
+}
+
+// Reads returns the number of times the setting at path was read while recording
+func (r *AccessRecorder) Reads(path string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reads[strings.ToLower(path)]
+}
+
+// Read returns if the setting at path was read while recording
+func (r *AccessRecorder) Read(path string) bool {
+	return r.Reads(path) > 0
+}
+
+// Paths returns the paths of every setting read while recording, sorted
+func (r *AccessRecorder) Paths() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	paths := make([]string, 0, len(r.paths))
+	for _, path := range r.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// Reset forgets every read recorded so far, so a test can record separate phases
+func (r *AccessRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reads = map[string]int{}
+	r.paths = map[string]string{}
+}
+
+// AssertRead reports an error to t for every path that was not read while recording
+func (r *AccessRecorder) AssertRead(t TestingT, paths ...string) {
+	t.Helper()
+
+	for _, path := range paths {
+		if !r.Read(path) {
+			t.Errorf("Expected setting %q to be read; read settings %v", path, r.Paths())
+		}
+	}
+}
+
+// AssertNotRead reports an error to t for every path that was read while recording
+func (r *AccessRecorder) AssertNotRead(t TestingT, paths ...string) {
+	t.Helper()
+
+	for _, path := range paths {
+		if n := r.Reads(path); n > 0 {
+			t.Errorf("Expected setting %q not to be read; read %d times", path, n)
+		}
+	}
+}
+
+// Close stops recording, the recorded reads remain available
+func (r *AccessRecorder) Close() error {
+	return r.handle.Close()
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fakeT records the failures of an assertion
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestSet_RecordAccess(t *testing.T) {
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", 80, "")
+	set.Setting("Debug", false, "")
+	set.Setting("Name", "app", "")
+
+	set.Get("Name")
+
+	r := set.RecordAccess()
+	set.Get("http.port")
+	set.Subset("HTTP").Get("Port")
+	set.Get("Missing")
+	_ = r.Close()
+
+	set.Get("Debug")
+
+	if n := r.Reads("HTTP.Port"); n != 2 {
+		t.Errorf("Failed to count reads: expected 2; got %d", n)
+	}
+
+	if paths := r.Paths(); !reflect.DeepEqual(paths, []string{"HTTP.Port"}) {
+		t.Errorf("Failed to record reads: got %v", paths)
+	}
+
+	var ft fakeT
+	r.AssertRead(&ft, "HTTP.Port", "Debug")
+	r.AssertNotRead(&ft, "Name", "HTTP.Port")
+	if len(ft.errors) != 2 {
+		t.Errorf("Failed to assert reads: expected 2 failures; got %v", ft.errors)
+	}
+
+	r.Reset()
+	if r.Read("HTTP.Port") {
+		t.Error("Failed to reset reads")
+	}
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LabelApprovalRequired marks a setting whose writes through admin surfaces (see Set.UpdateContext) are parked until a second actor approves them with Set.Approve, so bound fields can use `labels:"approval-required"`
+const LabelApprovalRequired = "approval-required"
+
+// ErrApprovalPending is returned by Set.UpdateContext and Set.UpdateIfMatch when the write was parked for approval rather than applied, see Set.PendingApprovals
+var ErrApprovalPending = errors.New("pending approval")
+
+// ErrUnknownApproval is returned by Set.Approve and Set.Reject for an approval request that is not pending
+var ErrUnknownApproval = errors.New("unknown approval request")
+
+type actorContextKey struct{}
+
+// WithActor returns a child context of ctx for a caller identified by the actor (i.e. a user name or service account), typically added by the authentication middleware of an admin surface. The actor requesting a change that requires approval can not approve it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor added to the ctx with WithActor
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	return actor
+}
+
+// ApprovalRequest is a write to a setting requiring approval waiting for a second actor, see Set.PendingApprovals
+type ApprovalRequest struct {
+	// ID of the request to pass to Set.Approve or Set.Reject
+	ID string `json:"id"`
+
+	// Path of the setting
+	Path string `json:"path"`
+
+	// From is the value of the setting when the change was requested, ***** for masked settings
+	From string `json:"from"`
+
+	// To is the value requested, ***** for masked settings
+	To string `json:"to"`
+
+	// RequestedBy is the actor that requested the change
+	RequestedBy string `json:"requestedBy"`
+
+	// Time the change was requested
+	Time time.Time `json:"time"`
+}
+
+// approvalRequest is the state of an ApprovalRequest
+type approvalRequest struct {
+	id          string
+	setting     *Setting
+	from        string
+	value       string
+	revision    uint64
+	requestedBy string
+	time        time.Time
+}
+
+// public returns the ApprovalRequest, masking the values of masked settings
+func (r *approvalRequest) public() ApprovalRequest {
+	return ApprovalRequest{ID: r.id, Path: r.setting.Path, From: r.setting.display(r.from), To: r.setting.display(r.value), RequestedBy: r.requestedBy, Time: r.time}
+}
+
+// requiresApproval returns if writes to the setting through admin surfaces need a second actor
+func (s *Setting) requiresApproval() bool {
+	return hasLabel(s.Labels, LabelApprovalRequired)
+}
+
+// OnApprovalRequest calls fn with every change parked for approval in the Set tree, so reviewers can be notified (i.e. through a chat or ticketing system). A nil fn stops the calls.
+func (s *Set) OnApprovalRequest(fn func(ApprovalRequest)) {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	root.approvalHook = fn
+}
+
+// park parks the write of the value to the setting requiring approval on behalf of the caller in the ctx, returning false when the setting does not require approval or the write was already authorized (i.e. a change received from a peer). A later write to the same setting replaces the request.
+func (s *Set) park(ctx context.Context, setting *Setting, v string) (bool, error) {
+	if !setting.requiresApproval() || ctx.Value(trustedContextKey{}) != nil {
+		return false, nil
+	}
+
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return true, fmt.Errorf("%w: %s requires approval, the change must be requested by an actor (see WithActor)", ErrForbidden, setting.Path)
+	}
+
+	// a value that can never be applied is rejected now rather than on approval
+	if err := setting.check(v); err != nil {
+		return true, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return true, fmt.Errorf("unable to create approval request: %w", err)
+	}
+
+	request := &approvalRequest{
+		id:          hex.EncodeToString(id),
+		setting:     setting,
+		from:        setting.format(),
+		value:       v,
+		revision:    setting.Revision(),
+		requestedBy: actor,
+		time:        time.Now(),
+	}
+
+	root := s.Root()
+	root.mu.Lock()
+	if root.approvals == nil {
+		root.approvals = map[string]*approvalRequest{}
+	}
+	for key, r := range root.approvals {
+		if r.setting == setting {
+			delete(root.approvals, key)
+		}
+	}
+	root.approvals[request.id] = request
+	hook := root.approvalHook
+	root.mu.Unlock()
+
+	if hook != nil {
+		hook(request.public())
+	}
+
+	return true, fmt.Errorf("%s: %w as %s", setting.Path, ErrApprovalPending, request.id)
+}
+
+// PendingApprovals returns the changes within the Set waiting for approval, sorted by path
+func (s *Set) PendingApprovals() []ApprovalRequest {
+	root := s.Root()
+
+	root.mu.Lock()
+	var requests []ApprovalRequest
+	for _, r := range root.approvals {
+		if s.contains(r.setting.Path) {
+			requests = append(requests, r.public())
+		}
+	}
+	root.mu.Unlock()
+
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Path < requests[j].Path })
+
+	return requests
+}
+
+// Approve applies the change parked for approval on behalf of the caller identified by the ctx, which must be an actor (see WithActor) other than the one that requested it and be allowed to write the setting by the Authorizer of the Set. The change fails with an error wrapping ErrRevisionMismatch when the setting changed since it was requested, so the approver never confirms a change against a value they have not seen. Approved changes are runtime changes saved by Set.Persist.
+func (s *Set) Approve(ctx context.Context, id string) error {
+	request, err := s.decide(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	setting := request.setting
+	err = setting.setIf(ctx, request.value, "", func() error {
+		if current := setting.Revision(); current != request.revision {
+			return fmt.Errorf("%w: expected %d; got %d", ErrRevisionMismatch, request.revision, current)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// runtime changes are saved when the Set is persisted
+	s.persist(setting)
+
+	return nil
+}
+
+// Reject discards the change parked for approval on behalf of the caller identified by the ctx, with the same requirements as Set.Approve. The actor that requested a change can not reject it either, but can replace it with another write.
+func (s *Set) Reject(ctx context.Context, id string) error {
+	_, err := s.decide(ctx, id)
+	return err
+}
+
+// decide removes the approval request by id when the caller in the ctx may decide on it
+func (s *Set) decide(ctx context.Context, id string) (*approvalRequest, error) {
+	root := s.Root()
+
+	root.mu.Lock()
+	request := root.approvals[id]
+	root.mu.Unlock()
+
+	if request == nil || !s.contains(request.setting.Path) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownApproval, id)
+	}
+
+	actor := ActorFromContext(ctx)
+	if actor == "" || strings.EqualFold(actor, request.requestedBy) {
+		return nil, fmt.Errorf("%w: %s must be approved by an actor other than %q", ErrForbidden, request.setting.Path, request.requestedBy)
+	}
+
+	if err := s.authorize(ctx, request.setting); err != nil {
+		return nil, err
+	}
+
+	// only one decision is made on a request
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	if root.approvals[id] != request {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownApproval, id)
+	}
+	delete(root.approvals, id)
+
+	return request, nil
+}
+
+// ApprovalServer exposes the changes of a Set waiting for approval over HTTP: GET lists them as JSON (see Set.PendingApprovals), and POST to <id>/approve or <id>/reject beneath the path the server is mounted on decides on one (see Set.Approve). The actor and roles of the caller are read from the request context, so the server must be wrapped by authentication middleware calling WithActor.
+type ApprovalServer struct {
+	// Set being exposed
+	Set *Set
+
+	// Token, when not empty, is required from clients as a bearer token
+	Token string
+}
+
+// ServeHTTP lists or decides on the changes waiting for approval
+func (as *ApprovalServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkBearer(w, r, as.Token) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		requests := as.Set.PendingApprovals()
+		if requests == nil {
+			requests = []ApprovalRequest{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(requests)
+
+	case http.MethodPost:
+		segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(segments) < 2 {
+			http.NotFound(w, r)
+			return
+		}
+
+		var err error
+		switch id, action := segments[len(segments)-2], segments[len(segments)-1]; action {
+		case "approve":
+			err = as.Set.Approve(r.Context(), id)
+		case "reject":
+			err = as.Set.Reject(r.Context(), id)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		switch {
+		case err == nil:
+			w.WriteHeader(http.StatusNoContent)
+		case errors.Is(err, ErrUnknownApproval):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ErrRevisionMismatch):
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		default:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		}
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSet_Approve(t *testing.T) {
+	cfg := struct {
+		Password string `mask:"true" labels:"approval-required"`
+		Limit    int    `labels:"approval-required"`
+		Name     string
+	}{Password: "old", Limit: 10}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	var notified []ApprovalRequest
+	set.OnApprovalRequest(func(r ApprovalRequest) { notified = append(notified, r) })
+
+	alice := WithActor(context.Background(), "alice")
+	bob := WithActor(context.Background(), "bob")
+
+	if _, err := set.UpdateContext(alice, "Name", "changed"); err != nil || cfg.Name != "changed" {
+		t.Errorf("Failed to update setting without approval: got %v", err)
+	}
+
+	if _, err := set.UpdateContext(context.Background(), "Password", "new"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Failed to require an actor: expected %v; got %v", ErrForbidden, err)
+	}
+	if _, err := set.UpdateContext(alice, "Limit", "many"); err == nil || errors.Is(err, ErrApprovalPending) {
+		t.Errorf("Failed to reject invalid value: got %v", err)
+	}
+
+	if _, err := set.UpdateContext(alice, "Password", "new"); !errors.Is(err, ErrApprovalPending) {
+		t.Fatalf("Failed to park change: expected %v; got %v", ErrApprovalPending, err)
+	}
+	if cfg.Password != "old" {
+		t.Errorf("Failed to hold change: expected %q; got %q", "old", cfg.Password)
+	}
+
+	pending := set.PendingApprovals()
+	if len(pending) != 1 || pending[0].Path != "Password" || pending[0].To != "*****" || pending[0].RequestedBy != "alice" {
+		t.Fatalf("Failed to list pending change: got %+v", pending)
+	}
+	if len(notified) != 1 || notified[0].ID != pending[0].ID {
+		t.Errorf("Failed to notify: got %+v", notified)
+	}
+
+	if err := set.Approve(WithActor(context.Background(), "Alice"), pending[0].ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Failed to require a second actor: expected %v; got %v", ErrForbidden, err)
+	}
+	if err := set.Approve(bob, pending[0].ID); err != nil {
+		t.Fatalf("Failed to approve: %v", err)
+	}
+	if cfg.Password != "new" || len(set.PendingApprovals()) != 0 {
+		t.Errorf("Failed to apply approved change: got %q", cfg.Password)
+	}
+	if err := set.Approve(bob, pending[0].ID); !errors.Is(err, ErrUnknownApproval) {
+		t.Errorf("Failed to approve once: expected %v; got %v", ErrUnknownApproval, err)
+	}
+
+	// a change approved against a value that has since changed is not applied
+	_, _ = set.UpdateContext(alice, "Limit", "20")
+	id := set.PendingApprovals()[0].ID
+	set.Get("Limit").Set("15")
+	if err := set.Approve(bob, id); !errors.Is(err, ErrRevisionMismatch) || cfg.Limit != 15 {
+		t.Errorf("Failed to reject stale approval: expected %v; got %v", ErrRevisionMismatch, err)
+	}
+
+	_, _ = set.UpdateContext(alice, "Limit", "20")
+	id = set.PendingApprovals()[0].ID
+	if err := set.Reject(bob, id); err != nil || cfg.Limit != 15 || len(set.PendingApprovals()) != 0 {
+		t.Errorf("Failed to reject change: got %v", err)
+	}
+}
+
+func TestApprovalServer(t *testing.T) {
+	cfg := struct {
+		Limit int `labels:"approval-required"`
+	}{Limit: 10}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	if _, err := set.UpdateContext(WithActor(context.Background(), "alice"), "Limit", "20"); !errors.Is(err, ErrApprovalPending) {
+		t.Fatalf("Failed to park change: %v", err)
+	}
+	id := set.PendingApprovals()[0].ID
+
+	server := &ApprovalServer{Set: set, Token: "secret"}
+	serve := func(method, path, actor string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		r = r.WithContext(WithActor(r.Context(), actor))
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve(http.MethodGet, "/approvals", "bob"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"id":"`+id+`"`) {
+		t.Errorf("Failed to list pending changes: got %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodPost, "/approvals/"+id+"/approve", "alice"); w.Code != http.StatusForbidden {
+		t.Errorf("Failed to forbid self approval: expected %d; got %d", http.StatusForbidden, w.Code)
+	}
+	if w := serve(http.MethodPost, "/approvals/"+id+"/approve", "bob"); w.Code != http.StatusNoContent || cfg.Limit != 20 {
+		t.Errorf("Failed to approve: got %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodPost, "/approvals/"+id+"/reject", "bob"); w.Code != http.StatusNotFound {
+		t.Errorf("Failed to report unknown request: expected %d; got %d", http.StatusNotFound, w.Code)
+	}
+}
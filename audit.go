@@ -0,0 +1,79 @@
+package config
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecretAccess describes a read of the value of a masked setting, see Set.AuditSecrets
+type SecretAccess struct {
+	// Path of the masked setting
+	Path string
+
+	// Via is the accessor the value was read with, Set.Get or Setting.Reveal
+	Via string
+
+	// Caller is the file:line the accessor was called from
+	Caller string
+
+	// Function the accessor was called from
+	Function string
+
+	// Time of the read
+	Time time.Time
+}
+
+// secretAuditor holds the audit function of a Set tree in an atomic.Value
+type secretAuditor struct {
+	fn func(SecretAccess)
+}
+
+// packagePath is the import path of this package, frames within it are skipped when finding the caller of an accessor
+var packagePath = reflect.TypeOf(Set{}).PkgPath()
+
+// AuditSecrets calls fn with a SecretAccess every time the value of a masked setting in the Set tree is read with Set.Get (or Set.GetContext) or Setting.Reveal, identifying the call site outside this package. This answers who touched a credential, fields of bound structs are read directly and are not seen. A nil fn stops auditing.
+func (s *Set) AuditSecrets(fn func(SecretAccess)) {
+	s.Root().auditValue.Store(&secretAuditor{fn: fn})
+}
+
+// Reveal returns the value of the setting as a string even when it is masked, unlike Setting.String. Reads of masked settings are reported to the audit function of the Set, see Set.AuditSecrets.
+func (s *Setting) Reveal() string {
+	s.auditSecret("Setting.Reveal")
+	return s.format()
+}
+
+// auditSecret reports the read of a masked setting via the accessor to the audit function of its Set
+func (s *Setting) auditSecret(via string) {
+	if !s.Mask || s.set == nil {
+		return
+	}
+
+	auditor, _ := s.set.Root().auditValue.Load().(*secretAuditor)
+	if auditor == nil || auditor.fn == nil {
+		return
+	}
+
+	access := SecretAccess{Path: s.Path, Via: via, Time: time.Now()}
+
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+
+		// the caller is the first frame outside the package, tests of the package count as outside
+		if !strings.HasPrefix(frame.Function, packagePath+".") || strings.HasSuffix(frame.File, "_test.go") {
+			access.Caller = frame.File + ":" + strconv.Itoa(frame.Line)
+			access.Function = frame.Function
+			break
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	auditor.fn(access)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSet_AuditSecrets(t *testing.T) {
+	set := &Set{}
+	db := set.Subset("DB")
+	db.Setting("Password", "secret", "").Mask = true
+	db.Setting("Host", "localhost", "")
+
+	var accesses []SecretAccess
+	set.AuditSecrets(func(a SecretAccess) { accesses = append(accesses, a) })
+
+	set.Get("DB.Host")
+	password := db.Get("Password")
+
+	if v := password.Reveal(); v != "secret" {
+		t.Errorf("Failed to reveal: expected %q; got %q", "secret", v)
+	}
+
+	if len(accesses) != 2 {
+		t.Fatalf("Failed to audit reads: expected 2; got %v", accesses)
+	}
+
+	for i, via := range []string{"Set.Get", "Setting.Reveal"} {
+		a := accesses[i]
+		if a.Path != "DB.Password" || a.Via != via {
+			t.Errorf("Failed to audit read %d: got %q via %q", i, a.Path, a.Via)
+		}
+
+		if !strings.Contains(a.Caller, "audit_test.go:") || !strings.HasSuffix(a.Function, "TestSet_AuditSecrets") {
+			t.Errorf("Failed to identify caller: got %q in %q", a.Caller, a.Function)
+		}
+	}
+
+	set.AuditSecrets(nil)
+	set.Get("DB.Password")
+	if len(accesses) != 2 {
+		t.Errorf("Failed to stop auditing: got %d reads", len(accesses))
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrForbidden is returned when the caller is not allowed to write a setting
+var ErrForbidden = errors.New("forbidden")
+
+// Authorizer decides whether the caller, identified by the ctx, may write the setting through an admin surface
+type Authorizer interface {
+	Authorize(ctx context.Context, setting *Setting) error
+}
+
+// AuthorizerFunc defines a function that decides whether the caller may write the setting
+type AuthorizerFunc func(ctx context.Context, setting *Setting) error
+
+// Authorize implements Authorizer.Authorize
+func (f AuthorizerFunc) Authorize(ctx context.Context, setting *Setting) error {
+	return f(ctx, setting)
+}
+
+// RoleAuthorizer allows writing settings without a Role, and settings whose Role is one of the roles of the caller added with WithRoles
+var RoleAuthorizer Authorizer = AuthorizerFunc(func(ctx context.Context, setting *Setting) error {
+	if setting.Role == "" {
+		return nil
+	}
+
+	for _, role := range RolesFromContext(ctx) {
+		if role == setting.Role {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s requires role %q", ErrForbidden, setting.Path, setting.Role)
+})
+
+type rolesContextKey struct{}
+
+// WithRoles returns a child context of ctx for a caller having the roles, typically added by the authentication middleware of an admin surface
+func WithRoles(ctx context.Context, roles ...string) context.Context {
+	return context.WithValue(ctx, rolesContextKey{}, roles)
+}
+
+// RolesFromContext returns the roles added to the ctx with WithRoles
+func RolesFromContext(ctx context.Context) []string {
+	roles, _ := ctx.Value(rolesContextKey{}).([]string)
+	return roles
+}
+
+// Authorize sets the Authorizer consulted by Set.UpdateContext for the whole Set tree, such as RoleAuthorizer. A nil Authorizer allows every write.
+func (s *Set) Authorize(a Authorizer) {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	root.authorizer = a
+}
+
+// UpdateContext updates an existing setting by name like Set.Update on behalf of the caller identified by the ctx, returning an error wrapping ErrForbidden when the Authorizer of the Set denies the write and a *GuardError when the value is rejected by the Guards of the Set, and an error wrapping ErrApprovalPending when the setting requires approval (see LabelApprovalRequired). Admin surfaces (i.e. Gossip.Update) write through UpdateContext while the application itself writes through Set.Update. Changes made through UpdateContext are runtime changes saved by Set.Persist.
+func (s *Set) UpdateContext(ctx context.Context, name, value string) (bool, error) {
+	setting := s.lookup(name)
+	if setting == nil {
+		return false, nil
+	}
+
+	if err := s.authorize(ctx, setting); err != nil {
+		return true, err
+	}
+
+	if err := s.guard(ctx, setting, value); err != nil {
+		return true, err
+	}
+
+	if parked, err := s.park(ctx, setting, value); parked {
+		return true, err
+	}
+
+	if err := setting.SetContext(ctx, value); err != nil {
+		return true, err
+	}
+
+	// runtime changes are saved when the Set is persisted
+	s.persist(setting)
+
+	return true, nil
+}
+
+type trustedContextKey struct{}
+
+// trusted returns a child context of ctx for writes that were already authorized, i.e. changes received from a peer
+func trusted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trustedContextKey{}, true)
+}
+
+// authorize the write of the setting by the caller in the ctx with the Authorizer of the Set
+func (s *Set) authorize(ctx context.Context, setting *Setting) error {
+	if ctx.Value(trustedContextKey{}) != nil {
+		return nil
+	}
+
+	root := s.Root()
+	root.mu.Lock()
+	authorizer := root.authorizer
+	root.mu.Unlock()
+
+	if authorizer == nil {
+		return nil
+	}
+
+	return authorizer.Authorize(ctx, setting)
+}
+
+// checkBearer returns if the request carries the token as a bearer token, responding 401 when it does not. An empty token allows every request, for servers protected by other means.
+func checkBearer(w http.ResponseWriter, r *http.Request, token string) bool {
+	if token == "" {
+		return true
+	}
+
+	bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSet_UpdateContext(t *testing.T) {
+	cfg := struct {
+		LogLevel string
+		DSN      string `role:"dba"`
+	}{LogLevel: "info", DSN: "postgres://localhost"}
+
+	set := &Set{}
+	set.Bind(&cfg)
+	set.Authorize(RoleAuthorizer)
+
+	support := WithRoles(context.Background(), "support")
+
+	if found, err := set.UpdateContext(support, "LogLevel", "debug"); !found || err != nil || cfg.LogLevel != "debug" {
+		t.Errorf("Failed to allow unrestricted setting: got %v with %q", err, cfg.LogLevel)
+	}
+
+	if _, err := set.UpdateContext(support, "DSN", "postgres://example.com"); !errors.Is(err, ErrForbidden) || cfg.DSN != "postgres://localhost" {
+		t.Errorf("Failed to forbid restricted setting: got %v with %q", err, cfg.DSN)
+	}
+
+	if _, err := set.UpdateContext(WithRoles(context.Background(), "support", "dba"), "DSN", "postgres://example.com"); err != nil || cfg.DSN != "postgres://example.com" {
+		t.Errorf("Failed to allow restricted setting for role: got %v with %q", err, cfg.DSN)
+	}
+
+	// the application itself is not restricted
+	if _, err := set.Update("DSN", "postgres://localhost"); err != nil {
+		t.Errorf("Failed to update directly: %v", err)
+	}
+}
+
+func TestGossip_Authorize(t *testing.T) {
+	var (
+		dsnA = "a"
+		dsnB = "a"
+	)
+
+	setA := &Set{}
+	setA.Setting("DSN", &dsnA, "").Role = "dba"
+	setA.Authorize(RoleAuthorizer)
+	setB := &Set{}
+	setB.Setting("DSN", &dsnB, "").Role = "dba"
+	setB.Authorize(RoleAuthorizer)
+
+	a := &Gossip{Set: setA, Token: "token"}
+	b := &Gossip{Set: setB, Token: "token"}
+	server := httptest.NewServer(b)
+	defer server.Close()
+	a.Peers = []string{server.URL}
+
+	if err := a.Update(context.Background(), "DSN", "b"); !errors.Is(err, ErrForbidden) || dsnA != "a" {
+		t.Errorf("Failed to forbid change: got %v with %q", err, dsnA)
+	}
+
+	if err := a.Update(WithRoles(context.Background(), "dba"), "DSN", "b"); err != nil || dsnA != "b" || dsnB != "b" {
+		t.Errorf("Failed to propagate authorized change: got %v with %q and %q", err, dsnA, dsnB)
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func BenchmarkSetting_Set(b *testing.B) {
+	set := &Set{}
+	port := 0
+	setting := set.Setting("Port", &port, "")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = setting.Set(strconv.Itoa(i))
+	}
+}
+
+func BenchmarkSet_Get(b *testing.B) {
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", 80, "")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = set.Get("HTTP.Port")
+	}
+}
+
+func BenchmarkSetting_String(b *testing.B) {
+	setting := (&Set{}).Setting("Timeout", 1.5, "")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = setting.String()
+	}
+}
+
+func BenchmarkSetting_Equals(b *testing.B) {
+	setting := (&Set{}).Setting("Debug", true, "")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = setting.Equals("true")
+	}
+}
+
+// largeStruct returns a pointer to a struct with n int fields
+func largeStruct(n int) interface{} {
+	fields := make([]reflect.StructField, n)
+	for i := range fields {
+		fields[i] = reflect.StructField{Name: fmt.Sprintf("Field%d", i), Type: reflect.TypeOf(0)}
+	}
+
+	return reflect.New(reflect.StructOf(fields)).Interface()
+}
+
+func BenchmarkSet_Bind(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				(&Set{}).Bind(largeStruct(n))
+			}
+		})
+	}
+}
+
+func BenchmarkSet_Range(b *testing.B) {
+	set := &Set{}
+	for i := 0; i < 10000; i++ {
+		set.Subset(fmt.Sprintf("Group%d", i%100)).Setting(fmt.Sprintf("Setting%d", i), i, "")
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		set.Range(func(string, *Setting) bool {
+			count++
+			return true
+		})
+		if count != 10000 {
+			b.Fatalf("Failed to range: expected %d; got %d", 10000, count)
+		}
+	}
+}
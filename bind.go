@@ -0,0 +1,234 @@
+package config
+
+import (
+	"net"
+	"net/mail"
+	"net/netip"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// valueTypes are structs and pointers to structs supported as setting values, Set.Bind binds them as settings rather than subsets
+var valueTypes = map[reflect.Type]bool{
+	reflect.TypeOf((*time.Location)(nil)): true,
+	reflect.TypeOf(netip.Addr{}):          true,
+	reflect.TypeOf(netip.AddrPort{}):      true,
+	reflect.TypeOf((*net.TCPAddr)(nil)):   true,
+	reflect.TypeOf((*mail.Address)(nil)):  true,
+}
+
+// BindOption configures how Set.Bind maps struct fields to settings
+type BindOption func(*bindOptions)
+
+// bindOptions are the resolved BindOption values
+type bindOptions struct {
+	tags   TagNames
+	mapper func(reflect.StructField) string
+
+	// ancestors are the struct types being bound above the current one, so nil recursive pointers are not allocated forever
+	ancestors []reflect.Type
+}
+
+// nested returns the options to bind the fields of a struct within the struct of type parent
+func (o *bindOptions) nested(parent reflect.Type) *bindOptions {
+	nested := *o
+	nested.ancestors = append(o.ancestors[:len(o.ancestors):len(o.ancestors)], parent)
+	return &nested
+}
+
+// within returns if a struct of the type is being bound above the current one
+func (o *bindOptions) within(t reflect.Type) bool {
+	for _, ancestor := range o.ancestors {
+		if ancestor == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TagNames are the struct field tag keys read by Set.Bind
+type TagNames struct {
+	// Setting overrides the name of the setting, defaults to "setting". Anything after a comma is ignored so existing `json` or `yaml` tags can be reused.
+	Setting string
+
+	// Description of the setting, defaults to "description"
+	Description string
+
+	// Mask of the setting when set to "true", defaults to "mask"
+	Mask string
+
+	// Flag to register the setting as, defaults to "flag"
+	Flag string
+
+	// Category of the setting, defaults to "category"
+	Category string
+
+	// Required marks the setting as required when set to "true", defaults to "required"
+	Required string
+
+	// Role required to write the setting through admin surfaces, defaults to "role"
+	Role string
+
+	// Constraint expression the value must satisfy, defaults to "constraint"
+	Constraint string
+
+	// Annotations of the setting as comma separated key=value pairs, defaults to "meta"
+	Annotations string
+
+	// Labels of the setting as a comma separated list, defaults to "labels"
+	Labels string
+}
+
+// WithTags remaps the struct field tag keys read by Set.Bind, empty names keep their default. This allows reusing existing tags, such as WithTags(TagNames{Setting: "json"}).
+func WithTags(tags TagNames) BindOption {
+	return func(o *bindOptions) {
+		if tags.Setting != "" {
+			o.tags.Setting = tags.Setting
+		}
+		if tags.Description != "" {
+			o.tags.Description = tags.Description
+		}
+		if tags.Mask != "" {
+			o.tags.Mask = tags.Mask
+		}
+		if tags.Flag != "" {
+			o.tags.Flag = tags.Flag
+		}
+		if tags.Category != "" {
+			o.tags.Category = tags.Category
+		}
+		if tags.Required != "" {
+			o.tags.Required = tags.Required
+		}
+		if tags.Role != "" {
+			o.tags.Role = tags.Role
+		}
+		if tags.Constraint != "" {
+			o.tags.Constraint = tags.Constraint
+		}
+		if tags.Annotations != "" {
+			o.tags.Annotations = tags.Annotations
+		}
+		if tags.Labels != "" {
+			o.tags.Labels = tags.Labels
+		}
+	}
+}
+
+// WithNameMapper sets the function deriving the setting name from a struct field that has no explicit name tag, such as KebabCase or SnakeCase
+func WithNameMapper(mapper func(reflect.StructField) string) BindOption {
+	return func(o *bindOptions) {
+		o.mapper = mapper
+	}
+}
+
+// KebabCase maps the field name to kebab-case keeping acronyms together (HTTPServerPort -> http-server-port)
+func KebabCase(field reflect.StructField) string {
+	return strings.Join(splitWords(field.Name), "-")
+}
+
+// SnakeCase maps the field name to snake_case keeping acronyms together (HTTPServerPort -> http_server_port)
+func SnakeCase(field reflect.StructField) string {
+	return strings.Join(splitWords(field.Name), "_")
+}
+
+// splitWords splits a camelCase name into lower case words, an upper case run is an acronym with the last upper case letter starting the next word (HTTPServer -> http, server) and digits stay with the preceding word (Base64Data -> base64, data)
+func splitWords(name string) []string {
+	runes := []rune(name)
+
+	var (
+		words []string
+		start int
+	)
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+
+		boundary := false
+		switch {
+		case unicode.IsLower(prev) && unicode.IsUpper(cur):
+			boundary = true
+		case unicode.IsDigit(prev) && unicode.IsUpper(cur):
+			boundary = true
+		case unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			boundary = true
+		case cur == '_' || cur == '-':
+			words = append(words, strings.ToLower(string(runes[start:i])))
+			start = i + 1
+			continue
+		}
+
+		if boundary && i > start {
+			words = append(words, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+
+	return words
+}
+
+// newBindOptions resolves the options on top of the defaults
+func newBindOptions(opts []BindOption) *bindOptions {
+	o := &bindOptions{
+		tags: TagNames{
+			Setting:     "setting",
+			Description: "description",
+			Mask:        "mask",
+			Flag:        "flag",
+			Category:    "category",
+			Required:    "required",
+			Role:        "role",
+			Constraint:  "constraint",
+			Annotations: "meta",
+			Labels:      "labels",
+		},
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// parseAnnotations parses comma separated key=value pairs, a key without a value is annotated with an empty value
+func parseAnnotations(tag string) map[string]string {
+	if tag == "" {
+		return nil
+	}
+
+	annotations := map[string]string{}
+	for _, pair := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(pair, "=")
+		if key = strings.TrimSpace(key); key != "" {
+			annotations[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return annotations
+}
+
+// formatAnnotations formats the annotations as sorted comma separated key=value pairs, the inverse of parseAnnotations
+func formatAnnotations(annotations map[string]string) string {
+	pairs := make([]string, 0, len(annotations))
+	for key, value := range annotations {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// tagName returns the name portion of a tag value, dropping options such as ",omitempty"
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSet_BindWithTags(t *testing.T) {
+	cfg := struct {
+		Port     int    `json:"port,omitempty" help:"Port to listen"`
+		Password string `json:"password" secret:"true"`
+		Host     string `json:",omitempty"`
+	}{}
+
+	set := (&Set{}).Bind(&cfg, WithTags(TagNames{Setting: "json", Description: "help", Mask: "secret"}))
+
+	port := set.Get("port")
+	if port == nil || port.Description != "Port to listen" {
+		t.Fatalf("Failed to bind with remapped tags: got %+v", port)
+	}
+
+	if password := set.Get("password"); password == nil || !password.Mask {
+		t.Errorf("Failed to mask with remapped tag: got %+v", password)
+	}
+
+	if set.Get("Host") == nil {
+		t.Errorf("Failed to fall back to the field name for an empty tag name")
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := map[string]string{
+		"Port":           "port",
+		"HTTPServerPort": "http-server-port",
+		"UserID":         "user-id",
+		"MaxIdleConns":   "max-idle-conns",
+		"Base64Data":     "base64-data",
+		"TLS2Enabled":    "tls2-enabled",
+		"Read_Timeout":   "read-timeout",
+	}
+
+	for name, expected := range tests {
+		if got := KebabCase(reflect.StructField{Name: name}); got != expected {
+			t.Errorf("Failed to map %q: expected %q; got %q", name, expected, got)
+		}
+	}
+}
+
+func TestSet_BindWithNameMapper(t *testing.T) {
+	cfg := struct {
+		MaxConns int
+		HTTP     struct {
+			ReadTimeout int
+		}
+		Explicit int `setting:"Explicit"`
+	}{}
+
+	set := (&Set{}).Bind(&cfg, WithNameMapper(SnakeCase))
+
+	for _, path := range []string{"max_conns", "http.read_timeout", "Explicit"} {
+		if set.Get(path) == nil {
+			t.Errorf("Failed to bind mapped name %q", path)
+		}
+	}
+}
+
+func TestSet_BindMap(t *testing.T) {
+	type Upstream struct {
+		Host string
+		Port int
+	}
+
+	cfg := struct {
+		Upstreams map[string]Upstream
+		Backends  map[string]*Upstream
+	}{
+		Backends: map[string]*Upstream{"default": {Host: "localhost", Port: 80}},
+	}
+
+	set := (&Set{}).Bind(&cfg)
+
+	if setting := set.Get("Backends.default.Port"); setting == nil || setting.String() != "80" {
+		t.Fatalf("Failed to bind existing map entry: got %+v", setting)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"Upstreams": {"primary": {"Host": "a", "Port": 1}, "secondary": {"Host": "b"}}, "Backends": {"extra": {"Port": 8080}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := set.LoadFile(path); err != nil {
+		t.Fatalf("Failed to load file: %v", err)
+	}
+
+	if len(cfg.Upstreams) != 2 || cfg.Upstreams["primary"] != (Upstream{Host: "a", Port: 1}) || cfg.Upstreams["secondary"].Host != "b" {
+		t.Errorf("Failed to materialize map entries: got %+v", cfg.Upstreams)
+	}
+
+	if backend := cfg.Backends["extra"]; backend == nil || backend.Port != 8080 {
+		t.Errorf("Failed to materialize pointer map entry: got %+v", backend)
+	}
+
+	if _, err := set.Update("Upstreams.primary.Port", "2"); err != nil {
+		t.Fatalf("Failed to update map entry: %v", err)
+	}
+
+	if port := cfg.Upstreams["primary"].Port; port != 2 {
+		t.Errorf("Failed to copy update into map entry: expected %d; got %d", 2, port)
+	}
+
+	if found, err := set.Update("Upstreams.tertiary.Host", "c"); !found || err != nil || cfg.Upstreams["tertiary"].Host != "c" {
+		t.Errorf("Failed to materialize map entry on update: got %+v (%v)", cfg.Upstreams["tertiary"], err)
+	}
+
+	if found, _ := set.Update("Upstreams.quaternary", "d"); found {
+		t.Errorf("Failed to ignore a map key without a setting")
+	}
+}
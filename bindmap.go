@@ -0,0 +1,314 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxSliceGrowth is how far past its length a bound slice grows for a single index, so a stray path can't allocate a huge slice
+const maxSliceGrowth = 1024
+
+// mapBinding is a map[string]Struct or []Struct field bound with Set.Bind, every key or index is a subset of the field
+type mapBinding struct {
+	// set the field is bound in, as the subset named after the field
+	set   *Set
+	value reflect.Value
+	opts  *bindOptions
+
+	mu sync.Mutex
+
+	// keys of a map that are bound, lower case
+	keys map[string]bool
+
+	// entries of a slice that are bound, pointers to the elements
+	entries []reflect.Value
+}
+
+// bindMap binds the field as a subset named after the field when it is a map of strings, or a slice, of structs or struct pointers. Every key or element already in the field is bound immediately and other keys or indexes when a path beneath them is first used. Returns false for other maps and slices.
+func (s *Set) bindMap(name string, value reflect.Value, opts *bindOptions) bool {
+	typ := value.Type()
+	if typ.Kind() == reflect.Map && typ.Key().Kind() != reflect.String {
+		return false
+	}
+
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct || valueTypes[typ.Elem()] {
+		return false
+	}
+
+	if value.Kind() == reflect.Map && value.IsNil() {
+		value.Set(reflect.MakeMap(typ))
+	}
+
+	binding := &mapBinding{
+		set:   s.Subset(name),
+		value: value,
+		opts:  opts,
+		keys:  map[string]bool{},
+	}
+
+	root := s.Root()
+	root.mu.Lock()
+	root.mapBindings = append(root.mapBindings, binding)
+	root.mu.Unlock()
+
+	if value.Kind() == reflect.Slice {
+		if value.Len() > 0 {
+			binding.bind(strconv.Itoa(value.Len() - 1))
+		}
+		return true
+	}
+
+	for _, key := range value.MapKeys() {
+		binding.bind(key.String())
+	}
+
+	return true
+}
+
+// pointer returns if the elements of the field are struct pointers
+func (b *mapBinding) pointer() bool {
+	return b.value.Type().Elem().Kind() == reflect.Ptr
+}
+
+// bound returns if the key or index is bound
+func (b *mapBinding) bound(key string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.value.Kind() == reflect.Slice {
+		index, err := strconv.Atoi(key)
+		return err == nil && index < len(b.entries)
+	}
+
+	return b.keys[strings.ToLower(key)]
+}
+
+// bind the key, or index of a slice, as a subset copying an existing entry of the field
+func (b *mapBinding) bind(key string) {
+	if b.value.Kind() == reflect.Slice {
+		b.bindIndex(key)
+		return
+	}
+
+	mapKey := reflect.ValueOf(key).Convert(b.value.Type().Key())
+
+	typ := b.value.Type().Elem()
+	pointer := b.pointer()
+	if pointer {
+		typ = typ.Elem()
+	}
+
+	b.mu.Lock()
+	if b.keys[strings.ToLower(key)] {
+		b.mu.Unlock()
+		return
+	}
+	b.keys[strings.ToLower(key)] = true
+
+	entry := reflect.New(typ)
+	if existing := b.value.MapIndex(mapKey); existing.IsValid() {
+		if !pointer {
+			entry.Elem().Set(existing)
+		} else if !existing.IsNil() {
+			entry = existing
+		}
+	}
+	b.mu.Unlock()
+
+	subset := b.set.Subset(key)
+	subset.bind(entry.Interface(), b.opts)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if pointer {
+		b.value.SetMapIndex(mapKey, entry)
+		return
+	}
+
+	b.value.SetMapIndex(mapKey, entry.Elem())
+
+	// entries of a map are not addressable, so changes are copied back into the map
+	subset.Notify(NotifyFunc(func(*Setting) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.value.SetMapIndex(mapKey, entry.Elem())
+	}))
+}
+
+// bindIndex binds the index of a slice, and every index before it, growing the slice as needed
+func (b *mapBinding) bindIndex(key string) {
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 || strconv.Itoa(index) != key {
+		return
+	}
+
+	typ := b.value.Type().Elem()
+	pointer := b.pointer()
+	if pointer {
+		typ = typ.Elem()
+	}
+
+	for {
+		b.mu.Lock()
+		next := len(b.entries)
+		if next > index || index >= b.value.Len()+maxSliceGrowth {
+			b.mu.Unlock()
+			return
+		}
+
+		entry := reflect.New(typ)
+		if next < b.value.Len() {
+			if existing := b.value.Index(next); !pointer {
+				entry.Elem().Set(existing)
+			} else if !existing.IsNil() {
+				entry = existing
+			}
+		}
+
+		b.entries = append(b.entries, entry)
+		b.store(next)
+		b.mu.Unlock()
+
+		subset := b.set.Subset(strconv.Itoa(next))
+		subset.bind(entry.Interface(), b.opts)
+
+		if pointer {
+			continue
+		}
+
+		// the slice is replaced when it grows, so elements are copied back rather than bound in place
+		i := next
+		subset.Notify(NotifyFunc(func(*Setting) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			b.store(i)
+		}))
+	}
+}
+
+// store the bound entry at the index of the slice, growing it when needed. The caller must hold mu.
+func (b *mapBinding) store(index int) {
+	if index >= b.value.Len() {
+		grown := reflect.MakeSlice(b.value.Type(), index+1, index+1)
+		reflect.Copy(grown, b.value)
+		b.value.Set(grown)
+	}
+
+	entry := b.entries[index]
+	if b.pointer() {
+		b.value.Index(index).Set(entry)
+	} else {
+		b.value.Index(index).Set(entry.Elem())
+	}
+}
+
+// materializeMap binds the key or index of a map or slice field the path is beneath, returning if a key was bound
+func (s *Set) materializeMap(path string) bool {
+	root := s.Root()
+
+	root.mu.Lock()
+	bindings := root.mapBindings
+	root.mu.Unlock()
+
+	for _, b := range bindings {
+		prefix := b.set.path + "."
+		if len(path) <= len(prefix) || !strings.EqualFold(path[:len(prefix)], prefix) {
+			continue
+		}
+
+		key, rest, found := strings.Cut(path[len(prefix):], ".")
+		if !found || rest == "" {
+			continue
+		}
+
+		if !b.bound(key) {
+			b.bind(key)
+			return b.bound(key)
+		}
+	}
+
+	return false
+}
+
+// peekMap resolves the path beneath an unbound key or index of a map or slice field to a setting of a detached entry, leaving the field as is. Returns if the path is beneath such a key, with a nil setting when the rest of the path is not a field of the entry.
+func (s *Set) peekMap(path string) (*Setting, bool) {
+	root := s.Root()
+
+	root.mu.Lock()
+	bindings := root.mapBindings
+	root.mu.Unlock()
+
+	for _, b := range bindings {
+		prefix := b.set.path + "."
+		if len(path) <= len(prefix) || !strings.EqualFold(path[:len(prefix)], prefix) {
+			continue
+		}
+
+		key, rest, found := strings.Cut(path[len(prefix):], ".")
+		if !found || rest == "" || b.bound(key) {
+			continue
+		}
+
+		entry, ok := b.detached(key)
+		if !ok {
+			return nil, true
+		}
+
+		scratch := &Set{}
+		scratch.bind(entry.Interface(), b.opts)
+
+		setting := scratch.lookup(rest)
+		if setting == nil {
+			return nil, true
+		}
+		setting.Path = b.set.pathOf(key) + "." + setting.Path
+
+		return setting, true
+	}
+
+	return nil, false
+}
+
+// detached returns a pointer to a copy of the entry for the key or index, or a new one when the field has none, returning false for an index the field can't grow to
+func (b *mapBinding) detached(key string) (reflect.Value, bool) {
+	typ := b.value.Type().Elem()
+	pointer := b.pointer()
+	if pointer {
+		typ = typ.Elem()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var existing reflect.Value
+	if b.value.Kind() == reflect.Slice {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || strconv.Itoa(index) != key || index >= b.value.Len()+maxSliceGrowth {
+			return reflect.Value{}, false
+		}
+		if index < b.value.Len() {
+			existing = b.value.Index(index)
+		}
+	} else {
+		existing = b.value.MapIndex(reflect.ValueOf(key).Convert(b.value.Type().Key()))
+	}
+
+	entry := reflect.New(typ)
+	if existing.IsValid() && (!pointer || !existing.IsNil()) {
+		if pointer {
+			existing = existing.Elem()
+		}
+		entry.Elem().Set(existing)
+	}
+
+	return entry, true
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Bootstrap describes where Set.Bootstrap loads the configuration of a service from
+type Bootstrap struct {
+	// Name of the service, the environment variables are prefixed with EnvName("", Name) and on Windows the values under the registry key HKEY_LOCAL_MACHINE\SOFTWARE\<Name> are loaded
+	Name string
+
+	// File to load with Set.LoadFile, skipped when it does not exist
+	File string
+
+	// Flags to parse the Args with, defaults to flag.CommandLine
+	Flags *flag.FlagSet
+
+	// Args to parse, defaults to os.Args[1:]
+	Args []string
+
+	// Report is called with the error Set.Bootstrap fails with before it is returned, on Windows it defaults to reporting the error to the event log as the source Name
+	Report func(error)
+}
+
+// Prefix returns the prefix of the environment variables of the service, i.e. MY_SERVICE for my-service
+func (b Bootstrap) Prefix() string {
+	return EnvName("", b.Name)
+}
+
+// Bootstrap loads the configuration of a service in the conventional order of precedence, each source overriding the previous one: the File, the registry on Windows (see Registry), the environment (see Set.LoadEnv) and finally the command line flags. Settings must be bound (and their flags registered) before calling Bootstrap.
+func (s *Set) Bootstrap(b Bootstrap) (err error) {
+	report := b.Report
+	if report == nil {
+		report = defaultReport(b.Name)
+	}
+	defer func() {
+		if err != nil && report != nil {
+			report(err)
+		}
+	}()
+
+	if b.File != "" {
+		if err := s.LoadFile(b.File); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	if err := s.bootstrapPlatform(b); err != nil {
+		return err
+	}
+
+	if err := s.LoadEnv(b.Prefix()); err != nil {
+		return fmt.Errorf("unable to load environment: %w", err)
+	}
+
+	flags, args := b.Flags, b.Args
+	if flags == nil {
+		flags = flag.CommandLine
+	}
+	if args == nil && len(os.Args) > 1 {
+		args = os.Args[1:]
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("unable to parse flags: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package config
+
+// bootstrapPlatform loads the platform specific sources of Set.Bootstrap, there are none besides the file, environment and flags
+func (s *Set) bootstrapPlatform(b Bootstrap) error {
+	return nil
+}
+
+// defaultReport returns the default Bootstrap.Report, the error is only returned
+func defaultReport(name string) func(error) {
+	return nil
+}
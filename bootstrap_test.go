@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet_Bootstrap(t *testing.T) {
+	cfg := struct {
+		Host string
+		Port int
+		Mode string
+	}{Host: "localhost", Port: 80, Mode: "dev"}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	dir := writeFiles(t, map[string]string{
+		"config.json": `{"Host": "file.example.com", "Port": 8080, "Mode": "file"}`,
+	})
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.Get("Mode").Flag("mode", fs)
+
+	t.Setenv("MY_SERVICE_PORT", "9090")
+	t.Setenv("MY_SERVICE_MODE", "env")
+
+	err := set.Bootstrap(Bootstrap{
+		Name:  "my-service",
+		File:  filepath.Join(dir, "config.json"),
+		Flags: fs,
+		Args:  []string{"-mode", "prod"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to bootstrap: %v", err)
+	}
+
+	if cfg.Host != "file.example.com" || cfg.Port != 9090 || cfg.Mode != "prod" {
+		t.Errorf("Failed to bootstrap: got %q, %d and %q", cfg.Host, cfg.Port, cfg.Mode)
+	}
+
+	if source := set.Get("Mode").Source(); source != SourceFlag {
+		t.Errorf("Failed to bootstrap: expected source %q; got %q", SourceFlag, source)
+	}
+}
+
+func TestSet_BootstrapMissingFile(t *testing.T) {
+	cfg := struct{ Port int }{Port: 80}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	err := set.Bootstrap(Bootstrap{
+		File:  filepath.Join(t.TempDir(), "missing.json"),
+		Flags: flag.NewFlagSet("test", flag.ContinueOnError),
+		Args:  []string{},
+	})
+	if err != nil {
+		t.Errorf("Failed to skip missing file: %v", err)
+	}
+}
+
+func TestSet_BootstrapReport(t *testing.T) {
+	set := &Set{}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var reported error
+	err := set.Bootstrap(Bootstrap{
+		Flags:  fs,
+		Args:   []string{"-unknown"},
+		Report: func(err error) { reported = err },
+	})
+	if err == nil {
+		t.Fatal("Failed to fail on unknown flag")
+	}
+
+	if !errors.Is(reported, err) {
+		t.Errorf("Failed to report: expected %v; got %v", err, reported)
+	}
+}
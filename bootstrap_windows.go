@@ -0,0 +1,262 @@
+//go:build windows
+
+package config
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var (
+	advapi32 = syscall.NewLazyDLL("advapi32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procRegEnumValueW             = advapi32.NewProc("RegEnumValueW")
+	procRegisterEventSourceW      = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource     = advapi32.NewProc("DeregisterEventSource")
+	procReportEventW              = advapi32.NewProc("ReportEventW")
+	procExpandEnvironmentStringsW = kernel32.NewProc("ExpandEnvironmentStringsW")
+)
+
+// event types of ReportEventW
+const (
+	eventlogErrorType   = 0x0001
+	eventlogWarningType = 0x0002
+)
+
+// bootstrapPlatform loads the values under the registry key HKEY_LOCAL_MACHINE\SOFTWARE\<Name>, a missing key is skipped
+func (s *Set) bootstrapPlatform(b Bootstrap) error {
+	if b.Name == "" {
+		return nil
+	}
+
+	values, err := Registry(`SOFTWARE\` + b.Name).Load(context.Background())
+	if errors.Is(err, syscall.ERROR_FILE_NOT_FOUND) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.updateAll(values, SourceRegistry)
+}
+
+// defaultReport returns the default Bootstrap.Report, reporting the error to the event log as the service
+func defaultReport(name string) func(error) {
+	if name == "" {
+		return nil
+	}
+
+	l := EventLog(name)
+	return func(err error) {
+		l.Error("unable to bootstrap configuration", "error", err)
+	}
+}
+
+// Registry returns a Provider reading the values under the key of HKEY_LOCAL_MACHINE (i.e. SOFTWARE\MyService), subkeys are resolved as subsets so the value Port of the subkey HTTP updates HTTP.Port. REG_EXPAND_SZ values have their environment variables expanded, REG_MULTI_SZ values are joined by commas and binary values are skipped.
+func Registry(key string) Provider {
+	return ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		values := map[string]string{}
+		if err := readRegistry(syscall.HKEY_LOCAL_MACHINE, key, "", values); err != nil {
+			return nil, fmt.Errorf("unable to read registry key %q: %w", key, err)
+		}
+
+		return values, nil
+	})
+}
+
+// readRegistry writes the values of the key and its subkeys to out keyed by their dot separated path below the prefix
+func readRegistry(parent syscall.Handle, key, prefix string, out map[string]string) error {
+	name, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return err
+	}
+
+	var h syscall.Handle
+	if err := syscall.RegOpenKeyEx(parent, name, 0, syscall.KEY_READ, &h); err != nil {
+		return err
+	}
+	defer syscall.RegCloseKey(h)
+
+	var subkeys, maxSubkeyLen, values, maxValueNameLen, maxValueLen uint32
+	if err := syscall.RegQueryInfoKey(h, nil, nil, nil, &subkeys, &maxSubkeyLen, nil, &values, &maxValueNameLen, &maxValueLen, nil, nil); err != nil {
+		return err
+	}
+
+	for i := uint32(0); i < values; i++ {
+		nameBuf := make([]uint16, maxValueNameLen+1)
+		nameLen := uint32(len(nameBuf))
+		data := make([]byte, maxValueLen+2)
+		dataLen := uint32(len(data))
+
+		var typ uint32
+		r, _, _ := procRegEnumValueW.Call(uintptr(h), uintptr(i),
+			uintptr(unsafe.Pointer(&nameBuf[0])), uintptr(unsafe.Pointer(&nameLen)), 0,
+			uintptr(unsafe.Pointer(&typ)), uintptr(unsafe.Pointer(&data[0])), uintptr(unsafe.Pointer(&dataLen)))
+		if r != 0 {
+			return syscall.Errno(r)
+		}
+
+		// the unnamed default value of a key is not a setting
+		name := syscall.UTF16ToString(nameBuf[:nameLen])
+		if name == "" {
+			continue
+		}
+
+		if value, ok := registryString(typ, data[:dataLen]); ok {
+			out[registryPath(prefix, name)] = value
+		}
+	}
+
+	for i := uint32(0); i < subkeys; i++ {
+		buf := make([]uint16, maxSubkeyLen+1)
+		n := uint32(len(buf))
+		if err := syscall.RegEnumKeyEx(h, i, &buf[0], &n, nil, nil, nil, nil); err != nil {
+			return err
+		}
+
+		sub := syscall.UTF16ToString(buf[:n])
+		if err := readRegistry(h, sub, registryPath(prefix, sub), out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registryPath joins the name to the dot separated prefix
+func registryPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
+
+// registryString formats the registry value of the type, returning false for types that have no string form
+func registryString(typ uint32, data []byte) (string, bool) {
+	switch typ {
+	case syscall.REG_SZ:
+		return utf16String(data), true
+	case syscall.REG_EXPAND_SZ:
+		return expandEnvironment(utf16String(data)), true
+	case syscall.REG_MULTI_SZ:
+		var parts []string
+		for _, part := range strings.Split(strings.TrimRight(string(utf16.Decode(utf16Units(data))), "\x00"), "\x00") {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, ","), true
+	case syscall.REG_DWORD:
+		if len(data) < 4 {
+			return "", false
+		}
+		return strconv.FormatUint(uint64(binary.LittleEndian.Uint32(data)), 10), true
+	case syscall.REG_QWORD:
+		if len(data) < 8 {
+			return "", false
+		}
+		return strconv.FormatUint(binary.LittleEndian.Uint64(data), 10), true
+	default:
+		return "", false
+	}
+}
+
+// utf16Units returns the little endian UTF-16 code units of the data
+func utf16Units(data []byte) []uint16 {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+
+	return units
+}
+
+// utf16String decodes the NUL terminated UTF-16 data
+func utf16String(data []byte) string {
+	return syscall.UTF16ToString(utf16Units(data))
+}
+
+// expandEnvironment expands the %NAME% references of s, returning s unchanged when it can't be expanded
+func expandEnvironment(s string) string {
+	src, err := syscall.UTF16PtrFromString(s)
+	if err != nil {
+		return s
+	}
+
+	buf := make([]uint16, len(s)+1)
+	for {
+		n, _, _ := procExpandEnvironmentStringsW.Call(uintptr(unsafe.Pointer(src)), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+		if n == 0 {
+			return s
+		}
+		if int(n) <= len(buf) {
+			return syscall.UTF16ToString(buf[:n])
+		}
+		buf = make([]uint16, n)
+	}
+}
+
+// EventLog returns a Logger reporting warnings and errors to the Windows event log as the source (i.e. the service name), debug messages are discarded. Arguments are appended to the message as key=value pairs, so the message is readable without a registered message file. Use it with SetLogger for the diagnostics of a service.
+func EventLog(source string) Logger {
+	return eventLog{source: source}
+}
+
+// eventLog is the Logger returned by EventLog
+type eventLog struct {
+	source string
+}
+
+func (l eventLog) Debug(string, ...interface{}) {}
+
+func (l eventLog) Warn(msg string, args ...interface{}) {
+	l.report(eventlogWarningType, msg, args)
+}
+
+func (l eventLog) Error(msg string, args ...interface{}) {
+	l.report(eventlogErrorType, msg, args)
+}
+
+// report writes the message with its arguments to the event log, failures are ignored as there is nowhere left to report them
+func (l eventLog) report(typ uint16, msg string, args []interface{}) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", args[i])
+		}
+	}
+
+	source, err := syscall.UTF16PtrFromString(l.source)
+	if err != nil {
+		return
+	}
+
+	text, err := syscall.UTF16PtrFromString(strings.ReplaceAll(b.String(), "\x00", ""))
+	if err != nil {
+		return
+	}
+
+	h, _, _ := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(source)))
+	if h == 0 {
+		return
+	}
+	defer procDeregisterEventSource.Call(h)
+
+	strs := []*uint16{text}
+	procReportEventW.Call(h, uintptr(typ), 0, 1, 0, 1, 0, uintptr(unsafe.Pointer(&strs[0])), 0)
+}
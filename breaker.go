@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen is returned by a Breaker while it is open
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// BreakerState of a Breaker
+type BreakerState int
+
+const (
+	// BreakerClosed allows loads through to the Provider
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen fails loads immediately with ErrBreakerOpen until the Cooldown has elapsed
+	BreakerOpen
+
+	// BreakerHalfOpen allows a single trial load through, closing the Breaker on success
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker is a circuit breaker around a Provider so a flapping source does not add latency and timeouts to every reload. The state of a Breaker is reported by Set.Health.
+type Breaker struct {
+	// Provider being protected
+	Provider Provider
+
+	// Threshold of consecutive failures that opens the Breaker, defaults to 5
+	Threshold int
+
+	// Cooldown is how long the Breaker stays open before allowing a trial load, defaults to 30 seconds
+	Cooldown time.Duration
+
+	// Clock the Cooldown is measured with, defaults to SystemClock
+	Clock Clock
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	opened   time.Time
+}
+
+// Load implements Provider.Load
+func (b *Breaker) Load(ctx context.Context) (map[string]string, error) {
+	b.mu.Lock()
+	if b.state == BreakerOpen {
+		if clockOrSystem(b.Clock).Now().Sub(b.opened) < b.cooldown() {
+			b.mu.Unlock()
+			return nil, ErrBreakerOpen
+		}
+		b.state = BreakerHalfOpen
+	} else if b.state == BreakerHalfOpen {
+		// a trial is already in flight
+		b.mu.Unlock()
+		return nil, ErrBreakerOpen
+	}
+	b.mu.Unlock()
+
+	values, err := b.Provider.Load(ctx)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil {
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.threshold() {
+			b.state = BreakerOpen
+			b.opened = clockOrSystem(b.Clock).Now()
+		}
+
+		return nil, err
+	}
+
+	b.failures = 0
+	b.state = BreakerClosed
+
+	return values, nil
+}
+
+// State of the Breaker
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Unwrap returns the Provider being protected
+func (b *Breaker) Unwrap() Provider {
+	return b.Provider
+}
+
+func (b *Breaker) threshold() int {
+	if b.Threshold <= 0 {
+		return 5
+	}
+
+	return b.Threshold
+}
+
+func (b *Breaker) cooldown() time.Duration {
+	if b.Cooldown <= 0 {
+		return 30 * time.Second
+	}
+
+	return b.Cooldown
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	calls := 0
+	fail := true
+
+	breaker := &Breaker{
+		Provider: ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+			calls++
+			if fail {
+				return nil, errors.New("unavailable")
+			}
+			return nil, nil
+		}),
+		Threshold: 2,
+		Cooldown:  10 * time.Millisecond,
+	}
+
+	set := &Set{}
+	set.AddProvider("remote", breaker)
+
+	for i := 0; i < 4; i++ {
+		_ = set.Reload(context.Background())
+	}
+
+	if calls != 2 {
+		t.Errorf("Failed to stop calling provider once open: expected 2 calls; got %d", calls)
+	}
+
+	if state := set.Health()[0].Breaker; state != "open" {
+		t.Errorf("Failed to report breaker state: expected %q; got %q", "open", state)
+	}
+
+	if _, err := breaker.Load(context.Background()); !errors.Is(err, ErrBreakerOpen) {
+		t.Errorf("Failed to return ErrBreakerOpen: got %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	fail = false
+
+	if err := set.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload after cooldown: %v", err)
+	}
+
+	if breaker.State() != BreakerClosed {
+		t.Errorf("Failed to close breaker after successful trial: got %v", breaker.State())
+	}
+}
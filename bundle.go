@@ -0,0 +1,179 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// maxBundleChanges is the number of recent changes included in a SupportBundle
+const maxBundleChanges = 100
+
+// SupportBundle is a redacted snapshot of the configuration of a Set for attaching to support tickets, see Set.SupportBundle
+type SupportBundle struct {
+	// Time the snapshot was taken
+	Time time.Time `json:"time"`
+
+	// Path of the Set, empty for a root
+	Path string `json:"path,omitempty"`
+
+	// Revision of the Set, see Set.Revision
+	Revision uint64 `json:"revision"`
+
+	// Settings of the Set with their effective values, sorted by path
+	Settings []BundleSetting `json:"settings"`
+
+	// Providers the Set is loaded from, in precedence order
+	Providers []BundleProvider `json:"providers"`
+
+	// Changes are the most recent changes retained by Set.KeepHistory, newest first
+	Changes []BundleChange `json:"changes"`
+}
+
+// BundleSetting is the state of a setting in a SupportBundle
+type BundleSetting struct {
+	// Path of the setting
+	Path string `json:"path"`
+
+	// Value of the setting, ***** for masked settings and redacted for Redacter values
+	Value string `json:"value"`
+
+	// Source that supplied the value, see Setting.Source
+	Source string `json:"source"`
+
+	// Revision of the setting, see Setting.Revision
+	Revision uint64 `json:"revision"`
+
+	// Default is set when the value is the default of the setting
+	Default bool `json:"default,omitempty"`
+}
+
+// BundleProvider is the health of a Provider in a SupportBundle, see ProviderStatus
+type BundleProvider struct {
+	// Name the Provider was added with
+	Name string `json:"name"`
+
+	// Path of the Set the Provider is attached to
+	Path string `json:"path,omitempty"`
+
+	// LastAttempt is the time of the last load, successful or not
+	LastAttempt time.Time `json:"lastAttempt"`
+
+	// LastSync is the time of the last successful load
+	LastSync time.Time `json:"lastSync"`
+
+	// Error of the last load, empty when it was successful
+	Error string `json:"error,omitempty"`
+
+	// Staleness is the duration since the last successful load
+	Staleness string `json:"staleness"`
+
+	// Stale is set when the last load supplied cached values
+	Stale bool `json:"stale,omitempty"`
+
+	// Breaker is the state of the Breaker wrapping the Provider
+	Breaker string `json:"breaker,omitempty"`
+}
+
+// BundleChange is a change of a setting in a SupportBundle
+type BundleChange struct {
+	// Path of the setting
+	Path string `json:"path"`
+
+	HistoryEntry
+}
+
+// SupportBundle returns a snapshot of the Set for support tickets: the effective value, source and revision of every setting with masked values redacted as ***** and Redacter values redacted, the health of the providers and the most recent changes when the Set keeps history (see Set.KeepHistory). The snapshot is timestamped with the Clock of the Set.
+func (s *Set) SupportBundle() SupportBundle {
+	bundle := SupportBundle{
+		Time:      s.clock().Now(),
+		Path:      s.path,
+		Revision:  s.Revision(),
+		Settings:  []BundleSetting{},
+		Providers: []BundleProvider{},
+		Changes:   []BundleChange{},
+	}
+
+	for _, setting := range s.sorted() {
+		bundle.Settings = append(bundle.Settings, BundleSetting{
+			Path:     setting.Path,
+			Value:    setting.display(setting.format()),
+			Source:   setting.Source(),
+			Revision: setting.Revision(),
+			Default:  setting.IsDefault(),
+		})
+
+		// history retains masked values as *****
+		for _, entry := range setting.History() {
+			bundle.Changes = append(bundle.Changes, BundleChange{Path: setting.Path, HistoryEntry: entry})
+		}
+	}
+
+	sort.SliceStable(bundle.Changes, func(i, j int) bool { return bundle.Changes[i].Revision > bundle.Changes[j].Revision })
+	if len(bundle.Changes) > maxBundleChanges {
+		bundle.Changes = bundle.Changes[:maxBundleChanges]
+	}
+
+	for _, status := range s.Health() {
+		provider := BundleProvider{
+			Name:        status.Name,
+			Path:        status.Path,
+			LastAttempt: status.LastAttempt,
+			LastSync:    status.LastSync,
+			Staleness:   status.Staleness.String(),
+			Stale:       status.Stale,
+			Breaker:     status.Breaker,
+		}
+		if status.LastError != nil {
+			provider.Error = status.LastError.Error()
+		}
+
+		bundle.Providers = append(bundle.Providers, provider)
+	}
+
+	return bundle
+}
+
+// WriteSupportBundle writes the SupportBundle of the Set as indented JSON, see Set.SupportBundle
+func (s *Set) WriteSupportBundle(w io.Writer) error {
+	data, err := json.MarshalIndent(s.SupportBundle(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode support bundle: %w", err)
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// SupportBundleServer serves the SupportBundle of a Set over HTTP as a JSON download named after the time it was taken, see Set.SupportBundle
+type SupportBundleServer struct {
+	// Set being exposed
+	Set *Set
+
+	// Token, when not empty, is required from clients as a bearer token
+	Token string
+}
+
+// ServeHTTP writes the SupportBundle of the Set
+func (bs *SupportBundleServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkBearer(w, r, bs.Token) {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bundle := bs.Set.SupportBundle()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="config-support-%s.json"`, bundle.Time.UTC().Format("20060102T150405Z")))
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(bundle)
+}
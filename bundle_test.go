@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_SupportBundle(t *testing.T) {
+	var (
+		port     = 80
+		password = "secret"
+		level    = "info"
+	)
+
+	set := &Set{}
+	set.KeepHistory(5)
+	set.UseClock(NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	set.Subset("HTTP").Setting("Port", &port, "")
+	set.Subset("HTTP").Setting("Password", &password, "").Mask = true
+	set.Setting("Level", &level, "")
+
+	set.AddProvider("remote", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return nil, errors.New("unreachable")
+	}))
+	_ = set.Reload(context.Background())
+
+	_, _ = set.Update("HTTP.Port", "8080")
+	_, _ = set.Update("HTTP.Password", "hunter2")
+	_, _ = set.Update("HTTP.Port", "9090")
+
+	bundle := set.SupportBundle()
+
+	if !bundle.Time.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) || bundle.Revision != set.Revision() {
+		t.Errorf("Failed to stamp bundle: got %v at %d", bundle.Time, bundle.Revision)
+	}
+
+	if len(bundle.Settings) != 3 || bundle.Settings[0].Path != "HTTP.Password" || bundle.Settings[0].Value != "*****" {
+		t.Fatalf("Failed to redact settings: got %+v", bundle.Settings)
+	}
+
+	if s := bundle.Settings[1]; s.Value != "9090" || s.Source != SourceRuntime || s.Revision == 0 || s.Default {
+		t.Errorf("Failed to capture setting: got %+v", s)
+	}
+
+	if s := bundle.Settings[2]; s.Path != "Level" || !s.Default {
+		t.Errorf("Failed to mark default: got %+v", s)
+	}
+
+	if len(bundle.Providers) != 1 || bundle.Providers[0].Name != "remote" || !strings.Contains(bundle.Providers[0].Error, "unreachable") {
+		t.Errorf("Failed to capture provider health: got %+v", bundle.Providers)
+	}
+
+	if len(bundle.Changes) != 3 || bundle.Changes[0].Path != "HTTP.Port" || bundle.Changes[0].Value != "9090" || bundle.Changes[1].Value != "*****" {
+		t.Errorf("Failed to capture recent changes newest first: got %+v", bundle.Changes)
+	}
+
+	buf := &strings.Builder{}
+	if err := set.WriteSupportBundle(buf); err != nil {
+		t.Fatalf("Failed to write bundle: %v", err)
+	}
+	if strings.Contains(buf.String(), "hunter2") || strings.Contains(buf.String(), "secret") {
+		t.Errorf("Failed to redact masked values:\n%s", buf.String())
+	}
+}
+
+func TestSet_SupportBundleRedacted(t *testing.T) {
+	buf := &strings.Builder{}
+	if err := redactedSet(t).WriteSupportBundle(buf); err != nil {
+		t.Fatalf("Failed to write bundle: %v", err)
+	}
+
+	assertRedacted(t, "support bundle", buf.String())
+}
+
+func TestSupportBundleServer(t *testing.T) {
+	port := 80
+
+	set := &Set{}
+	set.Setting("Port", &port, "")
+	set.UseClock(NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+
+	server := httptest.NewServer(&SupportBundleServer{Set: set, Token: "token"})
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to request bundle: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Failed to require token: expected %d; got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to request bundle: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if disposition := resp.Header.Get("Content-Disposition"); !strings.Contains(disposition, "config-support-20240301T120000Z.json") {
+		t.Errorf("Failed to name download: got %q", disposition)
+	}
+
+	var bundle SupportBundle
+	if err := json.NewDecoder(resp.Body).Decode(&bundle); err != nil || len(bundle.Settings) != 1 || bundle.Settings[0].Value != "80" {
+		t.Errorf("Failed to serve bundle: got %v with %+v", err, bundle)
+	}
+}
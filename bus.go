@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+// Publisher publishes a message to a topic of a message bus. The key is the path of the setting, so buses partitioning by key (i.e. Kafka) keep the changes of a setting in order.
+//
+// Clients of Kafka or NATS are adapted with a PublisherFunc, i.e. for NATS:
+//
+//	config.PublisherFunc(func(ctx context.Context, topic string, key, data []byte) error {
+//		return nc.Publish(topic, data)
+//	})
+//
+// or for Kafka with a kafka-go Writer:
+//
+//	config.PublisherFunc(func(ctx context.Context, topic string, key, data []byte) error {
+//		return w.WriteMessages(ctx, kafka.Message{Topic: topic, Key: key, Value: data})
+//	})
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key, data []byte) error
+}
+
+// PublisherFunc defines a function that publishes a message to a topic of a message bus
+type PublisherFunc func(ctx context.Context, topic string, key, data []byte) error
+
+// Publish implements Publisher.Publish
+func (f PublisherFunc) Publish(ctx context.Context, topic string, key, data []byte) error {
+	return f(ctx, topic, key, data)
+}
+
+// Bus is a Notifier publishing every change as a JSON ChangeEvent to a topic, enabling config change auditing pipelines without every service writing its own producer. Changes are published in order from a background goroutine so a slow bus never blocks Setting.Set.
+type Bus struct {
+	// Publisher of the message bus
+	Publisher Publisher
+
+	// Topic, or NATS subject, changes are published to
+	Topic string
+
+	// Format of the messages, defaults to JSONFormat
+	Format EventFormat
+
+	// OnError, when not nil, is called for every change that failed to publish
+	OnError func(event ChangeEvent, err error)
+
+	queue changeQueue
+}
+
+// Notify implements Notifier.Notify
+func (b *Bus) Notify(setting *Setting) {
+	b.queue.push(newChangeEvent(setting), b.publish)
+}
+
+// Close stops accepting changes and waits for the queued changes to be published
+func (b *Bus) Close() error {
+	b.queue.close(b.publish)
+
+	return nil
+}
+
+// publish the event to the topic
+func (b *Bus) publish(event ChangeEvent) {
+	data, _, err := b.Format.format(event)
+	if err == nil {
+		err = b.Publisher.Publish(context.Background(), b.Topic, []byte(event.Path), data)
+	}
+
+	if err != nil && b.OnError != nil {
+		b.OnError(event, fmt.Errorf("unable to publish to %q: %w", b.Topic, err))
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestBus_Notify(t *testing.T) {
+	var (
+		published []ChangeEvent
+		failed    []string
+	)
+
+	bus := &Bus{
+		Topic: "config.changes",
+		Publisher: PublisherFunc(func(ctx context.Context, topic string, key, data []byte) error {
+			if topic != "config.changes" {
+				t.Errorf("Failed to publish to topic: expected %q; got %q", "config.changes", topic)
+			}
+
+			var event ChangeEvent
+			_ = json.Unmarshal(data, &event)
+			if string(key) != event.Path {
+				t.Errorf("Failed to key message: expected %q; got %q", event.Path, key)
+			}
+
+			if event.Value == "0" {
+				return errors.New("unavailable")
+			}
+
+			published = append(published, event)
+			return nil
+		}),
+		OnError: func(event ChangeEvent, err error) {
+			failed = append(failed, event.Value)
+		},
+	}
+
+	port := 80
+	set := &Set{}
+	set.Setting("Port", &port, "")
+	set.Notify(bus)
+
+	_, _ = set.Update("Port", "8080")
+	_, _ = set.Update("Port", "0")
+	_, _ = set.Update("Port", "9090")
+	_ = bus.Close()
+
+	if len(published) != 2 || published[0].Value != "8080" || published[1].Value != "9090" {
+		t.Errorf("Failed to publish changes in order: got %+v", published)
+	}
+
+	if len(failed) != 1 || failed[0] != "0" {
+		t.Errorf("Failed to report publish error: got %v", failed)
+	}
+}
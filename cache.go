@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// StaleError is returned by a Provider that could not reach its source and supplied previously cached values instead. Set.Reload applies the values and marks the Provider as stale in Set.Health rather than failing.
+type StaleError struct {
+	// Err is the error from the source
+	Err error
+
+	// Cached is the time the values were cached
+	Cached time.Time
+}
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("using values cached at %s: %v", e.Cached.Format(time.RFC3339), e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *StaleError) Unwrap() error {
+	return e.Err
+}
+
+// Cache wraps the Provider persisting the last successfully loaded values to the file at path. When the Provider fails, the cached values are returned with a *StaleError so services can start without their remote source. The file is written with 0600 permissions as it may contain secrets, a failure to write it is logged (see SetLogger) rather than discarding the loaded values.
+func Cache(p Provider, path string) Provider {
+	return &cacheProvider{provider: p, path: path}
+}
+
+// cacheProvider is the Provider returned by Cache
+type cacheProvider struct {
+	provider Provider
+	path     string
+}
+
+// Load implements Provider.Load
+func (c *cacheProvider) Load(ctx context.Context) (map[string]string, error) {
+	values, err := c.provider.Load(ctx)
+	if err == nil {
+		// the fetched values are still good without a cache to fall back on
+		if err := writeCache(c.path, values); err != nil {
+			logger().Warn("unable to cache provider values", "path", c.path, "error", err)
+		}
+
+		return values, nil
+	}
+
+	info, statErr := os.Stat(c.path)
+	if statErr != nil {
+		return nil, err
+	}
+
+	data, readErr := os.ReadFile(c.path)
+	if readErr != nil {
+		return nil, err
+	}
+
+	var cached map[string]string
+	if json.Unmarshal(data, &cached) != nil {
+		return nil, err
+	}
+
+	return cached, &StaleError{Err: err, Cached: info.ModTime()}
+}
+
+// Unwrap returns the Provider being cached
+func (c *cacheProvider) Unwrap() Provider {
+	return c.provider
+}
+
+// writeCache atomically replaces the cache file at path with the values
+func writeCache(path string, values map[string]string) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("unable to encode cache: %w", err)
+	}
+
+	if err := writeAtomic(path, data); err != nil {
+		return fmt.Errorf("unable to write cache: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	var (
+		port = 80
+		fail = false
+	)
+
+	set := &Set{}
+	set.Setting("Port", &port, "")
+
+	remote := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		return map[string]string{"Port": "8080"}, nil
+	})
+
+	set.AddProvider("remote", Cache(remote, filepath.Join(t.TempDir(), "remote.json")))
+
+	if err := set.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+
+	// simulate a restart without the remote source
+	fail = true
+	port = 80
+
+	if err := set.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload from cache: %v", err)
+	}
+
+	if port != 8080 {
+		t.Errorf("Failed to load cached value: expected %d; got %d", 8080, port)
+	}
+
+	health := set.Health()
+	if !health[0].Stale {
+		t.Errorf("Failed to mark provider as stale: got %+v", health[0])
+	}
+
+	var staleErr *StaleError
+	if !errors.As(health[0].LastError, &staleErr) {
+		t.Errorf("Failed to report stale error: got %v", health[0].LastError)
+	}
+}
+
+func TestCache_WriteFailure(t *testing.T) {
+	recorder := &recordingLogger{}
+	SetLogger(recorder)
+	defer SetLogger(nil)
+
+	remote := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"Port": "8080"}, nil
+	})
+
+	// the directory of the cache file does not exist
+	values, err := Cache(remote, filepath.Join(t.TempDir(), "missing", "remote.json")).Load(context.Background())
+	if err != nil || values["Port"] != "8080" {
+		t.Errorf("Failed to return fetched values: got %v (%v)", values, err)
+	}
+
+	if len(recorder.messages) != 1 || recorder.messages[0] != "warn: unable to cache provider values" {
+		t.Errorf("Failed to log cache failure: got %v", recorder.messages)
+	}
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRolledBack is returned by Rollout.Run when the Health of the canary failed and the rollout was rolled back
+var ErrRolledBack = errors.New("rollout rolled back")
+
+// canaryPrefix is the subset of the provider values holding the canary rollouts, Canary.<path>.Value and Canary.<path>.Percent
+const canaryPrefix = "canary."
+
+// Canary wraps the Provider of every instance of a fleet so a Rollout can apply a new value to a percentage of the instances. The rollout of a setting is described by the values Canary.<path>.Value and Canary.<path>.Percent of the Provider, and an instance whose instanceID falls within the Percent loads the Value for the path in place of the value of the Provider. Instances are selected by a hash of the instanceID and the path, so every rollout picks a different cohort and a cohort only grows as the Percent is raised.
+func Canary(p Provider, instanceID string) Provider {
+	return &canaryProvider{provider: p, instanceID: instanceID}
+}
+
+type canaryProvider struct {
+	provider   Provider
+	instanceID string
+
+	mu  sync.Mutex
+	raw map[string]string
+}
+
+// Load implements Provider.Load
+func (c *canaryProvider) Load(ctx context.Context) (map[string]string, error) {
+	values, err := c.provider.Load(ctx)
+	if values == nil {
+		return values, err
+	}
+
+	c.mu.Lock()
+	c.raw = make(map[string]string, len(values))
+	for k, v := range values {
+		c.raw[k] = v
+	}
+	c.mu.Unlock()
+
+	rollouts := map[string]map[string]string{}
+	for k, v := range values {
+		lower := strings.ToLower(k)
+		if !strings.HasPrefix(lower, canaryPrefix) {
+			continue
+		}
+
+		path, field := splitLast(k[len(canaryPrefix):])
+		if rollouts[strings.ToLower(path)] == nil {
+			rollouts[strings.ToLower(path)] = map[string]string{}
+		}
+		rollouts[strings.ToLower(path)][strings.ToLower(field)] = v
+	}
+
+	canaried := make(map[string]string, len(values))
+	for k, v := range values {
+		canaried[k] = v
+	}
+
+	for k := range values {
+		rollout := rollouts[strings.ToLower(k)]
+		if rollout == nil {
+			continue
+		}
+
+		value, found := rollout["value"]
+		percent, _ := strconv.Atoi(rollout["percent"])
+		if found && inCohort(c.instanceID, k, percent) {
+			canaried[k] = value
+		}
+	}
+
+	return canaried, err
+}
+
+// Unwrap returns the wrapped Provider
+func (c *canaryProvider) Unwrap() Provider {
+	return c.provider
+}
+
+// remember the value written to the backend, so the next write of the path expects it
+func (c *canaryProvider) remember(path, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.raw == nil {
+		c.raw = map[string]string{}
+	}
+	c.raw[path] = value
+}
+
+// previous returns the value of the backend for the path as last loaded or written
+func (c *canaryProvider) previous(path string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, found := c.raw[path]
+	return v, found
+}
+
+// inCohort returns if the instance is within the percent of the fleet for the rollout of the path
+func inCohort(instanceID, path string, percent int) bool {
+	h := fnv.New32a()
+	h.Write([]byte(instanceID))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.ToLower(path)))
+
+	return int(h.Sum32()%100) < percent
+}
+
+// splitLast splits the path at its last dot
+func splitLast(path string) (string, string) {
+	i := strings.LastIndexByte(path, '.')
+	if i < 0 {
+		return "", path
+	}
+
+	return path[:i], path[i+1:]
+}
+
+// Rollout is a canary rollout of a new value of a setting across the instances of a fleet loading a Provider wrapped with Canary. The rollout is coordinated by writing Canary.<path>.Percent through the Writer of the Provider, so a single instance (or a deploy tool) runs it while every instance picks up the changes when it reloads.
+type Rollout struct {
+	// Set the Provider is attached to
+	Set *Set
+
+	// Provider name the rollout is written through, it must implement Writer or wrap a provider that does, and wrap a Canary
+	Provider string
+
+	// Path of the setting relative to the Set the Provider is attached to
+	Path string
+
+	// Value being rolled out
+	Value string
+
+	// Steps are the percentages of the fleet the Value is applied to in turn, defaults to 10, 50 and 100
+	Steps []int
+
+	// Interval each step runs before the Health is checked, defaults to 5 minutes
+	Interval time.Duration
+
+	// Health of the fleet with the Value applied to the percent of the instances, an error rolls the rollout back. A nil Health promotes every step.
+	Health func(ctx context.Context, percent int) error
+
+	// Clock the Interval is waited on, defaults to the Clock of the Set
+	Clock Clock
+}
+
+// Run rolls the Value out step by step, checking the Health after every Interval. Once the last step is healthy the Value is promoted to the value of the Provider for every instance, and the canary is ended. When the Health fails, or the ctx is done, the canary is ended leaving the previous value, returning an error wrapping ErrRolledBack or the ctx error.
+func (r *Rollout) Run(ctx context.Context) error {
+	setting := r.Set.lookup(r.Path)
+	if setting == nil {
+		return &SettingError{Path: r.Path, Err: ErrUnknownSetting}
+	}
+	if err := setting.check(r.Value); err != nil {
+		return &SettingError{Path: r.Path, Err: err}
+	}
+
+	steps := r.Steps
+	if len(steps) == 0 {
+		steps = []int{10, 50, 100}
+	}
+
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	clock := r.Clock
+	if clock == nil {
+		clock = r.Set.clock()
+	}
+
+	key := "Canary." + r.Path
+
+	if err := r.write(ctx, key+".Value", r.Value); err != nil {
+		return err
+	}
+
+	for _, percent := range steps {
+		if err := r.write(ctx, key+".Percent", strconv.Itoa(percent)); err != nil {
+			r.rollback(key)
+			return err
+		}
+
+		timer := clock.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			r.rollback(key)
+			return ctx.Err()
+		case <-timer.C():
+		}
+
+		if r.Health == nil {
+			continue
+		}
+
+		if err := r.Health(ctx, percent); err != nil {
+			logger().Warn("rolling back canary", "path", r.Path, "percent", percent, "error", err)
+			r.rollback(key)
+			return fmt.Errorf("%w at %d%%: %v", ErrRolledBack, percent, err)
+		}
+	}
+
+	if err := r.write(ctx, r.Path, r.Value); err != nil {
+		r.rollback(key)
+		return err
+	}
+
+	return r.write(ctx, key+".Percent", "0")
+}
+
+// rollback ends the canary, even when the ctx of the rollout is done
+func (r *Rollout) rollback(key string) {
+	if err := r.write(context.Background(), key+".Percent", "0"); err != nil {
+		logger().Error("unable to roll back canary", "path", r.Path, "error", err)
+	}
+}
+
+// write the value of the path relative to the Set through the Writer of the Provider
+func (r *Rollout) write(ctx context.Context, path, value string) error {
+	root := r.Set.Root()
+
+	root.mu.Lock()
+	var rp *registeredProvider
+	for _, p := range root.providers {
+		if strings.EqualFold(p.name, r.Provider) {
+			rp = p
+		}
+	}
+	root.mu.Unlock()
+
+	if rp == nil {
+		return fmt.Errorf("provider %q does not exist", r.Provider)
+	}
+
+	var writer Writer
+	var canary *canaryProvider
+	for p := rp.provider; p != nil; p = unwrapProvider(p) {
+		if w, ok := p.(Writer); ok && writer == nil {
+			writer = w
+		}
+		if c, ok := p.(*canaryProvider); ok {
+			canary = c
+		}
+	}
+	if writer == nil || canary == nil {
+		return fmt.Errorf("provider %q must be writable and wrapped with Canary", r.Provider)
+	}
+
+	req := WriteRequest{Path: path, Value: value}
+	req.Previous, req.Exists = canary.previous(path)
+
+	if err := writer.Write(ctx, req); err != nil {
+		return &ProviderError{Name: rp.name, Err: fmt.Errorf("unable to write %q: %w", path, err)}
+	}
+	canary.remember(path, value)
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCanary(t *testing.T) {
+	backend := &memoryBackend{values: map[string]string{
+		"Level":                  "info",
+		"Canary.Level.Value":     "debug",
+		"Canary.Level.Percent":   "50",
+		"Canary.Other.Percent":   "100",
+		"Canary.Missing.Value":   "x",
+		"Canary.Missing.Percent": "100",
+	}}
+
+	canaried := 0
+	for i := 0; i < 100; i++ {
+		values, err := Canary(backend, fmt.Sprintf("instance-%d", i)).Load(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to load: %v", err)
+		}
+		if values["Level"] == "debug" {
+			canaried++
+		}
+		if _, found := values["Missing"]; found {
+			t.Errorf("Failed to ignore canary of missing value: got %v", values)
+		}
+	}
+
+	if canaried < 30 || canaried > 70 {
+		t.Errorf("Failed to canary half of the fleet: got %d of 100", canaried)
+	}
+
+	// the cohort only grows with the percent
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("instance-%d", i)
+		if inCohort(id, "Level", 10) && !inCohort(id, "Level", 50) {
+			t.Errorf("Failed to keep %s in growing cohort", id)
+		}
+	}
+}
+
+// fleet creates instances loading the backend through Canary, the first one running the rollouts
+func fleet(t *testing.T, backend Provider, size int) ([]*Set, []*string) {
+	t.Helper()
+
+	sets := make([]*Set, size)
+	levels := make([]*string, size)
+	for i := range sets {
+		level := "info"
+		sets[i], levels[i] = &Set{}, &level
+		sets[i].Setting("Level", levels[i], "")
+		sets[i].AddProvider("backend", Canary(backend, fmt.Sprintf("instance-%d", i)))
+
+		if err := sets[i].Reload(context.Background()); err != nil {
+			t.Fatalf("Failed to load instance %d: %v", i, err)
+		}
+	}
+
+	return sets, levels
+}
+
+func TestRollout_Run(t *testing.T) {
+	backend := &memoryBackend{values: map[string]string{"Level": "info"}}
+	sets, levels := fleet(t, backend, 50)
+	clock := NewFakeClock(time.Unix(0, 0))
+
+	var observed []int
+	rollout := &Rollout{
+		Set:      sets[0],
+		Provider: "backend",
+		Path:     "Level",
+		Value:    "debug",
+		Steps:    []int{10, 100},
+		Interval: time.Minute,
+		Clock:    clock,
+		Health: func(ctx context.Context, percent int) error {
+			canaried := 0
+			for i, set := range sets {
+				_ = set.Reload(ctx)
+				if *levels[i] == "debug" {
+					canaried++
+				}
+			}
+			observed = append(observed, canaried)
+			return nil
+		},
+	}
+
+	done := make(chan error)
+	go func() { done <- rollout.Run(context.Background()) }()
+
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Failed to run rollout: %v", err)
+	}
+
+	if len(observed) != 2 || observed[0] == 0 || observed[0] > 15 || observed[1] != 50 {
+		t.Errorf("Failed to roll out in steps: got %v", observed)
+	}
+
+	if backend.values["Level"] != "debug" || backend.values["Canary.Level.Percent"] != "0" {
+		t.Errorf("Failed to promote: got %v", backend.values)
+	}
+
+	for i, set := range sets {
+		_ = set.Reload(context.Background())
+		if *levels[i] != "debug" {
+			t.Errorf("Failed to promote instance %d: got %q", i, *levels[i])
+		}
+	}
+}
+
+func TestRollout_Run_rollback(t *testing.T) {
+	backend := &memoryBackend{values: map[string]string{"Level": "info"}}
+	sets, levels := fleet(t, backend, 20)
+	clock := NewFakeClock(time.Unix(0, 0))
+
+	rollout := &Rollout{
+		Set:      sets[0],
+		Provider: "backend",
+		Path:     "Level",
+		Value:    "debug",
+		Clock:    clock,
+		Health: func(ctx context.Context, percent int) error {
+			if percent >= 50 {
+				return errors.New("error rate too high")
+			}
+			return nil
+		},
+	}
+
+	done := make(chan error)
+	go func() { done <- rollout.Run(context.Background()) }()
+
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Minute)
+	}
+
+	if err := <-done; !errors.Is(err, ErrRolledBack) {
+		t.Fatalf("Failed to roll back: expected %v; got %v", ErrRolledBack, err)
+	}
+
+	if backend.values["Level"] != "info" || backend.values["Canary.Level.Percent"] != "0" {
+		t.Errorf("Failed to end canary: got %v", backend.values)
+	}
+
+	for i, set := range sets {
+		_ = set.Reload(context.Background())
+		if *levels[i] != "info" {
+			t.Errorf("Failed to roll back instance %d: got %q", i, *levels[i])
+		}
+	}
+
+	// a value the setting can not hold is never rolled out
+	var port int
+	sets[0].Setting("Port", &port, "")
+	rollout.Path, rollout.Value = "Port", "eighty"
+
+	if err := rollout.Run(context.Background()); err == nil || backend.values["Canary.Port.Value"] != "" {
+		t.Errorf("Failed to reject invalid value: got %v with %v", err, backend.values)
+	}
+}
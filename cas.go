@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrRevisionMismatch is returned by Set.UpdateIfMatch when the setting changed since the expected revision
+var ErrRevisionMismatch = errors.New("revision mismatch")
+
+// errCompareFailed is the failed precondition of Setting.CompareAndSet
+var errCompareFailed = errors.New("compare failed")
+
+// CompareAndSet sets the Value like Setting.Set only when the current value equals expected, returning false without an error when it does not. The comparison and write are atomic with respect to other writes of the setting, so two operators editing the same value can't silently clobber each other.
+func (s *Setting) CompareAndSet(expected, v string) (bool, error) {
+	err := s.setIf(context.Background(), v, "", func() error {
+		if !s.Equals(expected) {
+			return errCompareFailed
+		}
+		return nil
+	})
+
+	if errors.Is(err, errCompareFailed) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// UpdateIfMatch updates an existing setting by name like Set.UpdateContext only when the Setting.Revision is still the expected revision, returning an error wrapping ErrRevisionMismatch otherwise. Admin surfaces (i.e. Gossip) return the Setting.Revision as the ETag of a write and pass its If-Match header as the revision, responding 412 Precondition Failed on a mismatch.
+func (s *Set) UpdateIfMatch(ctx context.Context, name, value string, revision uint64) (bool, error) {
+	setting := s.lookup(name)
+	if setting == nil {
+		return false, nil
+	}
+
+	if err := s.authorize(ctx, setting); err != nil {
+		return true, err
+	}
+
+	if err := s.guard(ctx, setting, value); err != nil {
+		return true, err
+	}
+
+	precondition := func() error {
+		if current := setting.Revision(); current != revision {
+			return fmt.Errorf("%w: expected %d; got %d", ErrRevisionMismatch, revision, current)
+		}
+		return nil
+	}
+
+	// the change is only parked for approval against the expected revision
+	if setting.requiresApproval() && ctx.Value(trustedContextKey{}) == nil {
+		if err := precondition(); err != nil {
+			return true, err
+		}
+	}
+	if parked, err := s.park(ctx, setting, value); parked {
+		return true, err
+	}
+
+	if err := setting.setIf(ctx, value, "", precondition); err != nil {
+		return true, err
+	}
+
+	// runtime changes are saved when the Set is persisted
+	s.persist(setting)
+
+	return true, nil
+}
+
+// ifMatchContextKey holds the revision expected by the If-Match header of an admin write
+type ifMatchContextKey struct{}
+
+// withIfMatch returns a child context of ctx carrying the revision expected by the If-Match header of the request, ctx is returned as is without the header or for If-Match: *
+func withIfMatch(ctx context.Context, r *http.Request) (context.Context, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return ctx, nil
+	}
+
+	revision, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), 10, 64)
+	if err != nil {
+		return ctx, fmt.Errorf("invalid If-Match %q: expected a revision", header)
+	}
+
+	return context.WithValue(ctx, ifMatchContextKey{}, revision), nil
+}
+
+// ifMatch returns the revision expected by the If-Match header of the write in the ctx, see withIfMatch
+func ifMatch(ctx context.Context) (uint64, bool) {
+	revision, ok := ctx.Value(ifMatchContextKey{}).(uint64)
+	return revision, ok
+}
+
+// etag returns the revision as an ETag header value
+func etag(revision uint64) string {
+	return strconv.Quote(strconv.FormatUint(revision, 10))
+}
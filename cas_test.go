@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSetting_CompareAndSet(t *testing.T) {
+	s := &Set{}
+	port := s.Setting("Port", 80, "")
+
+	if ok, err := port.CompareAndSet("8080", "9090"); ok || err != nil {
+		t.Errorf("Failed to reject stale compare: got %v with %v", ok, err)
+	}
+
+	if ok, err := port.CompareAndSet("80", "8080"); !ok || err != nil || port.String() != "8080" {
+		t.Errorf("Failed to compare and set: got %v with %v and value %q", ok, err, port.String())
+	}
+
+	if ok, err := port.CompareAndSet("8080", "http"); ok || err == nil {
+		t.Errorf("Failed to reject invalid value: got %v with %v", ok, err)
+	}
+
+	// only one of the concurrent writers expecting the same value wins
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		wins int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if ok, _ := port.CompareAndSet("8080", "9000"); ok {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if wins != 1 {
+		t.Errorf("Failed to serialize compare and set: expected %d winner; got %d", 1, wins)
+	}
+}
+
+func TestSet_UpdateIfMatch(t *testing.T) {
+	s := &Set{}
+	port := s.Setting("Port", 80, "")
+	_ = port.Set("8080")
+
+	revision := port.Revision()
+	if found, err := s.UpdateIfMatch(context.Background(), "Port", "9090", revision); !found || err != nil {
+		t.Fatalf("Failed to update at revision %d: %v", revision, err)
+	}
+
+	if _, err := s.UpdateIfMatch(context.Background(), "Port", "7070", revision); !errors.Is(err, ErrRevisionMismatch) || port.String() != "9090" {
+		t.Errorf("Failed to reject stale revision: got %v with value %q", err, port.String())
+	}
+
+	if found, _ := s.UpdateIfMatch(context.Background(), "Missing", "1", 0); found {
+		t.Errorf("Failed to report unknown setting")
+	}
+}
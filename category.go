@@ -0,0 +1,33 @@
+package config
+
+import "sort"
+
+// Category is a group of settings sharing the same Setting.Category
+type Category struct {
+	Name     string
+	Settings []*Setting
+}
+
+// Categories returns the settings of the Set grouped by Setting.Category, sorted by name with uncategorized settings last. Settings within a category are sorted by path.
+func (s *Set) Categories() []Category {
+	groups := map[string][]*Setting{}
+	s.Range(func(_ string, setting *Setting) bool {
+		groups[setting.Category] = append(groups[setting.Category], setting)
+		return true
+	})
+
+	categories := make([]Category, 0, len(groups))
+	for name, settings := range groups {
+		sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+		categories = append(categories, Category{Name: name, Settings: settings})
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Name == "" || categories[j].Name == "" {
+			return categories[j].Name == ""
+		}
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories
+}
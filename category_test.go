@@ -0,0 +1,40 @@
+package config
+
+import "testing"
+
+func TestSet_Categories(t *testing.T) {
+	cfg := struct {
+		Port     int    `category:"Networking"`
+		Host     string `category:"Networking"`
+		LogLevel string `category:"Observability"`
+		Name     string
+	}{}
+
+	categories := (&Set{}).Bind(&cfg).Categories()
+
+	if len(categories) != 3 {
+		t.Fatalf("Failed to group categories: got %+v", categories)
+	}
+
+	expected := []struct {
+		name  string
+		paths []string
+	}{
+		{"Networking", []string{"Host", "Port"}},
+		{"Observability", []string{"LogLevel"}},
+		{"", []string{"Name"}},
+	}
+
+	for i, e := range expected {
+		if categories[i].Name != e.name || len(categories[i].Settings) != len(e.paths) {
+			t.Errorf("Failed to group category %q: got %+v", e.name, categories[i])
+			continue
+		}
+
+		for j, path := range e.paths {
+			if categories[i].Settings[j].Path != path {
+				t.Errorf("Failed to sort category %q: expected %q; got %q", e.name, path, categories[i].Settings[j].Path)
+			}
+		}
+	}
+}
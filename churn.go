@@ -0,0 +1,131 @@
+package config
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ChurnAlert describes a setting changing more often than the Threshold of a ChurnDetector
+type ChurnAlert struct {
+	// Path of the setting
+	Path string
+
+	// Changes of the setting within the Window
+	Changes int
+
+	// Window the changes were counted in
+	Window time.Duration
+
+	// Sources that supplied the changes (see Setting.Source), more than one usually means controllers are fighting over the value
+	Sources []string
+}
+
+// ChurnDetector flags settings changing more often than the Threshold within the Window (i.e. 10 per hour), catching misbehaving controllers fighting over a value. A setting is flagged at most once per Window while it keeps churning.
+type ChurnDetector struct {
+	// Set being watched, including its subsets
+	Set *Set
+
+	// Threshold of changes within the Window, a setting is flagged on the first change beyond it
+	Threshold int
+
+	// Window the changes are counted in
+	Window time.Duration
+
+	// OnChurn is called with every setting flagged
+	OnChurn func(alert ChurnAlert)
+
+	alerts  uint64
+	mu      sync.Mutex
+	changes map[string]*churn
+}
+
+// churn is the recent changes of a single setting
+type churn struct {
+	revision uint64
+	times    []time.Time
+	sources  []string
+	flagged  time.Time
+}
+
+// Watch starts counting the changes of the settings of the Set until the returned handle is closed
+func (d *ChurnDetector) Watch() *NotifyHandle {
+	return d.Set.Notify(NotifyFunc(func(setting *Setting) {
+		d.observe(setting, d.Set.clock().Now())
+	}))
+}
+
+// Alerts returns the number of times a setting was flagged, for export as a metric
+func (d *ChurnDetector) Alerts() uint64 {
+	return atomic.LoadUint64(&d.alerts)
+}
+
+// observe the setting changing at now, flagging it when it exceeds the Threshold
+func (d *ChurnDetector) observe(setting *Setting, now time.Time) {
+	d.mu.Lock()
+
+	if d.changes == nil {
+		d.changes = map[string]*churn{}
+	}
+
+	c := d.changes[setting.Path]
+	if c == nil {
+		c = &churn{}
+		d.changes[setting.Path] = c
+	}
+
+	// registration and unchanged writes (see Setting.NotifyUnchanged) don't advance the revision
+	revision := setting.Revision()
+	if revision == 0 || revision == c.revision {
+		d.mu.Unlock()
+		return
+	}
+	c.revision = revision
+
+	// forget the changes that left the window
+	cutoff := now.Add(-d.Window)
+	keep := 0
+	for keep < len(c.times) && !c.times[keep].After(cutoff) {
+		keep++
+	}
+	c.times = append(c.times[:0], c.times[keep:]...)
+	c.sources = append(c.sources[:0], c.sources[keep:]...)
+
+	c.times = append(c.times, now)
+	c.sources = append(c.sources, setting.Source())
+
+	if len(c.times) <= d.Threshold || (!c.flagged.IsZero() && now.Sub(c.flagged) < d.Window) {
+		d.mu.Unlock()
+		return
+	}
+	c.flagged = now
+
+	alert := ChurnAlert{
+		Path:    setting.Path,
+		Changes: len(c.times),
+		Window:  d.Window,
+		Sources: distinct(c.sources),
+	}
+	d.mu.Unlock()
+
+	atomic.AddUint64(&d.alerts, 1)
+	if d.OnChurn != nil {
+		d.OnChurn(alert)
+	}
+}
+
+// distinct returns the sorted unique values
+func distinct(values []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	sort.Strings(unique)
+
+	return unique
+}
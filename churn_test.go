@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestChurnDetector_Watch(t *testing.T) {
+	set := &Set{}
+	setting := set.Setting("Replicas", 1, "")
+
+	var alerts []ChurnAlert
+	d := &ChurnDetector{Set: set, Threshold: 3, Window: time.Hour, OnChurn: func(a ChurnAlert) { alerts = append(alerts, a) }}
+	handle := d.Watch()
+	defer handle.Close()
+
+	for i := 2; i <= 6; i++ {
+		source := SourceRuntime
+		if i%2 == 0 {
+			source = "controller"
+		}
+		if err := setting.setFrom(context.Background(), strconv.Itoa(i), source); err != nil {
+			t.Fatalf("Failed to set: %v", err)
+		}
+	}
+
+	// unchanged writes are not changes
+	_ = setting.Set("6")
+
+	if len(alerts) != 1 || d.Alerts() != 1 {
+		t.Fatalf("Failed to flag churn once: got %v", alerts)
+	}
+
+	expected := ChurnAlert{Path: "Replicas", Changes: 4, Window: time.Hour, Sources: []string{"controller", SourceRuntime}}
+	if !reflect.DeepEqual(alerts[0], expected) {
+		t.Errorf("Failed to describe churn: expected %+v; got %+v", expected, alerts[0])
+	}
+}
+
+func TestChurnDetector_Window(t *testing.T) {
+	set := &Set{}
+	setting := set.Setting("Replicas", 1, "")
+
+	d := &ChurnDetector{Set: set, Threshold: 1, Window: time.Minute}
+
+	start := time.Now()
+	for i, offset := range []time.Duration{0, 2 * time.Minute, 4 * time.Minute} {
+		_ = setting.Set(strconv.Itoa(i + 2))
+		d.observe(setting, start.Add(offset))
+	}
+
+	if d.Alerts() != 0 {
+		t.Errorf("Failed to forget changes outside the window: got %d alerts", d.Alerts())
+	}
+
+	_ = setting.Set("9")
+	d.observe(setting, start.Add(4*time.Minute+time.Second))
+
+	if d.Alerts() != 1 {
+		t.Errorf("Failed to flag churn: got %d alerts", d.Alerts())
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import "context"
+
+// Clear reverts the setting to its DefaultValue and records that it is unset, so Setting.Source is SourceDefault again. Unlike setting an empty string, which is a value of its own, this removes an override. Notifiers are called when the value changes.
+func (s *Setting) Clear() error {
+	return s.setIf(context.Background(), s.DefaultValue, SourceDefault, func() error {
+		// recorded even when the value already is the default
+		s.source.Store(SourceDefault)
+		return nil
+	})
+}
+
+// Clear reverts an existing setting by name to its default like Setting.Clear, removing the override saved by Set.Persist as well
+func (s *Set) Clear(name string) (bool, error) {
+	setting := s.lookup(name)
+	if setting == nil {
+		return false, nil
+	}
+
+	if err := setting.Clear(); err != nil {
+		return true, err
+	}
+
+	root := s.Root()
+	root.mu.Lock()
+	p := root.persister
+	root.mu.Unlock()
+
+	if p != nil {
+		p.remove(setting.Path)
+	}
+
+	return true, nil
+}
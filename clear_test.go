@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetting_Clear(t *testing.T) {
+	set := &Set{}
+	name := set.Setting("Name", "app", "")
+
+	notified := 0
+	name.Notify(NotifyFunc(func(*Setting) { notified++ }))
+
+	_ = name.Set("")
+	if name.String() != "" || name.Source() != SourceRuntime {
+		t.Fatalf("Failed to set empty string: got %q from %q", name.String(), name.Source())
+	}
+
+	if err := name.Clear(); err != nil {
+		t.Fatalf("Failed to clear: %v", err)
+	}
+
+	if name.String() != "app" || name.Source() != SourceDefault || notified != 2 {
+		t.Errorf("Failed to revert to default: got %q from %q with %d notifications", name.String(), name.Source(), notified)
+	}
+
+	// an override equal to the default is still unset
+	if err := set.updateAll(map[string]string{"Name": "app"}, "file.json"); err != nil {
+		t.Fatal(err)
+	}
+	_ = name.Set("app")
+	if err := name.Clear(); err != nil || name.Source() != SourceDefault {
+		t.Errorf("Failed to record unset state: got %q (%v)", name.Source(), err)
+	}
+}
+
+func TestSet_Clear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+
+	set := &Set{}
+	set.Setting("Port", 80, "")
+	set.Setting("Host", "localhost", "")
+
+	p, err := set.Persist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = set.UpdateContext(context.Background(), "Port", "8080")
+	_, _ = set.UpdateContext(context.Background(), "Host", "example.com")
+
+	if found, err := set.Clear("port"); !found || err != nil || set.Get("Port").String() != "80" {
+		t.Fatalf("Failed to clear setting: got %v (%v)", set.Get("Port"), err)
+	}
+
+	if found, _ := set.Clear("Missing"); found {
+		t.Errorf("Failed to ignore unknown setting")
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found := values["Port"]; found || values["Host"] != "example.com" {
+		t.Errorf("Failed to remove cleared override: got %v", values)
+	}
+}
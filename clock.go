@@ -0,0 +1,240 @@
+package config
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of the time dependent features (i.e. Poller, RetryPolicy, Breaker, Set.ReadThrough, Set.Persist and ChurnDetector), so tests can replace SystemClock with a FakeClock and advance time deterministically instead of sleeping
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// NewTimer creates a Timer sending the time on its channel after the duration, see time.NewTimer
+	NewTimer(d time.Duration) Timer
+
+	// AfterFunc calls f after the duration, see time.AfterFunc
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a single event created by a Clock, see time.Timer
+type Timer interface {
+	// C returns the channel the time is sent on, nil for timers created by Clock.AfterFunc
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing, returning false when it already fired or was stopped
+	Stop() bool
+
+	// Reset changes the Timer to fire after the duration, returning false when it already fired or was stopped
+	Reset(d time.Duration) bool
+}
+
+// SystemClock is the Clock of the time package, used unless another Clock is supplied
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return systemTimer{time.AfterFunc(d, f)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// clockOrSystem returns the clock, or SystemClock when it is nil
+func clockOrSystem(c Clock) Clock {
+	if c == nil {
+		return SystemClock
+	}
+
+	return c
+}
+
+// clockHolder wraps the Clock of a Set, as an atomic.Value requires a consistent type
+type clockHolder struct {
+	clock Clock
+}
+
+// UseClock sets the Clock of the Set tree used by Set.Poll, Set.ReadThrough, Set.Persist, Set.Health and ChurnDetector, typically a FakeClock in tests. A nil Clock restores SystemClock.
+func (s *Set) UseClock(c Clock) {
+	s.Root().clockValue.Store(clockHolder{clock: c})
+}
+
+// clock returns the Clock of the Set tree
+func (s *Set) clock() Clock {
+	holder, _ := s.Root().clockValue.Load().(clockHolder)
+	return clockOrSystem(holder.clock)
+}
+
+// FakeClock is a Clock whose time only moves when advanced, firing the timers that become due in order. It is intended for tests of time dependent behavior, with FakeClock.BlockUntil synchronizing with goroutines waiting on its timers.
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock returns a FakeClock at the time
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// Now implements Clock.Now
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer implements Clock.NewTimer
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return c.add(&fakeTimer{clock: c, ch: make(chan time.Time, 1)}, d)
+}
+
+// AfterFunc implements Clock.AfterFunc, f is called by FakeClock.Advance
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	return c.add(&fakeTimer{clock: c, fn: f}, d)
+}
+
+// add the timer to fire after the duration
+func (c *FakeClock) add(t *fakeTimer, d time.Duration) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t.when = c.now.Add(d)
+	t.active = true
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+
+	return t
+}
+
+// Advance moves the time forward by the duration, firing every timer that becomes due in the order they are due with the time of the clock at their deadline. Timers created or reset by a fired timer fire within the same Advance when they become due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].when.Before(c.timers[j].when) })
+
+		var due *fakeTimer
+		for _, t := range c.timers {
+			if t.active && !t.when.After(target) {
+				due = t
+				break
+			}
+		}
+
+		if due == nil {
+			c.now = target
+			c.prune()
+			c.mu.Unlock()
+			return
+		}
+
+		if due.when.After(c.now) {
+			c.now = due.when
+		}
+		due.active = false
+		now := c.now
+		c.prune()
+		c.mu.Unlock()
+
+		if due.fn != nil {
+			due.fn()
+		} else {
+			select {
+			case due.ch <- now:
+			default:
+			}
+		}
+	}
+}
+
+// BlockUntil waits until at least n timers are waiting to fire, so a test can advance the clock once the goroutine under test is waiting on it
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// fired and stopped timers are pruned, so every timer is waiting
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// prune drops the timers that are no longer active, the caller must hold mu
+func (c *FakeClock) prune() {
+	active := c.timers[:0]
+	for _, t := range c.timers {
+		if t.active {
+			active = append(active, t)
+		}
+	}
+	for i := len(active); i < len(c.timers); i++ {
+		c.timers[i] = nil
+	}
+	c.timers = active
+}
+
+// fakeTimer is a Timer of a FakeClock
+type fakeTimer struct {
+	clock  *FakeClock
+	ch     chan time.Time
+	fn     func()
+	when   time.Time
+	active bool
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.active
+	t.active = false
+	t.clock.prune()
+
+	return active
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := t.active
+	t.when = c.now.Add(d)
+	t.active = true
+
+	// fired and stopped timers are pruned
+	if !active {
+		c.timers = append(c.timers, t)
+	}
+	c.cond.Broadcast()
+
+	return active
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	var fired []string
+	clock.AfterFunc(2*time.Second, func() { fired = append(fired, "second") })
+	first := clock.AfterFunc(time.Second, func() {
+		fired = append(fired, "first")
+		clock.AfterFunc(500*time.Millisecond, func() { fired = append(fired, "nested") })
+	})
+	stopped := clock.AfterFunc(time.Second, func() { fired = append(fired, "stopped") })
+	timer := clock.NewTimer(3 * time.Second)
+
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("Failed to stop timer once")
+	}
+
+	clock.Advance(2 * time.Second)
+	if len(fired) != 3 || fired[0] != "first" || fired[1] != "nested" || fired[2] != "second" {
+		t.Errorf("Failed to fire due timers in order: got %v", fired)
+	}
+	if now := clock.Now(); !now.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("Failed to advance: expected %s; got %s", start.Add(2*time.Second), now)
+	}
+
+	select {
+	case <-timer.C():
+		t.Error("Failed to hold timer that is not due")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	if at := <-timer.C(); !at.Equal(start.Add(3 * time.Second)) {
+		t.Errorf("Failed to send deadline: got %s", at)
+	}
+
+	if first.Reset(time.Second) {
+		t.Error("Failed to report fired timer on reset")
+	}
+	clock.Advance(2 * time.Second)
+	if len(fired) != 5 || fired[3] != "first" || fired[4] != "nested" {
+		t.Errorf("Failed to fire reset timer: got %v", fired)
+	}
+}
+
+func TestPoller_Clock(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+
+	set := &Set{}
+	set.UseClock(clock)
+
+	var loads int32
+	set.AddProvider("remote", ProviderFunc(func(context.Context) (map[string]string, error) {
+		atomic.AddInt32(&loads, 1)
+		return nil, nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- set.Poll(ctx, &Poller{Interval: time.Minute}) }()
+
+	for i := 1; i <= 3; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+
+		// the poller resets its timer once the reload completed
+		clock.BlockUntil(1)
+		if got := atomic.LoadInt32(&loads); got != int32(i) {
+			t.Fatalf("Failed to poll on the clock: expected %d; got %d", i, got)
+		}
+	}
+
+	if status := set.Health()[0]; !status.LastSync.Equal(clock.Now()) || status.Staleness != 0 {
+		t.Errorf("Failed to report health on the clock: got %+v", status)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Failed to stop polling: got %v", err)
+	}
+}
+
+func TestSet_UseClock(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+
+	set := &Set{}
+	set.UseClock(clock)
+
+	// read-through values expire on the clock
+	var resolves int32
+	set.ReadThrough(ResolverFunc(func(context.Context, string) (string, bool, error) {
+		atomic.AddInt32(&resolves, 1)
+		return "value", true, nil
+	}), time.Minute, time.Minute)
+
+	set.Get("Remote")
+	set.Get("Remote")
+	clock.Advance(time.Minute)
+	set.Get("Remote")
+	if got := atomic.LoadInt32(&resolves); got != 2 {
+		t.Errorf("Failed to expire read-through on the clock: expected %d; got %d", 2, got)
+	}
+
+	// runtime changes are written behind on the clock
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	if _, err := set.Persist(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.UpdateContext(context.Background(), "Remote", "changed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("Failed to delay persisting")
+	}
+	clock.Advance(persistDelay)
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Failed to persist on the clock: %v", err)
+	}
+
+	// a breaker cools down on its clock
+	breaker := &Breaker{Provider: ProviderFunc(func(context.Context) (map[string]string, error) {
+		return nil, errors.New("down")
+	}), Threshold: 1, Cooldown: time.Minute, Clock: clock}
+
+	_, _ = breaker.Load(context.Background())
+	if _, err := breaker.Load(context.Background()); !errors.Is(err, ErrBreakerOpen) {
+		t.Errorf("Failed to open breaker: got %v", err)
+	}
+	clock.Advance(time.Minute)
+	if _, err := breaker.Load(context.Background()); errors.Is(err, ErrBreakerOpen) {
+		t.Error("Failed to cool down breaker on the clock")
+	}
+}
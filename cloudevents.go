@@ -0,0 +1,46 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// CloudEventType is the type attribute of change events rendered by CloudEvents
+const CloudEventType = "io.portcullis.config.changed"
+
+// cloudEvent is the structured JSON mode of a CloudEvents 1.0 event
+type cloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject"`
+	Time            string      `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            ChangeEvent `json:"data"`
+}
+
+// CloudEvents returns an EventFormat rendering change events as CloudEvents 1.0 in structured JSON mode, so the Webhook and Bus notifiers interoperate with existing eventing infrastructure. The source identifies the service (i.e. "/services/billing"), the subject is the setting path and the type is CloudEventType.
+func CloudEvents(source string) EventFormat {
+	return func(event ChangeEvent) ([]byte, string, error) {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return nil, "", err
+		}
+
+		data, err := json.Marshal(cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              hex.EncodeToString(id),
+			Source:          source,
+			Type:            CloudEventType,
+			Subject:         event.Path,
+			Time:            event.Time.UTC().Format(time.RFC3339Nano),
+			DataContentType: "application/json",
+			Data:            event,
+		})
+
+		return data, "application/cloudevents+json", err
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCloudEvents(t *testing.T) {
+	format := CloudEvents("/services/billing")
+
+	data, contentType, err := format(ChangeEvent{Path: "HTTP.Port", Value: "8080", Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Failed to format event: %v", err)
+	}
+
+	if contentType != "application/cloudevents+json" {
+		t.Errorf("Failed to set content type: expected %q; got %q", "application/cloudevents+json", contentType)
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+
+	expected := map[string]string{
+		"specversion": "1.0",
+		"source":      "/services/billing",
+		"type":        CloudEventType,
+		"subject":     "HTTP.Port",
+		"time":        "2020-01-02T03:04:05Z",
+	}
+	for k, v := range expected {
+		if event[k] != v {
+			t.Errorf("Failed to set %s: expected %q; got %v", k, v, event[k])
+		}
+	}
+
+	if id, _ := event["id"].(string); len(id) != 32 {
+		t.Errorf("Failed to set id: got %v", event["id"])
+	}
+
+	if data, _ := event["data"].(map[string]interface{}); data["value"] != "8080" {
+		t.Errorf("Failed to set data: got %v", event["data"])
+	}
+}
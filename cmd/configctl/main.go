@@ -0,0 +1,128 @@
+// Command configctl validates configuration documents against the schema of a program built with github.com/portcullis/config, so broken configuration can be rejected in CI before it is deployed.
+//
+// The schema is written by the program with config.Set.WriteSchema, for example behind a hidden flag:
+//
+//	config.Default.WriteSchema(os.Stdout)
+//
+// Usage:
+//
+//	configctl -schema schema.json [-quiet] config.json...
+//	configctl -schema schema.json -find query
+//
+// Every document is checked for keys that don't match a setting and values that don't parse for the type of their setting. The effective values are printed for valid documents unless -quiet is set. The exit code is 1 when any document is invalid.
+//
+// With -find the settings matching the query are printed instead, see config.Set.Search.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/portcullis/config"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "", "path of the JSON schema written by config.Set.WriteSchema")
+	quiet := flag.Bool("quiet", false, "only print problems")
+	query := flag.String("find", "", "print the settings matching the query instead of validating")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s -schema schema.json [-quiet] config.json...\n       %s -schema schema.json -find query\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *schemaPath != "" && *query != "" {
+		if err := find(*schemaPath, *query); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	if *schemaPath == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		ok, err := validate(*schemaPath, path, *quiet)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(2)
+		}
+
+		if !ok {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// validate the document at path against a fresh Set read from the schema, returning false when there are problems
+func validate(schemaPath, path string, quiet bool) (bool, error) {
+	f, err := os.Open(schemaPath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	set, err := config.ReadSchema(f)
+	if err != nil {
+		return false, err
+	}
+
+	ok := true
+	if err := set.ValidateFile(path); err != nil {
+		var validationErr *config.ValidationError
+		if !errors.As(err, &validationErr) {
+			return false, err
+		}
+
+		for _, problem := range validationErr.Errors {
+			fmt.Printf("%s: %v\n", path, problem)
+		}
+		ok = false
+	}
+
+	if !ok || quiet {
+		return ok, nil
+	}
+
+	// apply to the schema Set so the effective values can be printed
+	if err := set.LoadFile(path); err != nil {
+		return false, err
+	}
+
+	fmt.Printf("%s: valid\n", path)
+	if err := set.Dump(os.Stdout); err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+// find prints the settings of the schema matching the query, best matches first
+func find(schemaPath, query string) error {
+	f, err := os.Open(schemaPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	set, err := config.ReadSchema(f)
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range set.Search(query) {
+		fmt.Printf("%s\t%s\t%s\n", setting.Path, setting.Type(), setting.Description)
+	}
+
+	return nil
+}
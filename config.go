@@ -1,6 +1,10 @@
 package config
 
-import "io"
+import (
+	"context"
+	"io"
+	"io/fs"
+)
 
 // Default configuration Set
 var Default = &Set{}
@@ -10,6 +14,11 @@ func New(name string, value Value, description string) *Setting {
 	return Default.Setting(name, value, description)
 }
 
+// NewSetting will create a new setting with the specified name and value in the Default Set configured by the options, see Set.NewSetting
+func NewSetting(name string, value Value, opts ...SettingOption) *Setting {
+	return Default.NewSetting(name, value, opts...)
+}
+
 // Get a setting by name
 func Get(name string) *Setting {
 	return Default.Get(name)
@@ -34,13 +43,18 @@ func Subset(name string) *Set {
 // You can mask the Stringer of the setting (set it to output *****) by setting the field tag `mask:"true"`. This is really important to do to passwords/tokens/etc... to make sure they don't end up in logs.
 //
 // If a `flag` field tag exists, the `setting.Flag()` function will be called with the value and `flag.CommandLine``
-func Bind(value interface{}) *Set {
-	return Default.Bind(value)
+func Bind(value interface{}, opts ...BindOption) *Set {
+	return Default.Bind(value, opts...)
 }
 
 // Notify when any of the settings in this set, or any child set is added or changed
-func Notify(n Notifier) *NotifyHandle {
-	return Default.Notify(n)
+func Notify(n Notifier, opts ...NotifyOption) *NotifyHandle {
+	return Default.Notify(n, opts...)
+}
+
+// NotifyContext registers the Notifier with the Default Set until the ctx is done, see Set.NotifyContext
+func NotifyContext(ctx context.Context, n Notifier, opts ...NotifyOption) *NotifyHandle {
+	return Default.NotifyContext(ctx, n, opts...)
 }
 
 // Range over the settings in the entire Set
@@ -49,6 +63,31 @@ func Range(fn func(string, *Setting) bool) {
 }
 
 // Dump the current settings to the specified io.Writer in a tab separated list
-func Dump(w io.Writer) error {
-	return Default.Dump(w)
+func Dump(w io.Writer, opts ...DumpOption) error {
+	return Default.Dump(w, opts...)
+}
+
+// LoadFile reads the document at path and updates the matching settings in the Default Set
+func LoadFile(path string) error {
+	return Default.LoadFile(path)
+}
+
+// LoadDefaults reads the document name from the file system (i.e. an embed.FS) as the defaults of the matching settings in the Default Set, see Set.LoadDefaults
+func LoadDefaults(fsys fs.FS, name string) error {
+	return Default.LoadDefaults(fsys, name)
+}
+
+// AddProvider attaches the Provider to the Default Set, see Set.AddProvider
+func AddProvider(name string, p Provider) {
+	Default.AddProvider(name, p)
+}
+
+// Reload will load every Provider attached to the Default Set and apply their values in precedence order
+func Reload(ctx context.Context) error {
+	return Default.Reload(ctx)
+}
+
+// Hash returns a stable digest of the path and value of every setting in the Default Set
+func Hash() string {
+	return Default.Hash()
 }
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// Conflict is a setting supplied by more than one source, see Set.ConflictReport
+type Conflict struct {
+	// Path of the setting
+	Path string `json:"path"`
+
+	// Winner is the value that took effect and its source
+	Winner Provenance `json:"winner"`
+
+	// Losers are the values of the other sources, overridden or rejected, in the order they were supplied
+	Losers []Provenance `json:"losers"`
+
+	// Masked is set for masked settings, whose values are reported as ***** and can not be compared
+	Masked bool `json:"masked,omitempty"`
+
+	setting *Setting
+}
+
+// Redundant returns if every other source supplied the value that took effect, so they can be removed without changing the configuration. Conflicts of masked settings are never redundant, as their values can not be compared.
+func (c Conflict) Redundant() bool {
+	for _, p := range c.Losers {
+		if !c.same(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// same returns if the source supplied the value that took effect, compared through the setting so equivalent forms (i.e. 1m and 60s) are the same
+func (c Conflict) same(p Provenance) bool {
+	if c.Masked || p.Error != "" {
+		return false
+	}
+	if p.Value == c.Winner.Value {
+		return true
+	}
+	if c.setting == nil {
+		return false
+	}
+
+	winner := c.setting.clone()
+	if err := winner.convert(c.Winner.Value); err != nil {
+		return false
+	}
+
+	return winner.Equals(p.Value)
+}
+
+// ConflictReport lists the settings supplied by more than one source during a layered load (i.e. a file, the environment and flags), see Set.ConflictReport
+type ConflictReport struct {
+	// Conflicts sorted by path
+	Conflicts []Conflict `json:"conflicts"`
+}
+
+// String formats the report with the winning source of every conflict followed by a line per losing source, stating whether it was overridden with the same value, another value or rejected
+func (r ConflictReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Conflicts {
+		fmt.Fprintf(&b, "%s = %q (from %s)\n", c.Path, c.Winner.Value, c.Winner.Source)
+
+		tw := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+		for _, p := range c.Losers {
+			outcome := "overridden"
+			switch {
+			case p.Error != "":
+				outcome = "rejected: " + p.Error
+			case c.same(p):
+				outcome = "redundant"
+			}
+
+			fmt.Fprintf(tw, "  %s\t%q\t%s\n", p.Source, p.Value, outcome)
+		}
+		_ = tw.Flush()
+	}
+
+	return b.String()
+}
+
+// ConflictReport returns the settings within the Set supplied by more than one source, with the value that took effect and the values of the other sources, so redundant or contradictory configuration can be cleaned up. The report is built from the values recorded since Set.TrackProvenance was called, which should be called before the layered load. Defaults are not sources, so a single source overriding a default is not a conflict.
+func (s *Set) ConflictReport() ConflictReport {
+	report := ConflictReport{Conflicts: []Conflict{}}
+
+	for _, setting := range s.sorted() {
+		setting.provenance.mu.Lock()
+		var supplied []Provenance
+		for _, p := range setting.provenance.entries {
+			if p.Source != SourceDefault {
+				supplied = append(supplied, p)
+			}
+		}
+		setting.provenance.mu.Unlock()
+
+		if len(supplied) < 2 {
+			continue
+		}
+
+		source := setting.Source()
+		winner := -1
+		for i, p := range supplied {
+			if p.Source == source && p.Error == "" {
+				winner = i
+			}
+		}
+
+		conflict := Conflict{Path: setting.Path, Masked: setting.Mask, setting: setting}
+		if winner < 0 {
+			// the value did not come from a tracked source, i.e. it was reverted to its default
+			conflict.Winner = Provenance{Source: source, Value: setting.format()}
+			if setting.Mask {
+				conflict.Winner.Value = "*****"
+			}
+		} else {
+			conflict.Winner = supplied[winner]
+		}
+
+		for i, p := range supplied {
+			if i != winner {
+				conflict.Losers = append(conflict.Losers, p)
+			}
+		}
+
+		report.Conflicts = append(report.Conflicts, conflict)
+	}
+
+	return report
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_ConflictReport(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"base.json":     `{"HTTP": {"Port": 8080, "Host": "localhost"}, "Level": "info", "Token": "a"}`,
+		"override.json": `{"HTTP": {"Port": 9090}, "Level": "info"}`,
+	})
+	t.Setenv("APP_HTTP_PORT", "7070")
+	t.Setenv("APP_TOKEN", "b")
+
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", 80, "")
+	set.Subset("HTTP").Setting("Host", "", "")
+	set.Setting("Level", "warn", "")
+	set.Setting("Token", "", "").Mask = true
+
+	set.TrackProvenance()
+
+	base, override := filepath.Join(dir, "base.json"), filepath.Join(dir, "override.json")
+	for _, path := range []string{base, override} {
+		if err := set.LoadFile(path); err != nil {
+			t.Fatalf("Failed to load %s: %v", path, err)
+		}
+	}
+	if err := set.LoadEnv("APP"); err != nil {
+		t.Fatalf("Failed to load env: %v", err)
+	}
+
+	report := set.ConflictReport()
+	if len(report.Conflicts) != 3 {
+		t.Fatalf("Failed to report conflicts: expected 3; got %+v", report.Conflicts)
+	}
+
+	port := report.Conflicts[0]
+	if port.Path != "HTTP.Port" || port.Winner.Source != SourceEnv || port.Winner.Value != "7070" || len(port.Losers) != 2 || port.Redundant() {
+		t.Errorf("Failed to report contradictory sources: got %+v", port)
+	}
+
+	level := report.Conflicts[1]
+	if level.Path != "Level" || level.Winner.Source != base || len(level.Losers) != 1 || level.Losers[0].Source != override || !level.Redundant() {
+		t.Errorf("Failed to report redundant source: got %+v", level)
+	}
+
+	token := report.Conflicts[2]
+	if token.Path != "Token" || token.Winner.Value != "*****" || token.Losers[0].Value != "*****" || token.Redundant() {
+		t.Errorf("Failed to mask conflict: got %+v", token)
+	}
+
+	lines := strings.Split(report.String(), "\n")
+	expected := [][]string{
+		{`HTTP.Port = "7070" (from env)`},
+		{base, `"8080"`, "overridden"},
+		{override, `"9090"`, "overridden"},
+		{`Level = "info" (from ` + base + ")"},
+		{override, `"info"`, "redundant"},
+		{`Token = "*****" (from env)`},
+		{base, `"*****"`, "overridden"},
+	}
+	if len(lines) != len(expected)+1 {
+		t.Fatalf("Failed to format report: got\n%s", report)
+	}
+	for i, fields := range expected {
+		if got := strings.Join(strings.Fields(lines[i]), " "); got != strings.Join(fields, " ") {
+			t.Errorf("Failed to format line %d: expected %q; got %q", i, strings.Join(fields, " "), got)
+		}
+	}
+}
+
+func TestSet_ConflictReportEquivalent(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"base.json":     `{"Timeout": "1m", "Port": 8080}`,
+		"override.json": `{"Timeout": "60s", "Port": "0x1F90"}`,
+	})
+
+	set := &Set{}
+	set.Setting("Timeout", time.Second, "")
+	set.Setting("Port", 80, "")
+
+	set.TrackProvenance()
+
+	for _, name := range []string{"base.json", "override.json"} {
+		if err := set.LoadFile(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("Failed to load %s: %v", name, err)
+		}
+	}
+
+	report := set.ConflictReport()
+	if len(report.Conflicts) != 2 {
+		t.Fatalf("Failed to report conflicts: expected 2; got %+v", report.Conflicts)
+	}
+
+	for _, conflict := range report.Conflicts {
+		if !conflict.Redundant() {
+			t.Errorf("Failed to compare %s through the setting: got %+v", conflict.Path, conflict)
+		}
+	}
+}
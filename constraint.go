@@ -0,0 +1,423 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ErrConstraint is wrapped by every ConstraintError
+var ErrConstraint = errors.New("constraint violated")
+
+// ConstraintError is returned when a value does not satisfy the Constraint of its setting
+type ConstraintError struct {
+	// Path of the setting
+	Path string
+
+	// Constraint that was not satisfied
+	Constraint string
+
+	// Err is set when the constraint could not be evaluated, nil when it evaluated to false
+	Err error
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: unable to evaluate constraint %q: %v", e.Path, e.Constraint, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v: %s", e.Path, ErrConstraint, e.Constraint)
+}
+
+// Unwrap returns ErrConstraint, or the evaluation error
+func (e *ConstraintError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+
+	return ErrConstraint
+}
+
+// constrain evaluates the Constraint of the setting with this bound to the candidate value v, lookup resolves the values of other settings before the current ones (i.e. the other values of a document being validated)
+func (s *Setting) constrain(v string, lookup func(path string) (string, bool)) error {
+	if s.Constraint == "" {
+		return nil
+	}
+
+	candidate := s.clone()
+	if err := candidate.convert(v); err != nil {
+		// reported by the conversion itself
+		return nil
+	}
+
+	e := &evaluator{this: candidate, set: s.set, lookup: lookup}
+	ok, err := e.test(s.Constraint)
+	if err != nil {
+		return &ConstraintError{Path: s.Path, Constraint: s.Constraint, Err: err}
+	}
+
+	if !ok {
+		return &ConstraintError{Path: s.Path, Constraint: s.Constraint}
+	}
+
+	return nil
+}
+
+type documentContextKey struct{}
+
+// withDocument returns a child context of ctx for values applied together from a document, whose constraints resolve the other settings of the document to their values in it rather than the current ones
+func withDocument(ctx context.Context, lookup func(path string) (string, bool)) context.Context {
+	return context.WithValue(ctx, documentContextKey{}, lookup)
+}
+
+// documentLookup returns the lookup of the document added to the ctx with withDocument, nil when the value is not applied from a document
+func documentLookup(ctx context.Context) func(path string) (string, bool) {
+	lookup, _ := ctx.Value(documentContextKey{}).(func(path string) (string, bool))
+	return lookup
+}
+
+// dependents returns the settings of the Set tree whose Constraint references any of the settings
+func (s *Set) dependents(settings map[*Setting]bool) []*Setting {
+	var deps []*Setting
+	for _, setting := range s.Root().sorted() {
+		if setting.Constraint == "" {
+			continue
+		}
+
+		// invalid constraints are reported when they are evaluated
+		node, err := parser.ParseExpr(setting.Constraint)
+		if err != nil {
+			continue
+		}
+
+		for _, path := range references(node) {
+			if ref := setting.set.referenced(path); ref != nil && settings[ref] {
+				deps = append(deps, setting)
+				break
+			}
+		}
+	}
+
+	return deps
+}
+
+// evaluator of constraint expressions, a subset of Go expression syntax:
+//   - this refers to the value being set, other identifiers and selectors (i.e. Pool.Max) to settings resolved like Set.Get from the Set of the setting
+//   - literals of numbers, strings, true and false
+//   - the operators ! - + * / && || == != < <= > >= and parentheses
+//   - the functions len(string) and duration(string), durations are compared as nanoseconds
+//   - the function in(x, a, b, ...) reporting if x equals any of the following arguments
+type evaluator struct {
+	this   *Setting
+	set    *Set
+	lookup func(path string) (string, bool)
+}
+
+// test parses and evaluates the expression, which must result in a bool
+func (e *evaluator) test(expr string) (bool, error) {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := e.eval(node)
+	if err != nil {
+		return false, err
+	}
+
+	ok, isBool := result.(bool)
+	if !isBool {
+		return false, fmt.Errorf("expression is %T, not bool", result)
+	}
+
+	return ok, nil
+}
+
+// eval the node into a bool, float64 or string
+func (e *evaluator) eval(node ast.Expr) (interface{}, error) {
+	switch n := node.(type) {
+	case *ast.ParenExpr:
+		return e.eval(n.X)
+
+	case *ast.BasicLit:
+		switch n.Kind {
+		case token.INT, token.FLOAT:
+			return strconv.ParseFloat(n.Value, 64)
+		case token.STRING:
+			return strconv.Unquote(n.Value)
+		}
+		return nil, fmt.Errorf("unsupported literal %s", n.Value)
+
+	case *ast.Ident:
+		switch n.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "this":
+			if e.this == nil {
+				return nil, errors.New("this is not available")
+			}
+			return operand(e.this), nil
+		}
+		return e.reference(n.Name)
+
+	case *ast.SelectorExpr:
+		path, ok := selectorPath(n)
+		if !ok {
+			return nil, errors.New("unsupported selector")
+		}
+		return e.reference(path)
+
+	case *ast.UnaryExpr:
+		x, err := e.eval(n.X)
+		if err != nil {
+			return nil, err
+		}
+
+		switch val := x.(type) {
+		case bool:
+			if n.Op == token.NOT {
+				return !val, nil
+			}
+		case float64:
+			if n.Op == token.SUB {
+				return -val, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported operator %s for %T", n.Op, x)
+
+	case *ast.BinaryExpr:
+		return e.binary(n)
+
+	case *ast.CallExpr:
+		return e.call(n)
+	}
+
+	return nil, fmt.Errorf("unsupported expression %T", node)
+}
+
+// binary evaluates the operator, && and || short circuit
+func (e *evaluator) binary(n *ast.BinaryExpr) (interface{}, error) {
+	x, err := e.eval(n.X)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Op == token.LAND || n.Op == token.LOR {
+		left, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("unsupported operator %s for %T", n.Op, x)
+		}
+		if left == (n.Op == token.LOR) {
+			return left, nil
+		}
+
+		y, err := e.eval(n.Y)
+		if err != nil {
+			return nil, err
+		}
+		right, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("unsupported operator %s for %T", n.Op, y)
+		}
+		return right, nil
+	}
+
+	y, err := e.eval(n.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case token.EQL:
+		return x == y, nil
+	case token.NEQ:
+		return x != y, nil
+	}
+
+	switch left := x.(type) {
+	case float64:
+		right, ok := y.(float64)
+		if !ok {
+			break
+		}
+
+		switch n.Op {
+		case token.LSS:
+			return left < right, nil
+		case token.LEQ:
+			return left <= right, nil
+		case token.GTR:
+			return left > right, nil
+		case token.GEQ:
+			return left >= right, nil
+		case token.ADD:
+			return left + right, nil
+		case token.SUB:
+			return left - right, nil
+		case token.MUL:
+			return left * right, nil
+		case token.QUO:
+			return left / right, nil
+		}
+
+	case string:
+		right, ok := y.(string)
+		if !ok {
+			break
+		}
+
+		switch n.Op {
+		case token.LSS:
+			return left < right, nil
+		case token.LEQ:
+			return left <= right, nil
+		case token.GTR:
+			return left > right, nil
+		case token.GEQ:
+			return left >= right, nil
+		case token.ADD:
+			return left + right, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported operator %s for %T and %T", n.Op, x, y)
+}
+
+// call evaluates the len, duration and in functions
+func (e *evaluator) call(n *ast.CallExpr) (interface{}, error) {
+	fn, ok := n.Fun.(*ast.Ident)
+	if !ok || len(n.Args) == 0 {
+		return nil, errors.New("unsupported call")
+	}
+
+	args := make([]interface{}, 0, len(n.Args))
+	for _, a := range n.Args {
+		arg, err := e.eval(a)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+
+	if fn.Name == "in" {
+		for _, arg := range args[1:] {
+			if arg == args[0] {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%s requires 1 argument, not %d", fn.Name, len(args))
+	}
+	arg := args[0]
+
+	str, ok := arg.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a string, not %T", fn.Name, arg)
+	}
+
+	switch fn.Name {
+	case "len":
+		return float64(len(str)), nil
+	case "duration":
+		d, err := time.ParseDuration(str)
+		return float64(d), err
+	}
+
+	return nil, fmt.Errorf("unknown function %s", fn.Name)
+}
+
+// reference resolves the value of another setting by path
+func (e *evaluator) reference(path string) (interface{}, error) {
+	if e.set == nil {
+		return nil, fmt.Errorf("unable to resolve %s outside of a Set", path)
+	}
+
+	setting := e.set.referenced(path)
+	if setting == nil {
+		return nil, fmt.Errorf("%s: %w", path, ErrUnknownSetting)
+	}
+
+	if e.lookup != nil {
+		if v, found := e.lookup(setting.Path); found {
+			candidate := setting.clone()
+			if err := candidate.convert(v); err != nil {
+				return nil, fmt.Errorf("unable to convert %s: %w", path, err)
+			}
+			return operand(candidate), nil
+		}
+	}
+
+	return operand(setting), nil
+}
+
+// references returns the paths of the settings referenced by the expression, excluding this
+func references(node ast.Expr) []string {
+	var paths []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.CallExpr:
+			// the function name is not a reference
+			for _, arg := range x.Args {
+				paths = append(paths, references(arg)...)
+			}
+			return false
+		case *ast.SelectorExpr:
+			if path, ok := selectorPath(x); ok {
+				paths = append(paths, path)
+			}
+			return false
+		case *ast.Ident:
+			if x.Name != "this" && x.Name != "true" && x.Name != "false" {
+				paths = append(paths, x.Name)
+			}
+		}
+		return true
+	})
+
+	return paths
+}
+
+// selectorPath returns the dot separated path of a selector such as Pool.Max
+func selectorPath(n *ast.SelectorExpr) (string, bool) {
+	switch x := n.X.(type) {
+	case *ast.Ident:
+		return x.Name + "." + n.Sel.Name, true
+	case *ast.SelectorExpr:
+		path, ok := selectorPath(x)
+		return path + "." + n.Sel.Name, ok
+	}
+
+	return "", false
+}
+
+// operand returns the value of the setting as a bool, float64 or string
+func operand(s *Setting) interface{} {
+	rv := reflect.ValueOf(s.Value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+
+	return s.format()
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetting_Constraint(t *testing.T) {
+	cfg := struct {
+		Pool struct {
+			Min int `constraint:"this >= 0 && this <= Max"`
+			Max int
+		}
+		Workers int           `constraint:"this <= Pool.Max * 2"`
+		Timeout time.Duration `constraint:"this < duration(\"1m\")"`
+		Name    string        `constraint:"len(this) > 0"`
+	}{}
+	cfg.Pool.Max = 10
+	cfg.Name = "portcullis"
+
+	s := &Set{}
+	s.Bind(&cfg)
+
+	tests := []struct {
+		path  string
+		value string
+		err   error
+	}{
+		{path: "Pool.Min", value: "5"},
+		{path: "Pool.Min", value: "11", err: ErrConstraint},
+		{path: "Pool.Min", value: "-1", err: ErrConstraint},
+		{path: "Workers", value: "20"},
+		{path: "Workers", value: "21", err: ErrConstraint},
+		{path: "Timeout", value: "30s"},
+		{path: "Timeout", value: "2m", err: ErrConstraint},
+		{path: "Name", value: "", err: ErrConstraint},
+	}
+
+	for _, tt := range tests {
+		if _, err := s.Update(tt.path, tt.value); !errors.Is(err, tt.err) {
+			t.Errorf("Failed to constrain %s=%s: expected %v; got %v", tt.path, tt.value, tt.err, err)
+		}
+	}
+
+	if cfg.Pool.Min != 5 {
+		t.Errorf("Failed to keep value on violation: expected %d; got %d", 5, cfg.Pool.Min)
+	}
+
+	s.Get("Name").Constraint = "this >"
+	var constraintErr *ConstraintError
+	if _, err := s.Update("Name", "x"); !errors.As(err, &constraintErr) || constraintErr.Err == nil {
+		t.Errorf("Failed to report invalid constraint: got %v", err)
+	}
+}
+
+func TestSet_ValidateFileConstraint(t *testing.T) {
+	s := &Set{}
+	s.Setting("Min", new(int), "").Constraint = "this <= Max"
+	s.Setting("Max", new(int), "")
+
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(valid, []byte(`{"Min": 5, "Max": 10}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ValidateFile(valid); err != nil {
+		t.Errorf("Failed to validate against pending values: %v", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"Min": 5}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var validationErr *ValidationError
+	if err := s.ValidateFile(invalid); !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 || !errors.Is(validationErr.Errors[0], ErrConstraint) {
+		t.Errorf("Failed to violate constraint: got %v", err)
+	}
+}
+
+func TestSet_LoadFileConstraint(t *testing.T) {
+	var (
+		min = 50
+		max = 100
+	)
+
+	s := &Set{}
+	s.Setting("Min", &min, "")
+	s.Setting("Max", &max, "").Constraint = "this >= Min"
+
+	dir := writeFiles(t, map[string]string{
+		"lower.json": `{"Min": 10, "Max": 20}`,
+		"min.json":   `{"Min": 30}`,
+	})
+
+	// Max is applied first, against the Min of the document rather than the current one
+	if err := s.LoadFile(filepath.Join(dir, "lower.json")); err != nil || min != 10 || max != 20 {
+		t.Errorf("Failed to constrain against the document: got %v with %d and %d", err, min, max)
+	}
+
+	// settings referencing a changed setting are checked again
+	if err := s.LoadFile(filepath.Join(dir, "min.json")); !errors.Is(err, ErrConstraint) || min != 10 {
+		t.Errorf("Failed to constrain dependent: got %v with %d", err, min)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+// Derive will create a new setting, like Set.Setting, whose value is computed by fn from other settings. The dependencies are paths resolved with Set.Get and must already exist. Whenever a dependency changes the value is recomputed, and notifications are sent when it differs. Computed values are applied with SourceDerived, so they are not written through to a writable provider.
+//
+//	scheme := set.Setting("Scheme", "http", "")
+//	host := set.Setting("Host", "localhost", "")
+//	set.Derive("BaseURL", "", "URL of the service", func() string {
+//		return scheme.String() + "://" + host.String()
+//	}, "Scheme", "Host")
+func (s *Set) Derive(name string, value Value, description string, fn func() string, dependencies ...string) *Setting {
+	if fn == nil {
+		panic("fn can not be nil")
+	}
+
+	deps := make([]*Setting, 0, len(dependencies))
+	for _, path := range dependencies {
+		dep := s.Get(path)
+		if dep == nil {
+			panic(fmt.Sprintf("dependency %q does not exist", path))
+		}
+		deps = append(deps, dep)
+	}
+
+	setting := s.Setting(name, value, description)
+
+	for _, dep := range deps {
+		setting.dependencies = append(setting.dependencies, dep.Path)
+	}
+
+	// the computed value is the default of a derived setting
+	_ = setting.setFrom(context.Background(), fn(), SourceDerived)
+	setting.DefaultValue = setting.format()
+
+	recompute := NotifyFunc(func(*Setting) {
+		// the computed value can not be rejected by anyone, errors mean fn and the value type disagree
+		if err := setting.setFrom(context.Background(), fn(), SourceDerived); err != nil {
+			logger().Error("unable to recompute derived setting", "path", setting.Path, "error", err)
+		}
+	})
+
+	for _, dep := range deps {
+		_ = dep.Notify(recompute)
+	}
+
+	return setting
+}
+
+// Dependencies returns the paths of the settings this setting is derived from, see Set.Derive
+func (s *Setting) Dependencies() []string {
+	deps := make([]string, len(s.dependencies))
+	copy(deps, s.dependencies)
+	return deps
+}
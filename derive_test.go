@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSet_Derive(t *testing.T) {
+	set := &Set{}
+	scheme := set.Setting("Scheme", "http", "")
+	host := set.Setting("Host", "localhost", "")
+
+	baseURL := set.Derive("BaseURL", "", "", func() string {
+		return scheme.String() + "://" + host.String()
+	}, "Scheme", "Host")
+
+	if baseURL.String() != "http://localhost" || baseURL.DefaultValue != "http://localhost" {
+		t.Errorf("Failed to compute initial value: got %q (default %q)", baseURL.String(), baseURL.DefaultValue)
+	}
+
+	notified := 0
+	baseURL.Notify(NotifyFunc(func(*Setting) { notified++ }))
+
+	if _, err := set.Update("Host", "example.com"); err != nil {
+		t.Fatalf("Failed to update dependency: %v", err)
+	}
+
+	if baseURL.String() != "http://example.com" {
+		t.Errorf("Failed to recompute value: expected %q; got %q", "http://example.com", baseURL.String())
+	}
+
+	if notified != 1 {
+		t.Errorf("Failed to notify derived change: expected 1; got %d", notified)
+	}
+
+	if deps := baseURL.Dependencies(); len(deps) != 2 || deps[0] != "Scheme" || deps[1] != "Host" {
+		t.Errorf("Failed to record dependencies: got %v", deps)
+	}
+}
+
+func TestSet_DeriveWriteThrough(t *testing.T) {
+	set := &Set{}
+	host := set.Setting("Host", "localhost", "")
+	baseURL := set.Derive("BaseURL", "", "", func() string {
+		return "http://" + host.String()
+	}, "Host")
+
+	backend := &memoryBackend{values: map[string]string{"Host": "localhost"}}
+	set.AddProvider("backend", backend)
+	set.WriteThrough("backend")
+
+	if err := set.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to load backend: %v", err)
+	}
+
+	if _, err := set.Update("Host", "example.com"); err != nil {
+		t.Fatalf("Failed to update dependency: %v", err)
+	}
+
+	if _, found := backend.values["BaseURL"]; found || backend.values["Host"] != "example.com" {
+		t.Errorf("Failed to keep derived value out of the backend: got %v", backend.values)
+	}
+
+	if baseURL.String() != "http://example.com" || baseURL.Source() != SourceDerived {
+		t.Errorf("Failed to apply derived value: got %q from %q", baseURL.String(), baseURL.Source())
+	}
+}
+
+func TestSet_Gate(t *testing.T) {
+	s := &Set{}
+	env := s.Setting("Env", "dev", "")
+	region := s.Subset("Cloud").Setting("Region", "us-east-1", "")
+
+	gate := s.Gate("Enabled", `Env == "prod" && in(Cloud.Region, "eu-west-1", "eu-central-1")`, "")
+
+	var notified int
+	gate.Notify(NotifyFunc(func(*Setting) { notified++ }))
+
+	if gate.String() != "false" || len(gate.Dependencies()) != 2 {
+		t.Errorf("Failed to create gate: got %q with dependencies %v", gate.String(), gate.Dependencies())
+	}
+
+	_ = env.Set("prod")
+	_ = region.Set("eu-west-1")
+
+	if gate.String() != "true" || notified != 1 {
+		t.Errorf("Failed to re-evaluate gate: expected %q; got %q after %d notifications", "true", gate.String(), notified)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Failed to panic on unknown reference")
+		}
+	}()
+
+	s.Gate("Invalid", "Missing == 1", "")
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ANSI escapes used by WriteDiff in color
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiReset = "\x1b[0m"
+)
+
+// WriteDiff writes the changes (i.e. from Set.Drift) to w as unified diff like text for human review: the previous value of every setting on a line starting with - followed by the new value on a line starting with +. Values that are empty or have surrounding space are quoted. With color the lines are red and green for a terminal, without it the text renders as a diff code block in chat messages and the admin UI.
+func WriteDiff(w io.Writer, changes []Change, color bool) error {
+	bw := bufio.NewWriter(w)
+
+	for _, change := range changes {
+		from := "- " + change.Path + ": " + diffValue(change.From)
+		to := "+ " + change.Path + ": " + diffValue(change.To)
+
+		if color {
+			from = ansiRed + from + ansiReset
+			to = ansiGreen + to + ansiReset
+		}
+
+		bw.WriteString(from + "\n")
+		bw.WriteString(to + "\n")
+	}
+
+	return bw.Flush()
+}
+
+// WriteDiffJSON writes the changes to w as a JSON array of objects with the path, from and to of every change, an empty array when nothing changed
+func WriteDiffJSON(w io.Writer, changes []Change) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	return json.NewEncoder(w).Encode(changes)
+}
+
+// diffValue quotes the value when it would be ambiguous unquoted
+func diffValue(v string) string {
+	if v == "" || strings.TrimSpace(v) != v || strconv.Quote(v) != `"`+v+`"` {
+		return strconv.Quote(v)
+	}
+
+	return v
+}
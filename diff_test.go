@@ -0,0 +1,50 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDiff(t *testing.T) {
+	changes := []Change{
+		{Path: "HTTP.Port", From: "80", To: "8080"},
+		{Path: "Name", From: "", To: "two words"},
+		{Path: "Motd", From: "hi\n", To: " padded"},
+	}
+
+	tests := []struct {
+		name     string
+		color    bool
+		expected string
+	}{
+		{"plain", false, "- HTTP.Port: 80\n+ HTTP.Port: 8080\n- Name: \"\"\n+ Name: two words\n- Motd: \"hi\\n\"\n+ Motd: \" padded\"\n"},
+		{"color", true, "\x1b[31m- HTTP.Port: 80\x1b[0m\n\x1b[32m+ HTTP.Port: 8080\x1b[0m\n\x1b[31m- Name: \"\"\x1b[0m\n\x1b[32m+ Name: two words\x1b[0m\n\x1b[31m- Motd: \"hi\\n\"\x1b[0m\n\x1b[32m+ Motd: \" padded\"\x1b[0m\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteDiff(&buf, changes, tt.color); err != nil {
+			t.Fatalf("Failed to write %s diff: %v", tt.name, err)
+		}
+
+		if buf.String() != tt.expected {
+			t.Errorf("Failed to write %s diff: expected %q; got %q", tt.name, tt.expected, buf.String())
+		}
+	}
+}
+
+func TestWriteDiffJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDiffJSON(&buf, []Change{{Path: "HTTP.Port", From: "80", To: "8080"}}); err != nil {
+		t.Fatalf("Failed to write diff: %v", err)
+	}
+
+	if expected := `[{"path":"HTTP.Port","from":"80","to":"8080"}]` + "\n"; buf.String() != expected {
+		t.Errorf("Failed to write diff: expected %q; got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteDiffJSON(&buf, nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("Failed to write empty diff: expected %q; got %q", "[]\n", buf.String())
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDigestMismatch is returned when the contents of a document do not match the expected digest
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// FileDigest returns a Provider reading the JSON document at path like File, failing when the hex encoded SHA-256 digest of the document does not match the expected digest. This protects against truncated or tampered fetches of the document.
+func FileDigest(path, digest string) Provider {
+	return ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		r := readerFrom(ctx)
+		r.digest = digest
+
+		return r.read(path, nil)
+	})
+}
+
+// checkDigest compares the hex encoded SHA-256 digest, optionally prefixed with sha256:, with the digest of the data
+func checkDigest(expected string, data []byte) error {
+	expected = strings.ToLower(strings.TrimPrefix(expected, "sha256:"))
+
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) != 1 {
+		return fmt.Errorf("%w: expected sha256:%s; got sha256:%s", ErrDigestMismatch, expected, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet_LoadFileDigest(t *testing.T) {
+	document := `{"Port": 8080}`
+	sum := sha256.Sum256([]byte(document))
+	digest := hex.EncodeToString(sum[:])
+	other := sha256.Sum256([]byte(`{"Port": 9090}`))
+
+	dir := writeFiles(t, map[string]string{"config.json": document})
+	path := filepath.Join(dir, "config.json")
+
+	port := 80
+	set := &Set{}
+	set.Setting("Port", &port, "")
+
+	if err := set.LoadFileDigest(path, "sha256:"+hex.EncodeToString(other[:])); !errors.Is(err, ErrDigestMismatch) || port != 80 {
+		t.Errorf("Failed to reject mismatched digest: got %v with %d", err, port)
+	}
+
+	if err := set.LoadFileDigest(path, "sha256:"+digest); err != nil || port != 8080 {
+		t.Errorf("Failed to load matching digest: got %v with %d", err, port)
+	}
+
+	values, err := FileDigest(path, digest).Load(context.Background())
+	if err != nil || values["Port"] != "8080" {
+		t.Errorf("Failed to load provider with matching digest: got %v with %v", err, values)
+	}
+}
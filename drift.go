@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strings"
+)
+
+// Change is a difference in the value of a setting
+type Change struct {
+	// Path of the setting
+	Path string `json:"path"`
+
+	// From is the expected or previous value, ***** for masked settings
+	From string `json:"from"`
+
+	// To is the actual or new value, ***** for masked settings
+	To string `json:"to"`
+}
+
+// Drift compares the live values of the settings of the Set against the reference document at path, read like Set.LoadFile, and returns a Change for every setting whose live value differs. Settings not in the document are expected to be at their default. The document is validated first and a *ValidationError returned when it does not apply to the Set.
+func (s *Set) Drift(path string) ([]Change, error) {
+	values, err := s.reader().read(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(values, path); err != nil {
+		return nil, err
+	}
+	values = s.resolveEnvNames(values)
+
+	expected := map[string]string{}
+	for k, v := range values {
+		expected[strings.ToLower(s.lookup(k).Path)] = v
+	}
+
+	var changes []Change
+	for _, setting := range s.sorted() {
+		want, found := expected[strings.ToLower(setting.Path)]
+		if !found {
+			want = setting.DefaultValue
+		}
+
+		// normalize the expected value through the setting so equivalent forms (i.e. 1m and 60s) are equal
+		reference := setting.clone()
+		if reference.convert(want) == nil {
+			want = reference.format()
+		}
+
+		if have := setting.format(); have != want {
+			change := Change{Path: setting.Path, From: setting.display(want), To: setting.display(have)}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSet_Drift(t *testing.T) {
+	cfg := struct {
+		Port     int
+		Timeout  time.Duration
+		Host     string
+		Password string `mask:"true"`
+	}{Port: 80, Timeout: time.Second, Host: "localhost", Password: "secret"}
+
+	set := &Set{}
+	set.Subset("HTTP").Bind(&cfg)
+
+	dir := writeFiles(t, map[string]string{
+		"config.json":  `{"HTTP": {"Port": 8080, "Timeout": "60s", "Password": "secret"}}`,
+		"unknown.json": `{"HTTP": {"Missing": 1}}`,
+	})
+
+	_ = set.LoadFile(filepath.Join(dir, "config.json"))
+	_, _ = set.Update("HTTP.Timeout", "1m")
+
+	changes, err := set.Drift(filepath.Join(dir, "config.json"))
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("Failed to report no drift: got %v with %v", err, changes)
+	}
+
+	_, _ = set.Update("HTTP.Port", "9090")
+	_, _ = set.Update("HTTP.Host", "example.com")
+	_, _ = set.Update("HTTP.Password", "hunter2")
+
+	changes, _ = set.Drift(filepath.Join(dir, "config.json"))
+
+	expected := []Change{
+		{Path: "HTTP.Host", From: "localhost", To: "example.com"},
+		{Path: "HTTP.Password", From: "*****", To: "*****"},
+		{Path: "HTTP.Port", From: "8080", To: "9090"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Failed to report drift: expected %v; got %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Failed to report drift: expected %v; got %v", expected[i], changes[i])
+		}
+	}
+
+	if _, err := set.Drift(filepath.Join(dir, "unknown.json")); err == nil {
+		t.Errorf("Failed to reject unknown setting")
+	}
+}
+
+func TestSet_DriftRedacted(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.json": `{"DB": "postgres://app:hunter4@db:5432/app"}`})
+
+	changes, err := redactedSet(t).Drift(filepath.Join(dir, "config.json"))
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("Failed to report drift: got %v with %v", err, changes)
+	}
+
+	assertRedacted(t, "drift", changes[0].From+changes[0].To)
+}
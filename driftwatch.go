@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DriftWatcher periodically checks the drift of a Set from a reference file (see Set.Drift) and alerts when differences appear that are not expected. Settings that change at runtime by design are excluded with the Allow list.
+type DriftWatcher struct {
+	// Set being checked
+	Set *Set
+
+	// Path of the reference file
+	Path string
+
+	// Interval between checks, must be positive
+	Interval time.Duration
+
+	// Allow lists the paths of settings, or subsets, expected to differ from the reference file
+	Allow []string
+
+	// OnDrift is called with the unexpected changes whenever they differ from the previous check
+	OnDrift func(changes []Change)
+
+	// OnError, when not nil, is called when a check fails
+	OnError func(err error)
+
+	alerts uint64
+	mu     sync.Mutex
+	last   string
+}
+
+// Run checks the drift on the Interval until the ctx is done, at which point the ctx error is returned. An error is returned right away when the Interval is not positive.
+func (w *DriftWatcher) Run(ctx context.Context) error {
+	if w.Interval <= 0 {
+		return fmt.Errorf("unable to watch drift: interval %s is not positive", w.Interval)
+	}
+
+	p := &Poller{Interval: w.Interval, Clock: w.Set.clock()}
+
+	return p.Run(ctx, func(ctx context.Context) error {
+		_, err := w.Check()
+		if err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+
+		return err
+	})
+}
+
+// Check the drift once, returning the unexpected changes. OnDrift is called, and the alert count incremented, when the unexpected changes differ from the previous check.
+func (w *DriftWatcher) Check() ([]Change, error) {
+	changes, err := w.Set.Drift(w.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check drift: %w", err)
+	}
+
+	var unexpected []Change
+	var key strings.Builder
+	for _, change := range changes {
+		if w.allowed(change.Path) {
+			continue
+		}
+
+		unexpected = append(unexpected, change)
+		fmt.Fprintf(&key, "%s\x00%s\x00%s\x00", change.Path, change.From, change.To)
+	}
+
+	w.mu.Lock()
+	changed := key.String() != w.last
+	w.last = key.String()
+	w.mu.Unlock()
+
+	if changed && len(unexpected) > 0 {
+		atomic.AddUint64(&w.alerts, 1)
+		if w.OnDrift != nil {
+			w.OnDrift(unexpected)
+		}
+	}
+
+	return unexpected, nil
+}
+
+// Alerts returns the number of times unexpected drift was reported, for export as a metric
+func (w *DriftWatcher) Alerts() uint64 {
+	return atomic.LoadUint64(&w.alerts)
+}
+
+// allowed returns if the path is expected to differ
+func (w *DriftWatcher) allowed(path string) bool {
+	for _, allow := range w.Allow {
+		if within(path, allow) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDriftWatcher_Check(t *testing.T) {
+	cfg := struct {
+		Port int
+		Log  struct {
+			Level string
+		}
+	}{Port: 80}
+	cfg.Log.Level = "info"
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	dir := writeFiles(t, map[string]string{"config.json": `{"Port": 8080}`})
+	_ = set.LoadFile(filepath.Join(dir, "config.json"))
+
+	var reported [][]Change
+	w := &DriftWatcher{
+		Set:     set,
+		Path:    filepath.Join(dir, "config.json"),
+		Allow:   []string{"Log"},
+		OnDrift: func(changes []Change) { reported = append(reported, changes) },
+	}
+
+	_, _ = set.Update("Log.Level", "debug")
+
+	if changes, err := w.Check(); err != nil || len(changes) != 0 {
+		t.Errorf("Failed to allow expected drift: got %v with %v", err, changes)
+	}
+
+	_, _ = set.Update("Port", "9090")
+	_, _ = w.Check()
+	_, _ = w.Check()
+
+	if len(reported) != 1 || reported[0][0].Path != "Port" || w.Alerts() != 1 {
+		t.Errorf("Failed to alert once on drift: got %v with %d alerts", reported, w.Alerts())
+	}
+
+	// back in line and drifting again alerts again
+	_, _ = set.Update("Port", "8080")
+	_, _ = w.Check()
+	_, _ = set.Update("Port", "9090")
+	_, _ = w.Check()
+
+	if w.Alerts() != 2 {
+		t.Errorf("Failed to alert on new drift: got %d alerts", w.Alerts())
+	}
+}
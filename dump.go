@@ -0,0 +1,334 @@
+package config
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// DumpOption configures the output of Set.Dump
+type DumpOption func(*dumpOptions)
+
+// dumpOptions are the resolved DumpOption values
+type dumpOptions struct {
+	reads       bool
+	annotations bool
+	locale      string
+	relative    bool
+	config      DumpConfig
+}
+
+// DumpReads adds the read count and last read time of every setting to the output, identifying hot settings and cold ones that are candidates for removal
+func DumpReads() DumpOption {
+	return func(o *dumpOptions) {
+		o.reads = true
+	}
+}
+
+// DumpRelative writes the paths of the settings relative to the Set being dumped, so a subset shows HTTP.Port as Port
+func DumpRelative() DumpOption {
+	return func(o *dumpOptions) {
+		o.relative = true
+	}
+}
+
+// DumpAnnotations adds the annotations of every setting to the output as comma separated key=value pairs
+func DumpAnnotations() DumpOption {
+	return func(o *dumpOptions) {
+		o.annotations = true
+	}
+}
+
+// DumpWith configures the layout of the output, so tools get the columns and format they need instead of post-processing the table
+func DumpWith(c DumpConfig) DumpOption {
+	return func(o *dumpOptions) {
+		o.config = c
+	}
+}
+
+// DumpConfig is the layout of the output of Set.Dump, the zero value is the default table
+type DumpConfig struct {
+	// Columns written in order, when empty Path, Type, Value, Default and Description along with the columns added by DumpReads, DumpAnnotations and Metadata
+	Columns []DumpColumn
+
+	// Format of the output, a table by default
+	Format DumpFormat
+
+	// Mask selects which values are written as *****, the masked settings by default
+	Mask MaskPolicy
+
+	// OmitDefaults leaves out the settings that still have their default value, showing only what was configured
+	OmitDefaults bool
+
+	// Metadata adds the Source, Category, Labels and Annotations columns when Columns is empty
+	Metadata bool
+}
+
+// DumpColumn is a column of the output of Set.Dump
+type DumpColumn int
+
+const (
+	// ColumnPath is the path of the setting
+	ColumnPath DumpColumn = iota
+
+	// ColumnType is the Go type of the value
+	ColumnType
+
+	// ColumnValue is the current value, (unset) in a table for an unset Optional
+	ColumnValue
+
+	// ColumnDefault is the default value
+	ColumnDefault
+
+	// ColumnSource is what supplied the value, see Setting.Source
+	ColumnSource
+
+	// ColumnReads is the number of reads, see Setting.Reads
+	ColumnReads
+
+	// ColumnLastRead is the time of the last read in RFC 3339, never when the setting was not read
+	ColumnLastRead
+
+	// ColumnCategory is the category of the setting
+	ColumnCategory
+
+	// ColumnLabels are the labels as a comma separated list
+	ColumnLabels
+
+	// ColumnAnnotations are the annotations as comma separated key=value pairs
+	ColumnAnnotations
+
+	// ColumnDescription is the description, in the locale of DumpLocale
+	ColumnDescription
+)
+
+// dumpColumns are the header and JSON key of every DumpColumn
+var dumpColumns = [...]struct{ header, key string }{
+	ColumnPath:        {"Path", "path"},
+	ColumnType:        {"Type", "type"},
+	ColumnValue:       {"Value", "value"},
+	ColumnDefault:     {"Default Value", "default"},
+	ColumnSource:      {"Source", "source"},
+	ColumnReads:       {"Reads", "reads"},
+	ColumnLastRead:    {"Last Read", "lastRead"},
+	ColumnCategory:    {"Category", "category"},
+	ColumnLabels:      {"Labels", "labels"},
+	ColumnAnnotations: {"Annotations", "annotations"},
+	ColumnDescription: {"Description", "description"},
+}
+
+// String returns the header of the column
+func (c DumpColumn) String() string {
+	if c < 0 || int(c) >= len(dumpColumns) {
+		return "DumpColumn(" + strconv.Itoa(int(c)) + ")"
+	}
+
+	return dumpColumns[c].header
+}
+
+// DumpFormat is the format of the output of Set.Dump
+type DumpFormat int
+
+const (
+	// DumpTable writes aligned columns with quoted values for people
+	DumpTable DumpFormat = iota
+
+	// DumpCSV writes RFC 4180 CSV with a header record
+	DumpCSV
+
+	// DumpJSON writes an array with an object per setting, keyed by the lower camel case name of the column (i.e. lastRead)
+	DumpJSON
+)
+
+// MaskPolicy selects the values Set.Dump writes as *****
+type MaskPolicy int
+
+const (
+	// MaskSecrets masks the values of masked settings (see Setting.Mask) and redacts Redacter values
+	MaskSecrets MaskPolicy = iota
+
+	// MaskAll masks every value, to share the shape of a configuration without its contents
+	MaskAll
+
+	// MaskNone writes every value as is, reads of masked settings are audited (see Set.AuditSecrets)
+	MaskNone
+)
+
+// columns returns the columns of the output
+func (o *dumpOptions) columns() []DumpColumn {
+	if len(o.config.Columns) > 0 {
+		return o.config.Columns
+	}
+
+	columns := []DumpColumn{ColumnPath, ColumnType, ColumnValue, ColumnDefault}
+	if o.reads {
+		columns = append(columns, ColumnReads, ColumnLastRead)
+	}
+	if o.config.Metadata {
+		columns = append(columns, ColumnSource, ColumnCategory, ColumnLabels)
+	}
+	if o.annotations || o.config.Metadata {
+		columns = append(columns, ColumnAnnotations)
+	}
+
+	return append(columns, ColumnDescription)
+}
+
+// dump writes the settings within the Set in the layout of the options
+func (s *Set) dump(w io.Writer, options *dumpOptions) error {
+	settings := []*Setting{}
+	s.Range(func(path string, setting *Setting) bool {
+		if !options.config.OmitDefaults || setting.Source() != SourceDefault {
+			settings = append(settings, setting)
+		}
+		return true
+	})
+
+	// sort by name
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	columns := options.columns()
+	for _, c := range columns {
+		if c < 0 || int(c) >= len(dumpColumns) {
+			return fmt.Errorf("unable to dump: unknown column %v", c)
+		}
+	}
+
+	table := options.config.Format == DumpTable
+	rows := make([][]string, 0, len(settings))
+	for _, setting := range settings {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = s.dumpCell(setting, c, options, table)
+		}
+		rows = append(rows, row)
+	}
+
+	switch options.config.Format {
+	case DumpTable:
+		tw := tabwriter.NewWriter(w, 10, 10, 5, ' ', 0)
+
+		headers := make([]string, len(columns))
+		for i, c := range columns {
+			headers[i] = c.String()
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+
+		return tw.Flush()
+
+	case DumpCSV:
+		cw := csv.NewWriter(w)
+
+		headers := make([]string, len(columns))
+		for i, c := range columns {
+			headers[i] = c.String()
+		}
+		_ = cw.Write(headers)
+		_ = cw.WriteAll(rows)
+
+		return cw.Error()
+
+	case DumpJSON:
+		objects := make([]map[string]string, 0, len(rows))
+		for _, row := range rows {
+			object := make(map[string]string, len(columns))
+			for i, c := range columns {
+				object[dumpColumns[c].key] = row[i]
+			}
+			objects = append(objects, object)
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(objects)
+
+	default:
+		return fmt.Errorf("unable to dump: unknown format %d", options.config.Format)
+	}
+}
+
+// dumpCell returns the column of the setting, values are quoted in a table
+func (s *Set) dumpCell(setting *Setting, c DumpColumn, options *dumpOptions, table bool) string {
+	quote := func(v string) string {
+		if table {
+			return strconv.Quote(v)
+		}
+		return v
+	}
+
+	switch c {
+	case ColumnPath:
+		if options.relative && s.path != "" {
+			return setting.Path[len(s.path)+1:]
+		}
+		return setting.Path
+
+	case ColumnType:
+		return fmt.Sprintf("%T", setting.Value)
+
+	case ColumnValue:
+		if setting.IsUnset() {
+			if table {
+				return "(unset)"
+			}
+			return ""
+		}
+
+		switch options.config.Mask {
+		case MaskAll:
+			return quote("*****")
+		case MaskNone:
+			setting.auditSecret("Set.Dump")
+			return quote(setting.format())
+		default:
+			return quote(setting.String())
+		}
+
+	case ColumnDefault:
+		switch options.config.Mask {
+		case MaskAll:
+			return quote("*****")
+		case MaskNone:
+			return quote(setting.DefaultValue)
+		default:
+			return quote(setting.display(setting.DefaultValue))
+		}
+
+	case ColumnSource:
+		return setting.Source()
+
+	case ColumnReads:
+		return strconv.FormatUint(setting.Reads(), 10)
+
+	case ColumnLastRead:
+		if t := setting.LastRead(); !t.IsZero() {
+			return t.Format(time.RFC3339)
+		}
+		if table {
+			return "never"
+		}
+		return ""
+
+	case ColumnCategory:
+		return setting.Category
+
+	case ColumnLabels:
+		return strings.Join(setting.Labels, ",")
+
+	case ColumnAnnotations:
+		return formatAnnotations(setting.Annotations)
+
+	default:
+		return setting.Describe(options.locale)
+	}
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSet_DumpReads(t *testing.T) {
+	set := &Set{}
+	set.Setting("Hot", 1, "")
+	set.Setting("Cold", 2, "")
+
+	for i := 0; i < 3; i++ {
+		set.Get("Hot")
+	}
+
+	if reads := set.Get("Hot").Reads(); reads != 4 {
+		t.Errorf("Failed to count reads: expected 4; got %d", reads)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := set.Dump(buf, DumpReads()); err != nil {
+		t.Fatalf("Failed to dump: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.Contains(lines[0], "Reads") || !strings.Contains(lines[1], "never") || !strings.Contains(lines[2], " 4 ") {
+		t.Errorf("Failed to dump reads:\n%s", buf.String())
+	}
+}
+
+func TestSet_DumpAnnotations(t *testing.T) {
+	cfg := struct {
+		Port int `meta:"owner=payments, ticket=OPS-42"`
+	}{}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	if owner := set.Get("Port").Annotations["owner"]; owner != "payments" {
+		t.Errorf("Failed to bind annotations: expected %q; got %q", "payments", owner)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := set.Dump(buf, DumpAnnotations()); err != nil {
+		t.Fatalf("Failed to dump: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.Contains(lines[0], "Annotations") || !strings.Contains(lines[1], "owner=payments,ticket=OPS-42") {
+		t.Errorf("Failed to dump annotations:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := set.WriteSchema(buf); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	read, err := ReadSchema(buf)
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	if ticket := read.Get("Port").Annotations["ticket"]; ticket != "OPS-42" {
+		t.Errorf("Failed to carry annotations through schema: expected %q; got %q", "OPS-42", ticket)
+	}
+}
+
+func TestSet_DumpSubset(t *testing.T) {
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", 80, "")
+	set.Subset("HTTPS").Setting("Port", 443, "")
+	set.Subset("HTTP").Subset("TLS").Setting("Cert", "cert.pem", "")
+
+	tests := []struct {
+		name     string
+		opts     []DumpOption
+		expected []string
+	}{
+		{"full", nil, []string{"HTTP.Port", "HTTP.TLS.Cert"}},
+		{"relative", []DumpOption{DumpRelative()}, []string{"Port", "TLS.Cert"}},
+	}
+
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		if err := set.Subset("HTTP").Dump(buf, tt.opts...); err != nil {
+			t.Fatalf("Failed to dump: %v", err)
+		}
+
+		var paths []string
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n")[1:] {
+			paths = append(paths, strings.Fields(line)[0])
+		}
+
+		if strings.Join(paths, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("Failed to dump %s subset: expected %v; got %v", tt.name, tt.expected, paths)
+		}
+	}
+}
+
+func TestSet_DumpWith(t *testing.T) {
+	set := &Set{}
+	set.Setting("Name", "a,b", "the name")
+	port := set.Setting("Port", 8080, "")
+	token := set.Setting("Token", "secret", "")
+	token.Mask = true
+	port.Category = "Networking"
+	_ = port.setFrom(context.Background(), "9090", "config.json")
+
+	buf := &bytes.Buffer{}
+	if err := set.Dump(buf, DumpWith(DumpConfig{Columns: []DumpColumn{ColumnPath, ColumnValue, ColumnSource}, Format: DumpCSV})); err != nil {
+		t.Fatalf("Failed to dump: %v", err)
+	}
+	if expected := "Path,Value,Source\nName,\"a,b\",default\nPort,9090,config.json\nToken,*****,default\n"; buf.String() != expected {
+		t.Errorf("Failed to dump CSV: expected %q; got %q", expected, buf.String())
+	}
+
+	var audited []string
+	set.AuditSecrets(func(a SecretAccess) { audited = append(audited, a.Via) })
+
+	buf.Reset()
+	if err := set.Dump(buf, DumpWith(DumpConfig{Format: DumpJSON, Mask: MaskNone, OmitDefaults: true, Metadata: true})); err != nil {
+		t.Fatalf("Failed to dump: %v", err)
+	}
+	var objects []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &objects); err != nil {
+		t.Fatalf("Failed to decode JSON dump: %v", err)
+	}
+	if len(objects) != 1 || objects[0]["path"] != "Port" || objects[0]["value"] != "9090" || objects[0]["category"] != "Networking" || objects[0]["source"] != "config.json" {
+		t.Errorf("Failed to dump JSON: got %v", objects)
+	}
+
+	buf.Reset()
+	if err := set.Dump(buf, DumpWith(DumpConfig{Columns: []DumpColumn{ColumnValue}, Format: DumpCSV, Mask: MaskNone})); err != nil {
+		t.Fatalf("Failed to dump: %v", err)
+	}
+	if !strings.Contains(buf.String(), "secret") || len(audited) != 1 || audited[0] != "Set.Dump" {
+		t.Errorf("Failed to reveal audited secrets: got %q with %v", buf.String(), audited)
+	}
+
+	buf.Reset()
+	if err := set.Dump(buf, DumpWith(DumpConfig{Columns: []DumpColumn{ColumnPath, ColumnValue, ColumnDefault}, Mask: MaskAll})); err != nil {
+		t.Fatalf("Failed to dump: %v", err)
+	}
+	if strings.Contains(buf.String(), "8080") || strings.Contains(buf.String(), "a,b") || !strings.HasPrefix(buf.String(), "Path ") {
+		t.Errorf("Failed to mask every value:\n%s", buf.String())
+	}
+
+	if err := set.Dump(buf, DumpWith(DumpConfig{Columns: []DumpColumn{42}})); err == nil {
+		t.Error("Failed to reject unknown column")
+	}
+	if ColumnLastRead.String() != "Last Read" || DumpColumn(42).String() != "DumpColumn(42)" {
+		t.Errorf("Failed to name columns: got %q and %q", ColumnLastRead, DumpColumn(42))
+	}
+}
+
+func TestSet_DumpRedacted(t *testing.T) {
+	set := redactedSet(t)
+
+	buf := &bytes.Buffer{}
+	if err := set.Dump(buf); err != nil {
+		t.Fatalf("Failed to dump: %v", err)
+	}
+
+	assertRedacted(t, "dump", buf.String())
+	if !strings.Contains(buf.String(), "postgres://app:*****@db:5432/app") {
+		t.Errorf("Failed to dump redacted value:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// Duration extends time.Duration parsing with day (d) and week (w) units, such as "30d" or "1w2d12h", and ISO-8601 durations such as "P1W" or "PT1H30M". Years and months are not supported as their length varies.
+type Duration time.Duration
+
+// UnmarshalSetting implements Unmarshaler
+func (d *Duration) UnmarshalSetting(v string) error {
+	pv, err := ParseDuration(v)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(pv)
+	return nil
+}
+
+// MarshalSetting implements Marshaler, whole weeks and days are formatted with their units
+func (d *Duration) MarshalSetting() string {
+	v := time.Duration(*d)
+
+	switch {
+	case v == 0:
+		return "0s"
+	case v%week == 0:
+		return strconv.FormatInt(int64(v/week), 10) + "w"
+	case v%day == 0:
+		return strconv.FormatInt(int64(v/day), 10) + "d"
+	default:
+		return v.String()
+	}
+}
+
+// Equals implements Equality
+func (d *Duration) Equals(v string) bool {
+	pv, err := ParseDuration(v)
+	if err != nil {
+		return false
+	}
+
+	return time.Duration(*d) == pv
+}
+
+// ParseDuration parses a duration string like time.ParseDuration, additionally accepting day (d) and week (w) units and ISO-8601 durations
+func ParseDuration(v string) (time.Duration, error) {
+	s := strings.TrimSpace(v)
+
+	neg := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	var (
+		d   time.Duration
+		err error
+	)
+
+	if strings.HasPrefix(s, "P") || strings.HasPrefix(s, "p") {
+		d, err = parseISODuration(s)
+	} else {
+		d, err = parseDayDuration(s)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", v, err)
+	}
+
+	if neg {
+		d = -d
+	}
+
+	return d, nil
+}
+
+// parseDayDuration extracts the day and week components, leaving the remainder to time.ParseDuration
+func parseDayDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var (
+		total time.Duration
+		rest  strings.Builder
+	)
+
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] == '.' || (s[i] >= '0' && s[i] <= '9')) {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("expected number at %q", s)
+		}
+
+		j := i
+		for j < len(s) && s[j] != '.' && (s[j] < '0' || s[j] > '9') {
+			j++
+		}
+
+		number, unit := s[:i], s[i:j]
+		s = s[j:]
+
+		switch unit {
+		case "d", "w":
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, err
+			}
+
+			scale := day
+			if unit == "w" {
+				scale = week
+			}
+
+			if total, err = addDuration(total, n, scale); err != nil {
+				return 0, err
+			}
+		default:
+			rest.WriteString(number)
+			rest.WriteString(unit)
+		}
+	}
+
+	if rest.Len() > 0 {
+		d, err := time.ParseDuration(rest.String())
+		if err != nil {
+			return 0, err
+		}
+		if total, err = addDuration(total, float64(d), 1); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+// parseISODuration parses an ISO-8601 duration (PnWnDTnHnMnS)
+func parseISODuration(s string) (time.Duration, error) {
+	s = strings.ToUpper(s[1:])
+	if s == "" {
+		return 0, fmt.Errorf("missing components")
+	}
+
+	var (
+		total      time.Duration
+		inTime     bool
+		components int
+	)
+
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime {
+				return 0, fmt.Errorf("duplicate T designator")
+			}
+			inTime = true
+			components = 0
+			s = s[1:]
+			continue
+		}
+
+		i := 0
+		for i < len(s) && (s[i] == '.' || s[i] == ',' || (s[i] >= '0' && s[i] <= '9')) {
+			i++
+		}
+		if i == 0 || i == len(s) {
+			return 0, fmt.Errorf("expected number and designator at %q", s)
+		}
+
+		n, err := strconv.ParseFloat(strings.Replace(s[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, err
+		}
+
+		var unit time.Duration
+		switch designator := s[i]; {
+		case !inTime && designator == 'W':
+			unit = week
+		case !inTime && designator == 'D':
+			unit = day
+		case inTime && designator == 'H':
+			unit = time.Hour
+		case inTime && designator == 'M':
+			unit = time.Minute
+		case inTime && designator == 'S':
+			unit = time.Second
+		case designator == 'Y' || designator == 'M':
+			return 0, fmt.Errorf("years and months are not supported")
+		default:
+			return 0, fmt.Errorf("unknown designator %q", designator)
+		}
+
+		if total, err = addDuration(total, n, unit); err != nil {
+			return 0, err
+		}
+		components++
+		s = s[i+1:]
+	}
+
+	if components == 0 {
+		return 0, fmt.Errorf("missing components")
+	}
+
+	return total, nil
+}
+
+// addDuration adds n of the unit to the total, returning an error rather than wrapping around when the result exceeds the range of a time.Duration
+func addDuration(total time.Duration, n float64, unit time.Duration) (time.Duration, error) {
+	v := n * float64(unit)
+	if !(v < float64(math.MaxInt64)) || time.Duration(v) > math.MaxInt64-total {
+		return 0, fmt.Errorf("duration overflows")
+	}
+
+	return total + time.Duration(v), nil
+}
+
+// DurationRange is a range of durations written as "5s..10s", or a single duration for a fixed value, so retry and poll loops can pick a jittered delay from one setting rather than two that can drift out of sync. Both ends accept the units of ParseDuration.
+type DurationRange struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// ParseDurationRange parses a range written as "min..max" or a single duration, the minimum can not exceed the maximum
+func ParseDurationRange(v string) (DurationRange, error) {
+	lower, upper, found := strings.Cut(v, "..")
+	if !found {
+		upper = lower
+	}
+
+	min, err := ParseDuration(lower)
+	if err != nil {
+		return DurationRange{}, err
+	}
+
+	max, err := ParseDuration(upper)
+	if err != nil {
+		return DurationRange{}, err
+	}
+
+	if min > max {
+		return DurationRange{}, fmt.Errorf("invalid duration range %q: minimum exceeds maximum", v)
+	}
+
+	return DurationRange{Min: min, Max: max}, nil
+}
+
+// UnmarshalSetting implements Unmarshaler
+func (r *DurationRange) UnmarshalSetting(v string) error {
+	pv, err := ParseDurationRange(v)
+	if err != nil {
+		return err
+	}
+
+	*r = pv
+	return nil
+}
+
+// MarshalSetting implements Marshaler, a fixed range is formatted as a single duration
+func (r *DurationRange) MarshalSetting() string {
+	min, max := Duration(r.Min), Duration(r.Max)
+	if min == max {
+		return min.MarshalSetting()
+	}
+
+	return min.MarshalSetting() + ".." + max.MarshalSetting()
+}
+
+// Equals implements Equality
+func (r *DurationRange) Equals(v string) bool {
+	pv, err := ParseDurationRange(v)
+	if err != nil {
+		return false
+	}
+
+	return *r == pv
+}
+
+// Jitter returns the width of the range, use it with the Min as the Poller Interval and Jitter
+func (r DurationRange) Jitter() time.Duration {
+	return r.Max - r.Min
+}
+
+// Rand returns a random duration within the range, inclusive of both ends
+func (r DurationRange) Rand() time.Duration {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+
+	return r.Min + time.Duration(rand.Int63n(int64(r.Max-r.Min)+1))
+}
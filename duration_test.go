@@ -0,0 +1,91 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30d":      30 * 24 * time.Hour,
+		"1w2d12h":  (7+2)*24*time.Hour + 12*time.Hour,
+		"1.5d":     36 * time.Hour,
+		"-1d":      -24 * time.Hour,
+		"90m":      90 * time.Minute,
+		"P1W":      7 * 24 * time.Hour,
+		"P1DT12H":  36 * time.Hour,
+		"PT1H30M":  90 * time.Minute,
+		"PT0.5S":   500 * time.Millisecond,
+		"1h30m10s": time.Hour + 30*time.Minute + 10*time.Second,
+	}
+
+	for input, expected := range tests {
+		d, err := ParseDuration(input)
+		if err != nil {
+			t.Errorf("Failed to parse %q: %v", input, err)
+			continue
+		}
+
+		if d != expected {
+			t.Errorf("Failed to parse %q: expected %v; got %v", input, expected, d)
+		}
+	}
+
+	for _, input := range []string{"", "d", "P", "PT", "P1DT", "P1Y", "P1M", "PT1D", "5x", "200000w", "106751d1w", "106751d9000h", "P200000W", "P106751DT9000H"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("Failed to reject invalid duration %q", input)
+		}
+	}
+}
+
+func TestDuration_Setting(t *testing.T) {
+	d := Duration(time.Hour)
+	st := &Setting{Value: &d}
+
+	if err := st.Set("30d"); err != nil {
+		t.Fatalf("Failed to set duration: %v", err)
+	}
+
+	if st.String() != "30d" {
+		t.Errorf("Failed to format duration: expected %q; got %q", "30d", st.String())
+	}
+
+	if !st.Equals("720h") {
+		t.Errorf("Failed to equality check equivalent duration")
+	}
+
+	if err := st.Set("2w"); err != nil || st.String() != "2w" {
+		t.Errorf("Failed to format weeks: got %q (%v)", st.String(), err)
+	}
+}
+
+func TestDurationRange_Setting(t *testing.T) {
+	var r DurationRange
+	st := &Setting{Value: &r}
+
+	if err := st.Set("5s..1m"); err != nil {
+		t.Fatalf("Failed to set range: %v", err)
+	}
+	if r.Min != 5*time.Second || r.Max != time.Minute || r.Jitter() != 55*time.Second {
+		t.Errorf("Failed to parse range: got %v", r)
+	}
+	if st.String() != "5s..1m0s" || !st.Equals("5s..60s") {
+		t.Errorf("Failed to format range: got %q", st.String())
+	}
+
+	for i := 0; i < 100; i++ {
+		if d := r.Rand(); d < r.Min || d > r.Max {
+			t.Fatalf("Failed to pick within range: got %v", d)
+		}
+	}
+
+	if err := st.Set("1d"); err != nil || st.String() != "1d" || r.Rand() != 24*time.Hour {
+		t.Errorf("Failed to set fixed range: got %q (%v)", st.String(), err)
+	}
+
+	for _, invalid := range []string{"10s..5s", "5s..", "fast"} {
+		if err := st.Set(invalid); err == nil {
+			t.Errorf("Failed to reject %q", invalid)
+		}
+	}
+}
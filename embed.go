@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"io/fs"
+	"sort"
+	"time"
+)
+
+// LoadDefaults reads the document name from the file system, typically an embed.FS holding the baseline configuration shipped inside the executable (i.e. //go:embed defaults.yaml), and makes its values the defaults of the matching settings in the Set. As defaults they are the lowest precedence layer whether loaded before or after the other sources: settings still at their default take the value, settings already supplied by another source keep theirs, and Setting.Clear reverts to the embedded value. Restart required settings take the value directly even after Set.Started, as the process starts with it.
+//
+// The document is read in any format of Set.LoadFile, with includes resolved within the file system. Nothing is applied when a value is invalid for its setting, all problems are returned in a *ValidationError. Embedded documents are part of the executable, so they are not verified against the keys of Set.RequireSignatures. LoadDefaults should be called once the settings are registered, before the Set is used concurrently (i.e. from init).
+func (s *Set) LoadDefaults(fsys fs.FS, name string) (err error) {
+	defer func(start time.Time) { s.observe(OpLoad, s.path, name, start, err) }(time.Now())
+
+	values, err := (&fileReader{fsys: fsys}).read(name, nil)
+	if err != nil {
+		return err
+	}
+	values = s.resolveEnvNames(values)
+
+	// like Set.LoadFile, values of settings the Set does not have are skipped
+	for path := range values {
+		if s.lookup(path) == nil {
+			delete(values, path)
+		}
+	}
+
+	if err := s.validate(values, SourceDefault); err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		setting := s.lookup(path)
+
+		// the default is held as the setting formats it, as for WithDefault
+		normalized := setting.clone()
+		_ = normalized.convert(values[path])
+		setting.DefaultValue = normalized.format()
+
+		if setting.Source() != SourceDefault {
+			continue
+		}
+
+		// the process starts with the default, so it is not pending a restart
+		if err := setting.setFrom(baseline(context.Background()), setting.DefaultValue, SourceDefault); err != nil {
+			return &SettingError{Path: path, Err: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSet_LoadDefaults(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/defaults.yaml": {Data: []byte("include: http.json\nLevel: warn\nUnknown: true\n")},
+		"config/http.json":     {Data: []byte(`{"HTTP": {"Port": 8080, "Timeout": "1m"}}`)},
+		"invalid.json":         {Data: []byte(`{"Level": "warn", "HTTP": {"Port": "eighty"}}`)},
+	}
+
+	var (
+		level   = "info"
+		port    = 80
+		timeout = time.Second
+	)
+
+	set := &Set{}
+	set.Setting("Level", &level, "")
+	set.Subset("HTTP").Setting("Port", &port, "")
+	set.Subset("HTTP").Setting("Timeout", &timeout, "")
+
+	// a value supplied before the defaults is kept
+	if _, err := set.Update("HTTP.Port", "9090"); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	if err := set.LoadDefaults(fsys, "config/defaults.yaml"); err != nil {
+		t.Fatalf("Failed to load defaults: %v", err)
+	}
+
+	if level != "warn" || timeout != time.Minute || port != 9090 {
+		t.Errorf("Failed to apply defaults: got %q, %v and %d", level, timeout, port)
+	}
+
+	if setting := set.Get("HTTP.Timeout"); setting.DefaultValue != "1m0s" || setting.Source() != SourceDefault {
+		t.Errorf("Failed to record default: got %q from %q", setting.DefaultValue, setting.Source())
+	}
+
+	// later sources override the defaults, and clearing reverts to them
+	if _, err := set.Update("Level", "debug"); err != nil || level != "debug" {
+		t.Errorf("Failed to override default: got %v with %q", err, level)
+	}
+
+	if err := set.Get("HTTP.Port").Clear(); err != nil || port != 8080 {
+		t.Errorf("Failed to clear to default: got %v with %d", err, port)
+	}
+
+	// nothing is applied from an invalid document
+	var validationErr *ValidationError
+	if err := set.LoadDefaults(fsys, "invalid.json"); !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 {
+		t.Fatalf("Failed to reject invalid defaults: got %v", err)
+	}
+
+	if setting := set.Get("Level"); setting.DefaultValue != "warn" {
+		t.Errorf("Failed to leave defaults untouched: got %q", setting.DefaultValue)
+	}
+
+	if err := set.LoadDefaults(fsys, "missing.json"); err == nil {
+		t.Errorf("Failed to report missing document")
+	}
+}
+
+func TestSet_LoadDefaultsRestartRequired(t *testing.T) {
+	fsys := fstest.MapFS{"defaults.json": {Data: []byte(`{"Workers": 8}`)}}
+
+	workers := 4
+	set := &Set{}
+	set.Setting("Workers", &workers, "").RestartRequired = true
+	set.Started()
+
+	if err := set.LoadDefaults(fsys, "defaults.json"); err != nil {
+		t.Fatalf("Failed to load defaults: %v", err)
+	}
+
+	if pending := set.PendingRestart(); workers != 8 || len(pending) != 0 {
+		t.Errorf("Failed to apply default without restart: got %d with %+v", workers, pending)
+	}
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvName returns the environment variable name for the setting path with an optional prefix. Path segments and the words of camelCase names are joined by underscores in upper case, so with the prefix APP the path HTTP.MaxConns is APP_HTTP_MAX_CONNS.
+func EnvName(prefix, path string) string {
+	var words []string
+	if prefix != "" {
+		words = append(words, strings.ToUpper(prefix))
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		for _, word := range splitWords(segment) {
+			if word != "" {
+				words = append(words, strings.ToUpper(word))
+			}
+		}
+	}
+
+	return strings.Join(words, "_")
+}
+
+// EnvPrefix declares the prefix of the environment variables of the settings within the Set, overriding the prefix passed to Set.LoadEnv for them. Variables are named relative to the Set, so with the prefix KAFKA the setting Kafka.Brokers is KAFKA_BROKERS rather than APP_KAFKA_BROKERS, keeping historical variable names while composing into one tree. The Set is returned for chaining.
+func (s *Set) EnvPrefix(prefix string) *Set {
+	s.envPrefix.Store(prefix)
+	return s
+}
+
+// EnvName returns the environment variable of the setting read by Set.LoadEnv with the prefix, see EnvName and Set.EnvPrefix
+func (s *Setting) EnvName(prefix string) string {
+	return envName(prefix, s.set, s.Path)
+}
+
+// envName returns the environment variable of the path within the set, named relative to the nearest Set declaring a prefix with Set.EnvPrefix or by EnvName with the prefix otherwise
+func envName(prefix string, set *Set, path string) string {
+	for p := set; p != nil; p = p.parent {
+		own, ok := p.envPrefix.Load().(string)
+		if !ok {
+			continue
+		}
+
+		if p.path != "" {
+			path = strings.TrimPrefix(strings.TrimPrefix(path, p.path), ".")
+		}
+
+		return EnvName(own, path)
+	}
+
+	return EnvName(prefix, path)
+}
+
+// EnvConflictError is returned when settings of the Set are named by the same environment variable, i.e. when subsets declare the same prefix with Set.EnvPrefix
+type EnvConflictError struct {
+	Name  string
+	Paths []string
+}
+
+func (e *EnvConflictError) Error() string {
+	return fmt.Sprintf("environment variable %s names settings %s", e.Name, strings.Join(e.Paths, ", "))
+}
+
+// envNames returns the environment variable of every setting of the Set sorted by path, failing with an *EnvConflictError when a variable names more than one setting
+func (s *Set) envNames(prefix string) ([]*Setting, []string, error) {
+	settings := s.sorted()
+	names := make([]string, len(settings))
+	paths := map[string][]string{}
+
+	for i, setting := range settings {
+		names[i] = setting.EnvName(prefix)
+		paths[names[i]] = append(paths[names[i]], setting.Path)
+	}
+
+	for _, name := range names {
+		if len(paths[name]) > 1 {
+			return nil, nil, &EnvConflictError{Name: name, Paths: paths[name]}
+		}
+	}
+
+	return settings, names, nil
+}
+
+// LoadEnv updates every setting of the Set that has an environment variable, named by EnvName with the prefix (or the prefix of its subset, see Set.EnvPrefix), stopping on the first error. Nothing is loaded when a variable names more than one setting. Elements of bound slices of structs are named by their index, so APP_SERVERS_1_ADDR is the Addr of the second element of Servers.
+func (s *Set) LoadEnv(prefix string) (err error) {
+	done := s.timeLoad(OpLoad, s.path, SourceEnv)
+	defer func() { done(err) }()
+
+	s.expandEnv(prefix)
+
+	settings, names, err := s.envNames(prefix)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{}
+	for i, setting := range settings {
+		if value, found := os.LookupEnv(names[i]); found {
+			values[setting.Path] = value
+		}
+	}
+
+	return s.Root().updateAll(values, SourceEnv)
+}
+
+// expandEnv binds the elements of the slices of structs within the Set named by an environment variable, so they are loaded like any other setting
+func (s *Set) expandEnv(prefix string) {
+	environ := os.Environ()
+	root := s.Root()
+
+	// elements may hold slices of their own, which are bound while expanding
+	for i := 0; ; i++ {
+		root.mu.Lock()
+		if i >= len(root.mapBindings) {
+			root.mu.Unlock()
+			return
+		}
+		b := root.mapBindings[i]
+		root.mu.Unlock()
+
+		if b.value.Kind() != reflect.Slice || !s.contains(b.set.path) {
+			continue
+		}
+
+		name := envName(prefix, b.set, b.set.path) + "_"
+		for _, kv := range environ {
+			key, _, _ := strings.Cut(kv, "=")
+			if !strings.HasPrefix(key, name) {
+				continue
+			}
+
+			if index, _, found := strings.Cut(key[len(name):], "_"); found {
+				b.bind(index)
+			}
+		}
+	}
+}
+
+// EnvFormat is the format written by Set.WriteEnv
+type EnvFormat int
+
+const (
+	// EnvCompose writes a docker-compose environment block, masked settings and values with secret parts (see Redacter) reference a variable of the same name from the host so secrets are not written
+	EnvCompose EnvFormat = iota
+
+	// EnvDockerfile writes Dockerfile ENV lines, masked settings and values with secret parts are left as comments so secrets are never baked into an image
+	EnvDockerfile
+)
+
+// WriteEnv writes the current value of every setting of the Set as environment variables, named like Set.LoadEnv, in the format. This eases containerizing a service built on this package.
+func (s *Set) WriteEnv(w io.Writer, f EnvFormat, prefix string) error {
+	bw := bufio.NewWriter(w)
+
+	switch f {
+	case EnvCompose:
+		fmt.Fprintln(bw, "environment:")
+	case EnvDockerfile:
+	default:
+		return fmt.Errorf("unsupported env format %d", f)
+	}
+
+	settings, names, err := s.envNames(prefix)
+	if err != nil {
+		return err
+	}
+
+	for i, setting := range settings {
+		name := names[i]
+
+		switch f {
+		case EnvCompose:
+			if setting.sensitive() {
+				fmt.Fprintf(bw, "  %s: ${%s}\n", name, name)
+			} else {
+				fmt.Fprintf(bw, "  %s: %s\n", name, strconv.Quote(setting.format()))
+			}
+		case EnvDockerfile:
+			if setting.sensitive() {
+				fmt.Fprintf(bw, "# %s is a secret and must be provided at runtime\n", name)
+			} else {
+				fmt.Fprintf(bw, "ENV %s=%s\n", name, strconv.Quote(setting.format()))
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// sorted returns the settings of the Set sorted by path
+func (s *Set) sorted() []*Setting {
+	var settings []*Setting
+	s.Range(func(_ string, setting *Setting) bool {
+		settings = append(settings, setting)
+		return true
+	})
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	return settings
+}
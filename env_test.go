@@ -0,0 +1,179 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		path     string
+		expected string
+	}{
+		{"", "Port", "PORT"},
+		{"APP", "HTTP.MaxConns", "APP_HTTP_MAX_CONNS"},
+		{"app", "TLSCert.key-file", "APP_TLS_CERT_KEY_FILE"},
+		{"", "OAuth2Token", "O_AUTH2_TOKEN"},
+	}
+
+	for _, tt := range tests {
+		if actual := EnvName(tt.prefix, tt.path); actual != tt.expected {
+			t.Errorf("Failed to name %q: expected %q; got %q", tt.path, tt.expected, actual)
+		}
+	}
+}
+
+func TestSet_LoadEnv(t *testing.T) {
+	cfg := struct {
+		MaxConns int
+		Host     string
+	}{MaxConns: 10, Host: "localhost"}
+
+	set := &Set{}
+	set.Subset("HTTP").Bind(&cfg)
+
+	t.Setenv("APP_HTTP_MAX_CONNS", "100")
+
+	if err := set.LoadEnv("APP"); err != nil {
+		t.Fatalf("Failed to load env: %v", err)
+	}
+
+	if cfg.MaxConns != 100 || cfg.Host != "localhost" {
+		t.Errorf("Failed to load env: got %d and %q", cfg.MaxConns, cfg.Host)
+	}
+}
+
+func TestSet_WriteEnv(t *testing.T) {
+	cfg := struct {
+		Port     int
+		Password string `mask:"true"`
+	}{Port: 8080, Password: "secret"}
+
+	set := &Set{}
+	set.Subset("DB").Bind(&cfg)
+
+	tests := []struct {
+		format   EnvFormat
+		expected string
+	}{
+		{EnvCompose, "environment:\n  APP_DB_PASSWORD: ${APP_DB_PASSWORD}\n  APP_DB_PORT: \"8080\"\n"},
+		{EnvDockerfile, "# APP_DB_PASSWORD is a secret and must be provided at runtime\nENV APP_DB_PORT=\"8080\"\n"},
+	}
+
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		if err := set.WriteEnv(buf, tt.format, "APP"); err != nil {
+			t.Fatalf("Failed to write env: %v", err)
+		}
+
+		if buf.String() != tt.expected {
+			t.Errorf("Failed to write env:\nexpected:\n%s\ngot:\n%s", tt.expected, buf.String())
+		}
+	}
+}
+
+func TestSet_LoadEnvIndexed(t *testing.T) {
+	type Server struct {
+		Addr   string
+		Weight int
+	}
+
+	cfg := struct {
+		Servers []Server
+		Backups []*Server
+	}{
+		Servers: []Server{{Addr: "localhost:80", Weight: 1}},
+	}
+
+	set := (&Set{}).Bind(&cfg)
+
+	t.Setenv("APP_SERVERS_0_WEIGHT", "5")
+	t.Setenv("APP_SERVERS_2_ADDR", "c:80")
+	t.Setenv("APP_SERVERS_1_ADDR", "b:80")
+	t.Setenv("APP_BACKUPS_0_ADDR", "backup:80")
+
+	if err := set.LoadEnv("APP"); err != nil {
+		t.Fatalf("Failed to load env: %v", err)
+	}
+
+	expected := []Server{{Addr: "localhost:80", Weight: 5}, {Addr: "b:80"}, {Addr: "c:80"}}
+	if !reflect.DeepEqual(cfg.Servers, expected) {
+		t.Errorf("Failed to load indexed env: expected %+v; got %+v", expected, cfg.Servers)
+	}
+
+	if len(cfg.Backups) != 1 || cfg.Backups[0].Addr != "backup:80" {
+		t.Errorf("Failed to load indexed env into pointers: got %+v", cfg.Backups)
+	}
+
+	// elements bound before the slice grew are still copied back
+	if _, err := set.Update("Servers.0.Addr", "a:80"); err != nil || cfg.Servers[0].Addr != "a:80" {
+		t.Errorf("Failed to update element: got %+v (%v)", cfg.Servers, err)
+	}
+
+	if found, _ := set.Update("Servers.5000.Addr", "x"); found {
+		t.Errorf("Failed to bound slice growth")
+	}
+}
+
+func TestSet_EnvPrefix(t *testing.T) {
+	set := &Set{}
+	set.Subset("Kafka").EnvPrefix("KAFKA").Setting("Brokers", "localhost:9092", "")
+	set.Subset("HTTP").Setting("Port", 80, "")
+
+	t.Setenv("KAFKA_BROKERS", "kafka:9092")
+	t.Setenv("APP_HTTP_PORT", "8080")
+	t.Setenv("APP_KAFKA_BROKERS", "ignored:9092")
+
+	if err := set.LoadEnv("APP"); err != nil {
+		t.Fatalf("Failed to load env: %v", err)
+	}
+
+	if v := set.Get("Kafka.Brokers").Value; v != "kafka:9092" {
+		t.Errorf("Failed to load prefixed subset: expected %q; got %q", "kafka:9092", v)
+	}
+
+	if v := set.Get("HTTP.Port").Value; v != 8080 {
+		t.Errorf("Failed to load env: expected %d; got %v", 8080, v)
+	}
+
+	if name := set.Get("Kafka.Brokers").EnvName("APP"); name != "KAFKA_BROKERS" {
+		t.Errorf("Failed to name setting: expected %q; got %q", "KAFKA_BROKERS", name)
+	}
+}
+
+func TestSet_EnvPrefixConflict(t *testing.T) {
+	set := &Set{}
+	set.Subset("Kafka").EnvPrefix("BROKER").Setting("Hosts", "a", "")
+	set.Subset("Rabbit").EnvPrefix("BROKER").Setting("Hosts", "b", "")
+
+	t.Setenv("BROKER_HOSTS", "c")
+
+	var conflict *EnvConflictError
+	if err := set.LoadEnv(""); !errors.As(err, &conflict) {
+		t.Fatalf("Failed to detect conflict: got %v", err)
+	}
+
+	if conflict.Name != "BROKER_HOSTS" || !reflect.DeepEqual(conflict.Paths, []string{"Kafka.Hosts", "Rabbit.Hosts"}) {
+		t.Errorf("Failed to report conflict: got %q and %v", conflict.Name, conflict.Paths)
+	}
+
+	if v := set.Get("Kafka.Hosts").Value; v != "a" {
+		t.Errorf("Failed to leave settings untouched: expected %q; got %q", "a", v)
+	}
+}
+
+func TestSet_WriteEnvRedacted(t *testing.T) {
+	set := redactedSet(t)
+
+	for _, f := range []EnvFormat{EnvCompose, EnvDockerfile} {
+		buf := &bytes.Buffer{}
+		if err := set.WriteEnv(buf, f, ""); err != nil {
+			t.Fatalf("Failed to write env: %v", err)
+		}
+
+		assertRedacted(t, "env", buf.String())
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"sync"
+)
+
+// Events returns a channel receiving every Event in the Set tree (see Hook) until the context is done, at which point the channel is closed. Events are queued rather than dropped so the stream can be used to mirror the state of the Set, and a slow reader never blocks operations on the Set.
+func (s *Set) Events(ctx context.Context) <-chan Event {
+	var (
+		mu     sync.Mutex
+		queue  []Event
+		signal = make(chan struct{}, 1)
+		ch     = make(chan Event)
+	)
+
+	handle := s.Hook(HookFunc(func(e Event) {
+		mu.Lock()
+		queue = append(queue, e)
+		mu.Unlock()
+
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}))
+
+	go func() {
+		defer close(ch)
+		defer handle.Close()
+
+		for {
+			mu.Lock()
+			pending := queue
+			queue = nil
+			mu.Unlock()
+
+			for _, e := range pending {
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-signal:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSet_Events(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	set := &Set{}
+	events := set.Events(ctx)
+
+	port := 80
+	scope := set.Scope("Plugin")
+	scope.Set().Setting("Port", &port, "")
+	_, _ = set.Update("Plugin.Port", "8080")
+	_ = scope.Close()
+
+	expected := []Op{OpRegister, OpSet, OpRemove}
+	for _, op := range expected {
+		select {
+		case e := <-events:
+			if e.Op != op || e.Path != "Plugin.Port" {
+				t.Errorf("Failed to receive event: expected %s Plugin.Port; got %s %s", op, e.Op, e.Path)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Failed to receive event: expected %s", op)
+		}
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		for ok {
+			_, ok = <-events
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Failed to close events channel")
+	}
+}
@@ -0,0 +1,450 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// includeKey is the document key used to reference other documents
+const includeKey = "include"
+
+// LoadFile reads the document at path and updates the matching settings in the Set. Nested objects are resolved as subsets, so {"HTTP": {"Port": 8080}} will update HTTP.Port.
+//
+// The format is selected by the extension: .json, .yaml or .yml, .toml, .ini (or .cfg and .conf) and .env (or a name starting with .env). Documents with any other extension are sniffed by their content. YAML and TOML are read in their commonly used subset: block and flow collections and plain, quoted and block scalars for YAML, tables, arrays of tables, dotted keys, arrays and inline tables for TOML. INI sections and dotted keys nest like objects. The keys of env documents are the environment variable names of the settings (see Setting.EnvName) or their paths.
+//
+// A document can reference other documents with the "include" key (a string or a list of strings), or in YAML with a value tagged !include (i.e. TLS: !include tls.yaml), resolved relative to the including document. Included values are placed under the object containing the include, or the key of the tag, and the including document overrides them. A path written twice by the same document, such as by a dotted and a nested key, is an error.
+func (s *Set) LoadFile(path string) (err error) {
+	defer func(start time.Time) { s.observe(OpLoad, s.path, path, start, err) }(time.Now())
+	done := s.timeLoad(OpLoad, s.path, path)
+	defer func() { done(err) }()
+
+	values, err := s.reader().read(path, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.updateAll(values, path)
+}
+
+// LoadFileDigest reads the document at path in any format of Set.LoadFile, failing without applying anything when the hex encoded SHA-256 digest of the document does not match the expected digest (i.e. from a deployment manifest). Included documents are not covered by the digest.
+func (s *Set) LoadFileDigest(path, digest string) (err error) {
+	defer func(start time.Time) { s.observe(OpLoad, s.path, path, start, err) }(time.Now())
+	done := s.timeLoad(OpLoad, s.path, path)
+	defer func() { done(err) }()
+
+	r := s.reader()
+	r.digest = digest
+
+	values, err := r.read(path, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.updateAll(values, path)
+}
+
+// ReadFile reads the document at path in any format of Set.LoadFile, resolving includes, into a map of values keyed by their dot separated path without applying them to a Set
+func ReadFile(path string) (map[string]string, error) {
+	return readFile(path, nil)
+}
+
+// updateAll will update all of the supplied path/value pairs from the source in sorted order, stopping on the first error
+func (s *Set) updateAll(values map[string]string, source string) error {
+	values = s.resolveEnvNames(values)
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// constraints see the other values of the document rather than the current ones, like Set.ValidateFile
+	pending := make(map[string]string, len(values))
+	changed := map[*Setting]bool{}
+	for path, v := range values {
+		if setting := s.lookup(path); setting != nil {
+			pending[strings.ToLower(setting.Path)] = v
+			if !setting.Equals(v) {
+				changed[setting] = true
+			}
+		}
+	}
+	lookup := func(path string) (string, bool) {
+		v, found := pending[strings.ToLower(path)]
+		return v, found
+	}
+
+	// settings constrained by the changed ones must still be satisfied by the document, nothing is applied when they are not
+	for _, dependent := range s.dependents(changed) {
+		if _, found := pending[strings.ToLower(dependent.Path)]; found {
+			continue
+		}
+
+		if err := dependent.constrain(dependent.format(), lookup); err != nil {
+			return fmt.Errorf("unable to update %q: %w", dependent.Path, err)
+		}
+	}
+
+	ctx := withDocument(context.Background(), lookup)
+	for _, path := range paths {
+		setting := s.lookup(path)
+		if setting == nil {
+			continue
+		}
+
+		if err := setting.setFrom(ctx, values[path], source); err != nil {
+			return fmt.Errorf("unable to update %q: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// resolveEnvNames replaces the keys of the values that are the environment variable names of settings within the Set, as in env documents, by their path relative to the Set. Names are those of Setting.EnvName without a prefix, for the full path or the path relative to the Set.
+func (s *Set) resolveEnvNames(values map[string]string) map[string]string {
+	var names map[string]string
+	resolved := make(map[string]string, len(values))
+
+	for key, v := range values {
+		if isEnvName(key) && s.lookup(key) == nil {
+			if names == nil {
+				names = map[string]string{}
+				for _, setting := range s.sorted() {
+					relative := setting.Path
+					if s.path != "" {
+						relative = relative[len(s.path)+1:]
+					}
+
+					names[setting.EnvName("")] = relative
+					names[EnvName("", relative)] = relative
+				}
+			}
+
+			if path, found := names[key]; found {
+				key = path
+			}
+		}
+
+		resolved[key] = v
+	}
+
+	return resolved
+}
+
+// readFile decodes the document at path into a flat map of dot separated paths, the stack holds the documents currently being read to detect include cycles
+func readFile(path string, stack []string) (map[string]string, error) {
+	return (&fileReader{}).read(path, stack)
+}
+
+// fileReader reads documents and the documents they include
+type fileReader struct {
+	// verify, when not nil, is called with the contents of every document before it is decoded
+	verify func(path string, data []byte) error
+
+	// digest, when not empty, is the expected hex encoded SHA-256 of the top level document
+	digest string
+
+	// fsys, when not nil, is the file system documents are read from instead of the operating system, with slash separated paths
+	fsys fs.FS
+}
+
+// read decodes the document at path into a flat map of dot separated paths, the stack holds the documents currently being read to detect include cycles
+func (r *fileReader) read(path string, stack []string) (map[string]string, error) {
+	if r.fsys != nil {
+		return r.readFS(path, stack)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve %q: %w", path, err)
+	}
+
+	for _, p := range stack {
+		if p == abs {
+			return nil, fmt.Errorf("include cycle detected: %s -> %s", strings.Join(stack, " -> "), abs)
+		}
+	}
+	stack = append(stack, abs)
+
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %q: %w", path, err)
+	}
+
+	if r.digest != "" && len(stack) == 1 {
+		if err := checkDigest(r.digest, data); err != nil {
+			return nil, fmt.Errorf("unable to verify %q: %w", path, err)
+		}
+	}
+
+	if r.verify != nil {
+		if err := r.verify(abs, data); err != nil {
+			return nil, fmt.Errorf("unable to verify %q: %w", path, err)
+		}
+	}
+
+	return r.decode(path, filepath.Dir(abs), data, stack)
+}
+
+// readFS decodes the document at the slash separated name within the fsys of the reader like read
+func (r *fileReader) readFS(name string, stack []string) (map[string]string, error) {
+	name = pathpkg.Clean(name)
+
+	for _, p := range stack {
+		if p == name {
+			return nil, fmt.Errorf("include cycle detected: %s -> %s", strings.Join(stack, " -> "), name)
+		}
+	}
+	stack = append(stack, name)
+
+	data, err := fs.ReadFile(r.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %q: %w", name, err)
+	}
+
+	return r.decode(name, pathpkg.Dir(name), data, stack)
+}
+
+// decode the data of the document at path into a flat map of dot separated paths, includes are resolved relative to dir and rejected when dir is empty (i.e. for remote documents)
+func (r *fileReader) decode(path, dir string, data []byte, stack []string) (map[string]string, error) {
+	document, err := decodeDocument(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %q: %w", path, err)
+	}
+
+	if _, ok := document.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("unable to decode %q: document must be an object", path)
+	}
+
+	values := &flatValues{values: map[string]string{}, paths: map[string]string{}, own: map[string]bool{}}
+	if err := r.flatten(dir, "", document, values, stack); err != nil {
+		return nil, fmt.Errorf("unable to decode %q: %w", path, err)
+	}
+
+	return values.values, nil
+}
+
+// flatValues collects the values of a document keyed by their dot separated path, paths differing only in case are the same path
+type flatValues struct {
+	values map[string]string
+
+	// paths of the values by their lower case path
+	paths map[string]string
+
+	// own holds the lower case paths written by the document itself rather than a document it includes
+	own map[string]bool
+}
+
+// set the value for the path, a value of the document itself overrides an included one and can not be written twice (i.e. by a dotted and a nested key)
+func (f *flatValues) set(path, v string, own bool) error {
+	lower := strings.ToLower(path)
+
+	if f.own[lower] {
+		if !own {
+			return nil
+		}
+		return fmt.Errorf("conflicting values for %q", path)
+	}
+	if own {
+		f.own[lower] = true
+	}
+
+	if existing, found := f.paths[lower]; found {
+		delete(f.values, existing)
+	}
+	f.paths[lower] = path
+	f.values[path] = v
+
+	return nil
+}
+
+// flatten walks the decoded document writing all values to out keyed by their dot separated path, keys are walked in order so the result never depends on the order of a map
+func (r *fileReader) flatten(dir, prefix string, value interface{}, out *flatValues, stack []string) error {
+	join := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		if include, found := val[includeKey]; found {
+			var files []string
+			switch inc := include.(type) {
+			case string:
+				files = append(files, inc)
+			case []interface{}:
+				for _, f := range inc {
+					name, ok := f.(string)
+					if !ok {
+						return fmt.Errorf("include %v must be a string", f)
+					}
+					files = append(files, name)
+				}
+			default:
+				return fmt.Errorf("include must be a string or list of strings")
+			}
+
+			if err := r.include(dir, prefix, files, out, stack); err != nil {
+				return err
+			}
+		}
+
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			if k != includeKey {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if err := r.flatten(dir, join(k), val[k], out, stack); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		for i, v := range val {
+			if err := r.flatten(dir, join(strconv.Itoa(i)), v, out, stack); err != nil {
+				return err
+			}
+		}
+
+	case yamlInclude:
+		return r.include(dir, prefix, []string{string(val)}, out, stack)
+
+	case nil:
+		// null leaves the setting untouched
+
+	case json.Number:
+		return out.set(prefix, val.String(), true)
+	case string:
+		return out.set(prefix, val, true)
+	case bool:
+		return out.set(prefix, strconv.FormatBool(val), true)
+
+	default:
+		return fmt.Errorf("unsupported value %v at %q", val, prefix)
+	}
+
+	return nil
+}
+
+// include reads the files, relative to dir, placing their values under the prefix
+func (r *fileReader) include(dir, prefix string, files []string, out *flatValues, stack []string) error {
+	if dir == "" {
+		return fmt.Errorf("include is not supported by remote documents")
+	}
+
+	for _, file := range files {
+		switch {
+		case r.fsys != nil:
+			file = pathpkg.Join(dir, file)
+		case !filepath.IsAbs(file):
+			file = filepath.Join(dir, file)
+		}
+
+		included, err := r.read(file, stack)
+		if err != nil {
+			return err
+		}
+
+		// sorted, as paths differing only in case replace each other
+		paths := make([]string, 0, len(included))
+		for k := range included {
+			paths = append(paths, k)
+		}
+		sort.Strings(paths)
+
+		for _, k := range paths {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			if err := out.set(path, included[k], false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SaveFile writes the values, keyed by dot separated path, to the file at path as a JSON document that can be read by Set.LoadFile. The file is written with 0600 permissions as it may contain secrets.
+func SaveFile(path string, values map[string]string) error {
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+
+	// sorted so a value is always placed before any values beneath it
+	sort.Strings(paths)
+
+	document := map[string]interface{}{}
+	for _, p := range paths {
+		nest(document, strings.Split(p, "."), values[p])
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode %q: %w", path, err)
+	}
+
+	if err := writeAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("unable to write %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// nest places the value in the document as nested objects, a path conflicting with an existing value is kept as a dotted key which Set.LoadFile resolves the same way
+func nest(document map[string]interface{}, segments []string, value string) {
+	for i, segment := range segments[:len(segments)-1] {
+		child, found := document[segment]
+		if !found {
+			child = map[string]interface{}{}
+			document[segment] = child
+		}
+
+		object, ok := child.(map[string]interface{})
+		if !ok {
+			document[strings.Join(segments[i:], ".")] = value
+			return
+		}
+
+		document = object
+	}
+
+	document[segments[len(segments)-1]] = value
+}
+
+// writeAtomic replaces the file at path with the data, writing it to a temporary file first so readers never see a partial file
+func writeAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %q: %v", name, err)
+		}
+	}
+
+	return dir
+}
+
+func TestSet_LoadFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app.json":        `{"Name": "app", "HTTP": {"include": "shared/tls.json", "Port": 8080, "TLS": {"Enabled": false}}}`,
+		"shared/tls.json": `{"TLS": {"Enabled": true, "Cert": "server.pem"}}`,
+	})
+
+	var (
+		name    = "default"
+		port    = 80
+		enabled = false
+		cert    = ""
+	)
+
+	set := &Set{}
+	set.Setting("Name", &name, "")
+	set.Subset("HTTP").Setting("Port", &port, "")
+	set.Subset("HTTP").Subset("TLS").Setting("Enabled", &enabled, "")
+	set.Subset("HTTP").Subset("TLS").Setting("Cert", &cert, "")
+
+	if err := set.LoadFile(filepath.Join(dir, "app.json")); err != nil {
+		t.Fatalf("Failed to load file: %v", err)
+	}
+
+	if name != "app" || port != 8080 || cert != "server.pem" {
+		t.Errorf("Failed to load values: got name %q, port %d, cert %q", name, port, cert)
+	}
+
+	// the including document overrides the included one
+	if enabled {
+		t.Errorf("Failed to override included value: expected %v; got %v", false, enabled)
+	}
+}
+
+func TestSet_LoadFileIncludeTag(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app.yaml":        "HTTP:\n  Port: 8080\n  TLS: !include shared/tls.yaml\n  TLS.Enabled: false\n",
+		"shared/tls.yaml": "Enabled: true\nCert: \"server.pem\"\n",
+		"tagged.yaml":     "HTTP:\n  Port: !int 8080\n",
+	})
+
+	var (
+		port    = 80
+		enabled = false
+		cert    = ""
+	)
+
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", &port, "")
+	set.Subset("HTTP").Subset("TLS").Setting("Enabled", &enabled, "")
+	set.Subset("HTTP").Subset("TLS").Setting("Cert", &cert, "")
+
+	if err := set.LoadFile(filepath.Join(dir, "app.yaml")); err != nil {
+		t.Fatalf("Failed to load file: %v", err)
+	}
+
+	if port != 8080 || cert != "server.pem" || enabled {
+		t.Errorf("Failed to load included values: got port %d, cert %q, enabled %v", port, cert, enabled)
+	}
+
+	if err := set.LoadFile(filepath.Join(dir, "tagged.yaml")); err == nil || !strings.Contains(err.Error(), "tags other than !include") {
+		t.Errorf("Failed to reject other tags: got %v", err)
+	}
+}
+
+func TestSet_LoadFileConflict(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"dotted.json": `{"HTTP.Port": 8080, "HTTP": {"Port": 9090}}`,
+		"case.json":   `{"http": {"port": 8080}, "HTTP": {"Port": 9090}}`,
+	})
+
+	port := 80
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", &port, "")
+
+	for _, name := range []string{"dotted.json", "case.json"} {
+		// every order of the keys must fail rather than pick a winner
+		for i := 0; i < 10; i++ {
+			err := set.LoadFile(filepath.Join(dir, name))
+			if err == nil || !strings.Contains(err.Error(), "conflicting values") {
+				t.Fatalf("Failed to reject conflicting keys in %s: got %v", name, err)
+			}
+		}
+	}
+
+	if port != 80 {
+		t.Errorf("Failed to leave setting untouched: got %d", port)
+	}
+}
+
+func TestSet_LoadFileIncludeCycle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.json": `{"include": "b.json"}`,
+		"b.json": `{"include": ["a.json"]}`,
+	})
+
+	err := (&Set{}).LoadFile(filepath.Join(dir, "a.json"))
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("Failed to detect include cycle: got %v", err)
+	}
+}
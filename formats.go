@@ -0,0 +1,981 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// document formats read by Set.LoadFile
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+	formatINI  = "ini"
+	formatEnv  = "env"
+)
+
+// decodeDocument decodes the data of the document at path in the format of its extension, or the format its content looks like
+func decodeDocument(path string, data []byte) (interface{}, error) {
+	switch detectFormat(path, data) {
+	case formatYAML:
+		return decodeYAML(data)
+	case formatTOML:
+		return decodeTOML(data)
+	case formatINI:
+		return decodeINI(data)
+	case formatEnv:
+		return decodeEnv(data)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			return nil, err
+		}
+
+		return document, nil
+	}
+}
+
+// detectFormat returns the format of the document at path by its extension, sniffing the data when the extension is unknown
+func detectFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return formatJSON
+	case ".yaml", ".yml":
+		return formatYAML
+	case ".toml":
+		return formatTOML
+	case ".ini", ".cfg", ".conf":
+		return formatINI
+	case ".env":
+		return formatEnv
+	}
+
+	// i.e. .env.local
+	if strings.HasPrefix(filepath.Base(path), ".env") {
+		return formatEnv
+	}
+
+	return sniffFormat(data)
+}
+
+// sniffFormat guesses the format of the data: objects are JSON, KEY=value lines are env, sections or assignments are TOML when every value is typed and INI otherwise, anything else is YAML
+func sniffFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] == '{' {
+		return formatJSON
+	}
+
+	var assignments, sections, others int
+	env, typed := true, true
+
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' || line == "---" {
+			continue
+		}
+
+		if line[0] == '[' && strings.HasSuffix(line, "]") {
+			sections++
+			env = false
+			continue
+		}
+
+		if i := strings.IndexAny(line, "=:"); i > 0 && line[i] == '=' {
+			assignments++
+			if !isEnvName(strings.TrimSpace(strings.TrimPrefix(line[:i], "export "))) {
+				env = false
+			}
+			if !isTOMLValue(strings.TrimSpace(line[i+1:])) {
+				typed = false
+			}
+			continue
+		}
+
+		others++
+	}
+
+	switch {
+	case others > 0 || assignments+sections == 0:
+		return formatYAML
+	case env && sections == 0:
+		return formatEnv
+	case typed:
+		return formatTOML
+	default:
+		return formatINI
+	}
+}
+
+// isEnvName returns if the name is an upper case environment variable name
+func isEnvName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+
+	for _, r := range name {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isTOMLValue returns if the value is a typed TOML value rather than a bare INI string
+func isTOMLValue(v string) bool {
+	if v == "" {
+		return false
+	}
+
+	switch {
+	case strings.ContainsRune(`"'[{`, rune(v[0])), v == "true", v == "false":
+		return true
+	case v[0] == '+' || v[0] == '-' || (v[0] >= '0' && v[0] <= '9'):
+		token := strings.Fields(v)[0]
+		if _, err := strconv.ParseFloat(strings.ReplaceAll(token, "_", ""), 64); err == nil {
+			return true
+		}
+
+		// dates and times
+		return v[0] >= '0' && v[0] <= '9' && strings.ContainsAny(token, "-:")
+	default:
+		return false
+	}
+}
+
+// documentTable returns the table at the keys within the document, creating the tables along the way. The last element of an array of tables is used, as TOML does for a table header beneath [[name]].
+func documentTable(document map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	table := document
+	for _, key := range keys {
+		switch next := table[key].(type) {
+		case nil:
+			child := map[string]interface{}{}
+			table[key] = child
+			table = child
+		case map[string]interface{}:
+			table = next
+		case []interface{}:
+			var last map[string]interface{}
+			if len(next) > 0 {
+				last, _ = next[len(next)-1].(map[string]interface{})
+			}
+			if last == nil {
+				return nil, fmt.Errorf("%q is not a table", key)
+			}
+			table = last
+		default:
+			return nil, fmt.Errorf("%q is not a table", key)
+		}
+	}
+
+	return table, nil
+}
+
+// closingQuote returns the index of the quote closing the string starting at s[0], backslash escapes are honored for double quotes and doubled quotes for single quotes, -1 when it is not closed
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == '\'' && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unquote the single or double quoted string, single quoted strings are literal and, when doubled is set (YAML), a quote is escaped by doubling it
+func unquote(s string, doubled bool) (string, error) {
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+
+	if s[0] == '"' {
+		return strconv.Unquote(s)
+	}
+
+	inner := s[1 : len(s)-1]
+	if doubled {
+		inner = strings.ReplaceAll(inner, "''", "'")
+	}
+
+	return inner, nil
+}
+
+// stripComment removes a comment starting with one of the markers, outside of quotes and at the start or after whitespace, from the line
+func stripComment(line, markers string) string {
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"' || c == '\'':
+			end := closingQuote(line[i:])
+			if end < 0 {
+				return line
+			}
+			i += end
+		case strings.IndexByte(markers, c) >= 0 && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+
+	return line
+}
+
+// splitOutside splits s on sep outside of quotes and brackets
+func splitOutside(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\'':
+			if end := closingQuote(s[i:]); end > 0 {
+				i += end
+			}
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// decodeEnv decodes KEY=value lines as written by Set.WriteEnv or docker --env-file, an export prefix is allowed. Keys are environment variable names of settings (see EnvName) or dot separated paths.
+func decodeEnv(data []byte) (interface{}, error) {
+	document := map[string]interface{}{}
+
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.IndexByte(line, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=value", n+1)
+		}
+
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		if value != "" && (value[0] == '"' || value[0] == '\'') {
+			end := closingQuote(value)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quote", n+1)
+			}
+
+			v, err := unquote(value[:end+1], false)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			value = v
+		} else {
+			value = stripComment(value, "#")
+		}
+
+		document[key] = value
+	}
+
+	return document, nil
+}
+
+// decodeINI decodes key = value (or key: value) lines within [section] headers, dots in section names and keys nest them. Comments start with ; or #.
+func decodeINI(data []byte) (interface{}, error) {
+	document := map[string]interface{}{}
+	section := document
+
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section", n+1)
+			}
+
+			table, err := documentTable(document, strings.Split(strings.TrimSpace(line[1:len(line)-1]), "."))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			section = table
+			continue
+		}
+
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", n+1)
+		}
+
+		keys := strings.Split(strings.TrimSpace(line[:i]), ".")
+		value := strings.TrimSpace(line[i+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			v, err := unquote(value, false)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			value = v
+		}
+
+		table, err := documentTable(section, keys[:len(keys)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		table[keys[len(keys)-1]] = value
+	}
+
+	return document, nil
+}
+
+// decodeTOML decodes the commonly used subset of TOML: tables, arrays of tables, dotted keys, basic and literal strings, numbers, booleans, dates (kept as strings), arrays spanning lines and inline tables. Duplicate keys and tables defined twice are rejected. Multi-line strings are not supported.
+func decodeTOML(data []byte) (interface{}, error) {
+	document := map[string]interface{}{}
+	table := document
+
+	// tables defined by a header, keyed by their joined keys
+	defined := map[string]bool{}
+
+	lines := strings.Split(string(data), "\n")
+	for n := 0; n < len(lines); n++ {
+		line := stripComment(strings.TrimSpace(lines[n]), "#")
+		if line == "" {
+			continue
+		}
+
+		start := n
+		fail := func(err error) (interface{}, error) {
+			return nil, fmt.Errorf("line %d: %w", start+1, err)
+		}
+
+		switch {
+		case strings.HasPrefix(line, "[["):
+			if !strings.HasSuffix(line, "]]") {
+				return fail(fmt.Errorf("unterminated table header"))
+			}
+
+			keys, err := tomlKeys(line[2 : len(line)-2])
+			if err != nil {
+				return fail(err)
+			}
+
+			parent, err := documentTable(document, keys[:len(keys)-1])
+			if err != nil {
+				return fail(err)
+			}
+
+			last := keys[len(keys)-1]
+			tables, ok := parent[last].([]interface{})
+			if !ok && parent[last] != nil {
+				return fail(fmt.Errorf("%q is not an array of tables", last))
+			}
+
+			table = map[string]interface{}{}
+			parent[last] = append(tables, table)
+
+			// the tables beneath the previous element may be defined again beneath the new one
+			prefix := strings.Join(keys, "\x00") + "\x00"
+			for name := range defined {
+				if strings.HasPrefix(name, prefix) {
+					delete(defined, name)
+				}
+			}
+
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return fail(fmt.Errorf("unterminated table header"))
+			}
+
+			keys, err := tomlKeys(line[1 : len(line)-1])
+			if err != nil {
+				return fail(err)
+			}
+
+			name := strings.Join(keys, "\x00")
+			if defined[name] {
+				return fail(fmt.Errorf("table %q is already defined", strings.Join(keys, ".")))
+			}
+			defined[name] = true
+
+			if table, err = documentTable(document, keys); err != nil {
+				return fail(err)
+			}
+
+		default:
+			// an array is continued on the following lines until its brackets are closed
+			for bracketDepth(line) > 0 && n+1 < len(lines) {
+				n++
+				line += " " + stripComment(strings.TrimSpace(lines[n]), "#")
+			}
+
+			if err := tomlAssign(table, line); err != nil {
+				return fail(err)
+			}
+		}
+	}
+
+	return document, nil
+}
+
+// tomlAssign parses the key = value assignment into the table
+func tomlAssign(table map[string]interface{}, assignment string) error {
+	parts := splitOutside(assignment, '=')
+	if len(parts) < 2 {
+		return fmt.Errorf("expected key = value")
+	}
+
+	keys, err := tomlKeys(parts[0])
+	if err != nil {
+		return err
+	}
+
+	value, rest, err := tomlValue(strings.TrimSpace(assignment[len(parts[0])+1:]))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return fmt.Errorf("unexpected %q after value", rest)
+	}
+
+	parent, err := documentTable(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+
+	last := keys[len(keys)-1]
+	if _, ok := parent[last]; ok {
+		return fmt.Errorf("duplicate key %q", strings.Join(keys, "."))
+	}
+	parent[last] = value
+
+	return nil
+}
+
+// bracketDepth returns the number of brackets opened but not closed in s, outside of quotes
+func bracketDepth(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			end := closingQuote(s[i:])
+			if end < 0 {
+				return depth
+			}
+			i += end
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+	}
+
+	return depth
+}
+
+// tomlKeys splits the dotted key into its bare or quoted parts
+func tomlKeys(s string) ([]string, error) {
+	var keys []string
+	for _, part := range splitOutside(s, '.') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty key in %q", s)
+		}
+
+		if part[0] == '"' || part[0] == '\'' {
+			key, err := unquote(part, false)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %s: %w", part, err)
+			}
+			part = key
+		}
+
+		keys = append(keys, part)
+	}
+
+	return keys, nil
+}
+
+// tomlValue parses the value at the start of s, returning the remainder of s
+func tomlValue(s string) (interface{}, string, error) {
+	if s == "" {
+		return nil, "", fmt.Errorf("missing value")
+	}
+
+	switch s[0] {
+	case '"', '\'':
+		if strings.HasPrefix(s, `"""`) || strings.HasPrefix(s, "'''") {
+			return nil, "", fmt.Errorf("multi-line strings are not supported")
+		}
+
+		end := closingQuote(s)
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated string")
+		}
+
+		v, err := unquote(s[:end+1], false)
+		return v, s[end+1:], err
+
+	case '[':
+		var items []interface{}
+		rest := strings.TrimSpace(s[1:])
+		for {
+			if rest == "" {
+				return nil, "", fmt.Errorf("unterminated array")
+			}
+			if rest[0] == ']' {
+				return items, rest[1:], nil
+			}
+
+			item, after, err := tomlValue(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+
+			rest = strings.TrimSpace(after)
+			if strings.HasPrefix(rest, ",") {
+				rest = strings.TrimSpace(rest[1:])
+			} else if !strings.HasPrefix(rest, "]") {
+				return nil, "", fmt.Errorf("expected , or ] in array")
+			}
+		}
+
+	case '{':
+		table := map[string]interface{}{}
+		end := strings.LastIndexByte(s, '}')
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated inline table")
+		}
+
+		if inner := strings.TrimSpace(s[1:end]); inner != "" {
+			for _, assignment := range splitOutside(inner, ',') {
+				if err := tomlAssign(table, strings.TrimSpace(assignment)); err != nil {
+					return nil, "", err
+				}
+			}
+		}
+
+		return table, s[end+1:], nil
+
+	default:
+		end := strings.IndexAny(s, ",]}")
+		if end < 0 {
+			end = len(s)
+		}
+
+		token := strings.TrimSpace(s[:end])
+		switch token {
+		case "":
+			return nil, "", fmt.Errorf("missing value")
+		case "true", "false":
+			return token == "true", s[end:], nil
+		}
+
+		if !isTOMLValue(token) {
+			return nil, "", fmt.Errorf("invalid value %q", token)
+		}
+
+		// underscores only separate digits, dates and times are kept as they are
+		if _, err := strconv.ParseFloat(strings.ReplaceAll(token, "_", ""), 64); err == nil {
+			token = strings.ReplaceAll(token, "_", "")
+		}
+
+		return token, s[end:], nil
+	}
+}
+
+// yamlLine is a line of a YAML document
+type yamlLine struct {
+	n      int
+	indent int
+	text   string
+	raw    string
+}
+
+// blank returns if the line is empty or only a comment
+func (l yamlLine) blank() bool {
+	return l.text == "" || l.text[0] == '#' || l.text == "---" || l.text == "..."
+}
+
+// yamlParser parses the commonly used subset of YAML: block mappings and sequences, flow sequences and mappings of scalars, quoted and plain scalars and literal and folded block scalars. Anchors, aliases, tags and multiple documents are not supported.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// decodeYAML decodes the YAML document
+func decodeYAML(data []byte) (interface{}, error) {
+	p := &yamlParser{}
+	for n, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(raw, " \t\r")
+		text := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", n+1)
+		}
+
+		p.lines = append(p.lines, yamlLine{n: n + 1, indent: len(raw) - len(text), text: text, raw: raw})
+	}
+
+	if !p.skip() {
+		return map[string]interface{}{}, nil
+	}
+
+	v, err := p.block(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skip() {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].n)
+	}
+
+	return v, nil
+}
+
+// skip the blank lines, returning if a line remains
+func (p *yamlParser) skip() bool {
+	for p.pos < len(p.lines) && p.lines[p.pos].blank() {
+		p.pos++
+	}
+
+	return p.pos < len(p.lines)
+}
+
+// block parses the mapping or sequence at the indent
+func (p *yamlParser) block(indent int) (interface{}, error) {
+	if isYAMLItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+
+	return p.mapping(indent)
+}
+
+// isYAMLItem returns if the text is a sequence item
+func isYAMLItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// sequence parses the items at the indent
+func (p *yamlParser) sequence(indent int) (interface{}, error) {
+	items := []interface{}{}
+
+	for p.skip() && p.lines[p.pos].indent == indent && isYAMLItem(p.lines[p.pos].text) {
+		line := &p.lines[p.pos]
+		rest := strings.TrimSpace(stripComment(strings.TrimPrefix(line.text, "-"), "#"))
+
+		switch {
+		case rest == "":
+			p.pos++
+			if p.skip() && p.lines[p.pos].indent > indent {
+				item, err := p.block(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			} else {
+				items = append(items, nil)
+			}
+
+		case isYAMLItem(rest) || isYAMLEntry(rest):
+			// the item is a block starting on the line of the dash
+			text := strings.TrimLeft(line.text[1:], " ")
+			line.indent += len(line.text) - len(text)
+			line.text = text
+			item, err := p.block(line.indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+
+		default:
+			item, err := p.value(rest, indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+	}
+
+	if p.skip() && p.lines[p.pos].indent > indent {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].n)
+	}
+
+	return items, nil
+}
+
+// mapping parses the entries at the indent
+func (p *yamlParser) mapping(indent int) (interface{}, error) {
+	m := map[string]interface{}{}
+
+	for p.skip() && p.lines[p.pos].indent == indent && !isYAMLItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+
+		key, rest, ok := splitYAMLEntry(stripComment(line.text, "#"))
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", line.n)
+		}
+
+		if _, ok := m[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.n, key)
+		}
+
+		if rest != "" {
+			v, err := p.value(rest, indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+
+		p.pos++
+		switch {
+		case p.skip() && p.lines[p.pos].indent > indent:
+			v, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		case p.skip() && p.lines[p.pos].indent == indent && isYAMLItem(p.lines[p.pos].text):
+			v, err := p.sequence(indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		default:
+			m[key] = nil
+		}
+	}
+
+	if p.skip() && p.lines[p.pos].indent > indent {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].n)
+	}
+
+	return m, nil
+}
+
+// value parses the scalar, flow collection or block scalar starting on the current line with the indent, moving past it
+func (p *yamlParser) value(s string, indent int) (interface{}, error) {
+	n := p.lines[p.pos].n
+	p.pos++
+
+	if s[0] == '|' || s[0] == '>' {
+		return p.blockScalar(s, indent), nil
+	}
+
+	// a plain scalar can not hold a mapping, i.e. a: b: c
+	if isYAMLEntry(s) {
+		return nil, fmt.Errorf("line %d: mapping values are not allowed here", n)
+	}
+
+	v, err := yamlScalar(s)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", n, err)
+	}
+
+	return v, nil
+}
+
+// blockScalar collects the lines indented beyond the indent as a literal (|) or folded (>) scalar, the - chomping indicator strips the final line break
+func (p *yamlParser) blockScalar(header string, indent int) string {
+	var lines []string
+	content := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if line.text == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if line.indent <= indent {
+			break
+		}
+		if content < 0 || line.indent < content {
+			content = line.indent
+		}
+		lines = append(lines, line.raw[content:])
+	}
+
+	// trailing empty lines belong to the document, not the scalar
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var v string
+	if header[0] == '|' {
+		v = strings.Join(lines, "\n")
+	} else {
+		var b strings.Builder
+		// lines are joined by spaces, empty lines are line breaks
+		for i, line := range lines {
+			switch {
+			case line == "":
+				b.WriteString("\n")
+				continue
+			case i > 0 && lines[i-1] != "":
+				b.WriteString(" ")
+			}
+			b.WriteString(line)
+		}
+		v = b.String()
+	}
+
+	if strings.Contains(header, "-") || v == "" {
+		return v
+	}
+
+	return v + "\n"
+}
+
+// isYAMLEntry returns if the text is a key: value entry
+func isYAMLEntry(text string) bool {
+	_, _, ok := splitYAMLEntry(stripComment(text, "#"))
+	return ok
+}
+
+// splitYAMLEntry splits the text of an entry into its key and value
+func splitYAMLEntry(text string) (string, string, bool) {
+	if text == "" {
+		return "", "", false
+	}
+
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 || !strings.HasPrefix(text[end+1:], ":") {
+			return "", "", false
+		}
+
+		key, err := unquote(text[:end+1], true)
+		if err != nil {
+			return "", "", false
+		}
+
+		rest := text[end+2:]
+		if rest != "" && rest[0] != ' ' {
+			return "", "", false
+		}
+
+		return key, strings.TrimSpace(rest), true
+	}
+
+	if text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+
+	if strings.HasSuffix(text, ":") && !strings.Contains(text, ": ") {
+		return strings.TrimSpace(text[:len(text)-1]), "", true
+	}
+
+	i := strings.Index(text, ": ")
+	if i <= 0 {
+		return "", "", false
+	}
+
+	return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+2:]), true
+}
+
+// yamlInclude is a scalar tagged !include, the document at the path is read in its place
+type yamlInclude string
+
+// yamlScalar parses a quoted or plain scalar or a flow collection of them, or a path tagged !include
+func yamlScalar(s string) (interface{}, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing value")
+	}
+
+	switch s[0] {
+	case '"', '\'':
+		end := closingQuote(s)
+		if end != len(s)-1 {
+			return nil, fmt.Errorf("invalid quoted scalar %s", s)
+		}
+		return unquote(s, true)
+
+	case '[', '{':
+		closing := byte(']')
+		if s[0] == '{' {
+			closing = '}'
+		}
+		if s[len(s)-1] != closing {
+			return nil, fmt.Errorf("unterminated flow collection %s", s)
+		}
+
+		inner := strings.TrimSpace(s[1 : len(s)-1])
+		if s[0] == '[' {
+			items := []interface{}{}
+			if inner == "" {
+				return items, nil
+			}
+
+			for _, part := range splitOutside(inner, ',') {
+				item, err := yamlScalar(strings.TrimSpace(part))
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+
+			return items, nil
+		}
+
+		m := map[string]interface{}{}
+		if inner == "" {
+			return m, nil
+		}
+
+		for _, part := range splitOutside(inner, ',') {
+			key, rest, ok := splitYAMLEntry(strings.TrimSpace(part))
+			if !ok {
+				return nil, fmt.Errorf("invalid flow mapping entry %q", part)
+			}
+
+			if _, ok := m[key]; ok {
+				return nil, fmt.Errorf("duplicate key %q", key)
+			}
+
+			v, err := yamlScalar(rest)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		}
+
+		return m, nil
+
+	case '!':
+		if tag, rest, _ := strings.Cut(s, " "); tag == "!include" {
+			path, err := yamlScalar(strings.TrimSpace(rest))
+			if name, ok := path.(string); err == nil && ok && name != "" {
+				return yamlInclude(name), nil
+			}
+			return nil, fmt.Errorf("!include must be followed by a path")
+		}
+		return nil, fmt.Errorf("tags other than !include are not supported")
+
+	case '&', '*':
+		return nil, fmt.Errorf("anchors and aliases are not supported")
+	}
+
+	switch s {
+	case "~", "null", "Null", "NULL":
+		return nil, nil
+	}
+
+	return s, nil
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSet_LoadFileFormats(t *testing.T) {
+	documents := map[string]string{
+		"config.json": `{"Name": "json", "HTTP": {"Port": 8081, "Debug": true}, "Servers": [{"Addr": "a:1"}, {"Addr": "b:2"}]}`,
+		"config.yaml": `# service
+Name: "yaml"
+HTTP:
+  Port: 8082   # listen port
+  Debug: true
+Servers:
+  - Addr: a:1
+  - Addr: 'b:2'
+`,
+		"config.toml": `Name = "toml"
+
+[HTTP]
+Port = 8_083
+Debug = true
+
+[[Servers]]
+Addr = "a:1"
+
+[[Servers]]
+Addr = "b:2"
+`,
+		"config.ini": `Name = ini
+; comment
+[HTTP]
+Port = 8084
+Debug = true
+
+[Servers.0]
+Addr = a:1
+[Servers.1]
+Addr = "b:2"
+`,
+		".env": `# written by WriteEnv
+NAME=env
+export HTTP_PORT=8085
+HTTP_DEBUG="true" # comment
+SERVERS.0.ADDR=a:1
+Servers.1.Addr='b:2'
+`,
+		"sniffed": `Name: sniffed
+HTTP: {Port: 8086, Debug: true}
+Servers: [{Addr: "a:1"}, {Addr: "b:2"}]
+`,
+	}
+	dir := writeFiles(t, documents)
+
+	type server struct{ Addr string }
+
+	tests := []struct {
+		file string
+		name string
+		port int
+	}{
+		{"config.json", "json", 8081},
+		{"config.yaml", "yaml", 8082},
+		{"config.toml", "toml", 8083},
+		{"config.ini", "ini", 8084},
+		{".env", "env", 8085},
+		{"sniffed", "sniffed", 8086},
+	}
+
+	for _, tt := range tests {
+		cfg := struct {
+			Name string
+			HTTP struct {
+				Port  int
+				Debug bool
+			}
+			Servers []server
+		}{}
+
+		set := &Set{}
+		set.Bind(&cfg)
+
+		if err := set.LoadFile(filepath.Join(dir, tt.file)); err != nil {
+			t.Errorf("Failed to load %s: %v", tt.file, err)
+			continue
+		}
+
+		if cfg.Name != tt.name || cfg.HTTP.Port != tt.port || !cfg.HTTP.Debug {
+			t.Errorf("Failed to load %s: got %q, %d and %t", tt.file, cfg.Name, cfg.HTTP.Port, cfg.HTTP.Debug)
+		}
+
+		if expected := []server{{"a:1"}, {"b:2"}}; !reflect.DeepEqual(cfg.Servers, expected) {
+			t.Errorf("Failed to load %s servers: expected %v; got %v", tt.file, expected, cfg.Servers)
+		}
+	}
+}
+
+func TestSniffFormat(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{`{"a": 1}`, formatJSON},
+		{"a: 1\nb:\n  c: 2", formatYAML},
+		{"- a\n- b", formatYAML},
+		{"A=1\nexport B_C=two", formatEnv},
+		{"[server]\nport = 8080\nhost = \"localhost\"", formatTOML},
+		{"[server]\nport = 8080\nhost = localhost", formatINI},
+		{"when = 1979-05-27T07:32:00Z", formatTOML},
+	}
+
+	for _, tt := range tests {
+		if actual := sniffFormat([]byte(tt.data)); actual != tt.expected {
+			t.Errorf("Failed to sniff %q: expected %q; got %q", tt.data, tt.expected, actual)
+		}
+	}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	data := `
+literal: |
+  line one
+  line two
+folded: >-
+  folded
+  text
+
+  paragraph
+list:
+- one
+- "two # not a comment"
+-
+  nested: true
+flow: [a, 'b', "c"]
+empty: ~
+quoted key: 'it''s'
+`
+	document, err := decodeYAML([]byte(data))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"literal":    "line one\nline two\n",
+		"folded":     "folded text\nparagraph",
+		"list":       []interface{}{"one", "two # not a comment", map[string]interface{}{"nested": "true"}},
+		"flow":       []interface{}{"a", "b", "c"},
+		"empty":      nil,
+		"quoted key": "it's",
+	}
+
+	if !reflect.DeepEqual(document, expected) {
+		t.Errorf("Failed to decode: expected %#v; got %#v", expected, document)
+	}
+
+	for _, invalid := range []string{"a: 1\n   b: 2", "a: &anchor 1", "\ta: 1", "a: [1, 2", "a: b: c", "a: 1\na: 2", "a: {b: 1, b: 2}"} {
+		if _, err := decodeYAML([]byte(invalid)); err == nil {
+			t.Errorf("Failed to reject %q", invalid)
+		}
+	}
+}
+
+func TestDecodeTOML(t *testing.T) {
+	data := `
+title = "TOML # not a comment" # comment
+owner.name = 'Tom'
+ports = [ 8000, 8001, ]
+hosts = [
+  "alpha", # first
+  "beta",
+  [ "gamma" ],
+]
+point = { x = 1, y = "two" }
+
+[database."primary db"]
+enabled = false
+`
+	document, err := decodeTOML([]byte(data))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"title": "TOML # not a comment",
+		"owner": map[string]interface{}{"name": "Tom"},
+		"ports": []interface{}{"8000", "8001"},
+		"hosts": []interface{}{"alpha", "beta", []interface{}{"gamma"}},
+		"point": map[string]interface{}{"x": "1", "y": "two"},
+		"database": map[string]interface{}{
+			"primary db": map[string]interface{}{"enabled": false},
+		},
+	}
+
+	if !reflect.DeepEqual(document, expected) {
+		t.Errorf("Failed to decode: expected %#v; got %#v", expected, document)
+	}
+
+	for _, invalid := range []string{"a = bare", `a = """multi"""`, "[table", "a = [1, 2", "a = [\n1,\n2", "a = 1\na = 2", "a.b = 1\na = 2", "a = 1\na.b = 2", "[a]\n[a]", "[a]\nb = 1\n[a.b]"} {
+		if _, err := decodeTOML([]byte(invalid)); err == nil {
+			t.Errorf("Failed to reject %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"go/parser"
+	"strconv"
+)
+
+// Gate will create a new bool setting, like Set.Derive, whose value is the expression over other settings (i.e. Env == "prod" && in(Region, "eu-west-1", "eu-central-1")). See Setting.Constraint for the expression syntax, this is not available. The gate is re-evaluated whenever a referenced setting changes, and is false whenever the expression can not be evaluated. The referenced settings must already exist and the expression must evaluate to a bool.
+func (s *Set) Gate(name, expr, description string) *Setting {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		panic(fmt.Sprintf("invalid gate %q: %v", expr, err))
+	}
+
+	e := &evaluator{set: s}
+	if _, err := e.test(expr); err != nil {
+		panic(fmt.Sprintf("invalid gate %q: %v", expr, err))
+	}
+
+	var dependencies []string
+	seen := map[string]bool{}
+	for _, path := range references(node) {
+		if dep := s.referenced(path); dep != nil && !seen[dep.Path] {
+			seen[dep.Path] = true
+			dependencies = append(dependencies, dep.Path)
+		}
+	}
+
+	return s.Derive(name, new(bool), description, func() string {
+		ok, _ := e.test(expr)
+		return strconv.FormatBool(ok)
+	}, dependencies...)
+}
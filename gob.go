@@ -0,0 +1,150 @@
+package config
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// snapshotVersion is the version of the stream written by Set.WriteTo
+const snapshotVersion = 1
+
+// snapshot is the stream written by Set.WriteTo
+type snapshot struct {
+	Version  int
+	Revision uint64
+	Settings []snapshotSetting
+}
+
+// snapshotSetting is a setting of a snapshot
+type snapshotSetting struct {
+	Path        string
+	Value       string
+	Source      string
+	Description string
+	Mask        bool
+	Annotations map[string]string
+	Labels      []string
+}
+
+// WriteTo writes the values and metadata of every setting of the Set to w in a compact binary (gob) encoding read by Set.ReadFrom, so a supervisor can hand its effective configuration to forked workers over a pipe without each one loading every provider again. Masked values are written as well, so w must only reach trusted processes.
+func (s *Set) WriteTo(w io.Writer) (int64, error) {
+	return s.writeSnapshot(w, func(*Setting) bool { return true })
+}
+
+// writeSnapshot writes the settings of the Set included by the filter to w
+func (s *Set) writeSnapshot(w io.Writer, include func(*Setting) bool) (int64, error) {
+	snap := snapshot{
+		Version:  snapshotVersion,
+		Revision: s.Revision(),
+	}
+
+	for _, setting := range s.sorted() {
+		if !include(setting) {
+			continue
+		}
+
+		snap.Settings = append(snap.Settings, snapshotSetting{
+			Path:        setting.Path,
+			Value:       setting.format(),
+			Source:      setting.Source(),
+			Description: setting.Description,
+			Mask:        setting.Mask,
+			Annotations: setting.Annotations,
+			Labels:      setting.Labels,
+		})
+	}
+
+	cw := &countingWriter{w: w}
+	if err := gob.NewEncoder(cw).Encode(snap); err != nil {
+		return cw.n, fmt.Errorf("unable to encode settings: %w", err)
+	}
+
+	return cw.n, nil
+}
+
+// ReadFrom reads the settings written by Set.WriteTo from r and applies their values, keeping the source that supplied them in the writer (see Setting.Source). Settings the Set does not have are registered as string settings with their metadata, like a WatchClient does. Nothing beyond the settings is read from r, so it can continue with other data.
+func (s *Set) ReadFrom(r io.Reader) (int64, error) {
+	snap, n, err := readSnapshot(r)
+	if err != nil {
+		return n, err
+	}
+
+	for _, entry := range snap.Settings {
+		if setting := s.lookup(entry.Path); setting != nil {
+			// defaults are registered by the reader as well
+			if entry.Source == SourceDefault {
+				continue
+			}
+
+			if err := setting.setFrom(context.Background(), entry.Value, entry.Source); err != nil {
+				return n, &SettingError{Path: entry.Path, Err: err}
+			}
+			continue
+		}
+
+		entry := entry
+		value := entry.Value
+		_, err := s.Root().registerPath(entry.Path, &value, entry.Description, func(setting *Setting) {
+			setting.Mask = entry.Mask
+			setting.Annotations = entry.Annotations
+			setting.Labels = entry.Labels
+		})
+		if err != nil {
+			return n, &SettingError{Path: entry.Path, Err: err}
+		}
+	}
+
+	return n, nil
+}
+
+// readSnapshot reads a snapshot written by Set.writeSnapshot from r, returning the number of bytes read
+func readSnapshot(r io.Reader) (snapshot, int64, error) {
+	cr := &countingReader{r: r}
+
+	var snap snapshot
+	if err := gob.NewDecoder(cr).Decode(&snap); err != nil {
+		return snap, cr.n, fmt.Errorf("unable to decode settings: %w", err)
+	}
+
+	if snap.Version != snapshotVersion {
+		return snap, cr.n, fmt.Errorf("unable to decode settings: unsupported version %d", snap.Version)
+	}
+
+	return snap, cr.n, nil
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read from r. It is an io.ByteReader so the gob decoder does not buffer, and read past the end of the settings.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ReadByte implements io.ByteReader
+func (c *countingReader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(c, b[:]); err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
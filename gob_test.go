@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSet_WriteToReadFrom(t *testing.T) {
+	parent := &Set{}
+	parent.Setting("Port", 80, "")
+	parent.Subset("HTTP").Setting("Host", "localhost", "Host to bind")
+	password := parent.Setting("Password", "", "")
+	password.Mask = true
+
+	_ = parent.Get("Port").Set("8080")
+	if err := parent.updateAll(map[string]string{"Password": "hunter2"}, "vault"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	written, err := parent.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	buf.WriteString("tail")
+
+	child := &Set{}
+	port := 80
+	child.Setting("Port", &port, "")
+	child.Setting("Password", "", "")
+
+	read, err := child.ReadFrom(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read settings: %v", err)
+	}
+
+	if read != written || buf.String() != "tail" {
+		t.Errorf("Failed to read only the settings: wrote %d bytes; read %d leaving %q", written, read, buf.String())
+	}
+
+	if port != 8080 || child.Get("Port").Source() != SourceRuntime {
+		t.Errorf("Failed to apply value: got %d from %q", port, child.Get("Port").Source())
+	}
+
+	if setting := child.Get("Password"); setting.format() != "hunter2" || setting.Source() != "vault" {
+		t.Errorf("Failed to apply masked value with its source: got %q from %q", setting.format(), setting.Source())
+	}
+
+	if host := child.Get("HTTP.Host"); host == nil || host.String() != "localhost" || host.Description != "Host to bind" {
+		t.Errorf("Failed to register missing setting: got %+v", host)
+	}
+
+	if _, err := child.ReadFrom(bytes.NewReader([]byte("garbage"))); err == nil {
+		t.Errorf("Failed to reject invalid stream")
+	}
+}
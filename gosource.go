@@ -0,0 +1,254 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// importPath of this package used by generated source
+const importPath = "github.com/portcullis/config"
+
+// WriteGo writes a Go source file for package pkg declaring func name(set *config.Set) registering every setting of the Set with its current value as the default. This is useful to promote a runtime tuned configuration back into code.
+//
+// Values are written as Go literals where possible, other values are restored from their string form when registered. Masked settings are registered with their zero value so secrets never end up in source, and derived settings (see Set.Derive) are left as a comment.
+func (s *Set) WriteGo(w io.Writer, pkg, name string) error {
+	var settings []*Setting
+	s.Range(func(_ string, setting *Setting) bool {
+		settings = append(settings, setting)
+		return true
+	})
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	imports := map[string]bool{importPath: true}
+	body := &bytes.Buffer{}
+
+	for _, setting := range settings {
+		if len(setting.Dependencies()) > 0 {
+			fmt.Fprintf(body, "\n// %s is derived from %s\n", setting.Path, strings.Join(setting.Dependencies(), ", "))
+			continue
+		}
+
+		goSetting(body, setting, imports)
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by config.WriteGo. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fmt.Fprintf(out, "\t%q\n", path)
+	}
+
+	fmt.Fprintf(out, ")\n\n// %s registers the settings with their defaults\nfunc %s(set *config.Set) {\n%s}\n", name, name, body.String())
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("unable to format source: %w", err)
+	}
+
+	_, err = w.Write(source)
+	return err
+}
+
+// WriteGoSubscriptions writes a Go source file for package pkg declaring the struct name holding a *config.Set, with a method per setting of the Set bound to a pointer (i.e. by Set.Bind) subscribing to its changes with the previous and new value in its own type, i.e. OnHTTPPortChange(fn func(old, new int)) *config.NotifyHandle for HTTP.Port. Consumers then never write paths or type assertions in change handlers.
+//
+// Method names are the path as an exported identifier, settings whose method collides with another or whose type can not be named from another package are left as a comment. The methods panic when the Set they are called on does not have the setting, as the source no longer matches the configuration.
+func (s *Set) WriteGoSubscriptions(w io.Writer, pkg, name string) error {
+	imports := map[string]bool{importPath: true, "sync": true}
+	body := &bytes.Buffer{}
+	methods := map[string]string{}
+
+	for _, setting := range s.sorted() {
+		path := s.relative(setting.Path)
+
+		rv := reflect.ValueOf(setting.Value)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() || !goReferable(rv.Type().Elem()) {
+			fmt.Fprintf(body, "\n// %s has unsupported type %T\n", path, setting.Value)
+			continue
+		}
+
+		method := "On" + goIdentifier(path) + "Change"
+		if other, found := methods[method]; found {
+			fmt.Fprintf(body, "\n// %s collides with %s as %s\n", path, other, method)
+			continue
+		}
+		methods[method] = path
+
+		typ := rv.Type().Elem()
+		goImport(typ, imports)
+
+		fmt.Fprintf(body, "\n// %s calls fn with the previous and new value of %s whenever it changes, until the handle is closed\n", method, path)
+		fmt.Fprintf(body, "func (c %s) %s(fn func(old, new %s)) *config.NotifyHandle {\n", name, method, typ)
+		fmt.Fprintf(body, "setting := c.Set.Get(%q)\nif setting == nil {\npanic(%q)\n}\n", path, fmt.Sprintf("setting %q does not exist", path))
+		fmt.Fprintf(body, "value, ok := setting.Value.(*%s)\nif !ok {\npanic(%q)\n}\n\n", typ, fmt.Sprintf("setting %q is not a %s", path, typ))
+		fmt.Fprintf(body, "var mu sync.Mutex\nprevious := *value\n\n")
+		fmt.Fprintf(body, "return setting.Notify(config.NotifyFunc(func(*config.Setting) {\nmu.Lock()\nold, current := previous, *value\nprevious = current\nmu.Unlock()\n\nfn(old, current)\n}))\n}\n")
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by config.WriteGoSubscriptions. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fmt.Fprintf(out, "\t%q\n", path)
+	}
+
+	fmt.Fprintf(out, ")\n\n// %s subscribes to the changes of the settings of a Set with typed values\ntype %s struct {\n// Set holding the settings\nSet *config.Set\n}\n%s", name, name, body.String())
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("unable to format source: %w", err)
+	}
+
+	_, err = w.Write(source)
+	return err
+}
+
+// goIdentifier returns the path as an exported Go identifier, i.e. HTTPMaxConns for HTTP.max-conns
+func goIdentifier(path string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range path {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// goReferable returns if the type can be named from another package
+func goReferable(t reflect.Type) bool {
+	if t.Name() != "" {
+		return t.PkgPath() == "" || token.IsExported(t.Name())
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return goReferable(t.Key()) && goReferable(t.Elem())
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return goReferable(t.Elem())
+	case reflect.Struct, reflect.Func, reflect.Interface, reflect.Chan:
+		// spelled out in full rather than named
+		return false
+	default:
+		return true
+	}
+}
+
+// goSetting writes the statements registering the setting, adding the packages it needs to imports
+func goSetting(w io.Writer, setting *Setting, imports map[string]bool) {
+	receiver := "set"
+	if i := strings.LastIndex(setting.Path, "."); i >= 0 {
+		for _, segment := range strings.Split(setting.Path[:i], ".") {
+			receiver += fmt.Sprintf(".Subset(%q)", segment)
+		}
+	}
+
+	rv := reflect.ValueOf(setting.Value)
+	pointer := rv.Kind() == reflect.Ptr && !rv.IsNil()
+
+	if !pointer {
+		// plain values are passed by value
+		literal, ok := goLiteral(rv, imports)
+		if !ok {
+			fmt.Fprintf(w, "\n// %s has unsupported type %T\n", setting.Path, setting.Value)
+			return
+		}
+
+		fmt.Fprintf(w, "\n{\nsetting := %s.Setting(%q, %s, %q)\n", receiver, setting.Name, literal, setting.Description)
+	} else {
+		elem := rv.Elem()
+		goImport(elem.Type(), imports)
+
+		literal, ok := goLiteral(elem, imports)
+		switch {
+		case setting.Mask:
+			fmt.Fprintf(w, "\n{\n// masked\nvar value %s\n", elem.Type())
+		case ok:
+			fmt.Fprintf(w, "\n{\nvalue := %s\n", literal)
+		default:
+			fmt.Fprintf(w, "\n{\nvar value %s\n", elem.Type())
+		}
+
+		fmt.Fprintf(w, "setting := %s.Setting(%q, &value, %q)\n", receiver, setting.Name, setting.Description)
+
+		if !ok && !setting.Mask {
+			fmt.Fprintf(w, "if err := setting.Set(%q); err != nil {\npanic(err)\n}\nsetting.DefaultValue = %q\n", setting.format(), setting.format())
+		}
+	}
+
+	if setting.Mask {
+		fmt.Fprintf(w, "setting.Mask = true\n")
+	}
+	if setting.Required {
+		fmt.Fprintf(w, "setting.Required = true\n")
+	}
+	if setting.Category != "" {
+		fmt.Fprintf(w, "setting.Category = %q\n", setting.Category)
+	}
+	if setting.Role != "" {
+		fmt.Fprintf(w, "setting.Role = %q\n", setting.Role)
+	}
+
+	fmt.Fprintf(w, "_ = setting\n}\n")
+}
+
+// goLiteral returns the value as a typed Go literal when it is a basic value or a slice of basic values
+func goLiteral(rv reflect.Value, imports map[string]bool) (string, bool) {
+	basic := func(k reflect.Kind) bool {
+		return k == reflect.Bool || k == reflect.String || (k >= reflect.Int && k <= reflect.Complex128)
+	}
+
+	switch {
+	case basic(rv.Kind()):
+		goImport(rv.Type(), imports)
+		return fmt.Sprintf("%s(%#v)", rv.Type(), rv.Interface()), true
+
+	case rv.Kind() == reflect.Slice && basic(rv.Type().Elem().Kind()):
+		goImport(rv.Type(), imports)
+		if rv.IsNil() {
+			return fmt.Sprintf("%s(nil)", rv.Type()), true
+		}
+		return fmt.Sprintf("%#v", rv.Interface()), true
+
+	default:
+		return "", false
+	}
+}
+
+// goImport adds the package of the type, or its element type, to the imports
+func goImport(t reflect.Type, imports map[string]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	if t.PkgPath() != "" {
+		imports[t.PkgPath()] = true
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_WriteGo(t *testing.T) {
+	cfg := struct {
+		Port     int    `category:"Networking"`
+		Password string `mask:"true"`
+		Timeout  time.Duration
+		Hosts    []string
+		Share    Percent
+	}{Port: 8080, Password: "secret", Timeout: 5 * time.Second, Hosts: []string{"a", "b"}, Share: 0.5}
+
+	set := &Set{}
+	set.Subset("HTTP").Bind(&cfg)
+	set.Setting("Scheme", "https", "the scheme")
+
+	buf := &bytes.Buffer{}
+	if err := set.WriteGo(buf, "defaults", "Register"); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	source := buf.String()
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "defaults.go", source, 0); err != nil {
+		t.Fatalf("Failed to write valid source: %v\n%s", err, source)
+	}
+
+	expected := []string{
+		`"github.com/portcullis/config"`,
+		`"time"`,
+		`value := int(8080)`,
+		`set.Subset("HTTP").Setting("Port", &value, "")`,
+		`setting.Category = "Networking"`,
+		`value := time.Duration(5000000000)`,
+		`value := []string{"a", "b"}`,
+		`value := config.Percent(0.5)`,
+		`set.Setting("Scheme", string("https"), "the scheme")`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(source, e) {
+			t.Errorf("Failed to write %s:\n%s", e, source)
+		}
+	}
+
+	if strings.Contains(source, "secret") {
+		t.Errorf("Failed to omit masked value:\n%s", source)
+	}
+}
+
+func TestSet_WriteGoSubscriptions(t *testing.T) {
+	cfg := struct {
+		Port     int16
+		Timeout  time.Duration
+		MaxConns int `name:"max-conns"`
+		Hosts    []string
+		Share    Percent
+	}{Port: 8080}
+
+	set := &Set{}
+	set.Subset("HTTP").Bind(&cfg)
+	set.Setting("Scheme", "https", "the scheme")
+
+	buf := &bytes.Buffer{}
+	if err := set.WriteGoSubscriptions(buf, "subscriptions", "Changes"); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	source := buf.String()
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "subscriptions.go", source, 0); err != nil {
+		t.Fatalf("Failed to write valid source: %v\n%s", err, source)
+	}
+
+	expected := []string{
+		`"github.com/portcullis/config"`,
+		`"sync"`,
+		`"time"`,
+		`type Changes struct`,
+		`func (c Changes) OnHTTPPortChange(fn func(old, new int16)) *config.NotifyHandle`,
+		`setting := c.Set.Get("HTTP.Port")`,
+		`value, ok := setting.Value.(*int16)`,
+		`func (c Changes) OnHTTPTimeoutChange(fn func(old, new time.Duration)) *config.NotifyHandle`,
+		`func (c Changes) OnHTTPHostsChange(fn func(old, new []string)) *config.NotifyHandle`,
+		`func (c Changes) OnHTTPShareChange(fn func(old, new config.Percent)) *config.NotifyHandle`,
+		`// Scheme has unsupported type string`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(source, e) {
+			t.Errorf("Failed to write %s:\n%s", e, source)
+		}
+	}
+
+	if identifier := goIdentifier("HTTP.max-conns"); identifier != "HTTPMaxConns" {
+		t.Errorf("Failed to convert path to identifier: expected %q; got %q", "HTTPMaxConns", identifier)
+	}
+}
@@ -0,0 +1,332 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNoLeader is returned by Gossip.Update when leader-gated writes are enabled and there is no leader to accept the change
+var ErrNoLeader = errors.New("no leader")
+
+// forwardHeader marks a change forwarded to the leader, which is applied and propagated rather than only applied
+const forwardHeader = "X-Config-Forwarded"
+
+// leaderHeader holds the leader a follower refers a forwarded change to, which the sender resends it to with its token rather than following a redirect that drops the Authorization header
+const leaderHeader = "X-Config-Leader"
+
+// maxLeaderReferrals is the number of times a forwarded change is resent to the leader named by a follower
+const maxLeaderReferrals = 2
+
+// RevisionHeader is the header admin surfaces (i.e. Gossip) return the Set.Revision after a write in
+const RevisionHeader = "X-Config-Revision"
+
+// gossipChange is the message sent between peers
+type gossipChange struct {
+	Path  string `json:"path"`
+	Value string `json:"value"`
+
+	// Actor that made a change forwarded to the leader, which parks changes requiring approval on its behalf
+	Actor string `json:"actor,omitempty"`
+}
+
+// PeerError is returned when a change could not be propagated to a peer
+type PeerError struct {
+	Peer string
+	Err  error
+}
+
+func (e *PeerError) Error() string {
+	return fmt.Sprintf("peer %q: %v", e.Peer, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *PeerError) Unwrap() error {
+	return e.Err
+}
+
+// GossipError contains the errors of every peer a change could not be propagated to
+type GossipError struct {
+	Errors []*PeerError
+}
+
+func (e *GossipError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Gossip propagates runtime changes between replicas of the same service over HTTP, so a change made on one replica (i.e. by an operator through an admin API) reaches the whole deployment.
+//
+// Each replica serves the Gossip as an http.Handler and lists the other replicas in Peers. Changes made through Gossip.Update are applied locally and sent to every peer, changes received from a peer are only applied locally so they are never echoed back. Values loaded from files or providers are not propagated as every replica loads them itself.
+type Gossip struct {
+	// Set changes are applied to
+	Set *Set
+
+	// Peers are the base URLs of the other replicas the Gossip handler is served at
+	Peers []string
+
+	// Token is sent to and required from peers as a bearer token. Changes received from peers are applied without the Authorizer of the Set, as the peer they were made on authorized them, so the handler rejects every change while the Token is empty.
+	Token string
+
+	// Client used to contact peers, defaults to http.DefaultClient
+	Client *http.Client
+
+	// Leader, when not nil, enables leader-gated writes to prevent split-brain configuration. It returns the base URL of the current leader, as elected outside of the Gossip, and whether this replica is the leader. Only the leader applies and propagates changes, a follower forwards changes made through Gossip.Update to the leader and refers forwarded changes it receives to the leader, which the sender resends them to.
+	Leader func() (url string, self bool)
+}
+
+// Update the setting at path to the value locally and propagate the change to every peer. The write is authorized for the caller in the ctx like Set.UpdateContext. Failing peers are returned in a *GossipError after the change has been applied locally.
+//
+// With leader-gated writes a follower only forwards the change to the leader, returning ErrNoLeader when there is none. The follower authorizes the change and checks it against the Guards of the Set, while changes requiring approval are parked by the leader on behalf of the actor in the ctx (see WithActor), returning an error wrapping ErrApprovalPending.
+func (g *Gossip) Update(ctx context.Context, path, value string) error {
+	if g.Leader != nil {
+		leader, self := g.Leader()
+		if !self {
+			if leader == "" {
+				return ErrNoLeader
+			}
+
+			return g.forward(ctx, leader, path, value)
+		}
+	}
+
+	body, err := json.Marshal(gossipChange{Path: path, Value: value})
+	if err != nil {
+		return fmt.Errorf("unable to encode change: %w", err)
+	}
+
+	if err := g.apply(ctx, path, value); err != nil {
+		return err
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures []*PeerError
+	)
+
+	for _, peer := range g.Peers {
+		wg.Add(1)
+		go func(peer string) {
+			defer wg.Done()
+
+			if err := g.send(ctx, peer, body, false); err != nil {
+				mu.Lock()
+				failures = append(failures, &PeerError{Peer: peer, Err: err})
+				mu.Unlock()
+			}
+		}(peer)
+	}
+
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return &GossipError{Errors: failures}
+	}
+
+	return nil
+}
+
+// forward the change made by the caller in the ctx to the leader. It is authorized and guarded here as the leader trusts its peers, and parked by the leader when it requires approval.
+func (g *Gossip) forward(ctx context.Context, leader, path, value string) error {
+	setting := g.Set.lookup(path)
+	if setting == nil {
+		return &SettingError{Path: path, Err: ErrUnknownSetting}
+	}
+	if err := g.Set.authorize(ctx, setting); err != nil {
+		return &SettingError{Path: path, Err: err}
+	}
+	if err := g.Set.guard(ctx, setting, value); err != nil {
+		return &SettingError{Path: path, Err: err}
+	}
+
+	body, err := json.Marshal(gossipChange{Path: path, Value: value, Actor: ActorFromContext(ctx)})
+	if err != nil {
+		return fmt.Errorf("unable to encode change: %w", err)
+	}
+
+	if err := g.send(ctx, leader, body, true); err != nil {
+		if errors.Is(err, ErrApprovalPending) {
+			return &SettingError{Path: path, Err: err}
+		}
+		return &PeerError{Peer: leader, Err: err}
+	}
+
+	return nil
+}
+
+// apply the change to the Set on behalf of the caller in the ctx, only when the setting is still at the revision expected by an If-Match header
+func (g *Gossip) apply(ctx context.Context, path, value string) error {
+	var (
+		found bool
+		err   error
+	)
+	if revision, ok := ifMatch(ctx); ok {
+		found, err = g.Set.UpdateIfMatch(ctx, path, value, revision)
+	} else {
+		found, err = g.Set.UpdateContext(ctx, path, value)
+	}
+	if !found {
+		return &SettingError{Path: path, Err: ErrUnknownSetting}
+	}
+	if err != nil {
+		return &SettingError{Path: path, Err: err}
+	}
+
+	return nil
+}
+
+// send the encoded change to the peer, a forwarded change is propagated by the peer or resent to the leader it refers the change to
+func (g *Gossip) send(ctx context.Context, peer string, body []byte, forward bool) error {
+	for referrals := 0; ; referrals++ {
+		leader, err := g.post(ctx, peer, body, forward)
+		if err != nil || leader == "" {
+			return err
+		}
+
+		if referrals == maxLeaderReferrals {
+			return fmt.Errorf("unable to reach leader: referred to %s after %d referrals", leader, referrals)
+		}
+
+		peer = leader
+	}
+}
+
+// post the encoded change to the peer, returning the leader the peer refers a forwarded change to
+func (g *Gossip) post(ctx context.Context, peer string, body []byte, forward bool) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, peer, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if forward {
+		req.Header.Set(forwardHeader, "true")
+	}
+	if g.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+	case http.StatusMisdirectedRequest:
+		if leader := resp.Header.Get(leaderHeader); forward && leader != "" {
+			return leader, nil
+		}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	case http.StatusAccepted:
+		// parked by the leader, the message names the approval request
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("%w: %s", ErrApprovalPending, strings.TrimSpace(string(message)))
+	case http.StatusForbidden:
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("%w: %s", ErrForbidden, strings.TrimSpace(string(message)))
+	default:
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return "", nil
+}
+
+// ServeHTTP applies changes received from peers, the leader also propagates changes forwarded by followers. A change with an If-Match header is only applied when the setting is still at that revision, responding 412 Precondition Failed otherwise (see Set.UpdateIfMatch), and the Setting.Revision after a write is returned as the ETag.
+func (g *Gossip) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// received changes are trusted, which only authenticated peers may make
+	if g.Token == "" {
+		http.Error(w, "gossip requires a token", http.StatusForbidden)
+		return
+	}
+
+	if !checkBearer(w, r, g.Token) {
+		return
+	}
+
+	var change gossipChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		http.Error(w, fmt.Sprintf("unable to decode change: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx, err := withIfMatch(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Header.Get(forwardHeader) != "" && g.Leader != nil {
+		leader, self := g.Leader()
+		switch {
+		case leader == "" && !self:
+			http.Error(w, ErrNoLeader.Error(), http.StatusServiceUnavailable)
+			return
+		case !self:
+			// the sender resends the change to the leader
+			w.Header().Set(leaderHeader, leader)
+			http.Error(w, fmt.Sprintf("not the leader, forward to %s", leader), http.StatusMisdirectedRequest)
+			return
+		}
+
+		// changes from peers were authorized by the peer they were made on, those requiring approval are parked for the actor that made them
+		if setting := g.Set.lookup(change.Path); setting != nil && setting.requiresApproval() {
+			if revision, ok := ifMatch(ctx); ok && setting.Revision() != revision {
+				err = fmt.Errorf("%w: expected %d; got %d", ErrRevisionMismatch, revision, setting.Revision())
+			} else {
+				_, err = g.Set.park(WithActor(ctx, change.Actor), setting, change.Value)
+			}
+		} else {
+			err = g.Update(trusted(ctx), change.Path, change.Value)
+		}
+	} else {
+		err = g.apply(trusted(ctx), change.Path, change.Value)
+	}
+
+	var gossipErr *GossipError
+	switch {
+	case errors.Is(err, ErrApprovalPending):
+		http.Error(w, err.Error(), http.StatusAccepted)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrUnknownSetting):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrRevisionMismatch):
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+	case errors.As(err, &gossipErr):
+		http.Error(w, fmt.Sprintf("applied but not propagated: %v", err), http.StatusBadGateway)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		if setting := g.Set.lookup(change.Path); setting != nil {
+			w.Header().Set("ETag", etag(setting.Revision()))
+		}
+		w.Header().Set(RevisionHeader, strconv.FormatUint(g.Set.Revision(), 10))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
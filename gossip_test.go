@@ -0,0 +1,200 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGossip_Update(t *testing.T) {
+	var (
+		portA = 80
+		portB = 80
+	)
+
+	setA := &Set{}
+	setA.Setting("Port", &portA, "")
+	setB := &Set{}
+	setB.Setting("Port", &portB, "")
+
+	a := &Gossip{Set: setA, Token: "token"}
+	b := &Gossip{Set: setB, Token: "token"}
+
+	serverA := httptest.NewServer(a)
+	defer serverA.Close()
+	serverB := httptest.NewServer(b)
+	defer serverB.Close()
+
+	a.Peers = []string{serverB.URL}
+	b.Peers = []string{serverA.URL}
+
+	if err := a.Update(context.Background(), "Port", "8080"); err != nil {
+		t.Fatalf("Failed to propagate change: %v", err)
+	}
+
+	if portA != 8080 || portB != 8080 {
+		t.Errorf("Failed to propagate change: expected %d; got %d and %d", 8080, portA, portB)
+	}
+
+	err := a.Update(context.Background(), "Port", "nope")
+	if err == nil || portB != 8080 {
+		t.Errorf("Failed to reject invalid value: got %v and %d", err, portB)
+	}
+
+	b.Token = "other"
+	err = a.Update(context.Background(), "Port", "9090")
+
+	var gossipErr *GossipError
+	if !errors.As(err, &gossipErr) || len(gossipErr.Errors) != 1 || portA != 9090 || portB != 8080 {
+		t.Errorf("Failed to report rejected peer: got %v with %d and %d", err, portA, portB)
+	}
+}
+
+func TestGossip_Leader(t *testing.T) {
+	var (
+		ports   = make([]int, 3)
+		gossips = make([]*Gossip, 3)
+		urls    = make([]string, 3)
+	)
+
+	for i := range gossips {
+		set := &Set{}
+		set.Setting("Port", &ports[i], "")
+		gossips[i] = &Gossip{Set: set, Token: "token"}
+
+		server := httptest.NewServer(gossips[i])
+		defer server.Close()
+		urls[i] = server.URL
+	}
+
+	leader := 0
+	for i, g := range gossips {
+		i := i
+		for j := range gossips {
+			if j != i {
+				g.Peers = append(g.Peers, urls[j])
+			}
+		}
+		g.Leader = func() (string, bool) {
+			if leader < 0 {
+				return "", false
+			}
+			return urls[leader], leader == i
+		}
+	}
+
+	// follower forwards to the leader which propagates to everyone
+	if err := gossips[1].Update(context.Background(), "Port", "8080"); err != nil {
+		t.Fatalf("Failed to forward change: %v", err)
+	}
+
+	for i, port := range ports {
+		if port != 8080 {
+			t.Errorf("Failed to propagate forwarded change to %d: expected %d; got %d", i, 8080, port)
+		}
+	}
+
+	// a stale follower forwarding to another follower is referred to the leader and resends the change with its token, the other follower is named by another host than the leader so a redirect would drop the token
+	stale := gossips[1].Leader
+	follower := strings.Replace(urls[2], "127.0.0.1", "localhost", 1)
+	gossips[1].Leader = func() (string, bool) { return follower, false }
+
+	if err := gossips[1].Update(context.Background(), "Port", "9090"); err != nil || ports[0] != 9090 || ports[1] != 9090 {
+		t.Errorf("Failed to refer change to leader: got %v with %v", err, ports)
+	}
+	gossips[1].Leader = stale
+
+	leader = -1
+	if err := gossips[1].Update(context.Background(), "Port", "1"); !errors.Is(err, ErrNoLeader) || ports[1] != 9090 {
+		t.Errorf("Failed to reject change without leader: got %v with %d", err, ports[1])
+	}
+}
+
+func TestGossip_LeaderChecks(t *testing.T) {
+	var (
+		leaderPort   = 80
+		followerPort = 80
+	)
+
+	leaderSet := &Set{}
+	leaderSet.Setting("Port", &leaderPort, "").Labels = []string{LabelApprovalRequired}
+	followerSet := &Set{}
+	followerSet.Setting("Port", &followerPort, "").Labels = []string{LabelApprovalRequired}
+	followerSet.Guard(Guards{MaxMagnitude: 65535})
+
+	leader := &Gossip{Set: leaderSet, Token: "token"}
+	leaderServer := httptest.NewServer(leader)
+	defer leaderServer.Close()
+
+	follower := &Gossip{Set: followerSet, Token: "token", Peers: []string{leaderServer.URL}}
+	leader.Leader = func() (string, bool) { return leaderServer.URL, true }
+	follower.Leader = func() (string, bool) { return leaderServer.URL, false }
+
+	// guarded by the follower before forwarding
+	ctx := WithActor(context.Background(), "alice")
+	if err := follower.Update(ctx, "Port", "70000"); !errors.Is(err, ErrGuardRejected) {
+		t.Errorf("Failed to guard forwarded change: expected %v; got %v", ErrGuardRejected, err)
+	}
+
+	// parked by the leader for the actor that made it
+	if err := follower.Update(ctx, "Port", "8080"); !errors.Is(err, ErrApprovalPending) {
+		t.Fatalf("Failed to park forwarded change: expected %v; got %v", ErrApprovalPending, err)
+	}
+
+	pending := leaderSet.PendingApprovals()
+	if len(pending) != 1 || pending[0].RequestedBy != "alice" || pending[0].To != "8080" || leaderPort != 80 || followerPort != 80 {
+		t.Errorf("Failed to park forwarded change: got %+v with %d and %d", pending, leaderPort, followerPort)
+	}
+
+	if err := follower.Update(context.Background(), "Port", "8080"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Failed to require actor: expected %v; got %v", ErrForbidden, err)
+	}
+}
+
+func TestGossip_IfMatch(t *testing.T) {
+	port := 80
+	set := &Set{}
+	set.Setting("Port", &port, "")
+	g := &Gossip{Set: set, Token: "token"}
+
+	post := func(ifMatch, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer token")
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, r)
+		return w
+	}
+
+	w := post("", `{"path": "Port", "value": "8080"}`)
+	revision := w.Header().Get("ETag")
+	if w.Code != http.StatusNoContent || revision == "" {
+		t.Fatalf("Failed to apply change: got %d with ETag %q", w.Code, revision)
+	}
+
+	if w := post(revision, `{"path": "Port", "value": "9090"}`); w.Code != http.StatusNoContent || port != 9090 {
+		t.Errorf("Failed to apply matching change: got %d with %d", w.Code, port)
+	}
+	if w := post(revision, `{"path": "Port", "value": "7070"}`); w.Code != http.StatusPreconditionFailed || port != 9090 {
+		t.Errorf("Failed to reject stale change: expected %d; got %d with %d", http.StatusPreconditionFailed, w.Code, port)
+	}
+}
+
+func TestGossip_RequiresToken(t *testing.T) {
+	port := 80
+	set := &Set{}
+	set.Setting("Port", &port, "")
+
+	w := httptest.NewRecorder()
+	(&Gossip{Set: set}).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"path": "Port", "value": "8080"}`)))
+
+	if w.Code != http.StatusForbidden || port != 80 {
+		t.Errorf("Failed to reject change without a token: expected %d; got %d with %d", http.StatusForbidden, w.Code, port)
+	}
+}
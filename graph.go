@@ -0,0 +1,114 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"go/parser"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Dependency is an edge of the dependency graph of a Set, a change of From cascades into To
+type Dependency struct {
+	// From is the path of the setting depended on
+	From string
+
+	// To is the path of the dependent setting
+	To string
+
+	// Kind of the dependency, "derive" for settings created by Set.Derive or Set.Gate and "constraint" for settings whose Constraint references From
+	Kind string
+}
+
+// Dependencies returns the dependency graph of the settings within the Set, sorted by From and To
+func (s *Set) Dependencies() []Dependency {
+	var deps []Dependency
+	s.Range(func(_ string, setting *Setting) bool {
+		for _, path := range setting.dependencies {
+			deps = append(deps, Dependency{From: path, To: setting.Path, Kind: "derive"})
+		}
+
+		if setting.Constraint == "" {
+			return true
+		}
+
+		// invalid constraints are reported when they are evaluated
+		node, err := parser.ParseExpr(setting.Constraint)
+		if err != nil {
+			return true
+		}
+
+		seen := map[string]bool{}
+		for _, path := range references(node) {
+			if dep := setting.set.referenced(path); dep != nil && !seen[dep.Path] {
+				seen[dep.Path] = true
+				deps = append(deps, Dependency{From: dep.Path, To: setting.Path, Kind: "constraint"})
+			}
+		}
+
+		return true
+	})
+
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].From != deps[j].From {
+			return deps[i].From < deps[j].From
+		}
+		return deps[i].To < deps[j].To
+	})
+
+	return deps
+}
+
+// Dependents returns the sorted paths of every setting within the Set a change of the setting at path cascades into, directly or transitively
+func (s *Set) Dependents(path string) []string {
+	setting := s.lookup(path)
+	if setting == nil {
+		return nil
+	}
+
+	edges := map[string][]string{}
+	for _, dep := range s.Dependencies() {
+		edges[strings.ToLower(dep.From)] = append(edges[strings.ToLower(dep.From)], dep.To)
+	}
+
+	seen := map[string]bool{}
+	queue := []string{setting.Path}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+
+		for _, to := range edges[strings.ToLower(from)] {
+			if !seen[to] && !strings.EqualFold(to, setting.Path) {
+				seen[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	dependents := make([]string, 0, len(seen))
+	for path := range seen {
+		dependents = append(dependents, path)
+	}
+	sort.Strings(dependents)
+
+	return dependents
+}
+
+// WriteDOT writes the dependency graph of the Set in the Graphviz DOT language, constraint edges are dashed. Settings without dependencies are left out.
+func (s *Set) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph config {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	for _, dep := range s.Dependencies() {
+		style := ""
+		if dep.Kind == "constraint" {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(bw, "  %q -> %q%s;\n", dep.From, dep.To, style)
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSet_Dependencies(t *testing.T) {
+	s := &Set{}
+	dataDir := s.Setting("DataDir", "/var/lib/app", "")
+	s.Derive("CacheDir", "", "", func() string { return dataDir.String() + "/cache" }, "DataDir")
+	s.Derive("CacheIndex", "", "", func() string { return s.Get("CacheDir").String() + "/index" }, "CacheDir")
+	s.Setting("Env", "dev", "")
+	s.Gate("Persistent", `DataDir != "" && Env == "prod"`, "")
+	s.Setting("MaxCache", 10, "").Constraint = `this < len(CacheDir) * 100`
+
+	expected := "[{CacheDir CacheIndex derive} {CacheDir MaxCache constraint} {DataDir CacheDir derive} {DataDir Persistent derive} {Env Persistent derive}]"
+	if got := fmt.Sprint(s.Dependencies()); got != expected {
+		t.Errorf("Failed to list dependencies: expected %s; got %s", expected, got)
+	}
+
+	if got := s.Dependents("datadir"); fmt.Sprint(got) != "[CacheDir CacheIndex MaxCache Persistent]" {
+		t.Errorf("Failed to find dependents: got %v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteDOT(&buf); err != nil {
+		t.Fatalf("Failed to write DOT: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, `"DataDir" -> "CacheDir";`) || !strings.Contains(out, `"CacheDir" -> "MaxCache" [style=dashed];`) {
+		t.Errorf("Failed to write DOT:\n%s", out)
+	}
+}
@@ -0,0 +1,889 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WriteGraphQL writes a GraphQL schema (SDL) describing the Set as a typed graph: subsets are object types, settings are fields with their description, and the path, default, category, labels and annotations of every setting are queryable through the Setting type. Masked settings are nullable strings so resolvers can return null.
+//
+// The schema is served and resolved by GraphQLServer, it can also be wired to the GraphQL library an internal platform has standardized on: Query.config resolves from Set.Get, Query.setting and Query.search from Set.Get and Set.Search, and Subscription.changed from Set.Notify.
+func (s *Set) WriteGraphQL(w io.Writer) error {
+	// object types by name, with their fields by name
+	objects := map[string]map[string]string{}
+	descriptions := map[string]string{}
+
+	rootType := "Config"
+	objects[rootType] = map[string]string{}
+
+	s.Range(func(_ string, setting *Setting) bool {
+		path := setting.Path
+		if s.path != "" {
+			path = strings.TrimPrefix(path[len(s.path):], ".")
+		}
+
+		segments := strings.Split(path, ".")
+
+		parent := rootType
+		for _, segment := range segments[:len(segments)-1] {
+			child := parent + "_" + graphqlName(segment)
+			if objects[child] == nil {
+				objects[child] = map[string]string{}
+			}
+			objects[parent][graphqlName(segment)] = child + "!"
+			parent = child
+		}
+
+		field := graphqlName(segments[len(segments)-1])
+		objects[parent][field] = graphqlType(setting)
+		descriptions[parent+"."+field] = setting.Description
+
+		return true
+	})
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprint(bw, `type Query {
+  "Current values of the settings"
+  config: Config!
+  "Setting by path, null when it does not exist"
+  setting(path: String!): Setting
+  "Settings matching the query, best matches first"
+  search(query: String!): [Setting!]!
+}
+
+type Subscription {
+  "Changes of settings within the prefix, every setting when omitted"
+  changed(prefix: String): SettingChange!
+}
+
+"A setting and its metadata, masked values are null"
+type Setting {
+  path: String!
+  value: String
+  default: String
+  description: String!
+  category: String!
+  labels: [String!]!
+  annotations: [Annotation!]!
+}
+
+type Annotation {
+  key: String!
+  value: String!
+}
+
+"A change of a setting, masked values are *****"
+type SettingChange {
+  path: String!
+  value: String!
+  time: String!
+}
+`)
+
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fields := objects[name]
+		if len(fields) == 0 {
+			continue
+		}
+
+		fieldNames := make([]string, 0, len(fields))
+		for field := range fields {
+			fieldNames = append(fieldNames, field)
+		}
+		sort.Strings(fieldNames)
+
+		fmt.Fprintf(bw, "\ntype %s {\n", name)
+		for _, field := range fieldNames {
+			if description := descriptions[name+"."+field]; description != "" {
+				fmt.Fprintf(bw, "  %q\n", description)
+			}
+			fmt.Fprintf(bw, "  %s: %s\n", field, fields[field])
+		}
+		fmt.Fprintln(bw, "}")
+	}
+
+	return bw.Flush()
+}
+
+// graphqlType returns the GraphQL type of the setting value, types that do not fit a built in scalar are strings
+func graphqlType(setting *Setting) string {
+	if setting.Mask {
+		return "String"
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(setting.Value)).Kind() {
+	case reflect.Bool:
+		return "Boolean!"
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "Int!"
+	case reflect.Float32, reflect.Float64:
+		return "Float!"
+	}
+
+	// Int is 32-bit in GraphQL, larger integers (and durations) are strings to avoid overflow
+	return "String!"
+}
+
+// graphqlName replaces the characters not allowed in GraphQL names with underscores
+func graphqlName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// GraphQLServer serves the Set over HTTP as the GraphQL schema written by Set.WriteGraphQL, for admin tooling standardized on GraphQL. A GET without a query returns the schema, queries are accepted as the query (and variables) parameter of a GET or a JSON body of a POST, and subscriptions are streamed as server-sent events (next events carrying the result of every change) until the client disconnects. Masked values are null, or ***** in changes.
+//
+// A single operation of fields, aliases, arguments and variables is supported, fragments, directives, mutations and introspection queries are not.
+type GraphQLServer struct {
+	// Set being exposed
+	Set *Set
+
+	// Token, when not empty, is required from clients as a bearer token
+	Token string
+}
+
+// graphqlRequest is a GraphQL request as sent by clients
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP writes the schema, or executes the query or subscription of the request
+func (gs *GraphQLServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkBearer(w, r, gs.Token) {
+		return
+	}
+
+	var request graphqlRequest
+	switch r.Method {
+	case http.MethodGet:
+		request.Query = r.URL.Query().Get("query")
+		if request.Query == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_ = gs.Set.WriteGraphQL(w)
+			return
+		}
+
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				writeGraphQLError(w, fmt.Errorf("unable to decode variables: %w", err))
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeGraphQLError(w, fmt.Errorf("unable to decode request: %w", err))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	operation, fields, err := parseGraphQL(request.Query, request.Variables)
+	if err != nil {
+		writeGraphQLError(w, err)
+		return
+	}
+
+	if operation == "subscription" {
+		gs.subscribe(w, r, fields)
+		return
+	}
+
+	data, err := gs.query(fields)
+	if err != nil {
+		writeGraphQLError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(graphqlObject{{key: "data", value: data}})
+}
+
+// writeGraphQLError writes the error of a request that could not be executed as a GraphQL response
+func writeGraphQLError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"message": err.Error()}},
+	})
+}
+
+// query resolves the fields of the Query type
+func (gs *GraphQLServer) query(fields []graphqlField) (graphqlObject, error) {
+	return graphqlSelect("Query", fields, func(field graphqlField) (interface{}, error) {
+		switch field.name {
+		case "config":
+			if err := field.selection(true); err != nil {
+				return nil, err
+			}
+			return gs.config("Config", gs.Set.graphqlTree(), field.fields)
+
+		case "setting":
+			path, err := field.argument("path", true)
+			if err != nil {
+				return nil, err
+			}
+			if err := field.selection(true); err != nil {
+				return nil, err
+			}
+
+			setting := gs.Set.lookup(path)
+			if setting == nil {
+				return nil, nil
+			}
+			return graphqlSetting(setting, field.fields)
+
+		case "search":
+			query, err := field.argument("query", true)
+			if err != nil {
+				return nil, err
+			}
+			if err := field.selection(true); err != nil {
+				return nil, err
+			}
+
+			results := []interface{}{}
+			for _, setting := range gs.Set.Search(query) {
+				result, err := graphqlSetting(setting, field.fields)
+				if err != nil {
+					return nil, err
+				}
+				results = append(results, result)
+			}
+			return results, nil
+		}
+
+		return nil, fmt.Errorf("unknown field %s of Query", field.name)
+	})
+}
+
+// graphqlNode is a subset or setting of the typed graph of a Set, see Set.WriteGraphQL
+type graphqlNode struct {
+	setting  *Setting
+	children map[string]*graphqlNode
+}
+
+// graphqlTree returns the typed graph of the Set, keyed by GraphQL names like Set.WriteGraphQL
+func (s *Set) graphqlTree() *graphqlNode {
+	root := &graphqlNode{children: map[string]*graphqlNode{}}
+
+	s.Range(func(_ string, setting *Setting) bool {
+		segments := strings.Split(s.relative(setting.Path), ".")
+
+		node := root
+		for _, segment := range segments[:len(segments)-1] {
+			name := graphqlName(segment)
+			if node.children[name] == nil {
+				node.children[name] = &graphqlNode{children: map[string]*graphqlNode{}}
+			}
+			node = node.children[name]
+		}
+
+		node.children[graphqlName(segments[len(segments)-1])] = &graphqlNode{setting: setting}
+		return true
+	})
+
+	return root
+}
+
+// config resolves the fields of the object type of the node
+func (gs *GraphQLServer) config(typeName string, node *graphqlNode, fields []graphqlField) (graphqlObject, error) {
+	return graphqlSelect(typeName, fields, func(field graphqlField) (interface{}, error) {
+		child := node.children[field.name]
+		switch {
+		case child == nil:
+			return nil, fmt.Errorf("unknown field %s of %s", field.name, typeName)
+		case child.setting != nil:
+			if err := field.selection(false); err != nil {
+				return nil, err
+			}
+			return graphqlValue(child.setting), nil
+		}
+
+		if err := field.selection(true); err != nil {
+			return nil, err
+		}
+		return gs.config(typeName+"_"+field.name, child, field.fields)
+	})
+}
+
+// graphqlValue returns the value of the setting as the GraphQL type of Set.WriteGraphQL, null for masked settings
+func graphqlValue(setting *Setting) interface{} {
+	if setting.Mask {
+		return nil
+	}
+
+	v := setting.format()
+	switch graphqlType(setting) {
+	case "Boolean!":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case "Int!":
+		if i, err := strconv.ParseInt(v, 10, 32); err == nil {
+			return i
+		}
+	case "Float!":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+
+	return setting.display(v)
+}
+
+// graphqlSetting resolves the fields of the Setting type
+func graphqlSetting(setting *Setting, fields []graphqlField) (graphqlObject, error) {
+	return graphqlSelect("Setting", fields, func(field graphqlField) (interface{}, error) {
+		if field.name == "annotations" {
+			if err := field.selection(true); err != nil {
+				return nil, err
+			}
+
+			keys := make([]string, 0, len(setting.Annotations))
+			for key := range setting.Annotations {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			annotations := []interface{}{}
+			for _, key := range keys {
+				annotation, err := graphqlSelect("Annotation", field.fields, func(field graphqlField) (interface{}, error) {
+					if err := field.selection(false); err != nil {
+						return nil, err
+					}
+
+					switch field.name {
+					case "key":
+						return key, nil
+					case "value":
+						return setting.Annotations[key], nil
+					}
+
+					return nil, fmt.Errorf("unknown field %s of Annotation", field.name)
+				})
+				if err != nil {
+					return nil, err
+				}
+				annotations = append(annotations, annotation)
+			}
+			return annotations, nil
+		}
+
+		if err := field.selection(false); err != nil {
+			return nil, err
+		}
+
+		switch field.name {
+		case "path":
+			return setting.Path, nil
+		case "value":
+			if setting.Mask {
+				return nil, nil
+			}
+			return setting.display(setting.format()), nil
+		case "default":
+			if setting.Mask {
+				return nil, nil
+			}
+			return setting.display(setting.DefaultValue), nil
+		case "description":
+			return setting.Description, nil
+		case "category":
+			return setting.Category, nil
+		case "labels":
+			return append([]string{}, setting.Labels...), nil
+		}
+
+		return nil, fmt.Errorf("unknown field %s of Setting", field.name)
+	})
+}
+
+// graphqlChange is a change of a setting streamed to subscribers
+type graphqlChange struct {
+	path  string
+	value string
+	time  time.Time
+}
+
+// object resolves the fields of the SettingChange type
+func (c graphqlChange) object(fields []graphqlField) (graphqlObject, error) {
+	return graphqlSelect("SettingChange", fields, func(field graphqlField) (interface{}, error) {
+		if err := field.selection(false); err != nil {
+			return nil, err
+		}
+
+		switch field.name {
+		case "path":
+			return c.path, nil
+		case "value":
+			return c.value, nil
+		case "time":
+			return c.time.UTC().Format(time.RFC3339Nano), nil
+		}
+
+		return nil, fmt.Errorf("unknown field %s of SettingChange", field.name)
+	})
+}
+
+// subscribe streams the changes of the Subscription.changed field as server-sent events until the client disconnects
+func (gs *GraphQLServer) subscribe(w http.ResponseWriter, r *http.Request, fields []graphqlField) {
+	if len(fields) != 1 || fields[0].name != "changed" {
+		writeGraphQLError(w, fmt.Errorf("a subscription selects the changed field only"))
+		return
+	}
+
+	field := fields[0]
+	prefix, err := field.argument("prefix", false)
+	if err == nil {
+		err = field.selection(true)
+	}
+	if err == nil {
+		// the selection is checked before streaming, so the client is told about mistakes
+		_, err = graphqlChange{}.object(field.fields)
+	}
+	if err != nil {
+		writeGraphQLError(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	var (
+		mu      sync.Mutex
+		changes []graphqlChange
+		signal  = make(chan struct{}, 1)
+	)
+
+	handle := gs.Set.Notify(NotifyFunc(func(setting *Setting) {
+		if !within(setting.Path, prefix) {
+			return
+		}
+
+		mu.Lock()
+		changes = append(changes, graphqlChange{path: setting.Path, value: setting.display(setting.format()), time: gs.Set.clock().Now()})
+		mu.Unlock()
+
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}))
+	defer handle.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-signal:
+		}
+
+		mu.Lock()
+		pending := changes
+		changes = nil
+		mu.Unlock()
+
+		for _, change := range pending {
+			object, err := change.object(field.fields)
+			if err != nil {
+				return
+			}
+
+			data, err := json.Marshal(graphqlObject{{key: "data", value: graphqlObject{{key: field.alias, value: object}}}})
+			if err != nil {
+				return
+			}
+
+			if _, err := fmt.Fprintf(w, "event: next\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// graphqlObject is a resolved object, its fields are kept in the order they were selected
+type graphqlObject []graphqlEntry
+
+// graphqlEntry is a resolved field of an object
+type graphqlEntry struct {
+	key   string
+	value interface{}
+}
+
+// MarshalJSON implements json.Marshaler keeping the fields in order
+func (o graphqlObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, entry := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(entry.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(entry.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// graphqlSelect resolves the selected fields of an object of the type, __typename is resolved for every type
+func graphqlSelect(typeName string, fields []graphqlField, resolve func(graphqlField) (interface{}, error)) (graphqlObject, error) {
+	object := graphqlObject{}
+	for _, field := range fields {
+		if field.name == "__typename" {
+			object = append(object, graphqlEntry{key: field.alias, value: typeName})
+			continue
+		}
+
+		v, err := resolve(field)
+		if err != nil {
+			return nil, err
+		}
+		object = append(object, graphqlEntry{key: field.alias, value: v})
+	}
+
+	return object, nil
+}
+
+// graphqlField is a field selected by a query
+type graphqlField struct {
+	alias     string
+	name      string
+	arguments map[string]interface{}
+	fields    []graphqlField
+}
+
+// selection returns an error unless the field has a selection set when it is an object, and none when it is a scalar
+func (f graphqlField) selection(object bool) error {
+	switch {
+	case object && f.fields == nil:
+		return fmt.Errorf("field %s must have a selection", f.name)
+	case !object && f.fields != nil:
+		return fmt.Errorf("field %s can not have a selection", f.name)
+	}
+
+	return nil
+}
+
+// argument returns the String argument of the field, an error when it is required and missing or null
+func (f graphqlField) argument(name string, required bool) (string, error) {
+	switch v := f.arguments[name].(type) {
+	case string:
+		return v, nil
+	case nil:
+		if required {
+			return "", fmt.Errorf("argument %s of field %s is required", name, f.name)
+		}
+		return "", nil
+	}
+
+	return "", fmt.Errorf("argument %s of field %s must be a String", name, f.name)
+}
+
+// graphqlParser parses a GraphQL document of a single operation, variables are substituted as they are parsed
+type graphqlParser struct {
+	src       string
+	pos       int
+	variables map[string]interface{}
+}
+
+// parseGraphQL parses the query, returning its operation type (query or subscription) and selected fields
+func parseGraphQL(query string, variables map[string]interface{}) (string, []graphqlField, error) {
+	p := &graphqlParser{src: query, variables: variables}
+
+	operation := "query"
+	if p.peek() != '{' {
+		keyword, err := p.name()
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch keyword {
+		case "query", "subscription":
+			operation = keyword
+		case "mutation", "fragment":
+			return "", nil, fmt.Errorf("%s is not supported", keyword)
+		default:
+			return "", nil, fmt.Errorf("unexpected %s", keyword)
+		}
+
+		// the name of the operation is not needed
+		if c := p.peek(); c != '{' && c != '(' {
+			if _, err := p.name(); err != nil {
+				return "", nil, err
+			}
+		}
+
+		// the variables are typed by the client, their definitions are skipped
+		if p.peek() == '(' {
+			if err := p.skipDefinitions(); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	fields, err := p.selections()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if p.peek() != 0 {
+		return "", nil, fmt.Errorf("only a single operation is supported")
+	}
+
+	return operation, fields, nil
+}
+
+// skip the whitespace, commas and comments
+func (p *graphqlParser) skip() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r', ',':
+			p.pos++
+		case '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next character after skipping, 0 at the end of the document
+func (p *graphqlParser) peek() byte {
+	p.skip()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+// expect the next character to be c, moving past it
+func (p *graphqlParser) expect(c byte) error {
+	switch next := p.peek(); next {
+	case c:
+		p.pos++
+		return nil
+	case 0:
+		return fmt.Errorf("expected %q at end of query", c)
+	default:
+		return fmt.Errorf("expected %q at %d, found %q", c, p.pos, next)
+	}
+}
+
+// name parses the next name
+func (p *graphqlParser) name() (string, error) {
+	p.skip()
+
+	start := p.pos
+	for ; p.pos < len(p.src); p.pos++ {
+		c := p.src[p.pos]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (p.pos == start || c < '0' || c > '9') {
+			break
+		}
+	}
+
+	switch {
+	case p.pos > start:
+		return p.src[start:p.pos], nil
+	case start == len(p.src):
+		return "", fmt.Errorf("unexpected end of query")
+	default:
+		return "", fmt.Errorf("unexpected %q at %d", p.src[start], start)
+	}
+}
+
+// skipDefinitions moves past the parenthesized variable definitions
+func (p *graphqlParser) skipDefinitions() error {
+	p.pos++
+	for {
+		switch p.peek() {
+		case 0:
+			return fmt.Errorf("unterminated variable definitions")
+		case ')':
+			p.pos++
+			return nil
+		case '"':
+			if _, err := p.string(); err != nil {
+				return err
+			}
+		default:
+			p.pos++
+		}
+	}
+}
+
+// selections parses the selection set
+func (p *graphqlParser) selections() ([]graphqlField, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	fields := []graphqlField{}
+	for {
+		switch p.peek() {
+		case 0:
+			return nil, fmt.Errorf("unterminated selection set")
+		case '}':
+			p.pos++
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("empty selection set")
+			}
+			return fields, nil
+		case '.':
+			return nil, fmt.Errorf("fragments are not supported")
+		}
+
+		field, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+}
+
+// field parses the selected field with its alias, arguments and selection set
+func (p *graphqlParser) field() (graphqlField, error) {
+	name, err := p.name()
+	if err != nil {
+		return graphqlField{}, err
+	}
+
+	field := graphqlField{alias: name, name: name}
+	if p.peek() == ':' {
+		p.pos++
+		if field.name, err = p.name(); err != nil {
+			return graphqlField{}, err
+		}
+	}
+
+	if p.peek() == '(' {
+		p.pos++
+		field.arguments = map[string]interface{}{}
+		for p.peek() != ')' {
+			argument, err := p.name()
+			if err != nil {
+				return graphqlField{}, err
+			}
+			if err := p.expect(':'); err != nil {
+				return graphqlField{}, err
+			}
+			if field.arguments[argument], err = p.value(); err != nil {
+				return graphqlField{}, err
+			}
+		}
+		p.pos++
+	}
+
+	switch p.peek() {
+	case '@':
+		return graphqlField{}, fmt.Errorf("directives are not supported")
+	case '{':
+		if field.fields, err = p.selections(); err != nil {
+			return graphqlField{}, err
+		}
+	}
+
+	return field, nil
+}
+
+// value parses an argument value: a string, boolean, null or variable
+func (p *graphqlParser) value() (interface{}, error) {
+	switch p.peek() {
+	case '$':
+		p.pos++
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		return p.variables[name], nil
+	case '"':
+		return p.string()
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "null":
+		return nil, nil
+	case "true", "false":
+		return name == "true", nil
+	}
+
+	return nil, fmt.Errorf("unsupported value %s", name)
+}
+
+// string parses the quoted string, block strings are not supported
+func (p *graphqlParser) string() (string, error) {
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		return "", fmt.Errorf("block strings are not supported")
+	}
+
+	end := closingQuote(p.src[p.pos:])
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string at %d", p.pos)
+	}
+
+	var s string
+	if err := json.Unmarshal([]byte(p.src[p.pos:p.pos+end+1]), &s); err != nil {
+		return "", fmt.Errorf("invalid string at %d: %w", p.pos, err)
+	}
+	p.pos += end + 1
+
+	return s, nil
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_WriteGraphQL(t *testing.T) {
+	s := &Set{}
+	s.Setting("Debug", false, "Enable debug logging")
+	http := s.Subset("HTTP")
+	http.Setting("Max-Conns", int16(100), "")
+	http.Setting("Token", "secret", "").Mask = true
+	http.Subset("TLS").Setting("Timeout", 1.5, "")
+
+	var buf bytes.Buffer
+	if err := s.WriteGraphQL(&buf); err != nil {
+		t.Fatalf("Failed to write GraphQL: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"type Config {\n  \"Enable debug logging\"\n  Debug: Boolean!\n  HTTP: Config_HTTP!\n}",
+		"type Config_HTTP {\n  Max_Conns: Int!\n  TLS: Config_HTTP_TLS!\n  Token: String\n}",
+		"type Config_HTTP_TLS {\n  Timeout: Float!\n}",
+		"changed(prefix: String): SettingChange!",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Failed to write %q:\n%s", expected, out)
+		}
+	}
+
+	if strings.Contains(out, "secret") {
+		t.Errorf("Failed to keep masked value out of schema:\n%s", out)
+	}
+}
+
+func TestGraphQLServer_Query(t *testing.T) {
+	s := &Set{}
+	s.Setting("Debug", false, "Enable debug logging").Labels = []string{"team:core"}
+	subset := s.Subset("HTTP")
+	subset.Setting("Max-Conns", int16(100), "Maximum connections")
+	subset.Setting("Token", "secret", "").Mask = true
+
+	server := &GraphQLServer{Set: s, Token: "token"}
+
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		expected string
+	}{
+		{
+			name:     "config",
+			body:     `{"query": "{ config { Debug HTTP { conns: Max_Conns Token __typename } } }"}`,
+			status:   200,
+			expected: `{"data":{"config":{"Debug":false,"HTTP":{"conns":100,"Token":null,"__typename":"Config_HTTP"}}}}`,
+		},
+		{
+			name:     "setting",
+			body:     `{"query": "query Lookup($path: String!) { setting(path: $path) { path value default labels } }", "variables": {"path": "debug"}}`,
+			status:   200,
+			expected: `{"data":{"setting":{"path":"Debug","value":"false","default":"false","labels":["team:core"]}}}`,
+		},
+		{
+			name:     "masked",
+			body:     `{"query": "{ setting(path: \"HTTP.Token\") { value default } missing: setting(path: \"Nope\") { path } }"}`,
+			status:   200,
+			expected: `{"data":{"setting":{"value":null,"default":null},"missing":null}}`,
+		},
+		{
+			name:     "search",
+			body:     `{"query": "{ search(query: \"connections\") { path description } }"}`,
+			status:   200,
+			expected: `{"data":{"search":[{"path":"HTTP.Max-Conns","description":"Maximum connections"}]}}`,
+		},
+		{
+			name:     "unknown field",
+			body:     `{"query": "{ config { Nope } }"}`,
+			status:   400,
+			expected: `{"errors":[{"message":"unknown field Nope of Config"}]}`,
+		},
+		{
+			name:     "mutation",
+			body:     `{"query": "mutation { config { Debug } }"}`,
+			status:   400,
+			expected: `{"errors":[{"message":"mutation is not supported"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status || strings.TrimSpace(rec.Body.String()) != tt.expected {
+			t.Errorf("Failed to query %s: expected %d %s; got %d %s", tt.name, tt.status, tt.expected, rec.Code, rec.Body.String())
+		}
+	}
+
+	// the schema is served without a query
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), "type Config_HTTP {") {
+		t.Errorf("Failed to serve schema: got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest("GET", "/?query="+url.QueryEscape("{ config { Debug } }"), nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Failed to require token: expected %d; got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestGraphQLServer_Subscription(t *testing.T) {
+	s := &Set{}
+	subset := s.Subset("HTTP")
+	port := subset.Setting("Port", 8080, "")
+	subset.Setting("Token", "secret", "").Mask = true
+	debug := s.Setting("Debug", false, "")
+
+	server := httptest.NewServer(&GraphQLServer{Set: s})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := url.QueryEscape(`subscription { change: changed(prefix: "HTTP") { path value } }`)
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/?query="+query, nil)
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Failed to subscribe: got status %s and content type %q", resp.Status, resp.Header.Get("Content-Type"))
+	}
+
+	// changes outside of the prefix are not sent
+	_ = debug.Set("true")
+	_ = port.Set("9090")
+	_ = s.Get("HTTP.Token").Set("changed")
+
+	reader := bufio.NewReader(resp.Body)
+	var events []string
+	for len(events) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event: %v", err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			events = append(events, strings.TrimSpace(strings.TrimPrefix(line, "data: ")))
+		}
+	}
+
+	expected := []string{
+		`{"data":{"change":{"path":"HTTP.Port","value":"9090"}}}`,
+		`{"data":{"change":{"path":"HTTP.Token","value":"*****"}}}`,
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Failed to receive change %d: expected %s; got %s", i, expected[i], events[i])
+		}
+	}
+}
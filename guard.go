@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ErrGuardRejected is wrapped by every GuardError
+var ErrGuardRejected = errors.New("rejected by guard")
+
+// Guards protect settings from values written through admin surfaces (see Set.UpdateContext) and supplied by remote sources (providers loaded by Set.Reload, Set.LoadURL, WatchClient and Set.ReadThrough), such as an accidental megabyte sized paste into a setting. They are checked before the value is parsed, values loaded from the files and environment of the process are not guarded. The length of values is capped for every source by Limits.MaxValueLength. Zero values disable a guard.
+type Guards struct {
+	// MaxMagnitude is the maximum absolute value of a numeric setting
+	MaxMagnitude float64
+
+	// ValidUTF8 rejects values that are not valid UTF-8
+	ValidUTF8 bool
+}
+
+// GuardError is returned when a value is rejected by the Guards of a Set
+type GuardError struct {
+	// Path of the setting
+	Path string
+
+	// Guard that rejected the value (i.e. MaxMagnitude)
+	Guard string
+
+	// Reason the value was rejected
+	Reason string
+}
+
+func (e *GuardError) Error() string {
+	return fmt.Sprintf("%s: %v: %s %s", e.Path, ErrGuardRejected, e.Guard, e.Reason)
+}
+
+// Unwrap returns ErrGuardRejected
+func (e *GuardError) Unwrap() error {
+	return ErrGuardRejected
+}
+
+// Guard sets the Guards of the Set tree checked by Set.UpdateContext, Set.UpdateIfMatch and remote sources
+func (s *Set) Guard(g Guards) {
+	s.Root().guardValues.Store(g)
+}
+
+// guard checks the value written to the setting on behalf of the caller in the ctx against the Guards of the Set tree
+func (s *Set) guard(ctx context.Context, setting *Setting, v string) error {
+	if ctx.Value(trustedContextKey{}) != nil {
+		return nil
+	}
+
+	g, _ := s.Root().guardValues.Load().(Guards)
+
+	if g.ValidUTF8 && !utf8.ValidString(v) {
+		return &GuardError{Path: setting.Path, Guard: "ValidUTF8", Reason: "invalid UTF-8"}
+	}
+
+	if g.MaxMagnitude > 0 && numeric(setting.Value) {
+		// values that are not numbers are left for the conversion to reject
+		if n, ok := parseNumber(v); ok && math.Abs(n) > g.MaxMagnitude {
+			return &GuardError{Path: setting.Path, Guard: "MaxMagnitude", Reason: fmt.Sprintf("%s exceeds %g", v, g.MaxMagnitude)}
+		}
+	}
+
+	return nil
+}
+
+// updateRemote updates the values supplied by a remote source like updateAll, failing without applying anything when a value is rejected by the Guards of the Set
+func (s *Set) updateRemote(values map[string]string, source string) error {
+	values = s.resolveEnvNames(values)
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if setting := s.lookup(path); setting != nil {
+			if err := s.guard(context.Background(), setting, values[path]); err != nil {
+				return fmt.Errorf("unable to update %q: %w", path, err)
+			}
+		}
+	}
+
+	return s.updateAll(values, source)
+}
+
+// numeric returns if the value, or the value it points to, is an integer or float
+func numeric(value Value) bool {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseNumber parses the value as a float or an integer in any base accepted by Setting.Set
+func parseNumber(v string) (float64, bool) {
+	if n, err := strconv.ParseFloat(v, 64); err == nil {
+		return n, true
+	}
+
+	if n, err := strconv.ParseInt(v, 0, 64); err == nil {
+		return float64(n), true
+	}
+
+	if n, err := strconv.ParseUint(v, 0, 64); err == nil {
+		return float64(n), true
+	}
+
+	return 0, false
+}
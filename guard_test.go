@@ -0,0 +1,82 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSet_Guard(t *testing.T) {
+	set := &Set{}
+	name := set.Setting("Name", "", "")
+	set.Setting("Workers", 4, "")
+	set.Guard(Guards{MaxMagnitude: 1000, ValidUTF8: true})
+
+	tests := map[string]struct {
+		path  string
+		value string
+		guard string
+	}{
+		"utf8":      {path: "Name", value: "\xff", guard: "ValidUTF8"},
+		"magnitude": {path: "Workers", value: "1e6", guard: "MaxMagnitude"},
+		"hex":       {path: "Workers", value: "-0x10000", guard: "MaxMagnitude"},
+		"valid":     {path: "Workers", value: "16"},
+	}
+
+	for name, test := range tests {
+		_, err := set.UpdateContext(context.Background(), test.path, test.value)
+
+		var guardErr *GuardError
+		if test.guard == "" {
+			if err != nil {
+				t.Errorf("Failed to allow %s value: %v", name, err)
+			}
+			continue
+		}
+
+		if !errors.As(err, &guardErr) || guardErr.Guard != test.guard || !errors.Is(err, ErrGuardRejected) {
+			t.Errorf("Failed to reject %s value: expected %s; got %v", name, test.guard, err)
+		}
+	}
+
+	// the application itself is not guarded
+	if err := name.Set("\xff"); err != nil {
+		t.Errorf("Failed to set unguarded value: %v", err)
+	}
+
+	if _, err := set.UpdateIfMatch(context.Background(), "Workers", "5000", set.Get("Workers").Revision()); !errors.Is(err, ErrGuardRejected) {
+		t.Errorf("Failed to guard conditional update: got %v", err)
+	}
+}
+
+func TestSet_GuardRemote(t *testing.T) {
+	workers := 4
+
+	set := &Set{}
+	set.Setting("Workers", &workers, "")
+	set.Guard(Guards{MaxMagnitude: 1000})
+
+	// values of remote sources are guarded, nothing is applied when one is rejected
+	set.AddProvider("remote", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"Workers": "5000"}, nil
+	}))
+	var reloadErr *ReloadError
+	if err := set.Reload(context.Background()); !errors.As(err, &reloadErr) || !errors.Is(reloadErr.Errors[0], ErrGuardRejected) || workers != 4 {
+		t.Errorf("Failed to guard provider: got %v with %d", err, workers)
+	}
+
+	set.ReadThrough(ResolverFunc(func(ctx context.Context, path string) (string, bool, error) {
+		return "5000", true, nil
+	}), time.Minute, time.Minute)
+	if set.Get("Workers"); workers != 4 {
+		t.Errorf("Failed to guard read-through: got %d", workers)
+	}
+
+	// local files are not guarded
+	dir := writeFiles(t, map[string]string{"config.json": `{"Workers": 5000}`})
+	if err := set.LoadFile(filepath.Join(dir, "config.json")); err != nil || workers != 5000 {
+		t.Errorf("Failed to load unguarded file: got %v with %d", err, workers)
+	}
+}
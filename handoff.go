@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// HandoffEnv is the environment variable locating the runtime overrides handed to a new process by Set.Handoff, either "fd:N" for an inherited file descriptor or the path of a temporary file
+const HandoffEnv = "CONFIG_HANDOFF"
+
+// Handoff writes the runtime overrides of the Set (settings whose Setting.Source is SourceRuntime, i.e. operator tweaks made through an admin surface) to a temporary file handed to the cmd, so they survive a zero-downtime restart into a new binary which restores them with Set.RestoreHandoff. The file is inherited as a file descriptor through cmd.ExtraFiles, or passed by path on platforms that do not support it (Windows), and located by HandoffEnv in the environment of the cmd. Call Handoff after the ExtraFiles of the cmd (i.e. listeners) are set up and before it is started.
+func (s *Set) Handoff(cmd *exec.Cmd) error {
+	f, err := os.CreateTemp("", "config-handoff-*")
+	if err != nil {
+		return fmt.Errorf("unable to create handoff file: %w", err)
+	}
+
+	if _, err := s.writeSnapshot(f, func(setting *Setting) bool { return setting.Source() == SourceRuntime }); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+
+	// the new process removes the file once restored
+	if runtime.GOOS == "windows" {
+		if err := f.Close(); err != nil {
+			os.Remove(f.Name())
+			return fmt.Errorf("unable to write handoff file: %w", err)
+		}
+
+		cmd.Env = append(cmd.Env, HandoffEnv+"="+f.Name())
+		return nil
+	}
+
+	// an inherited descriptor needs no cleanup, the file is gone once both processes close it
+	os.Remove(f.Name())
+	if _, err := f.Seek(0, 0); err != nil {
+		f.Close()
+		return fmt.Errorf("unable to rewind handoff file: %w", err)
+	}
+
+	// descriptors 0 through 2 are stdin, stdout and stderr, ExtraFiles follow
+	cmd.ExtraFiles = append(cmd.ExtraFiles, f)
+	cmd.Env = append(cmd.Env, HandoffEnv+"=fd:"+strconv.Itoa(2+len(cmd.ExtraFiles)))
+
+	return nil
+}
+
+// RestoreHandoff restores the runtime overrides handed to the process by Set.Handoff, located by HandoffEnv, returning false when there are none. Overrides of settings the Set no longer has are skipped. Call it after every setting is registered and the providers are loaded, so the overrides take precedence as they did in the previous process.
+func (s *Set) RestoreHandoff() (bool, error) {
+	location, found := os.LookupEnv(HandoffEnv)
+	if !found || location == "" {
+		return false, nil
+	}
+	os.Unsetenv(HandoffEnv)
+
+	var f *os.File
+	if strings.HasPrefix(location, "fd:") {
+		n, err := strconv.Atoi(strings.TrimPrefix(location, "fd:"))
+		if err != nil {
+			return false, fmt.Errorf("invalid %s %q: %w", HandoffEnv, location, err)
+		}
+
+		f = os.NewFile(uintptr(n), "handoff")
+		if f == nil {
+			return false, fmt.Errorf("invalid %s %q", HandoffEnv, location)
+		}
+	} else {
+		var err error
+		if f, err = os.Open(location); err != nil {
+			return false, fmt.Errorf("unable to open handoff file: %w", err)
+		}
+		defer os.Remove(location)
+	}
+	defer f.Close()
+
+	snap, _, err := readSnapshot(f)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range snap.Settings {
+		setting := s.lookup(entry.Path)
+		if setting == nil {
+			logger().Warn("handed off setting no longer exists", "path", entry.Path)
+			continue
+		}
+
+		if err := setting.setFrom(context.Background(), entry.Value, SourceRuntime); err != nil {
+			return true, &SettingError{Path: entry.Path, Err: err}
+		}
+	}
+
+	return true, nil
+}
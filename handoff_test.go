@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSet_Handoff(t *testing.T) {
+	parent := &Set{}
+	parent.Setting("Workers", 4, "")
+	parent.Setting("Host", "localhost", "")
+	parent.Setting("Removed", "", "")
+
+	_ = parent.Get("Workers").Set("16")
+	_ = parent.Get("Removed").Set("x")
+	if err := parent.updateAll(map[string]string{"Host": "example.com"}, "file.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the new process is this test binary running TestSet_HandoffChild
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSet_HandoffChild$")
+	if err := parent.Handoff(cmd); err != nil {
+		t.Fatalf("Failed to hand off: %v", err)
+	}
+	cmd.Env = append(cmd.Env, "CONFIG_HANDOFF_CHILD=1")
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to run child: %v: %s", err, out)
+	}
+
+	if !strings.Contains(string(out), "Workers=16 (runtime) Host=localhost") {
+		t.Errorf("Failed to restore runtime overrides in child: got %s", out)
+	}
+}
+
+// TestSet_HandoffChild is the new process of TestSet_Handoff
+func TestSet_HandoffChild(t *testing.T) {
+	if os.Getenv("CONFIG_HANDOFF_CHILD") == "" {
+		t.Skip("run by TestSet_Handoff")
+	}
+
+	set := &Set{}
+	workers := set.Setting("Workers", 4, "")
+	host := set.Setting("Host", "localhost", "")
+
+	if restored, err := set.RestoreHandoff(); !restored || err != nil {
+		t.Fatalf("Failed to restore handoff: %v", err)
+	}
+
+	if restored, err := set.RestoreHandoff(); restored || err != nil {
+		t.Errorf("Failed to restore only once: got %v (%v)", restored, err)
+	}
+
+	fmt.Printf("Workers=%s (%s) Host=%s\n", workers, workers.Source(), host)
+}
+
+func TestSet_RestoreHandoffFile(t *testing.T) {
+	parent := &Set{}
+	parent.Setting("Workers", 4, "")
+	_ = parent.Get("Workers").Set("16")
+
+	path := filepath.Join(t.TempDir(), "handoff")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parent.WriteTo(f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	t.Setenv(HandoffEnv, path)
+
+	child := &Set{}
+	child.Setting("Workers", 4, "")
+	if restored, err := child.RestoreHandoff(); !restored || err != nil || child.Get("Workers").String() != "16" {
+		t.Errorf("Failed to restore handoff file: got %v (%v)", child.Get("Workers"), err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Failed to remove handoff file: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// Hash returns a stable hex encoded SHA-256 digest of the path and value of every setting in the Set. Masked values are hashed before being added to the digest so they are never revealed. This allows instances to advertise their configuration version and operators to detect divergence across a fleet.
+func (s *Set) Hash() string {
+	type entry struct {
+		path  string
+		value string
+	}
+
+	var entries []entry
+	s.Range(func(path string, setting *Setting) bool {
+		value := setting.format()
+		if setting.Mask {
+			sum := sha256.Sum256([]byte(value))
+			value = hex.EncodeToString(sum[:])
+		}
+
+		entries = append(entries, entry{path: path, value: value})
+		return true
+	})
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+
+	h := sha256.New()
+	for _, e := range entries {
+		h.Write([]byte(e.path))
+		h.Write([]byte{0})
+		h.Write([]byte(e.value))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
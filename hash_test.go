@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestSet_Hash(t *testing.T) {
+	newSet := func(port int, password string) *Set {
+		set := &Set{}
+		set.Setting("Port", port, "")
+		set.Setting("Password", password, "").Mask = true
+		return set
+	}
+
+	if newSet(80, "secret").Hash() != newSet(80, "secret").Hash() {
+		t.Errorf("Failed to produce a stable hash for equal sets")
+	}
+
+	if newSet(80, "secret").Hash() == newSet(81, "secret").Hash() {
+		t.Errorf("Failed to change hash when a value changed")
+	}
+
+	if newSet(80, "secret").Hash() == newSet(80, "other").Hash() {
+		t.Errorf("Failed to change hash when a masked value changed")
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteHelm writes a Helm values.yaml skeleton of the Set to values, with every setting at its default preceded by its description, and a templates snippet to template mapping every value onto an environment variable named by EnvName with the prefix. The snippet is intended for the env of a container in a Deployment template. Masked settings have an empty default.
+func (s *Set) WriteHelm(values, template io.Writer, prefix string) error {
+	if err := s.writeSample(values, SampleYAML, ""); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(template)
+	fmt.Fprintln(bw, "env:")
+
+	for _, setting := range s.sorted() {
+		path := setting.Path
+		if s.path != "" {
+			path = strings.TrimPrefix(path[len(s.path):], ".")
+		}
+
+		keys := strings.Split(path, ".")
+		for i, key := range keys {
+			keys[i] = strconv.Quote(key)
+		}
+
+		fmt.Fprintf(bw, "  - name: %s\n    value: {{ index .Values %s | quote }}\n", setting.EnvName(prefix), strings.Join(keys, " "))
+	}
+
+	return bw.Flush()
+}
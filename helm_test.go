@@ -0,0 +1,42 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSet_WriteHelm(t *testing.T) {
+	cfg := struct {
+		Port     int    `description:"port to listen on"`
+		Password string `mask:"true"`
+	}{Port: 8080, Password: "secret"}
+
+	set := &Set{}
+	set.Subset("HTTP").Bind(&cfg)
+
+	values := &bytes.Buffer{}
+	template := &bytes.Buffer{}
+	if err := set.WriteHelm(values, template, "APP"); err != nil {
+		t.Fatalf("Failed to write helm: %v", err)
+	}
+
+	expectedValues := `HTTP:
+  # (secret)
+  Password: ""
+  # port to listen on
+  Port: 8080
+`
+	if values.String() != expectedValues {
+		t.Errorf("Failed to write values:\nexpected:\n%s\ngot:\n%s", expectedValues, values.String())
+	}
+
+	expectedTemplate := `env:
+  - name: APP_HTTP_PASSWORD
+    value: {{ index .Values "HTTP" "Password" | quote }}
+  - name: APP_HTTP_PORT
+    value: {{ index .Values "HTTP" "Port" | quote }}
+`
+	if template.String() != expectedTemplate {
+		t.Errorf("Failed to write template:\nexpected:\n%s\ngot:\n%s", expectedTemplate, template.String())
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HistoryEntry is a value a setting held, retained when the Set keeps history (see Set.KeepHistory)
+type HistoryEntry struct {
+	// Value of the setting, ***** for masked settings
+	Value string `json:"value"`
+
+	// Source that supplied the value (i.e. a provider name or file), empty for direct writes
+	Source string `json:"source,omitempty"`
+
+	// Time the value was applied
+	Time time.Time `json:"time"`
+
+	// Revision of the change, see Setting.Revision
+	Revision uint64 `json:"revision"`
+
+	// previous value and revision of the setting before the change, restored by Set.Rollback
+	previous         string
+	previousRevision uint64
+}
+
+// history is the ring buffer of the values of a setting
+type history struct {
+	mu      sync.Mutex
+	entries []HistoryEntry
+	next    int
+}
+
+// KeepHistory retains the last n values of every setting of the Set tree, queryable with Setting.History and served by HistoryServer. Only changes are retained, and masked values are retained as *****. A size of 0 disables history, dropping what was retained as settings change.
+func (s *Set) KeepHistory(n int) {
+	if n < 0 {
+		panic("history size can not be negative")
+	}
+
+	atomic.StoreInt32(&s.Root().historySize, int32(n))
+}
+
+// History returns the values retained for the setting, oldest first, see Set.KeepHistory
+func (s *Setting) History() []HistoryEntry {
+	s.history.mu.Lock()
+	defer s.history.mu.Unlock()
+
+	return s.history.ordered()
+}
+
+// ordered returns a copy of the entries, oldest first, the caller must hold mu
+func (h *history) ordered() []HistoryEntry {
+	if len(h.entries) < cap(h.entries) {
+		return append([]HistoryEntry(nil), h.entries...)
+	}
+
+	return append(append([]HistoryEntry(nil), h.entries[h.next:]...), h.entries[:h.next]...)
+}
+
+// historySize returns the number of values retained for the setting, see Set.KeepHistory
+func (s *Setting) historySize() int {
+	if s.set == nil {
+		return 0
+	}
+
+	return int(atomic.LoadInt32(&s.set.Root().historySize))
+}
+
+// record the current value of the setting in its history, along with the previous value and revision it replaced
+func (s *Setting) record(source, previous string, previousRevision uint64) {
+	size := s.historySize()
+
+	s.history.mu.Lock()
+	defer s.history.mu.Unlock()
+
+	h := &s.history
+
+	// the buffer is replaced when the size changed, keeping the most recent values
+	if cap(h.entries) != size {
+		previous := h.ordered()
+		if len(previous) > size {
+			previous = previous[len(previous)-size:]
+		}
+
+		h.entries = append(make([]HistoryEntry, 0, size), previous...)
+		h.next = 0
+	}
+
+	if size == 0 {
+		return
+	}
+
+	entry := HistoryEntry{
+		Value:    s.String(),
+		Source:   source,
+		Time:     time.Now(),
+		Revision: s.Revision(),
+
+		previous:         previous,
+		previousRevision: previousRevision,
+	}
+
+	if len(h.entries) < size {
+		h.entries = append(h.entries, entry)
+		h.next = len(h.entries) % size
+		return
+	}
+
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % size
+}
+
+// HistoryServer exposes the history of the settings of a Set over HTTP as JSON, so incident responders can see what a setting was set to without consulting logs. The path query parameter selects a single setting, otherwise the history of every setting with retained values is returned keyed by path. The Set must keep history, see Set.KeepHistory.
+type HistoryServer struct {
+	// Set being exposed
+	Set *Set
+
+	// Token, when not empty, is required from clients as a bearer token
+	Token string
+}
+
+// ServeHTTP writes the history of the settings of the Set
+func (hs *HistoryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkBearer(w, r, hs.Token) {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body interface{}
+	if path := r.URL.Query().Get("path"); path != "" {
+		setting := hs.Set.lookup(path)
+		if setting == nil {
+			http.Error(w, "unknown setting", http.StatusNotFound)
+			return
+		}
+
+		body = setting.History()
+	} else {
+		all := map[string][]HistoryEntry{}
+		for _, setting := range hs.Set.sorted() {
+			if entries := setting.History(); len(entries) > 0 {
+				all[setting.Path] = entries
+			}
+		}
+
+		body = all
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetting_History(t *testing.T) {
+	set := &Set{}
+	port := set.Setting("Port", 80, "")
+
+	_ = port.Set("1")
+	if history := port.History(); len(history) != 0 {
+		t.Errorf("Failed to disable history by default: got %+v", history)
+	}
+
+	set.KeepHistory(3)
+	for _, v := range []string{"2", "3", "4", "5"} {
+		if err := set.updateAll(map[string]string{"Port": v}, "file.json"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history := port.History()
+	if len(history) != 3 || history[0].Value != "3" || history[2].Value != "5" || history[2].Source != "file.json" || history[2].Revision != port.Revision() {
+		t.Fatalf("Failed to retain the last values: got %+v", history)
+	}
+
+	set.KeepHistory(2)
+	_ = port.Set("6")
+	_ = port.Set("7")
+	if history := port.History(); len(history) != 2 || history[0].Value != "6" || history[1].Value != "7" {
+		t.Errorf("Failed to shrink history: got %+v", history)
+	}
+
+	password := set.Setting("Password", "", "")
+	password.Mask = true
+	_ = password.Set("hunter2")
+	if history := password.History(); len(history) != 1 || history[0].Value != "*****" {
+		t.Errorf("Failed to mask history: got %+v", history)
+	}
+}
+
+func TestHistoryServer(t *testing.T) {
+	set := &Set{}
+	set.KeepHistory(5)
+	port := set.Setting("Port", 80, "")
+	set.Setting("Host", "localhost", "")
+	_ = port.Set("8080")
+
+	server := httptest.NewServer(&HistoryServer{Set: set, Token: "secret"})
+	defer server.Close()
+
+	get := func(query, token string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if resp := get("", "wrong"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Failed to require token: expected %d; got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	resp := get("", "secret")
+	defer resp.Body.Close()
+
+	var all map[string][]HistoryEntry
+	if err := json.NewDecoder(resp.Body).Decode(&all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || len(all["Port"]) != 1 || all["Port"][0].Value != "8080" {
+		t.Errorf("Failed to serve history: got %+v", all)
+	}
+
+	if resp := get("?path=missing", "secret"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Failed to reject unknown setting: expected %d; got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Op is the kind of operation reported in an Event
+type Op string
+
+const (
+	// OpRegister is reported when a setting is added to a Set
+	OpRegister Op = "register"
+
+	// OpGet is reported for Set.Get, Err is ErrUnknownSetting when the setting was not found
+	OpGet Op = "get"
+
+	// OpSet is reported for Setting.Set
+	OpSet Op = "set"
+
+	// OpBind is reported for Set.Bind
+	OpBind Op = "bind"
+
+	// OpLoad is reported for Set.LoadFile and Set.LoadURL with the file or URL as the Source
+	OpLoad Op = "load"
+
+	// OpReload is reported for Set.Reload
+	OpReload Op = "reload"
+
+	// OpNotify is reported for every Notifier called with a changed setting
+	OpNotify Op = "notify"
+
+	// OpLoaded is reported by Set.Resume for the Set, timed from the first Set.Silence
+	OpLoaded Op = "loaded"
+
+	// OpFetch is reported for every Provider loaded by Set.Reload with the provider name as the Source, Err holds the result of the sync
+	OpFetch Op = "fetch"
+
+	// OpRemove is reported for every setting removed from a Set by Scope.Close
+	OpRemove Op = "remove"
+)
+
+// Event describes an operation performed on a Set
+type Event struct {
+	// Op performed
+	Op Op
+
+	// Path of the setting or Set the operation was performed on
+	Path string
+
+	// Source of the operation such as the provider name or file, when there is one
+	Source string
+
+	// Time the operation started
+	Time time.Time
+
+	// Duration of the operation
+	Duration time.Duration
+
+	// Err the operation failed with
+	Err error
+}
+
+// Hook receives an Event for every operation performed on a Set, allowing tracing, metrics and logging to be attached uniformly
+type Hook interface {
+	Event(e Event)
+}
+
+// HookFunc defines a function that receives an Event for every operation performed on a Set
+type HookFunc func(e Event)
+
+// Event implements Hook.Event
+func (f HookFunc) Event(e Event) {
+	f(e)
+}
+
+// Hook attaches the Hook to the Set tree, it receives events for operations anywhere in the tree until the returned handle is closed
+func (s *Set) Hook(h Hook) *NotifyHandle {
+	if h == nil {
+		return &NotifyHandle{}
+	}
+
+	root := s.Root()
+
+	handle := &NotifyHandle{}
+	handle.stopFunc = func(key interface{}) {
+		if _, loaded := root.hooks.LoadAndDelete(key); loaded {
+			atomic.AddInt32(&root.hookCount, -1)
+		}
+	}
+
+	root.hooks.Store(handle, h)
+	atomic.AddInt32(&root.hookCount, 1)
+
+	return handle
+}
+
+// hooked returns if any Hook is attached, so callers can avoid the cost of building events
+func (s *Set) hooked() bool {
+	return atomic.LoadInt32(&s.Root().hookCount) > 0
+}
+
+// emit the event to every attached Hook
+func (s *Set) emit(e Event) {
+	s.Root().hooks.Range(func(_, v interface{}) bool {
+		v.(Hook).Event(e)
+		return true
+	})
+}
+
+// observe emits an event for the operation started at start when any Hook is attached
+func (s *Set) observe(op Op, path, source string, start time.Time, err error) {
+	if !s.hooked() {
+		return
+	}
+
+	s.emit(Event{
+		Op:       op,
+		Path:     path,
+		Source:   source,
+		Time:     start,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+}
+
+// dispatch calls the Notifier with the changed setting, reporting the call to any Hook. A panic of the Notifier is logged (see SetLogger) rather than crashing the writer.
+func (s *Set) dispatch(n Notifier, setting *Setting) {
+	defer recoverNotifier(setting)
+
+	latency := s.latency()
+	if latency == nil && !s.hooked() {
+		n.Notify(setting)
+		return
+	}
+
+	start := time.Now()
+	n.Notify(setting)
+
+	if latency != nil {
+		latency.observe(notifierName(n), time.Since(start))
+	}
+	s.observe(OpNotify, setting.Path, "", start, nil)
+}
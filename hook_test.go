@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSet_Hook(t *testing.T) {
+	var events []string
+
+	set := &Set{}
+	handle := set.Hook(HookFunc(func(e Event) {
+		event := string(e.Op) + " " + e.Path
+		if e.Source != "" {
+			event += " " + e.Source
+		}
+		if e.Err != nil {
+			event += " error"
+		}
+		events = append(events, event)
+	}))
+
+	cfg := struct {
+		Port int
+	}{}
+	set.Subset("HTTP").Bind(&cfg)
+	set.Subset("HTTP").Notify(NotifyFunc(func(*Setting) {}))
+
+	set.Get("HTTP.Port")
+	set.Get("HTTP.Missing")
+	_, _ = set.Update("HTTP.Port", "8080")
+
+	set.AddProvider("remote", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return nil, errors.New("unavailable")
+	}))
+	_ = set.Reload(context.Background())
+
+	expected := []string{
+		"register HTTP.Port",
+		"bind HTTP",
+		"get HTTP.Port",
+		"get HTTP.Missing error",
+		"notify HTTP.Port",
+		"set HTTP.Port",
+		"fetch  remote error",
+		"reload  error",
+	}
+
+	if strings.Join(events, ",") != strings.Join(expected, ",") {
+		t.Errorf("Failed to report operations:\nexpected %v\ngot      %v", expected, events)
+	}
+
+	handle.Close()
+	set.Get("HTTP.Port")
+
+	if len(events) != len(expected) {
+		t.Errorf("Failed to detach hook: got %v", events[len(expected):])
+	}
+}
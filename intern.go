@@ -0,0 +1,56 @@
+package config
+
+import "sync"
+
+// interner deduplicates strings so equal values share memory
+type interner struct {
+	mu     sync.Mutex
+	values map[string]string
+	saved  int
+}
+
+// InternValues deduplicates the string values and defaults of the settings of the Set tree from now on, so settings holding equal strings share their memory. This cuts the memory of Sets with many mostly identical values (i.e. tenant overlays, see Set.Tenant) at the cost of a lookup for every string written. Every distinct value is kept for the lifetime of the Set, so it suits values from a bounded vocabulary. The effect is reported by Set.Stats.
+func (s *Set) InternValues() {
+	s.Root().internValue.Store(&interner{values: map[string]string{}})
+}
+
+// intern returns the shared copy of v when the Set tree interns values
+func (s *Set) intern(v string) string {
+	in, _ := s.Root().internValue.Load().(*interner)
+	if in == nil || v == "" {
+		return v
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if shared, found := in.values[v]; found {
+		in.saved += len(v)
+		return shared
+	}
+
+	in.values[v] = v
+	return v
+}
+
+// internStats returns the number of distinct interned values and the bytes saved by sharing them
+func (s *Set) internStats() (int, int) {
+	in, _ := s.Root().internValue.Load().(*interner)
+	if in == nil {
+		return 0, 0
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return len(in.values), in.saved
+}
+
+// intern returns the shared copy of v when the Set of the setting interns values
+func (s *Setting) intern(v string) string {
+	if s.set == nil {
+		return v
+	}
+
+	return s.set.intern(v)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// KubernetesManifest names the manifests written by Set.WriteKubernetes
+type KubernetesManifest struct {
+	// Name of the ConfigMap and Secret
+	Name string
+
+	// Namespace of the ConfigMap and Secret, omitted when empty
+	Namespace string
+
+	// Prefix of the keys, see EnvName
+	Prefix string
+}
+
+// WriteKubernetes writes a ConfigMap YAML manifest holding the unmasked settings of the Set and, when there are masked settings, a Secret manifest holding the masked settings and those whose value has secret parts (see Redacter). Keys are named by EnvName so both can be referenced with envFrom and read with Set.LoadEnv. The Secret contains the current secret values and must be handled accordingly.
+func (s *Set) WriteKubernetes(w io.Writer, m KubernetesManifest) error {
+	var plain, secret []*Setting
+	for _, setting := range s.sorted() {
+		if setting.sensitive() {
+			secret = append(secret, setting)
+		} else {
+			plain = append(plain, setting)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+
+	writeKubernetes(bw, "ConfigMap", "data", m, plain)
+
+	if len(secret) > 0 {
+		fmt.Fprintln(bw, "---")
+		writeKubernetes(bw, "Secret", "stringData", m, secret)
+	}
+
+	return bw.Flush()
+}
+
+// writeKubernetes writes a single manifest of kind with the settings under the data key
+func writeKubernetes(w io.Writer, kind, key string, m KubernetesManifest, settings []*Setting) {
+	fmt.Fprintf(w, "apiVersion: v1\nkind: %s\nmetadata:\n  name: %s\n", kind, strconv.Quote(m.Name))
+	if m.Namespace != "" {
+		fmt.Fprintf(w, "  namespace: %s\n", strconv.Quote(m.Namespace))
+	}
+	if kind == "Secret" {
+		fmt.Fprintln(w, "type: Opaque")
+	}
+
+	if len(settings) == 0 {
+		fmt.Fprintf(w, "%s: {}\n", key)
+		return
+	}
+
+	fmt.Fprintf(w, "%s:\n", key)
+	for _, setting := range settings {
+		fmt.Fprintf(w, "  %s: %s\n", setting.EnvName(m.Prefix), strconv.Quote(setting.format()))
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSet_WriteKubernetes(t *testing.T) {
+	cfg := struct {
+		Host     string
+		Port     int
+		Password string `mask:"true"`
+	}{Host: "db", Port: 5432, Password: "secret"}
+
+	set := &Set{}
+	set.Subset("DB").Bind(&cfg)
+
+	buf := &bytes.Buffer{}
+	if err := set.WriteKubernetes(buf, KubernetesManifest{Name: "api", Namespace: "prod"}); err != nil {
+		t.Fatalf("Failed to write manifests: %v", err)
+	}
+
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "api"
+  namespace: "prod"
+data:
+  DB_HOST: "db"
+  DB_PORT: "5432"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: "api"
+  namespace: "prod"
+type: Opaque
+stringData:
+  DB_PASSWORD: "secret"
+`
+
+	if buf.String() != expected {
+		t.Errorf("Failed to write manifests:\nexpected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestSet_WriteKubernetesRedacted(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := redactedSet(t).WriteKubernetes(buf, KubernetesManifest{Name: "app"}); err != nil {
+		t.Fatalf("Failed to write manifests: %v", err)
+	}
+
+	configMap, secret, _ := strings.Cut(buf.String(), "---")
+	assertRedacted(t, "ConfigMap", configMap)
+	if !strings.Contains(secret, `DB: "postgres://app:hunter3@db:5432/app"`) {
+		t.Errorf("Failed to write DSN to Secret:\n%s", secret)
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Select returns the settings within the Set matching the label selector, sorted by path. The selector is a comma separated list of terms that must all match:
+//
+//	restart-required    has the label restart-required
+//	team:payments       has the label team:payments
+//	team                has a label team or team:<anything>
+//	!restart-required   does not match the term restart-required
+//
+// Labels are compared case insensitive. An empty selector matches every setting. Combined with Setting.LastChanged this lists, for example, the settings requiring a restart that changed since boot.
+func (s *Set) Select(selector string) []*Setting {
+	terms := parseLabels(selector)
+
+	var settings []*Setting
+	s.Range(func(_ string, setting *Setting) bool {
+		for _, term := range terms {
+			negate := strings.HasPrefix(term, "!")
+			if hasLabel(setting.Labels, strings.TrimPrefix(term, "!")) == negate {
+				return true
+			}
+		}
+
+		settings = append(settings, setting)
+		return true
+	})
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	return settings
+}
+
+// hasLabel returns if any of the labels matches the term, a term without a value matches labels with the term as key
+func hasLabel(labels []string, term string) bool {
+	for _, label := range labels {
+		if strings.EqualFold(label, term) {
+			return true
+		}
+
+		if key, _, found := strings.Cut(label, ":"); found && !strings.Contains(term, ":") && strings.EqualFold(key, term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseLabels splits the comma separated labels, dropping empty ones
+func parseLabels(tag string) []string {
+	var labels []string
+	for _, label := range strings.Split(tag, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels
+}
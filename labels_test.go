@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSet_Select(t *testing.T) {
+	cfg := struct {
+		Port    int    `labels:"restart-required,team:platform"`
+		Workers int    `labels:"restart-required,team:payments"`
+		Name    string `labels:"team:payments"`
+		Debug   bool
+	}{}
+
+	s := &Set{}
+	s.Bind(&cfg)
+
+	paths := func(settings []*Setting) []string {
+		var paths []string
+		for _, setting := range settings {
+			paths = append(paths, setting.Path)
+		}
+		return paths
+	}
+
+	tests := []struct {
+		selector string
+		expected []string
+	}{
+		{selector: "restart-required", expected: []string{"Port", "Workers"}},
+		{selector: "team:payments", expected: []string{"Name", "Workers"}},
+		{selector: "Team", expected: []string{"Name", "Port", "Workers"}},
+		{selector: "team:payments, !restart-required", expected: []string{"Name"}},
+		{selector: "", expected: []string{"Debug", "Name", "Port", "Workers"}},
+	}
+
+	for _, tt := range tests {
+		if got := paths(s.Select(tt.selector)); fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+			t.Errorf("Failed to select %q: expected %v; got %v", tt.selector, tt.expected, got)
+		}
+	}
+
+	boot := time.Now()
+	_ = s.Get("Workers").Set("8")
+
+	var changed []string
+	for _, setting := range s.Select("restart-required") {
+		if setting.LastChanged().After(boot) {
+			changed = append(changed, setting.Path)
+		}
+	}
+
+	if len(changed) != 1 || changed[0] != "Workers" {
+		t.Errorf("Failed to find changed settings: expected %v; got %v", []string{"Workers"}, changed)
+	}
+}
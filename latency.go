@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyBuckets is the number of exponential buckets of a LatencyHistogram, the last one holds everything from about 8s
+const latencyBuckets = 24
+
+// LatencyBucket counts the notifications that took less than the UpperBound and at least the UpperBound of the previous bucket
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// LatencyHistogram is the exponential histogram of the time a notifier took to handle its notifications, see Set.MeasureNotifiers
+type LatencyHistogram struct {
+	// Notifier the histogram is for, the name given with NotifyName, the function of a NotifyFunc or the type of any other Notifier
+	Notifier string
+
+	// Count of notifications handled
+	Count uint64
+
+	// Sum of the time spent handling the notifications
+	Sum time.Duration
+
+	// Max time spent handling a single notification
+	Max time.Duration
+
+	// Buckets doubling from 1µs, the UpperBound of the last bucket is the largest time.Duration
+	Buckets []LatencyBucket
+}
+
+// Quantile returns the UpperBound of the bucket holding the quantile q (i.e. 0.99) of the notifications, zero when nothing was measured
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(h.Count))
+	var seen uint64
+	for _, b := range h.Buckets {
+		seen += b.Count
+		if seen > rank {
+			return b.UpperBound
+		}
+	}
+
+	return h.Buckets[len(h.Buckets)-1].UpperBound
+}
+
+// latencyRecorder holds the histograms of the notifiers of a Set tree
+type latencyRecorder struct {
+	mu         sync.Mutex
+	histograms map[string]*LatencyHistogram
+}
+
+// observe that the notifier took d to handle a notification
+func (r *latencyRecorder) observe(notifier string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.histograms[notifier]
+	if h == nil {
+		h = &LatencyHistogram{Notifier: notifier, Buckets: make([]LatencyBucket, latencyBuckets)}
+		for i := range h.Buckets {
+			h.Buckets[i].UpperBound = time.Microsecond << uint(i)
+		}
+		h.Buckets[latencyBuckets-1].UpperBound = time.Duration(1<<63 - 1)
+		r.histograms[notifier] = h
+	}
+
+	h.Count++
+	h.Sum += d
+	if d > h.Max {
+		h.Max = d
+	}
+
+	for i := range h.Buckets {
+		if d < h.Buckets[i].UpperBound || i == latencyBuckets-1 {
+			h.Buckets[i].Count++
+			break
+		}
+	}
+}
+
+// MeasureNotifiers starts measuring the time every notifier of the Set tree takes to handle its notifications, reported by Set.NotifierLatency. This finds the subscriber that makes every change slow, at the cost of reading the clock twice per notification.
+func (s *Set) MeasureNotifiers() {
+	s.Root().latencyValue.Store(&latencyRecorder{histograms: map[string]*LatencyHistogram{}})
+}
+
+// NotifierLatency returns the latency histogram of every notifier measured since Set.MeasureNotifiers, the slowest in total first. Notifiers sharing a name share a histogram.
+func (s *Set) NotifierLatency() []LatencyHistogram {
+	r, _ := s.Root().latencyValue.Load().(*latencyRecorder)
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	histograms := make([]LatencyHistogram, 0, len(r.histograms))
+	for _, h := range r.histograms {
+		cp := *h
+		cp.Buckets = append([]LatencyBucket(nil), h.Buckets...)
+		histograms = append(histograms, cp)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(histograms, func(i, j int) bool {
+		if histograms[i].Sum != histograms[j].Sum {
+			return histograms[i].Sum > histograms[j].Sum
+		}
+		return histograms[i].Notifier < histograms[j].Notifier
+	})
+
+	return histograms
+}
+
+// latency returns the recorder of the Set tree, nil unless notifiers are measured
+func (s *Set) latency() *latencyRecorder {
+	r, _ := s.Root().latencyValue.Load().(*latencyRecorder)
+	return r
+}
+
+// NotifyName names the Notifier in its LatencyHistogram, see Set.NotifierLatency
+func NotifyName(name string) NotifyOption {
+	return func(o *notifyOptions) {
+		o.name = name
+	}
+}
+
+// namedNotifier is a Notifier with the name it is measured as
+type namedNotifier struct {
+	Notifier
+	name string
+}
+
+// named returns the Notifier n measured under the name of the notifier it wraps
+func named(wrapped, n Notifier) Notifier {
+	return namedNotifier{Notifier: n, name: notifierName(wrapped)}
+}
+
+// notifierName returns the name a Notifier is measured as
+func notifierName(n Notifier) string {
+	switch v := n.(type) {
+	case namedNotifier:
+		return v.name
+	case NotifyFunc:
+		if fn := runtime.FuncForPC(reflect.ValueOf(v).Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+
+	return fmt.Sprintf("%T", n)
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_MeasureNotifiers(t *testing.T) {
+	set := &Set{}
+	setting := set.Setting("Level", "info", "")
+
+	slow := func(*Setting) { time.Sleep(2 * time.Millisecond) }
+	setting.Notify(NotifyFunc(slow), NotifyName("slow"))
+	set.Notify(NotifyFunc(func(*Setting) {}))
+	set.NotifyContext(context.Background(), NotifyFunc(func(*Setting) {}), NotifyName("ctx"))
+
+	_ = setting.Set("debug")
+	if latency := set.NotifierLatency(); latency != nil {
+		t.Errorf("Failed to measure only when enabled: got %v", latency)
+	}
+
+	set.MeasureNotifiers()
+	_ = setting.Set("warn")
+	_ = setting.Set("error")
+
+	latency := set.NotifierLatency()
+	if len(latency) != 3 {
+		t.Fatalf("Failed to measure notifiers: expected 3; got %d", len(latency))
+	}
+
+	h := latency[0]
+	if h.Notifier != "slow" || h.Count != 2 || h.Sum < 4*time.Millisecond || h.Max < 2*time.Millisecond {
+		t.Errorf("Failed to measure slowest notifier first: got %+v", h)
+	}
+
+	if q := h.Quantile(0.5); q < 2*time.Millisecond || q > 16*time.Millisecond {
+		t.Errorf("Failed to compute quantile: got %v", q)
+	}
+
+	var names []string
+	for _, h := range latency[1:] {
+		names = append(names, h.Notifier)
+	}
+	if !strings.Contains(strings.Join(names, " "), "ctx") || !strings.Contains(strings.Join(names, " "), "TestSet_MeasureNotifiers.func") {
+		t.Errorf("Failed to name notifiers: got %v", names)
+	}
+}
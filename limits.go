@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded is wrapped by every LimitError
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits cap the growth of a Set tree, protecting hosts that register settings on behalf of plugins. Zero values are unlimited.
+type Limits struct {
+	// MaxSettings is the maximum number of settings in the Set tree
+	MaxSettings int
+
+	// MaxDepth is the maximum nesting of subsets, a setting of the root Set has a depth of 0
+	MaxDepth int
+
+	// MaxValueLength is the maximum length of a value passed to Setting.Set
+	MaxValueLength int
+}
+
+// LimitError is returned when one of the Limits of a Set is exceeded
+type LimitError struct {
+	// Limit that was exceeded (i.e. MaxSettings)
+	Limit string
+
+	// Path of the setting or subset
+	Path string
+
+	// Max is the configured value of the limit
+	Max int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %v: %s of %d", e.Path, ErrLimitExceeded, e.Limit, e.Max)
+}
+
+// Unwrap returns ErrLimitExceeded
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// Limit sets the Limits of the Set tree. Limits are enforced on new settings, values and subsets, existing ones are left untouched. Set.Register returns a *LimitError when a limit is exceeded while Set.Setting and Set.Subset panic.
+func (s *Set) Limit(l Limits) {
+	s.Root().limitValues.Store(l)
+}
+
+// limits returns the Limits of the Set tree
+func (s *Set) limits() Limits {
+	l, _ := s.Root().limitValues.Load().(Limits)
+	return l
+}
+
+// depth of the Set in the tree, the root Set has a depth of 0
+func (s *Set) depth() int {
+	depth := 0
+	for set := s; set.parent != nil; set = set.parent {
+		depth++
+	}
+
+	return depth
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSet_Limit(t *testing.T) {
+	set := &Set{}
+	set.Limit(Limits{MaxSettings: 2, MaxDepth: 1, MaxValueLength: 8})
+
+	var a, b, c string
+	if _, err := set.Register("A", &a, ""); err != nil {
+		t.Fatalf("Failed to register setting: %v", err)
+	}
+
+	if _, err := set.Register("A", &b, ""); err == nil {
+		t.Errorf("Failed to reject duplicate setting")
+	}
+
+	if _, err := set.Subset("Plugin").Register("B", &b, ""); err != nil {
+		t.Fatalf("Failed to register setting: %v", err)
+	}
+
+	var limitErr *LimitError
+	if _, err := set.Register("C", &c, ""); !errors.As(err, &limitErr) || limitErr.Limit != "MaxSettings" || !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Failed to limit settings: got %v", err)
+	}
+
+	if _, err := set.Update("A", strings.Repeat("a", 9)); !errors.As(err, &limitErr) || limitErr.Limit != "MaxValueLength" || a != "" {
+		t.Errorf("Failed to limit value length: got %v", err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil || !strings.Contains(r.(string), "MaxDepth") {
+				t.Errorf("Failed to limit depth: got %v", r)
+			}
+		}()
+
+		set.Subset("Plugin").Subset("Nested")
+	}()
+}
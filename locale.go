@@ -0,0 +1,71 @@
+package config
+
+import "strings"
+
+// Catalog supplies localized descriptions of settings, i.e. backed by the message catalog of a product, see Set.Localize
+type Catalog interface {
+	Describe(locale, path string) (string, bool)
+}
+
+// CatalogFunc defines a function that supplies localized descriptions of settings
+type CatalogFunc func(locale, path string) (string, bool)
+
+// Describe implements Catalog.Describe
+func (f CatalogFunc) Describe(locale, path string) (string, bool) {
+	return f(locale, path)
+}
+
+// Localize sets the Catalog consulted by Setting.Describe for settings of the Set tree without a description of the locale in Setting.Descriptions, a nil Catalog removes it
+func (s *Set) Localize(c Catalog) {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	root.catalog = c
+}
+
+// Describe returns the description of the setting in the locale (i.e. fr or pt-BR) for usage output such as Set.Dump with DumpLocale. Setting.Descriptions are consulted first, then the Catalog of the Set (see Set.Localize), each for the locale and then its base language (fr for fr-CA), falling back to the Description.
+func (s *Setting) Describe(locale string) string {
+	if locale == "" {
+		return s.Description
+	}
+
+	locales := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locales = append(locales, locale[:i])
+	}
+
+	for _, l := range locales {
+		for key, description := range s.Descriptions {
+			if strings.EqualFold(key, l) {
+				return description
+			}
+		}
+	}
+
+	if s.set != nil {
+		root := s.set.Root()
+
+		root.mu.Lock()
+		catalog := root.catalog
+		root.mu.Unlock()
+
+		if catalog != nil {
+			for _, l := range locales {
+				if description, ok := catalog.Describe(l, s.Path); ok {
+					return description
+				}
+			}
+		}
+	}
+
+	return s.Description
+}
+
+// DumpLocale writes the descriptions of the settings in the locale, see Setting.Describe
+func DumpLocale(locale string) DumpOption {
+	return func(o *dumpOptions) {
+		o.locale = locale
+	}
+}
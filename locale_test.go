@@ -0,0 +1,50 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetting_Describe(t *testing.T) {
+	set := &Set{}
+	port := set.Subset("HTTP").Setting("Port", 80, "port to listen on")
+	port.Descriptions = map[string]string{"fr": "port d'écoute", "pt-BR": "porta de escuta"}
+	host := set.Subset("HTTP").Setting("Host", "localhost", "host to listen on")
+
+	set.Localize(CatalogFunc(func(locale, path string) (string, bool) {
+		if locale == "de" && path == "HTTP.Host" {
+			return "Host für eingehende Verbindungen", true
+		}
+		return "", false
+	}))
+
+	tests := []struct {
+		setting  *Setting
+		locale   string
+		expected string
+	}{
+		{port, "", "port to listen on"},
+		{port, "fr", "port d'écoute"},
+		{port, "fr-CA", "port d'écoute"},
+		{port, "PT-br", "porta de escuta"},
+		{port, "de", "port to listen on"},
+		{host, "de_AT", "Host für eingehende Verbindungen"},
+		{host, "fr", "host to listen on"},
+	}
+
+	for _, tt := range tests {
+		if actual := tt.setting.Describe(tt.locale); actual != tt.expected {
+			t.Errorf("Failed to describe %q in %q: expected %q; got %q", tt.setting.Path, tt.locale, tt.expected, actual)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := set.Dump(&buf, DumpLocale("fr")); err != nil {
+		t.Fatalf("Failed to dump: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "port d'écoute") {
+		t.Errorf("Failed to dump localized description: got %q", buf.String())
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Logger receives the diagnostics of the package: provider failures, notifier panics, derived settings that fail to recompute, fields Bind can not bind, cache files Cache can not write and write-behind failures of Set.Persist. Arguments are alternating keys and values, so a *slog.Logger is a Logger as is.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// loggerHolder allows storing any Logger implementation in an atomic.Value
+type loggerHolder struct {
+	Logger
+}
+
+var packageLogger atomic.Value
+
+// SetLogger sets the Logger receiving the diagnostics of the package, a nil Logger discards them which is the default
+func SetLogger(l Logger) {
+	packageLogger.Store(loggerHolder{l})
+}
+
+// logger returns the package Logger
+func logger() Logger {
+	if holder, _ := packageLogger.Load().(loggerHolder); holder.Logger != nil {
+		return holder.Logger
+	}
+
+	return nopLogger{}
+}
+
+// nopLogger discards everything
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// recoverNotifier logs the panic of a notifier for the setting rather than crashing the writer, so the remaining notifiers still run
+func recoverNotifier(setting *Setting) {
+	if r := recover(); r != nil {
+		logger().Error("notifier panicked", "path", setting.Path, "panic", fmt.Sprint(r))
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// recordingLogger records every message as "level: msg"
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) log(level, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.messages = append(l.messages, level+": "+msg)
+}
+
+func (l *recordingLogger) Debug(msg string, _ ...interface{}) { l.log("debug", msg) }
+func (l *recordingLogger) Warn(msg string, _ ...interface{})  { l.log("warn", msg) }
+func (l *recordingLogger) Error(msg string, _ ...interface{}) { l.log("error", msg) }
+
+func TestSetLogger(t *testing.T) {
+	recorder := &recordingLogger{}
+	SetLogger(recorder)
+	defer SetLogger(nil)
+
+	set := &Set{}
+	setting := set.Setting("Port", 80, "")
+
+	notified := false
+	setting.Notify(NotifyFunc(func(*Setting) { panic("boom") }))
+	set.Notify(NotifyFunc(func(*Setting) { notified = true }))
+
+	if err := setting.Set("8080"); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+
+	if !notified {
+		t.Errorf("Failed to notify after a notifier panicked")
+	}
+
+	set.AddProvider("remote", ProviderFunc(func(context.Context) (map[string]string, error) {
+		return nil, errors.New("unreachable")
+	}))
+	_ = set.Reload(context.Background())
+
+	cfg := struct {
+		Callback func()
+	}{}
+	set.Bind(&cfg)
+
+	expected := []string{"error: notifier panicked", "warn: provider failed", "warn: field not bound"}
+	if fmt.Sprint(recorder.messages) != fmt.Sprint(expected) {
+		t.Errorf("Failed to log diagnostics: expected %v; got %v", expected, recorder.messages)
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Logging is a reusable subset of logging settings, bind it into a subset (i.e. set.Subset("Log").Bind(&logging)) and apply level changes at runtime with OnLevel or SlogLevel
+type Logging struct {
+	// Level of the least severe messages written
+	Level string `description:"Minimum level of log messages (debug, info, warn or error)" constraint:"in(this, \"debug\", \"info\", \"warn\", \"error\")"`
+
+	// Format of the messages
+	Format string `description:"Format of log messages (text or json)" constraint:"in(this, \"text\", \"json\")"`
+
+	// Output messages are written to, stderr, stdout or the path of a file
+	Output string `description:"Output of log messages (stderr, stdout or a file path)"`
+
+	// Sampling writes only the first of every N identical messages, 0 or 1 writes every message. This is applied by the logger, see Logging.Sampled
+	Sampling int `description:"Write only the first of every N identical log messages, 0 disables sampling" constraint:"this >= 0"`
+}
+
+// DefaultLogging returns Logging writing info level text messages to stderr without sampling
+func DefaultLogging() Logging {
+	return Logging{
+		Level:  "info",
+		Format: "text",
+		Output: "stderr",
+	}
+}
+
+// Writer opens the Output, files are created when missing and appended to. Closing the standard streams does nothing.
+func (l *Logging) Writer() (io.WriteCloser, error) {
+	switch l.Output {
+	case "", "stderr":
+		return nopCloser{os.Stderr}, nil
+	case "stdout":
+		return nopCloser{os.Stdout}, nil
+	}
+
+	f, err := os.OpenFile(l.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open log output: %w", err)
+	}
+
+	return f, nil
+}
+
+// Sampled returns if the nth (starting at 1) occurrence of a message should be written with the current Sampling
+func (l *Logging) Sampled(n int) bool {
+	return l.Sampling <= 1 || n%l.Sampling == 1
+}
+
+// OnLevel calls fn with the Level setting of the Set, bound to Logging, now and whenever it changes. This bridges the level to any logger, for zap:
+//
+//	level := zap.NewAtomicLevel()
+//	config.OnLevel(set.Subset("Log"), func(l string) { _ = level.UnmarshalText([]byte(l)) })
+func OnLevel(set *Set, fn func(level string)) *NotifyHandle {
+	setting := set.lookup(set.pathOf("Level"))
+	if setting == nil {
+		panic(fmt.Sprintf("%q does not contain a Level setting", set.path))
+	}
+
+	return setting.Notify(NotifyFunc(func(s *Setting) {
+		fn(s.format())
+	}), NotifyCurrent())
+}
+
+// nopCloser is an io.WriteCloser that does not close the writer
+type nopCloser struct {
+	io.Writer
+}
+
+// Close does nothing
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build go1.21
+
+package config
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// SlogLevel applies the Level setting of the Set, bound to Logging, to the level now and whenever it changes, see OnLevel
+func SlogLevel(set *Set, level *slog.LevelVar) *NotifyHandle {
+	return OnLevel(set, func(l string) {
+		// the Level constraint keeps unknown levels out, the level is kept if one gets through
+		_ = level.UnmarshalText([]byte(l))
+	})
+}
+
+// SlogHandler returns a slog.Handler writing messages in the Format to w at the level, pass a slog.LevelVar updated by SlogLevel to change the level at runtime
+func (l *Logging) SlogHandler(w io.Writer, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+
+	if strings.EqualFold(l.Format, "json") {
+		return slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.NewTextHandler(w, opts)
+}
+
+// *slog.Logger receives the diagnostics of the package as is, see SetLogger
+var _ Logger = (*slog.Logger)(nil)
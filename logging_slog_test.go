@@ -0,0 +1,35 @@
+//go:build go1.21
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLevel(t *testing.T) {
+	logging := DefaultLogging()
+	logging.Format = "json"
+
+	s := &Set{}
+	log := s.Subset("Log").Bind(&logging)
+
+	var level slog.LevelVar
+	defer SlogLevel(log, &level).Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(logging.SlogHandler(&buf, &level))
+
+	logger.Debug("hidden")
+	if _, err := s.Update("Log.Level", "debug"); err != nil {
+		t.Fatalf("Failed to update level: %v", err)
+	}
+	logger.DebugContext(context.Background(), "shown")
+
+	if out := buf.String(); strings.Contains(out, "hidden") || !strings.Contains(out, `"msg":"shown"`) {
+		t.Errorf("Failed to apply level: got %q", out)
+	}
+}
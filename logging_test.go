@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOnLevel(t *testing.T) {
+	logging := DefaultLogging()
+
+	s := &Set{}
+	log := s.Subset("Log").Bind(&logging)
+
+	var levels []string
+	handle := OnLevel(log, func(level string) { levels = append(levels, level) })
+	defer handle.Close()
+
+	if _, err := s.Update("Log.Level", "debug"); err != nil {
+		t.Fatalf("Failed to update level: %v", err)
+	}
+	if _, err := s.Update("Log.Level", "verbose"); !errors.Is(err, ErrConstraint) {
+		t.Errorf("Failed to reject level: expected %v; got %v", ErrConstraint, err)
+	}
+
+	if len(levels) != 2 || levels[0] != "info" || levels[1] != "debug" {
+		t.Errorf("Failed to apply levels: expected %v; got %v", []string{"info", "debug"}, levels)
+	}
+}
+
+func TestLogging_Sampled(t *testing.T) {
+	logging := Logging{Sampling: 3}
+
+	var written int
+	for n := 1; n <= 9; n++ {
+		if logging.Sampled(n) {
+			written++
+		}
+	}
+
+	if written != 3 {
+		t.Errorf("Failed to sample: expected %d; got %d", 3, written)
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Mount grafts the independently constructed root Set under the path within the current Set (i.e. root.Mount("Plugin.Foo", pluginSet)), so libraries can build their configuration tree standalone and hosts compose them. The mounted Set becomes the subset at the path: its settings, subsets, providers, templates and map bindings move into the tree with their paths prefixed, so Get and Range on it keep resolving relative names, the host sees the full paths and notifications propagate up to the host.
+//
+// Constraints of the mounted settings keep resolving the settings they reference within the mounted Set first (see Setting.Constraint), so names the host also uses are not confused. Options of the mounted root that apply to a whole tree (i.e. Set.Hook, Set.Limit or Set.MeasureNotifiers) are replaced by the ones of the host. Mount must be called before either Set is used concurrently. Path can not be empty or already in use, set must be a root other than the root of the current Set.
+func (s *Set) Mount(path string, set *Set) {
+	if path == "" {
+		panic("path can not be empty")
+	}
+	if set == nil {
+		panic("set can not be nil")
+	}
+	if set.root != nil {
+		panic(fmt.Sprintf("set %q is not a root", set.path))
+	}
+
+	root := s.Root()
+	if set == root {
+		panic("set can not be mounted within itself")
+	}
+
+	full := s.pathOf(path)
+	key := strings.ToLower(full)
+
+	if _, found := root.children.Load(key); found {
+		panic(fmt.Sprintf("path %q already in use", full))
+	}
+	root.settings.Range(func(_, v interface{}) bool {
+		if within(v.(*Setting).Path, full) {
+			panic(fmt.Sprintf("path %q already in use", full))
+		}
+		return true
+	})
+
+	count := atomic.LoadInt32(&set.settingCount)
+	if limits := root.limits(); limits.MaxSettings > 0 && int(atomic.LoadInt32(&root.settingCount)+count) > limits.MaxSettings {
+		panic((&LimitError{Limit: "MaxSettings", Path: full, Max: limits.MaxSettings}).Error())
+	}
+	atomic.AddInt32(&root.settingCount, count)
+	atomic.StoreInt32(&set.settingCount, 0)
+
+	parent := s
+	name := path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		for _, segment := range strings.Split(path[:i], ".") {
+			parent = parent.Subset(segment)
+		}
+		name = path[i+1:]
+	}
+
+	prefix := func(p string) string {
+		if p == "" {
+			return full
+		}
+		return full + "." + p
+	}
+
+	set.settings.Range(func(k, v interface{}) bool {
+		setting := v.(*Setting)
+		setting.Path = prefix(setting.Path)
+		root.settings.Store(strings.ToLower(setting.Path), setting)
+		set.settings.Delete(k)
+		return true
+	})
+
+	set.children.Range(func(k, v interface{}) bool {
+		child := v.(*Set)
+		child.path = prefix(child.path)
+		child.root = root
+		root.children.Store(strings.ToLower(child.path), child)
+		set.children.Delete(k)
+		return true
+	})
+
+	set.mu.Lock()
+	providers, templates, bindings, pending, approvals := set.providers, set.templates, set.mapBindings, set.pendingRestart, set.approvals
+	set.providers, set.templates, set.mapBindings, set.pendingRestart, set.approvals = nil, nil, nil, nil, nil
+	set.mu.Unlock()
+
+	for _, t := range templates {
+		t.segments = append(strings.Split(key, "."), t.segments...)
+		t.prototype.Path = prefix(t.prototype.Path)
+	}
+
+	root.mu.Lock()
+	root.providers = append(root.providers, providers...)
+	root.templates = append(root.templates, templates...)
+	// a new slice, as the bindings are ranged over outside the lock
+	root.mapBindings = append(append([]*mapBinding(nil), root.mapBindings...), bindings...)
+	for k, p := range pending {
+		if root.pendingRestart == nil {
+			root.pendingRestart = map[string]*pendingChange{}
+		}
+		root.pendingRestart[prefix(k)] = p
+	}
+	for id, r := range approvals {
+		if root.approvals == nil {
+			root.approvals = map[string]*approvalRequest{}
+		}
+		root.approvals[id] = r
+	}
+	root.mu.Unlock()
+
+	set.name = name
+	set.path = full
+	set.parent = parent
+	set.root = root
+	set.mounted = true
+	root.children.Store(key, set)
+
+	s.trace("mount", full, "mounted %d settings", count)
+}
+
+// referenced returns the setting referenced by path from a Constraint or Gate of the Set: within the Set mounted closest above it first (see Set.Mount), so a mounted library keeps resolving its own settings, and like Set.lookup otherwise
+func (s *Set) referenced(path string) *Setting {
+	for set := s; set != nil; set = set.parent {
+		if !set.mounted {
+			continue
+		}
+
+		if setting, found := set.Root().settings.Load(strings.ToLower(set.path + "." + path)); found {
+			return setting.(*Setting)
+		}
+		break
+	}
+
+	return s.lookup(path)
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSet_Mount(t *testing.T) {
+	plugin := &Set{}
+	level := plugin.Setting("Level", "info", "")
+	plugin.Subset("Retry").Setting("Max", 3, "")
+	plugin.Template("Queues.*.Size", 10, "")
+	plugin.AddProvider("plugin", ProviderFunc(func(context.Context) (map[string]string, error) {
+		return map[string]string{"Retry.Max": "5"}, nil
+	}))
+
+	var pluginNotified int
+	plugin.Notify(NotifyFunc(func(*Setting) { pluginNotified++ }))
+
+	root := &Set{}
+	root.Setting("Debug", false, "")
+	root.Mount("Plugin.Foo", plugin)
+
+	var hostNotified []string
+	root.Notify(NotifyFunc(func(s *Setting) { hostNotified = append(hostNotified, s.Path) }))
+
+	if plugin.Path() != "Plugin.Foo" || plugin.Name() != "Foo" || plugin.Root() != root || plugin.Parent() != root.Subset("Plugin") {
+		t.Errorf("Failed to mount set: got path %q and name %q", plugin.Path(), plugin.Name())
+	}
+
+	if root.Get("Plugin.Foo.Level") != level || plugin.Get("Level") != level || root.Subset("Plugin").Subset("Foo").Get("Retry.Max") == nil {
+		t.Error("Failed to get mounted settings")
+	}
+	if level.Path != "Plugin.Foo.Level" {
+		t.Errorf("Failed to translate path: expected %q; got %q", "Plugin.Foo.Level", level.Path)
+	}
+
+	var paths []string
+	plugin.Range(func(_ string, setting *Setting) bool {
+		paths = append(paths, setting.Path)
+		return true
+	})
+	if len(paths) != 2 {
+		t.Errorf("Failed to range mounted settings: got %v", paths)
+	}
+
+	_ = level.Set("debug")
+	if pluginNotified != 1 || len(hostNotified) != 1 || hostNotified[0] != "Plugin.Foo.Level" {
+		t.Errorf("Failed to notify: plugin %d times, host with %v", pluginNotified, hostNotified)
+	}
+
+	if err := root.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if v := root.Get("Plugin.Foo.Retry.Max").String(); v != "5" {
+		t.Errorf("Failed to reload mounted provider: expected %q; got %q", "5", v)
+	}
+
+	if setting := plugin.Get("Queues.Jobs.Size"); setting == nil || setting.Path != "Plugin.Foo.Queues.Jobs.Size" {
+		t.Errorf("Failed to materialize mounted template: got %v", setting)
+	}
+
+	for name, mount := range map[string]func(){
+		"path in use":  func() { root.Mount("Plugin.Foo", &Set{}) },
+		"setting path": func() { root.Mount("Debug", &Set{}) },
+		"not a root":   func() { (&Set{}).Mount("Other", root.Subset("Plugin")) },
+		"itself":       func() { root.Subset("Other").Mount("Root", root) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Failed to panic mounting %s", name)
+				}
+			}()
+			mount()
+		}()
+	}
+}
+
+func TestSet_MountConstraint(t *testing.T) {
+	library := &Set{}
+	library.Setting("Max", 10, "")
+	library.Setting("Port", 5, "").Constraint = "this <= Max"
+
+	host := &Set{}
+	host.Setting("Max", 1, "")
+	host.Mount("Plugin.Foo", library)
+
+	// Max resolves to the setting of the library, not the one of the host
+	if err := host.Get("Plugin.Foo.Port").Set("8"); err != nil {
+		t.Errorf("Failed to resolve constraint within mounted set: %v", err)
+	}
+	if err := host.Get("Plugin.Foo.Port").Set("11"); !errors.Is(err, ErrConstraint) {
+		t.Errorf("Failed to violate constraint: expected %v; got %v", ErrConstraint, err)
+	}
+
+	deps := host.Dependencies()
+	if len(deps) != 1 || deps[0].From != "Plugin.Foo.Max" || deps[0].To != "Plugin.Foo.Port" {
+		t.Errorf("Failed to resolve dependency within mounted set: got %+v", deps)
+	}
+}
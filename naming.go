@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NamingCase is the letter case and separator convention of a name
+type NamingCase int
+
+const (
+	// CaseAny allows any name
+	CaseAny NamingCase = iota
+
+	// CasePascal requires names like HTTPServer or MaxConns
+	CasePascal
+
+	// CaseCamel requires names like httpServer or maxConns
+	CaseCamel
+
+	// CaseKebab requires names like http-server or max-conns
+	CaseKebab
+
+	// CaseSnake requires names like http_server or max_conns
+	CaseSnake
+)
+
+var namingCases = map[NamingCase]*regexp.Regexp{
+	CasePascal: regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`),
+	CaseCamel:  regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`),
+	CaseKebab:  regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+	CaseSnake:  regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+}
+
+func (c NamingCase) String() string {
+	switch c {
+	case CaseAny:
+		return "any"
+	case CasePascal:
+		return "PascalCase"
+	case CaseCamel:
+		return "camelCase"
+	case CaseKebab:
+		return "kebab-case"
+	case CaseSnake:
+		return "snake_case"
+	default:
+		return "unknown"
+	}
+}
+
+// NamingPolicy is the convention the names of settings and subsets are checked against by Set.CheckNaming
+type NamingPolicy struct {
+	// Case every name must be in
+	Case NamingCase
+
+	// MaxLength of every name, zero for no limit
+	MaxLength int
+
+	// MaxPathLength of the full dot separated path of a setting, zero for no limit
+	MaxPathLength int
+
+	// Pattern every name must match when set
+	Pattern *regexp.Regexp
+}
+
+// NamingViolation is a name that does not follow the NamingPolicy
+type NamingViolation struct {
+	// Path of the setting or subset
+	Path string
+
+	// Reason the name violates the policy
+	Reason string
+}
+
+func (v NamingViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Reason)
+}
+
+// CheckNaming validates the names of every setting, and the subsets leading to them, against the policy and returns the violations sorted by path. Platform teams can enforce naming consistency across services at startup or in tests.
+func (s *Set) CheckNaming(policy NamingPolicy) []NamingViolation {
+	seen := map[string]bool{}
+
+	var violations []NamingViolation
+	add := func(path, format string, args ...interface{}) {
+		reason := fmt.Sprintf(format, args...)
+		if seen[path+reason] {
+			return
+		}
+		seen[path+reason] = true
+
+		violations = append(violations, NamingViolation{Path: path, Reason: reason})
+	}
+
+	s.Range(func(_ string, setting *Setting) bool {
+		if policy.MaxPathLength > 0 && len(setting.Path) > policy.MaxPathLength {
+			add(setting.Path, "path is longer than %d characters", policy.MaxPathLength)
+		}
+
+		segments := strings.Split(setting.Path, ".")
+		for i, name := range segments {
+			path := strings.Join(segments[:i+1], ".")
+
+			if re := namingCases[policy.Case]; re != nil && !re.MatchString(name) {
+				add(path, "name %q is not %s", name, policy.Case)
+			}
+
+			if policy.MaxLength > 0 && len(name) > policy.MaxLength {
+				add(path, "name %q is longer than %d characters", name, policy.MaxLength)
+			}
+
+			if policy.Pattern != nil && !policy.Pattern.MatchString(name) {
+				add(path, "name %q does not match %s", name, policy.Pattern)
+			}
+		}
+
+		return true
+	})
+
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+
+	return violations
+}
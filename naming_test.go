@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestSet_CheckNaming(t *testing.T) {
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", 80, "")
+	set.Subset("HTTP").Setting("read_timeout", 0, "")
+	set.Subset("http_client").Setting("MaximumNumberOfIdleConnections", 0, "")
+
+	violations := set.CheckNaming(NamingPolicy{Case: CasePascal, MaxLength: 20})
+
+	expected := []string{
+		"HTTP.read_timeout: name \"read_timeout\" is not PascalCase",
+		"http_client: name \"http_client\" is not PascalCase",
+		"http_client.MaximumNumberOfIdleConnections: name \"MaximumNumberOfIdleConnections\" is longer than 20 characters",
+	}
+
+	if len(violations) != len(expected) {
+		t.Fatalf("Failed to check naming: expected %d violations; got %v", len(expected), violations)
+	}
+
+	for i, v := range violations {
+		if v.String() != expected[i] {
+			t.Errorf("Failed to report violation: expected %q; got %q", expected[i], v.String())
+		}
+	}
+
+	if violations := set.CheckNaming(NamingPolicy{}); len(violations) != 0 {
+		t.Errorf("Failed to allow any name: got %v", violations)
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
+	"strconv"
+)
+
+// parseAddr parses an IP address, an empty string is the zero Addr
+func parseAddr(v string) (netip.Addr, error) {
+	if v == "" {
+		return netip.Addr{}, nil
+	}
+
+	return netip.ParseAddr(v)
+}
+
+// formatAddr formats the IP address, the zero Addr is an empty string
+func formatAddr(a netip.Addr) string {
+	if !a.IsValid() {
+		return ""
+	}
+
+	return a.String()
+}
+
+// parseAddrPort parses an IP address and port (i.e. 10.0.0.1:8080 or [::1]:8080), an empty string is the zero AddrPort
+func parseAddrPort(v string) (netip.AddrPort, error) {
+	if v == "" {
+		return netip.AddrPort{}, nil
+	}
+
+	return netip.ParseAddrPort(v)
+}
+
+// formatAddrPort formats the IP address and port, the zero AddrPort is an empty string
+func formatAddrPort(a netip.AddrPort) string {
+	if !a.IsValid() {
+		return ""
+	}
+
+	return a.String()
+}
+
+// parseTCPAddr parses a TCP address without resolving host names, the host may be empty to listen on all addresses (i.e. :8080), an empty string is nil
+func parseTCPAddr(v string) (*net.TCPAddr, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	host, port, err := net.SplitHostPort(v)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", port)
+	}
+
+	addr := &net.TCPAddr{Port: int(p)}
+	if host != "" {
+		ip, err := netip.ParseAddr(host)
+		if err != nil {
+			return nil, err
+		}
+		addr.IP = ip.AsSlice()
+		addr.Zone = ip.Zone()
+	}
+
+	return addr, nil
+}
+
+// formatTCPAddr formats the TCP address, nil is an empty string
+func formatTCPAddr(a *net.TCPAddr) string {
+	if a == nil {
+		return ""
+	}
+
+	return a.String()
+}
+
+// parseMailAddress parses a single RFC 5322 address (i.e. Ops <ops@example.com>), an empty string is nil
+func parseMailAddress(v string) (*mail.Address, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	return mail.ParseAddress(v)
+}
+
+// formatMailAddress formats the address, nil is an empty string
+func formatMailAddress(a *mail.Address) string {
+	if a == nil {
+		return ""
+	}
+
+	return a.String()
+}
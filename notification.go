@@ -1,5 +1,10 @@
 package config
 
+import (
+	"context"
+	"sync"
+)
+
 // Notifier for configuration Setting changes
 type Notifier interface {
 	// Notify defines a function that is called when s.Set is called with a different value other than the current
@@ -22,6 +27,90 @@ func (h *NotifyHandle) Close() error {
 	return nil
 }
 
+// notifyContext registers the Notifier with register until the ctx is done. The Notifier is not called once the ctx is done, and closing the returned handle stops watching the ctx.
+func notifyContext(ctx context.Context, register func(Notifier) *NotifyHandle, n Notifier) *NotifyHandle {
+	if n == nil || ctx.Err() != nil {
+		return &NotifyHandle{}
+	}
+
+	var notifier Notifier = NotifyFunc(func(s *Setting) {
+		if ctx.Err() == nil {
+			n.Notify(s)
+		}
+	})
+	if loaded, ok := n.(LoadedNotifier); ok {
+		notifier = loadedContext{Notifier: notifier, ctx: ctx, loaded: loaded}
+	}
+
+	handle := register(named(n, notifier))
+
+	// a ctx that is never done needs no watching
+	if ctx.Done() == nil {
+		return handle
+	}
+
+	var once sync.Once
+	stop := make(chan struct{})
+	wrapped := &NotifyHandle{
+		stopFunc: func(interface{}) {
+			once.Do(func() {
+				close(stop)
+				_ = handle.Close()
+			})
+		},
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = wrapped.Close()
+		case <-stop:
+		}
+	}()
+
+	return wrapped
+}
+
+// loadedContext is a LoadedNotifier registered with a ctx, not called once the ctx is done
+type loadedContext struct {
+	Notifier
+	ctx    context.Context
+	loaded LoadedNotifier
+}
+
+// Loaded implements LoadedNotifier.Loaded
+func (l loadedContext) Loaded(set *Set, changed []*Setting) {
+	if l.ctx.Err() == nil {
+		l.loaded.Loaded(set, changed)
+	}
+}
+
+// NotifyOption configures a notification registered with Setting.Notify or Set.Notify
+type NotifyOption func(*notifyOptions)
+
+// notifyOptions are the resolved NotifyOption values
+type notifyOptions struct {
+	current bool
+	name    string
+}
+
+// newNotifyOptions resolves the options
+func newNotifyOptions(opts []NotifyOption) *notifyOptions {
+	o := &notifyOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// NotifyCurrent delivers the current value immediately upon registration, for a Set every setting within it in path order, so a subscriber never misses a value that changed just before it attached. The current value is delivered after registering, so a concurrent change may be delivered twice but never lost.
+func NotifyCurrent() NotifyOption {
+	return func(o *notifyOptions) {
+		o.current = true
+	}
+}
+
 // NotifyFunc defines a function that is called when s.Set is called with a different value other than the current
 type NotifyFunc func(s *Setting)
 
@@ -29,3 +118,135 @@ type NotifyFunc func(s *Setting)
 func (f NotifyFunc) Notify(s *Setting) {
 	f(s)
 }
+
+// changeQueue delivers change events in order from a background goroutine, so notifiers sending changes to external systems never block Setting.Set
+type changeQueue struct {
+	once    sync.Once
+	mu      sync.Mutex
+	events  []ChangeEvent
+	signal  chan struct{}
+	done    chan struct{}
+	closing bool
+}
+
+// push the event to be delivered, events pushed after close are dropped
+func (q *changeQueue) push(event ChangeEvent, deliver func(ChangeEvent)) {
+	q.once.Do(func() { q.start(deliver) })
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// the signal is closed by close under the lock
+	if q.closing {
+		return
+	}
+	q.events = append(q.events, event)
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// close stops accepting events and waits for the queued events to be delivered
+func (q *changeQueue) close(deliver func(ChangeEvent)) {
+	q.once.Do(func() { q.start(deliver) })
+
+	q.mu.Lock()
+	if !q.closing {
+		q.closing = true
+		close(q.signal)
+	}
+	q.mu.Unlock()
+
+	<-q.done
+}
+
+// start the delivery goroutine
+func (q *changeQueue) start(deliver func(ChangeEvent)) {
+	q.signal = make(chan struct{}, 1)
+	q.done = make(chan struct{})
+
+	go func() {
+		defer close(q.done)
+
+		for {
+			_, open := <-q.signal
+
+			q.mu.Lock()
+			pending := q.events
+			q.events = nil
+			q.mu.Unlock()
+
+			for _, event := range pending {
+				deliver(event)
+			}
+
+			if !open {
+				return
+			}
+		}
+	}()
+}
+
+// NotifierGroup registers notifiers across many settings and Sets so they can all be closed with one call, sparing components that watch dozens of settings from tracking every NotifyHandle. The zero value is ready to use.
+type NotifierGroup struct {
+	mu      sync.Mutex
+	handles []*NotifyHandle
+	closed  bool
+}
+
+// Setting registers the Notifier with the setting, see Setting.Notify
+func (g *NotifierGroup) Setting(s *Setting, n Notifier, opts ...NotifyOption) {
+	g.add(func(n Notifier) *NotifyHandle { return s.Notify(n, opts...) }, n)
+}
+
+// Set registers the Notifier with the Set, see Set.Notify
+func (g *NotifierGroup) Set(s *Set, n Notifier, opts ...NotifyOption) {
+	g.add(func(n Notifier) *NotifyHandle { return s.Notify(n, opts...) }, n)
+}
+
+// Add the handle to the group, so it is closed with the group. A handle added to a closed group is closed immediately.
+func (g *NotifierGroup) Add(h *NotifyHandle) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		_ = h.Close()
+		return
+	}
+
+	g.handles = append(g.handles, h)
+}
+
+// add registers the Notifier with register, wrapped so it is never called once the group is closed even if a change is being delivered concurrently
+func (g *NotifierGroup) add(register func(Notifier) *NotifyHandle, n Notifier) {
+	if n == nil {
+		return
+	}
+
+	g.Add(register(named(n, NotifyFunc(func(s *Setting) {
+		g.mu.Lock()
+		closed := g.closed
+		g.mu.Unlock()
+
+		if !closed {
+			n.Notify(s)
+		}
+	}))))
+}
+
+// Close every notifier of the group, later registrations are closed immediately
+func (g *NotifierGroup) Close() error {
+	g.mu.Lock()
+	handles := g.handles
+	g.handles = nil
+	g.closed = true
+	g.mu.Unlock()
+
+	for _, h := range handles {
+		_ = h.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+// ErrPrecision is returned by Setting.Set for a float32 setting when the value can not be represented without losing precision and the Set is strict about floats, see Set.StrictFloats
+var ErrPrecision = errors.New("value loses precision")
+
+// RangeError is returned when a number is outside of the range of the type of the setting
+type RangeError struct {
+	// Path of the setting
+	Path string
+
+	// Input that is out of range
+	Input string
+
+	// Type of the setting (i.e. int8)
+	Type string
+
+	// Min and Max allowed by the Type
+	Min string
+	Max string
+
+	// Err of the conversion
+	Err error
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%s: %q is out of range for %s [%s, %s]", e.Path, e.Input, e.Type, e.Min, e.Max)
+}
+
+// Unwrap returns the error of the conversion, which wraps strconv.ErrRange
+func (e *RangeError) Unwrap() error {
+	return e.Err
+}
+
+// newRangeError describes the range allowed by the type of the setting for the input
+func newRangeError(s *Setting, v string, err error) error {
+	rv := reflect.ValueOf(s.Value)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	rangeErr := &RangeError{Path: s.Path, Input: v, Type: rv.Type().String(), Err: err}
+
+	bits := rv.Type().Bits
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rangeErr.Min = strconv.FormatInt(-1<<(bits()-1), 10)
+		rangeErr.Max = strconv.FormatInt(1<<(bits()-1)-1, 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		rangeErr.Min = "0"
+		rangeErr.Max = strconv.FormatUint(math.MaxUint64>>(64-bits()), 10)
+	case reflect.Float32:
+		rangeErr.Min = strconv.FormatFloat(-math.MaxFloat32, 'g', -1, 32)
+		rangeErr.Max = strconv.FormatFloat(math.MaxFloat32, 'g', -1, 32)
+	case reflect.Float64:
+		rangeErr.Min = strconv.FormatFloat(-math.MaxFloat64, 'g', -1, 64)
+		rangeErr.Max = strconv.FormatFloat(math.MaxFloat64, 'g', -1, 64)
+	default:
+		// types of their own (i.e. time.Duration parsing) report the range themselves
+		return err
+	}
+
+	return rangeErr
+}
+
+// StrictFloats rejects values of float32 settings of the Set tree that can not be represented without losing precision (i.e. 16777217) with ErrPrecision, rather than silently rounding them
+func (s *Set) StrictFloats(strict bool) {
+	var v int32
+	if strict {
+		v = 1
+	}
+
+	atomic.StoreInt32(&s.Root().strictFloats, v)
+}
+
+// parseFloat32 parses the value for a float32 setting, checking the precision when the Set is strict about floats
+func (s *Setting) parseFloat32(v string) (float32, error) {
+	pv, err := strconv.ParseFloat(v, 32)
+	if err != nil {
+		return 0, fmt.Errorf("unable to cast value to float32: %w", err)
+	}
+
+	if s.set == nil || atomic.LoadInt32(&s.set.Root().strictFloats) == 0 {
+		return float32(pv), nil
+	}
+
+	// the shortest form of the float32 must be the same number as the value, so 0.1 is fine while 16777217 is not
+	exact, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to cast value to float32: %w", err)
+	}
+
+	if shortest, _ := strconv.ParseFloat(strconv.FormatFloat(pv, 'g', -1, 32), 64); shortest != exact {
+		return 0, fmt.Errorf("%w in float32: %q is stored as %s", ErrPrecision, v, strconv.FormatFloat(pv, 'g', -1, 32))
+	}
+
+	return float32(pv), nil
+}
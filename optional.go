@@ -0,0 +1,78 @@
+package config
+
+// Optional is a value that is either set or unset, distinguishing a value the user supplied from one they did not (i.e. "false" from nothing for a tri-state bool). An empty string unsets it, and it formats as an empty string while unset. Bound fields of type Optional are settings, and Set.Dump shows them as (unset) while unset.
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+// Some returns an Optional set to the value
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, set: true}
+}
+
+// Get returns the value and whether it is set
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// IsSet returns whether the value is set
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// Or returns the value when it is set, otherwise fallback
+func (o Optional[T]) Or(fallback T) T {
+	if !o.set {
+		return fallback
+	}
+
+	return o.value
+}
+
+// UnmarshalSetting implements Unmarshaler, the value is parsed like a setting of T
+func (o *Optional[T]) UnmarshalSetting(v string) error {
+	if v == "" {
+		*o = Optional[T]{}
+		return nil
+	}
+
+	var value T
+	if err := (&Setting{Value: &value}).convert(v); err != nil {
+		return err
+	}
+
+	*o = Optional[T]{value: value, set: true}
+	return nil
+}
+
+// MarshalSetting implements Marshaler
+func (o *Optional[T]) MarshalSetting() string {
+	if !o.set {
+		return ""
+	}
+
+	value := o.value
+	return (&Setting{Value: &value}).format()
+}
+
+// Equals implements Equality
+func (o *Optional[T]) Equals(v string) bool {
+	if v == "" || !o.set {
+		return v == "" && !o.set
+	}
+
+	value := o.value
+	return (&Setting{Value: &value}).Equals(v)
+}
+
+// optional is implemented by Optional of any type
+type optional interface {
+	IsSet() bool
+}
+
+// IsUnset returns whether the Value is an Optional that is not set
+func (s *Setting) IsUnset() bool {
+	o, ok := s.Value.(optional)
+	return ok && !o.IsSet()
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptional_Setting(t *testing.T) {
+	cfg := struct {
+		Debug   Optional[bool]
+		Timeout Optional[time.Duration]
+	}{
+		Timeout: Some(5 * time.Second),
+	}
+
+	set := (&Set{}).Bind(&cfg)
+
+	debug := set.Get("Debug")
+	if debug == nil || !debug.IsUnset() || debug.DefaultValue != "" {
+		t.Fatalf("Failed to bind unset optional: got %+v", debug)
+	}
+
+	var buf bytes.Buffer
+	if err := set.Dump(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "(unset)") || !strings.Contains(buf.String(), `"5s"`) {
+		t.Errorf("Failed to dump optional values: got %s", buf.String())
+	}
+
+	if err := debug.Set("false"); err != nil {
+		t.Fatalf("Failed to set optional: %v", err)
+	}
+
+	if v, ok := cfg.Debug.Get(); !ok || v {
+		t.Errorf("Failed to distinguish false from unset: got %v (set %v)", v, ok)
+	}
+
+	if debug.IsUnset() || !debug.Equals("false") || debug.Equals("") {
+		t.Errorf("Failed to compare set optional")
+	}
+
+	if err := set.Get("Timeout").Set(""); err != nil || cfg.Timeout.IsSet() || cfg.Timeout.Or(time.Minute) != time.Minute {
+		t.Errorf("Failed to unset optional: got %+v (%v)", cfg.Timeout, err)
+	}
+
+	if err := debug.Set("maybe"); err == nil {
+		t.Errorf("Failed to reject invalid optional value")
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrImmutable is returned when changing a setting registered with WithImmutable
+var ErrImmutable = errors.New("setting is immutable")
+
+// SettingOption configures a setting created with Set.NewSetting
+type SettingOption func(*settingOptions)
+
+// settingOptions are the resolved SettingOption values
+type settingOptions struct {
+	description  string
+	mask         bool
+	category     string
+	defaultValue *string
+	validator    func(string) error
+	immutable    bool
+}
+
+// WithDescription sets the Description of the setting
+func WithDescription(description string) SettingOption {
+	return func(o *settingOptions) {
+		o.description = description
+	}
+}
+
+// WithMask masks the value of the setting, see Setting.Mask
+func WithMask() SettingOption {
+	return func(o *settingOptions) {
+		o.mask = true
+	}
+}
+
+// WithCategory sets the Category of the setting
+func WithCategory(category string) SettingOption {
+	return func(o *settingOptions) {
+		o.category = category
+	}
+}
+
+// WithDefault converts the string to the value before registering it, so it becomes the DefaultValue of the setting
+func WithDefault(v string) SettingOption {
+	return func(o *settingOptions) {
+		o.defaultValue = &v
+	}
+}
+
+// WithValidator checks every value written to the setting, the default included, rejecting the write with the error it returns
+func WithValidator(fn func(string) error) SettingOption {
+	return func(o *settingOptions) {
+		o.validator = fn
+	}
+}
+
+// WithImmutable rejects every change of the setting after it is registered with an error wrapping ErrImmutable, for values that are fixed for the life of the process but should still be visible (i.e. in Set.Dump)
+func WithImmutable() SettingOption {
+	return func(o *settingOptions) {
+		o.immutable = true
+	}
+}
+
+// NewSetting creates a new setting with the specified name and value in the current Set like Set.Setting, configured by the options. It fails fast by panicking when the setting can not be created, the default does not convert or the validator rejects the default.
+func (s *Set) NewSetting(name string, value Value, opts ...SettingOption) *Setting {
+	setting, err := s.newSetting(name, value, opts)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return setting
+}
+
+// newSetting registers the setting configured by the options
+func (s *Set) newSetting(name string, value Value, opts []SettingOption) (*Setting, error) {
+	o := &settingOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if value == nil {
+		return nil, errors.New("value can not be nil")
+	}
+
+	// the default is applied and validated before the setting is visible to anyone else
+	initial := &Setting{Name: name, Path: name, Value: value, set: s}
+	if o.defaultValue != nil {
+		if err := initial.convert(*o.defaultValue); err != nil {
+			return nil, fmt.Errorf("invalid default for %q: %w", name, err)
+		}
+	}
+
+	if o.validator != nil {
+		if err := o.validator(initial.format()); err != nil {
+			return nil, fmt.Errorf("invalid default for %q: %w", name, err)
+		}
+	}
+
+	return s.register(name, initial.Value, o.description, func(setting *Setting) {
+		setting.Mask = o.mask
+		setting.Category = o.category
+		setting.validator = o.validator
+		setting.immutable = o.immutable
+	})
+}
+
+// Immutable returns if the setting was registered with WithImmutable
+func (s *Setting) Immutable() bool {
+	return s.immutable
+}
+
+// validate the string with the validator of the setting and reject changing an immutable setting
+func (s *Setting) validate(v string) error {
+	if s.immutable {
+		return fmt.Errorf("unable to change %q: %w", s.Path, ErrImmutable)
+	}
+
+	if s.validator != nil {
+		if err := s.validator(v); err != nil {
+			return fmt.Errorf("invalid value for %q: %w", s.Path, err)
+		}
+	}
+
+	return nil
+}
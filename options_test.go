@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet_NewSetting(t *testing.T) {
+	set := &Set{}
+
+	errNegative := errors.New("must not be negative")
+	setting := set.Subset("HTTP").NewSetting("Port", 0,
+		WithDescription("port to listen on"),
+		WithCategory("Networking"),
+		WithDefault("8080"),
+		WithValidator(func(v string) error {
+			if len(v) > 0 && v[0] == '-' {
+				return errNegative
+			}
+			return nil
+		}),
+	)
+
+	if setting.Path != "HTTP.Port" || setting.Description != "port to listen on" || setting.Category != "Networking" {
+		t.Errorf("Failed to configure setting: got %q, %q and %q", setting.Path, setting.Description, setting.Category)
+	}
+
+	if setting.DefaultValue != "8080" || setting.Value != 8080 {
+		t.Errorf("Failed to apply default: expected %q; got %q", "8080", setting.DefaultValue)
+	}
+
+	if err := setting.Set("-1"); !errors.Is(err, errNegative) {
+		t.Errorf("Failed to validate: expected %v; got %v", errNegative, err)
+	}
+
+	dir := writeFiles(t, map[string]string{"config.json": `{"Port": -2}`})
+
+	var validationErr *ValidationError
+	err := set.Subset("HTTP").ValidateFile(filepath.Join(dir, "config.json"))
+	if !errors.As(err, &validationErr) || !errors.Is(validationErr.Errors[0], errNegative) {
+		t.Errorf("Failed to validate file: expected %v; got %v", errNegative, err)
+	}
+
+	if err := setting.Set("9090"); err != nil || setting.Value != 9090 {
+		t.Errorf("Failed to set valid value: %v", err)
+	}
+}
+
+func TestSet_NewSettingImmutable(t *testing.T) {
+	set := &Set{}
+
+	setting := set.NewSetting("Version", "1.2.3", WithImmutable(), WithMask())
+	if !setting.Immutable() || !setting.Mask {
+		t.Fatal("Failed to configure immutable masked setting")
+	}
+
+	if err := setting.Set("1.2.3"); err != nil {
+		t.Errorf("Failed to set unchanged value: %v", err)
+	}
+
+	if err := setting.Set("2.0.0"); !errors.Is(err, ErrImmutable) {
+		t.Errorf("Failed to reject change: expected %v; got %v", ErrImmutable, err)
+	}
+
+	if setting.Value != "1.2.3" {
+		t.Errorf("Failed to keep value: expected %q; got %q", "1.2.3", setting.Value)
+	}
+}
+
+func TestSet_NewSettingFailFast(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []SettingOption
+	}{
+		{"bad default", []SettingOption{WithDefault("abc")}},
+		{"rejected default", []SettingOption{WithValidator(func(string) error { return errors.New("no") })}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Failed to panic")
+				}
+			}()
+
+			(&Set{}).NewSetting("Workers", 1, tt.opts...)
+		})
+	}
+}
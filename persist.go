@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+)
+
+// persistDelay is how long a Persister waits for more changes before saving
+const persistDelay = 100 * time.Millisecond
+
+// Persister saves the runtime changes of a Set to an overrides file, see Set.Persist
+type Persister struct {
+	path   string
+	mu     sync.Mutex
+	values map[string]string
+	clock  Clock
+	timer  Timer
+	err    error
+	closed bool
+}
+
+// Persist applies the runtime changes saved in the overrides file at path, when it exists, and saves every later runtime change made through Set.UpdateContext (i.e. by an admin surface or Gossip) to it, so runtime tweaks survive a restart. Changes are written behind, shortly after they are made, with Persister.Close saving any pending changes. The file is written with 0600 permissions as it may contain secrets.
+//
+// Persist should be called after the other sources are loaded so the overrides take precedence. The overrides file is rewritten by the process and can not be signed, so Persist fails with ErrInvalidSignature while signatures are required (see Set.RequireSignatures).
+func (s *Set) Persist(path string) (*Persister, error) {
+	if s.signaturesRequired() {
+		return nil, fmt.Errorf("unable to persist to %q: %w: the overrides file can not be signed while signatures are required", path, ErrInvalidSignature)
+	}
+
+	p := &Persister{path: path, values: map[string]string{}, clock: s.clock()}
+
+	values, err := readFile(path, nil)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	root := s.Root()
+	for k, v := range values {
+		p.values[k] = v
+	}
+
+	if err := root.updateAll(values, path); err != nil {
+		return nil, fmt.Errorf("unable to apply overrides %q: %w", path, err)
+	}
+
+	root.mu.Lock()
+	root.persister = p
+	root.mu.Unlock()
+
+	return p, nil
+}
+
+// persist the runtime change of the setting when the Set is persisted
+func (s *Set) persist(setting *Setting) {
+	root := s.Root()
+
+	root.mu.Lock()
+	p := root.persister
+	root.mu.Unlock()
+
+	if p != nil {
+		p.change(setting.Path, setting.format())
+	}
+}
+
+// change records the value of the path and schedules a save
+func (p *Persister) change(path, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	p.values[path] = value
+
+	p.schedule()
+}
+
+// schedule a save unless one is pending, the caller must hold mu
+func (p *Persister) schedule() {
+	if p.timer == nil {
+		p.timer = p.clock.AfterFunc(persistDelay, func() {
+			if err := p.Flush(); err != nil {
+				logger().Error("unable to persist runtime changes", "path", p.path, "error", err)
+			}
+		})
+	}
+}
+
+// remove the path from the overrides and schedule a save
+func (p *Persister) remove(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	for k := range p.values {
+		if strings.EqualFold(k, path) {
+			delete(p.values, k)
+		}
+	}
+
+	p.schedule()
+}
+
+// Flush saves the pending changes now, returning the error of the last save
+func (p *Persister) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.timer == nil {
+		return p.err
+	}
+
+	p.timer.Stop()
+	p.timer = nil
+
+	p.err = SaveFile(p.path, p.values)
+
+	return p.err
+}
+
+// Close saves the pending changes and stops persisting changes
+func (p *Persister) Close() error {
+	err := p.Flush()
+
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	return err
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet_Persist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+
+	cfg := struct {
+		Port  int
+		Level string
+	}{Port: 80, Level: "info"}
+
+	set := &Set{}
+	set.Subset("HTTP").Bind(&cfg)
+
+	p, err := set.Persist(path)
+	if err != nil {
+		t.Fatalf("Failed to persist without overrides: %v", err)
+	}
+
+	_, _ = set.UpdateContext(context.Background(), "HTTP.Level", "debug")
+	_, _ = set.Update("HTTP.Port", "8080")
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Failed to save overrides: %v", err)
+	}
+
+	restarted := struct {
+		Port  int
+		Level string
+	}{Port: 80, Level: "info"}
+
+	set = &Set{}
+	set.Subset("HTTP").Bind(&restarted)
+
+	if _, err := set.Persist(path); err != nil {
+		t.Fatalf("Failed to apply overrides: %v", err)
+	}
+
+	// only runtime changes are persisted
+	if restarted.Level != "debug" || restarted.Port != 80 {
+		t.Errorf("Failed to apply overrides: got %q and %d", restarted.Level, restarted.Port)
+	}
+}
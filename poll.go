@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Poller calls a function on an interval with jitter, backing off exponentially while the function fails. It is intended to be shared by remote providers rather than each one implementing its own timer loop.
+type Poller struct {
+	// Interval between successful calls, must be positive
+	Interval time.Duration
+
+	// Jitter is the maximum random duration added to every wait to spread load across instances
+	Jitter time.Duration
+
+	// MaxBackoff caps the exponential backoff applied after failures, when zero it defaults to 10 times the Interval
+	MaxBackoff time.Duration
+
+	// MaxStaleness is the duration without a successful call after which OnStale is called
+	MaxStaleness time.Duration
+
+	// OnStale is called once every time the Poller becomes stale with the time of the last success and the last error
+	OnStale func(lastSuccess time.Time, err error)
+
+	// Clock the Poller waits on, defaults to SystemClock or the Clock of the Set for Set.Poll
+	Clock Clock
+}
+
+// Run will call fn until the ctx is done, at which point the ctx error is returned. An error is returned right away when the Interval is not positive.
+func (p *Poller) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.Interval <= 0 {
+		return fmt.Errorf("unable to poll: interval %s is not positive", p.Interval)
+	}
+
+	clock := clockOrSystem(p.Clock)
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	lastSuccess := clock.Now()
+	failures := 0
+	stale := false
+
+	timer := clock.NewTimer(p.delay(failures, random))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C():
+		}
+
+		if err := fn(ctx); err != nil {
+			failures++
+
+			if p.MaxStaleness > 0 && !stale && clock.Now().Sub(lastSuccess) > p.MaxStaleness {
+				stale = true
+				if p.OnStale != nil {
+					p.OnStale(lastSuccess, err)
+				}
+			}
+		} else {
+			failures = 0
+			stale = false
+			lastSuccess = clock.Now()
+		}
+
+		timer.Reset(p.delay(failures, random))
+	}
+}
+
+// delay returns the duration to wait before the next call based on the number of consecutive failures
+func (p *Poller) delay(failures int, random *rand.Rand) time.Duration {
+	delay := p.Interval
+
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = p.Interval * 10
+	}
+
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	if p.Jitter > 0 {
+		delay += time.Duration(random.Int63n(int64(p.Jitter)))
+	}
+
+	return delay
+}
+
+// Poll will reload the providers attached to the Set on the schedule of the supplied Poller until the ctx is done, waiting on the Clock of the Set (see Set.UseClock) unless the Poller has its own
+func (s *Set) Poll(ctx context.Context, p *Poller) error {
+	if p.Clock == nil {
+		withClock := *p
+		withClock.Clock = s.clock()
+		p = &withClock
+	}
+
+	return p.Run(ctx, s.Reload)
+}
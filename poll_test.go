@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPoller_Delay(t *testing.T) {
+	p := &Poller{Interval: time.Second, MaxBackoff: 5 * time.Second}
+	random := rand.New(rand.NewSource(1))
+
+	tests := map[int]time.Duration{
+		0: time.Second,
+		1: 2 * time.Second,
+		2: 4 * time.Second,
+		3: 5 * time.Second,
+		9: 5 * time.Second,
+	}
+
+	for failures, expected := range tests {
+		if delay := p.delay(failures, random); delay != expected {
+			t.Errorf("Failed to compute delay after %d failures: expected %v; got %v", failures, expected, delay)
+		}
+	}
+
+	p.Jitter = time.Second
+	if delay := p.delay(0, random); delay < time.Second || delay >= 2*time.Second {
+		t.Errorf("Failed to apply jitter: got %v", delay)
+	}
+}
+
+func TestPoller_Stale(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stale := 0
+	p := &Poller{
+		Interval:     time.Millisecond,
+		MaxBackoff:   time.Millisecond,
+		MaxStaleness: 5 * time.Millisecond,
+		OnStale: func(time.Time, error) {
+			stale++
+			cancel()
+		},
+	}
+
+	err := p.Run(ctx, func(context.Context) error { return errors.New("unavailable") })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Failed to stop on cancelled context: got %v", err)
+	}
+
+	if stale != 1 {
+		t.Errorf("Failed to report staleness: expected 1 call; got %d", stale)
+	}
+}
+
+func TestPoller_Interval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		p := &Poller{Interval: interval}
+		err := p.Run(context.Background(), func(ctx context.Context) error {
+			t.Errorf("Failed to reject interval %v: called fn", interval)
+			return nil
+		})
+		if err == nil {
+			t.Errorf("Failed to reject interval %v", interval)
+		}
+	}
+
+	w := &DriftWatcher{Set: &Set{}, Path: "config.json"}
+	if err := w.Run(context.Background()); err == nil {
+		t.Errorf("Failed to reject zero drift watcher interval")
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// wellKnownPackage is the import path prefix of the protobuf well-known types (i.e. google.golang.org/protobuf/types/known/durationpb)
+const wellKnownPackage = "google.golang.org/protobuf/types/known/"
+
+// oneofMember returns the pointer to the wrapper struct of the member set in the oneof field of a generated protobuf message, invalid when the field is not a oneof or no member is set
+func oneofMember(field reflect.StructField, value reflect.Value) reflect.Value {
+	if field.Tag.Get("protobuf_oneof") == "" || value.IsNil() {
+		return reflect.Value{}
+	}
+
+	member := value.Elem()
+	if member.Kind() != reflect.Ptr || member.IsNil() || member.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+
+	return member
+}
+
+// wellKnownValue returns the setting value of a pointer field to a protobuf well-known type: Duration as a duration, Timestamp as RFC 3339 and the wrappers as their value. Known is false for any other type, and the value is nil for the well-known types that can not be represented as a string (i.e. Struct or Any).
+func wellKnownValue(field reflect.Value) (value Value, known bool) {
+	t := field.Type().Elem()
+	if !strings.HasPrefix(t.PkgPath(), wellKnownPackage) {
+		return nil, false
+	}
+
+	switch strings.TrimPrefix(t.PkgPath(), wellKnownPackage) + "." + t.Name() {
+	case "durationpb.Duration":
+		return &pointerValue{field: field, format: formatProtoDuration, parse: parseProtoDuration}, true
+	case "timestamppb.Timestamp":
+		return &pointerValue{field: field, format: formatProtoTimestamp, parse: parseProtoTimestamp}, true
+	case "wrapperspb.DoubleValue", "wrapperspb.FloatValue", "wrapperspb.Int64Value", "wrapperspb.UInt64Value",
+		"wrapperspb.Int32Value", "wrapperspb.UInt32Value", "wrapperspb.BoolValue", "wrapperspb.StringValue":
+		return &pointerValue{
+			field:  field,
+			format: func(v reflect.Value) string { return formatElem(v.FieldByName("Value")) },
+			parse:  func(v reflect.Value, s string) error { return parseElem(v.FieldByName("Value"), s) },
+		}, true
+	}
+
+	return nil, true
+}
+
+// pointerValue is the setting value of a pointer field, such as a proto3 optional field or a protobuf well-known type. The pointer is nil while unset: it formats as an empty string, and an empty string unsets it.
+type pointerValue struct {
+	// field is the settable pointer
+	field reflect.Value
+
+	format func(elem reflect.Value) string
+	parse  func(elem reflect.Value, v string) error
+}
+
+// newPointerValue returns the setting value of a pointer field to a value, formatted and parsed like a setting of the value
+func newPointerValue(field reflect.Value) *pointerValue {
+	return &pointerValue{field: field, format: formatElem, parse: parseElem}
+}
+
+// UnmarshalSetting implements Unmarshaler, the value is parsed into a new element so the field is untouched on error
+func (p *pointerValue) UnmarshalSetting(v string) error {
+	if v == "" {
+		p.field.Set(reflect.Zero(p.field.Type()))
+		return nil
+	}
+
+	elem := reflect.New(p.field.Type().Elem())
+	if err := p.parse(elem.Elem(), v); err != nil {
+		return err
+	}
+
+	p.field.Set(elem)
+	return nil
+}
+
+// MarshalSetting implements Marshaler
+func (p *pointerValue) MarshalSetting() string {
+	if p.field.IsNil() {
+		return ""
+	}
+
+	return p.format(p.field.Elem())
+}
+
+// Equals implements Equality
+func (p *pointerValue) Equals(v string) bool {
+	if v == "" || p.field.IsNil() {
+		return v == "" && p.field.IsNil()
+	}
+
+	elem := reflect.New(p.field.Type().Elem())
+	if err := p.parse(elem.Elem(), v); err != nil {
+		return false
+	}
+
+	return p.format(elem.Elem()) == p.format(p.field.Elem())
+}
+
+// formatElem formats the addressable value like a setting of its type
+func formatElem(v reflect.Value) string {
+	return (&Setting{Value: v.Addr().Interface()}).format()
+}
+
+// parseElem parses the string into the addressable value like a setting of its type
+func parseElem(v reflect.Value, s string) error {
+	return (&Setting{Value: v.Addr().Interface()}).convert(s)
+}
+
+// formatProtoDuration formats the Seconds and Nanos of a durationpb.Duration like a time.Duration
+func formatProtoDuration(v reflect.Value) string {
+	return (time.Duration(v.FieldByName("Seconds").Int())*time.Second + time.Duration(v.FieldByName("Nanos").Int())).String()
+}
+
+// parseProtoDuration parses the duration (see ParseDuration) into the Seconds and Nanos of a durationpb.Duration
+func parseProtoDuration(v reflect.Value, s string) error {
+	d, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	v.FieldByName("Seconds").SetInt(int64(d / time.Second))
+	v.FieldByName("Nanos").SetInt(int64(d % time.Second))
+	return nil
+}
+
+// formatProtoTimestamp formats the Seconds and Nanos of a timestamppb.Timestamp as RFC 3339 in UTC
+func formatProtoTimestamp(v reflect.Value) string {
+	return time.Unix(v.FieldByName("Seconds").Int(), v.FieldByName("Nanos").Int()).UTC().Format(time.RFC3339Nano)
+}
+
+// parseProtoTimestamp parses the RFC 3339 time into the Seconds and Nanos of a timestamppb.Timestamp
+func parseProtoTimestamp(v reflect.Value, s string) error {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return err
+	}
+
+	v.FieldByName("Seconds").SetInt(t.Unix())
+	v.FieldByName("Nanos").SetInt(int64(t.Nanosecond()))
+	return nil
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// generated by protoc-gen-go from a message with an optional field, a oneof, a nested and a recursive message
+type protoConfig struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	Name    string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Retries *int32         `protobuf:"varint,2,opt,name=retries,proto3,oneof" json:"retries,omitempty"`
+	Backend isProtoBackend `protobuf_oneof:"backend"`
+	Tls     *protoTLS      `protobuf:"bytes,5,opt,name=tls,proto3" json:"tls,omitempty"`
+	Next    *protoConfig   `protobuf:"bytes,6,opt,name=next,proto3" json:"next,omitempty"`
+
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+type isProtoBackend interface{ isProtoBackend() }
+
+type protoConfig_File struct {
+	File string `protobuf:"bytes,3,opt,name=file,proto3,oneof"`
+}
+
+func (*protoConfig_File) isProtoBackend() {}
+
+type protoTLS struct {
+	state struct{}
+
+	Cert string `protobuf:"bytes,1,opt,name=cert,proto3" json:"cert,omitempty"`
+}
+
+func TestSet_BindProtobuf(t *testing.T) {
+	cfg := &protoConfig{Backend: &protoConfig_File{File: "config.json"}}
+
+	set := &Set{}
+	set.Bind(cfg)
+
+	var paths []string
+	set.Range(func(_ string, setting *Setting) bool {
+		paths = append(paths, setting.Path)
+		return true
+	})
+	sort.Strings(paths)
+
+	// the nil Tls is allocated, the nil recursive Next is left alone
+	if expected := []string{"File", "Name", "Retries", "Tls.Cert"}; !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Failed to bind message: expected %v; got %v", expected, paths)
+	}
+
+	retries := set.Get("Retries")
+	if retries.String() != "" || !retries.IsDefault() {
+		t.Errorf("Failed to bind unset optional field: got %q", retries.String())
+	}
+	if err := retries.Set("3"); err != nil || cfg.Retries == nil || *cfg.Retries != 3 {
+		t.Errorf("Failed to set optional field: got %v with %v", cfg.Retries, err)
+	}
+	if err := retries.Set("many"); err == nil || *cfg.Retries != 3 {
+		t.Errorf("Failed to reject invalid optional field: got %v", err)
+	}
+	if err := retries.Set(""); err != nil || cfg.Retries != nil {
+		t.Errorf("Failed to unset optional field: got %v with %v", cfg.Retries, err)
+	}
+
+	if err := set.Get("File").Set("other.json"); err != nil || cfg.Backend.(*protoConfig_File).File != "other.json" {
+		t.Errorf("Failed to set oneof member: got %v", err)
+	}
+
+	if err := set.Get("Tls.Cert").Set("cert.pem"); err != nil || cfg.Tls.Cert != "cert.pem" {
+		t.Errorf("Failed to set nested message: got %v", err)
+	}
+
+	if cfg.Next != nil {
+		t.Errorf("Failed to leave recursive message unset: got %+v", cfg.Next)
+	}
+
+	chained := &Set{}
+	chained.Bind(&protoConfig{Next: &protoConfig{Name: "next"}})
+	if setting := chained.Get("Next.Name"); setting == nil || setting.String() != "next" || chained.Get("Next.Next.Name") != nil {
+		t.Error("Failed to bind recursive message that is set")
+	}
+}
+
+func TestPointerValue_WellKnown(t *testing.T) {
+	// shaped like durationpb.Duration and timestamppb.Timestamp
+	var duration *struct {
+		Seconds int64
+		Nanos   int32
+	}
+	var timestamp *struct {
+		Seconds int64
+		Nanos   int32
+	}
+
+	d := &pointerValue{field: reflect.ValueOf(&duration).Elem(), format: formatProtoDuration, parse: parseProtoDuration}
+	if err := d.UnmarshalSetting("1m30.5s"); err != nil || duration.Seconds != 90 || duration.Nanos != 5e8 {
+		t.Errorf("Failed to parse duration: got %+v with %v", duration, err)
+	}
+	if v := d.MarshalSetting(); v != "1m30.5s" || !d.Equals("90.5s") {
+		t.Errorf("Failed to format duration: expected %q; got %q", "1m30.5s", v)
+	}
+
+	ts := &pointerValue{field: reflect.ValueOf(&timestamp).Elem(), format: formatProtoTimestamp, parse: parseProtoTimestamp}
+	if ts.MarshalSetting() != "" || !ts.Equals("") {
+		t.Errorf("Failed to format unset timestamp: got %q", ts.MarshalSetting())
+	}
+	if err := ts.UnmarshalSetting("2024-02-29T12:00:00.25+01:00"); err != nil || timestamp.Seconds != 1709204400 || timestamp.Nanos != 25e7 {
+		t.Errorf("Failed to parse timestamp: got %+v with %v", timestamp, err)
+	}
+	if v := ts.MarshalSetting(); v != "2024-02-29T11:00:00.25Z" {
+		t.Errorf("Failed to format timestamp: expected %q; got %q", "2024-02-29T11:00:00.25Z", v)
+	}
+
+	if _, known := wellKnownValue(reflect.ValueOf(&duration).Elem()); known {
+		t.Error("Failed to ignore type outside of the well-known types")
+	}
+}
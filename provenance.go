@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// Provenance is a value a source supplied for a setting, recorded when the Set tracks provenance (see Set.TrackProvenance)
+type Provenance struct {
+	// Source that supplied the value: the provider name, the file path, SourceEnv, SourceFlag, SourceDerived, SourceRuntime or SourceDefault
+	Source string `json:"source"`
+
+	// Value supplied by the source, ***** for masked settings
+	Value string `json:"value"`
+
+	// Time the value was last supplied, zero for the default
+	Time time.Time `json:"time"`
+
+	// Error the value was rejected with, empty when it was accepted
+	Error string `json:"error,omitempty"`
+}
+
+// Explanation is the chain of sources that supplied a value for a setting, answering why the setting has its value, see Set.Explain
+type Explanation struct {
+	// Path of the setting
+	Path string `json:"path"`
+
+	// Value of the setting, ***** for masked settings
+	Value string `json:"value"`
+
+	// Source of the value, see Setting.Source
+	Source string `json:"source"`
+
+	// Chain of the values supplied by every source, starting with the default, in the order the sources last supplied them
+	Chain []Provenance `json:"chain"`
+}
+
+// String formats the explanation with a line per source, stating whether its value is the current one, was overridden or was rejected
+func (e *Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s = %q (from %s)\n", e.Path, e.Value, e.Source)
+
+	tw := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	for _, p := range e.Chain {
+		outcome := "overridden"
+		switch {
+		case p.Error != "":
+			outcome = "rejected: " + p.Error
+		case p.Source == e.Source:
+			outcome = "current"
+		case p.Value == e.Value:
+			outcome = "same value"
+		}
+
+		fmt.Fprintf(tw, "  %s\t%q\t%s\n", p.Source, p.Value, outcome)
+	}
+	_ = tw.Flush()
+
+	return b.String()
+}
+
+// provenance is the chain of values supplied for a setting, one per source
+type provenance struct {
+	mu      sync.Mutex
+	entries []Provenance
+}
+
+// TrackProvenance records every value supplied for the settings of the Set tree from now on, not only the ones that won, along with the source that supplied it and whether it was rejected. Set.Explain then answers precedence questions, such as why a value from a file did not take effect.
+func (s *Set) TrackProvenance() {
+	atomic.StoreInt32(&s.Root().trackProvenance, 1)
+}
+
+// Explain returns the chain of sources that supplied a value for the setting at the path, see Set.TrackProvenance. Only the default is known for settings written before tracking started.
+func (s *Set) Explain(path string) (*Explanation, error) {
+	setting := s.lookup(path)
+	if setting == nil {
+		return nil, &SettingError{Path: path, Err: ErrUnknownSetting}
+	}
+
+	e := &Explanation{
+		Path:   setting.Path,
+		Value:  setting.display(setting.format()),
+		Source: setting.Source(),
+		Chain:  []Provenance{{Source: SourceDefault, Value: setting.display(setting.DefaultValue)}},
+	}
+
+	setting.provenance.mu.Lock()
+	e.Chain = append(e.Chain, setting.provenance.entries...)
+	setting.provenance.mu.Unlock()
+
+	return e, nil
+}
+
+// supplied records that the source supplied the value, rejected with err, when the Set tree tracks provenance. A source supplying another value replaces its previous one and moves to the end of the chain.
+func (s *Setting) supplied(v, source string, err error) {
+	if s.set == nil || atomic.LoadInt32(&s.set.Root().trackProvenance) == 0 {
+		return
+	}
+
+	if source == "" {
+		source = SourceRuntime
+	}
+
+	p := Provenance{Source: source, Value: s.display(v), Time: time.Now()}
+	if err != nil {
+		p.Error = err.Error()
+	}
+
+	s.provenance.mu.Lock()
+	defer s.provenance.mu.Unlock()
+
+	for i, existing := range s.provenance.entries {
+		if existing.Source == source {
+			s.provenance.entries = append(s.provenance.entries[:i], s.provenance.entries[i+1:]...)
+			break
+		}
+	}
+
+	s.provenance.entries = append(s.provenance.entries, p)
+}
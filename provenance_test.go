@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSet_Explain(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.json": `{"HTTP": {"Port": 9090}, "Token": "secret"}`})
+	t.Setenv("APP_HTTP_PORT", "9090")
+
+	set := &Set{}
+	port := set.Subset("HTTP").NewSetting("Port", 8080, WithValidator(func(v string) error {
+		if v == "1" {
+			return errors.New("privileged port")
+		}
+		return nil
+	}))
+	token := set.Setting("Token", "", "")
+	token.Mask = true
+
+	_ = port.Set("1")
+	if e, err := set.Explain("HTTP.Port"); err != nil || len(e.Chain) != 1 {
+		t.Errorf("Failed to track only when enabled: got %v with %v", e, err)
+	}
+
+	set.TrackProvenance()
+
+	path := filepath.Join(dir, "config.json")
+	if err := set.LoadFile(path); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if err := set.LoadEnv("APP"); err != nil {
+		t.Fatalf("Failed to load env: %v", err)
+	}
+	if err := port.Set("1"); err == nil {
+		t.Fatal("Failed to reject value")
+	}
+
+	e, err := set.Explain("HTTP.Port")
+	if err != nil {
+		t.Fatalf("Failed to explain: %v", err)
+	}
+
+	if e.Path != "HTTP.Port" || e.Value != "9090" || e.Source != path {
+		t.Errorf("Failed to explain current value: got %q from %q", e.Value, e.Source)
+	}
+
+	expected := []Provenance{
+		{Source: SourceDefault, Value: "8080"},
+		{Source: path, Value: "9090"},
+		{Source: SourceEnv, Value: "9090"},
+		{Source: SourceRuntime, Value: "1", Error: "privileged port"},
+	}
+	if len(e.Chain) != len(expected) {
+		t.Fatalf("Failed to explain chain: expected %d sources; got %+v", len(expected), e.Chain)
+	}
+	for i, p := range e.Chain {
+		if p.Source != expected[i].Source || p.Value != expected[i].Value || !strings.Contains(p.Error, expected[i].Error) {
+			t.Errorf("Failed to explain source %d: expected %+v; got %+v", i, expected[i], p)
+		}
+	}
+
+	text := e.String()
+	for _, line := range []string{`HTTP.Port = "9090" (from ` + path + ")", "current", "same value", "rejected: "} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Failed to format explanation: expected %q in %q", line, text)
+		}
+	}
+
+	if e, _ := set.Explain("Token"); e.Value != "*****" || e.Chain[1].Value != "*****" {
+		t.Errorf("Failed to mask explanation: got %+v", e)
+	}
+
+	var settingErr *SettingError
+	if _, err := set.Explain("Missing"); !errors.As(err, &settingErr) || !errors.Is(settingErr.Err, ErrUnknownSetting) {
+		t.Errorf("Failed to reject unknown setting: got %v", err)
+	}
+}
+
+func TestSet_ExplainRedacted(t *testing.T) {
+	set := redactedSet(t)
+	set.TrackProvenance()
+	_, _ = set.Update("DB", "postgres://app:hunter4@db:5432/app")
+
+	e, err := set.Explain("DB")
+	if err != nil {
+		t.Fatalf("Failed to explain: %v", err)
+	}
+
+	assertRedacted(t, "explanation", e.String())
+}
@@ -0,0 +1,381 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Provider supplies setting values from a source such as a file or a remote service. Values are keyed by their dot separated path relative to the Set the Provider is attached to.
+type Provider interface {
+	Load(ctx context.Context) (map[string]string, error)
+}
+
+// ProviderFunc defines a function that supplies setting values
+type ProviderFunc func(ctx context.Context) (map[string]string, error)
+
+// Load implements Provider.Load
+func (f ProviderFunc) Load(ctx context.Context) (map[string]string, error) {
+	return f(ctx)
+}
+
+// File returns a Provider reading the document at path, see Set.LoadFile for the document format
+func File(path string) Provider {
+	return ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// verified when signatures are required by the Set loading the provider, see Set.RequireSignatures
+		return readerFrom(ctx).read(path, nil)
+	})
+}
+
+// unwrapProvider returns the Provider wrapped by p (i.e. Cache or Breaker), or nil when p does not wrap another Provider
+func unwrapProvider(p Provider) Provider {
+	if u, ok := p.(interface{ Unwrap() Provider }); ok {
+		return u.Unwrap()
+	}
+
+	return nil
+}
+
+// ProviderError is returned when a provider fails to load or apply its values
+type ProviderError struct {
+	Name string
+	Err  error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("provider %q: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// ReloadError contains the errors of every provider that failed during Set.Reload
+type ReloadError struct {
+	Errors []*ProviderError
+}
+
+func (e *ReloadError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// registeredProvider is a Provider attached to a Set
+type registeredProvider struct {
+	name     string
+	set      *Set
+	provider Provider
+
+	// guarded by the root Set mu
+	lastAttempt time.Time
+	lastSync    time.Time
+	lastError   error
+	stale       bool
+	writer      Writer
+	values      map[string]string
+}
+
+// ProviderStatus reports the health of a Provider attached to a Set
+type ProviderStatus struct {
+	// Name the Provider was added with
+	Name string
+
+	// Path of the Set the Provider is attached to
+	Path string
+
+	// LastAttempt is the time of the last load, successful or not
+	LastAttempt time.Time
+
+	// LastSync is the time of the last successful load, zero if the Provider has never loaded successfully
+	LastSync time.Time
+
+	// LastError of the last load, nil when it was successful
+	LastError error
+
+	// Staleness is the duration since the last successful load
+	Staleness time.Duration
+
+	// Stale is set when the last load supplied cached values because the source was unreachable, see Cache
+	Stale bool
+
+	// Breaker is the state of the Breaker wrapping the Provider, empty when there is none
+	Breaker string
+}
+
+// AddProvider attaches the Provider to the current Set. Providers are applied by Set.Reload in the order they were added, so later providers take precedence. Name can not be empty or already in use, provider can not be nil
+func (s *Set) AddProvider(name string, p Provider) {
+	if name == "" {
+		panic("name can not be empty")
+	}
+	if p == nil {
+		panic("provider can not be nil")
+	}
+
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	for _, rp := range root.providers {
+		if strings.EqualFold(rp.name, name) {
+			panic(fmt.Sprintf("provider %q already exists", name))
+		}
+	}
+
+	root.providers = append(root.providers, &registeredProvider{
+		name:     name,
+		set:      s,
+		provider: p,
+	})
+}
+
+// Reload will load every Provider attached to the Set tree and apply their values in precedence order. Every setting is applied once with the value of the provider taking precedence, so settings supplied by several providers are only changed, and notified, when the winning value changes. A failing provider does not stop the remaining providers from being applied, all failures are returned in a *ReloadError
+//
+// The ctx bounds the whole reload: providers are abandoned once it is done, even if they do not respect it themselves, and the remaining providers fail with the ctx error.
+//
+// When called on a subset, only providers attached within the subset or to one of its parents are loaded, and only values within the subset are applied. This allows reloading a subset when its backing source changes without touching unrelated parts of the tree.
+func (s *Set) Reload(ctx context.Context) (err error) {
+	defer func(start time.Time) { s.observe(OpReload, s.path, "", start, err) }(time.Now())
+
+	root := s.Root()
+
+	root.mu.Lock()
+	providers := make([]*registeredProvider, len(root.providers))
+	copy(providers, root.providers)
+	root.mu.Unlock()
+
+	// a provider loaded by the reload, failed or not
+	type loaded struct {
+		rp     *registeredProvider
+		values map[string]string
+		err    error
+		stale  bool
+
+		// abandoned is set for providers not loaded as the ctx was done, their status is left as is
+		abandoned bool
+	}
+
+	var (
+		errs    []*ProviderError
+		results []loaded
+	)
+	for _, rp := range providers {
+		// providers of unrelated subsets are left alone
+		if !s.contains(rp.set.path) && !rp.set.contains(s.path) {
+			continue
+		}
+
+		// the remaining providers are not loaded once the caller gives up
+		if ctx.Err() != nil {
+			results = append(results, loaded{rp: rp, err: ctx.Err(), abandoned: true})
+			continue
+		}
+
+		start := time.Now()
+		done := s.timeLoad(OpFetch, rp.set.path, rp.name)
+		values, err := load(withReader(ctx, rp.set.reader()), rp.provider)
+		done(err)
+		s.observe(OpFetch, rp.set.path, rp.name, start, err)
+
+		// stale values are still applied, the provider is only flagged as stale
+		var staleErr *StaleError
+		results = append(results, loaded{rp: rp, values: values, err: err, stale: errors.As(err, &staleErr)})
+	}
+
+	// every path is applied once with the value of the provider taking precedence, so a value overridden by a later provider does not pass through the setting on every reload
+	type winner struct {
+		provider int
+		key      string
+	}
+	won := make([]map[string]string, len(results))
+	winners := map[string]winner{}
+	for i, r := range results {
+		won[i] = map[string]string{}
+		if r.err != nil && !r.stale {
+			continue
+		}
+
+		for key, v := range r.rp.set.resolveEnvNames(s.scope(r.rp.set, r.values)) {
+			path := r.rp.set.pathOf(key)
+			if setting := r.rp.set.lookup(key); setting != nil {
+				path = setting.Path
+			}
+			path = strings.ToLower(path)
+
+			if previous, found := winners[path]; found {
+				delete(won[previous.provider], previous.key)
+			}
+			winners[path] = winner{provider: i, key: key}
+			won[i][key] = v
+		}
+	}
+
+	for i, r := range results {
+		rp, err, stale := r.rp, r.err, r.stale
+		if r.abandoned {
+			errs = append(errs, &ProviderError{Name: rp.name, Err: err})
+			continue
+		}
+
+		if err == nil || stale {
+			if updateErr := rp.set.updateRemote(won[i], rp.name); updateErr != nil {
+				err = updateErr
+				stale = false
+			}
+		}
+
+		root.mu.Lock()
+		rp.lastAttempt = s.clock().Now()
+		rp.lastError = err
+		rp.stale = stale
+		if err == nil {
+			rp.lastSync = rp.lastAttempt
+		}
+		if err == nil || stale {
+			rp.values = r.values
+		}
+		root.mu.Unlock()
+
+		if stale {
+			logger().Warn("provider is stale", "provider", rp.name, "path", rp.set.path, "error", err)
+			continue
+		}
+
+		if err != nil {
+			logger().Warn("provider failed", "provider", rp.name, "path", rp.set.path, "error", err)
+			errs = append(errs, &ProviderError{Name: rp.name, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ReloadError{Errors: errs}
+	}
+
+	return nil
+}
+
+// load the Provider, returning when the ctx is done even if the Provider does not respect it
+func load(ctx context.Context, p Provider) (map[string]string, error) {
+	type result struct {
+		values map[string]string
+		err    error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		values, err := p.Load(ctx)
+		done <- result{values: values, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.values, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Health returns the status of every Provider attached to the Set tree, in precedence order. When called on a subset only the providers that Set.Reload would load are returned.
+func (s *Set) Health() []ProviderStatus {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	now := root.clock().Now()
+
+	var statuses []ProviderStatus
+	for _, rp := range root.providers {
+		if !s.contains(rp.set.path) && !rp.set.contains(s.path) {
+			continue
+		}
+
+		status := ProviderStatus{
+			Name:        rp.name,
+			Path:        rp.set.path,
+			LastAttempt: rp.lastAttempt,
+			LastSync:    rp.lastSync,
+			LastError:   rp.lastError,
+			Stale:       rp.stale,
+		}
+
+		if !rp.lastSync.IsZero() {
+			status.Staleness = now.Sub(rp.lastSync)
+		}
+
+		for p := rp.provider; p != nil; p = unwrapProvider(p) {
+			if breaker, ok := p.(*Breaker); ok {
+				status.Breaker = breaker.State().String()
+				break
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// Healthy returns an error when any Provider returned by Set.Health has never loaded successfully or has not loaded successfully within maxStaleness. A maxStaleness of zero disables the staleness check. This is suitable for wiring into readiness probes.
+func (s *Set) Healthy(maxStaleness time.Duration) error {
+	for _, status := range s.Health() {
+		if status.LastSync.IsZero() {
+			if status.LastError != nil {
+				return fmt.Errorf("provider %q has never loaded: %w", status.Name, status.LastError)
+			}
+			return fmt.Errorf("provider %q has never loaded", status.Name)
+		}
+
+		if maxStaleness > 0 && status.Staleness > maxStaleness {
+			return fmt.Errorf("provider %q is stale: last loaded %v ago", status.Name, status.Staleness.Round(time.Second))
+		}
+	}
+
+	return nil
+}
+
+// contains returns if the path is the current Set or within it
+func (s *Set) contains(path string) bool {
+	return within(path, s.path)
+}
+
+// within returns if the path is the prefix path or beneath it, case insensitive, an empty prefix contains every path
+func within(path, prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+
+	return strings.EqualFold(path, prefix) || (len(path) > len(prefix) && path[len(prefix)] == '.' && strings.EqualFold(path[:len(prefix)], prefix))
+}
+
+// scope filters the values, relative to the supplied Set, down to the ones within the current Set
+func (s *Set) scope(set *Set, values map[string]string) map[string]string {
+	if s.contains(set.path) {
+		return values
+	}
+
+	scoped := map[string]string{}
+	for k, v := range values {
+		path := k
+		if set.path != "" {
+			path = set.path + "." + k
+		}
+
+		if s.contains(path) {
+			scoped[k] = v
+		}
+	}
+
+	return scoped
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSet_Reload(t *testing.T) {
+	var (
+		host = "localhost"
+		port = 80
+	)
+
+	set := &Set{}
+	set.Subset("HTTP").Setting("Host", &host, "")
+	set.Subset("HTTP").Setting("Port", &port, "")
+
+	remote := map[string]string{"Port": "8080"}
+
+	set.AddProvider("base", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"HTTP.Host": "example.com", "HTTP.Port": "81"}, nil
+	}))
+	set.Subset("HTTP").AddProvider("remote", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return remote, nil
+	}))
+	set.AddProvider("broken", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return nil, errors.New("unavailable")
+	}))
+
+	err := set.Reload(context.Background())
+
+	var reloadErr *ReloadError
+	if !errors.As(err, &reloadErr) || len(reloadErr.Errors) != 1 || reloadErr.Errors[0].Name != "broken" {
+		t.Fatalf("Failed to report provider error: got %v", err)
+	}
+
+	if host != "example.com" {
+		t.Errorf("Failed to apply provider value: expected %q; got %q", "example.com", host)
+	}
+
+	// providers are applied in the order they were added
+	if port != 8080 {
+		t.Errorf("Failed to apply providers in order: expected %d; got %d", 8080, port)
+	}
+
+	remote["Port"] = "9090"
+	_ = set.Reload(context.Background())
+
+	if port != 9090 {
+		t.Errorf("Failed to reload provider value: expected %d; got %d", 9090, port)
+	}
+}
+
+func TestSet_ReloadOverridden(t *testing.T) {
+	port := 80
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", &port, "")
+
+	set.AddProvider("base", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"HTTP.Port": "81"}, nil
+	}))
+	set.Subset("HTTP").AddProvider("remote", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"port": "8080"}, nil
+	}))
+
+	var notified []string
+	set.Notify(NotifyFunc(func(setting *Setting) {
+		notified = append(notified, setting.String())
+	}))
+
+	for i := 0; i < 2; i++ {
+		if err := set.Reload(context.Background()); err != nil {
+			t.Fatalf("Failed to reload: %v", err)
+		}
+	}
+
+	// the overridden value never passes through the setting
+	if port != 8080 || len(notified) != 1 || notified[0] != "8080" || set.Revision() != 1 {
+		t.Errorf("Failed to apply the winning value once: got %d with notifications %v and revision %d", port, notified, set.Revision())
+	}
+	if source := set.Get("HTTP.Port").Source(); source != "remote" {
+		t.Errorf("Failed to keep source of winning provider: expected %q; got %q", "remote", source)
+	}
+}
+
+func TestSet_ReloadSubset(t *testing.T) {
+	var (
+		brokers = ""
+		port    = 80
+		loaded  = 0
+	)
+
+	set := &Set{}
+	set.Subset("Kafka").Setting("Brokers", &brokers, "")
+	set.Subset("HTTP").Setting("Port", &port, "")
+
+	set.AddProvider("file", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"Kafka.Brokers": "kafka:9092", "HTTP.Port": "8080"}, nil
+	}))
+	set.Subset("HTTP").AddProvider("http", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		loaded++
+		return map[string]string{"Port": "8081"}, nil
+	}))
+
+	if err := set.Subset("Kafka").Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload subset: %v", err)
+	}
+
+	if brokers != "kafka:9092" {
+		t.Errorf("Failed to reload subset value: expected %q; got %q", "kafka:9092", brokers)
+	}
+
+	if port != 80 || loaded != 0 {
+		t.Errorf("Failed to leave unrelated subset untouched: got port %d, loaded %d times", port, loaded)
+	}
+}
+
+func TestSet_Health(t *testing.T) {
+	fail := true
+
+	set := &Set{}
+	set.AddProvider("remote", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		return nil, nil
+	}))
+
+	_ = set.Reload(context.Background())
+
+	health := set.Health()
+	if len(health) != 1 || health[0].Name != "remote" || health[0].LastError == nil || !health[0].LastSync.IsZero() {
+		t.Fatalf("Failed to report failed provider: got %+v", health)
+	}
+
+	if err := set.Healthy(0); err == nil {
+		t.Errorf("Failed to report unhealthy provider")
+	}
+
+	fail = false
+	_ = set.Reload(context.Background())
+
+	health = set.Health()
+	if health[0].LastError != nil || health[0].LastSync.IsZero() {
+		t.Errorf("Failed to report recovered provider: got %+v", health)
+	}
+
+	if err := set.Healthy(time.Minute); err != nil {
+		t.Errorf("Failed to report healthy provider: %v", err)
+	}
+}
+
+func TestSet_ReloadDeadline(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	loaded := false
+
+	set := &Set{}
+	set.AddProvider("stuck", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		// ignores the ctx
+		<-block
+		return nil, nil
+	}))
+	set.AddProvider("next", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		loaded = true
+		return nil, nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := set.Reload(ctx)
+
+	var reloadErr *ReloadError
+	if !errors.As(err, &reloadErr) || len(reloadErr.Errors) != 2 || !errors.Is(reloadErr.Errors[1], context.DeadlineExceeded) {
+		t.Errorf("Failed to fail remaining providers: got %v", err)
+	}
+
+	if loaded || time.Since(start) > time.Second {
+		t.Errorf("Failed to respect deadline: took %s", time.Since(start))
+	}
+}
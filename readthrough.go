@@ -0,0 +1,166 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver looks up the value of a single setting path from a remote source, found is false when the source has no value for the path
+type Resolver interface {
+	Resolve(ctx context.Context, path string) (value string, found bool, err error)
+}
+
+// ResolverFunc defines a function that looks up the value of a single setting path
+type ResolverFunc func(ctx context.Context, path string) (string, bool, error)
+
+// Resolve implements Resolver.Resolve
+func (f ResolverFunc) Resolve(ctx context.Context, path string) (string, bool, error) {
+	return f(ctx, path)
+}
+
+// readThrough is the Resolver of a Set with its cache
+type readThrough struct {
+	resolver Resolver
+	ttl      time.Duration
+	negative time.Duration
+
+	mu       sync.Mutex
+	expires  map[string]time.Time
+	sweepAt  int
+	inflight map[string]chan struct{}
+}
+
+// minReadThroughSweep is the number of cached paths below which expired entries are not swept
+const minReadThroughSweep = 64
+
+// ReadThrough makes Set.Get consult the Resolver for settings it has not resolved within the ttl, so very large keyspaces do not have to be loaded up front. A value found by the Resolver is applied to the setting, registering it as a string when it is not registered locally. Paths the Resolver has no value for, or fails to resolve, are cached for the negativeTTL and fall back to the locally registered setting and its default. Concurrent lookups of a path share a single call of the Resolver, and expired cache entries are evicted as the cache grows. A nil Resolver disables read-through.
+func (s *Set) ReadThrough(r Resolver, ttl, negativeTTL time.Duration) {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	if r == nil {
+		root.resolver = nil
+		return
+	}
+
+	root.resolver = &readThrough{
+		resolver: r,
+		ttl:      ttl,
+		negative: negativeTTL,
+		expires:  map[string]time.Time{},
+		sweepAt:  minReadThroughSweep,
+		inflight: map[string]chan struct{}{},
+	}
+}
+
+// resolve the name through the Resolver of the Set when it is not cached, returning the setting to use
+func (s *Set) resolve(ctx context.Context, name string, setting *Setting) *Setting {
+	root := s.Root()
+
+	root.mu.Lock()
+	rt := root.resolver
+	root.mu.Unlock()
+
+	if rt == nil {
+		return setting
+	}
+
+	path := s.pathOf(name)
+	if setting != nil {
+		path = setting.Path
+	}
+	key := strings.ToLower(path)
+
+	rt.mu.Lock()
+	if s.clock().Now().Before(rt.expires[key]) {
+		rt.mu.Unlock()
+		return setting
+	}
+
+	// the path is being resolved by another Get, which this one waits for rather than calling the Resolver again
+	if call, ok := rt.inflight[key]; ok {
+		rt.mu.Unlock()
+
+		select {
+		case <-call:
+		case <-ctx.Done():
+			return setting
+		}
+
+		if setting == nil {
+			return root.lookup(path)
+		}
+		return setting
+	}
+
+	call := make(chan struct{})
+	rt.inflight[key] = call
+	rt.mu.Unlock()
+
+	defer rt.done(key, call)
+
+	// resolved without holding the lock so notifiers of the setting can call Set.Get
+	value, found, err := rt.resolver.Resolve(ctx, path)
+	if err == nil && found {
+		// a setting not registered locally is guarded as a string
+		guarded := setting
+		if guarded == nil {
+			guarded = &Setting{Path: path, Value: value}
+		}
+		err = root.guard(ctx, guarded, value)
+	}
+	if err != nil || !found {
+		rt.expire(key, s.clock().Now(), rt.negative)
+		return setting
+	}
+
+	if setting == nil {
+		v := value
+		if setting, err = root.registerPath(path, &v, "", nil); err != nil {
+			// registered concurrently
+			setting = root.lookup(path)
+		}
+	} else if err := setting.setFrom(ctx, value, "read-through"); err != nil {
+		s.trace("convert", path, "unable to apply resolved value: %v", err)
+	}
+
+	rt.expire(key, s.clock().Now(), rt.ttl)
+
+	return setting
+}
+
+// expire the cache entry of the key after the ttl, sweeping the expired entries once the cache doubled in size since the last sweep
+func (rt *readThrough) expire(key string, now time.Time, ttl time.Duration) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.expires[key] = now.Add(ttl)
+
+	if len(rt.expires) < rt.sweepAt {
+		return
+	}
+
+	for k, at := range rt.expires {
+		if !now.Before(at) {
+			delete(rt.expires, k)
+		}
+	}
+
+	rt.sweepAt = 2 * len(rt.expires)
+	if rt.sweepAt < minReadThroughSweep {
+		rt.sweepAt = minReadThroughSweep
+	}
+}
+
+// done releases the Gets waiting for the key to be resolved
+func (rt *readThrough) done(key string, call chan struct{}) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	delete(rt.inflight, key)
+	close(call)
+}
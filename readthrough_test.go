@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSet_ReadThrough(t *testing.T) {
+	remote := map[string]string{"Flags.Beta": "true", "Limits.Requests": "500"}
+	calls := map[string]int{}
+
+	limit := 100
+	set := &Set{}
+	set.Subset("Limits").Setting("Requests", &limit, "")
+	set.Subset("Limits").Setting("Burst", new(int), "")
+
+	set.ReadThrough(ResolverFunc(func(ctx context.Context, path string) (string, bool, error) {
+		calls[path]++
+		if path == "Broken" {
+			return "", false, errors.New("unavailable")
+		}
+		value, found := remote[path]
+		return value, found, nil
+	}), time.Hour, time.Hour)
+
+	if s := set.Get("Limits.Requests"); s == nil || limit != 500 {
+		t.Errorf("Failed to resolve registered setting: got %d", limit)
+	}
+
+	if s := set.Get("Flags.Beta"); s == nil || s.String() != "true" {
+		t.Errorf("Failed to register resolved setting: got %v", s)
+	}
+
+	if s := set.Subset("Limits").Get("Burst"); s == nil || s.String() != "0" {
+		t.Errorf("Failed to fall back to default: got %v", s)
+	}
+
+	if s := set.Get("Broken"); s != nil {
+		t.Errorf("Failed to miss unresolved setting: got %v", s)
+	}
+
+	// cached and negatively cached
+	set.Get("Limits.Requests")
+	set.Get("Flags.Beta")
+	set.Get("Limits.Burst")
+	set.Get("Broken")
+
+	for path, count := range calls {
+		if count != 1 {
+			t.Errorf("Failed to cache %q: resolved %d times", path, count)
+		}
+	}
+}
+
+func TestSet_ReadThroughConcurrent(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+
+	set := &Set{}
+	set.ReadThrough(ResolverFunc(func(ctx context.Context, path string) (string, bool, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "true", true, nil
+	}), time.Hour, time.Hour)
+
+	var wg sync.WaitGroup
+	results := make([]*Setting, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = set.Get("Flags.Beta")
+		}(i)
+	}
+
+	// let the lookups pile up on the first one
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Failed to share concurrent lookup: resolved %d times", n)
+	}
+	for i, s := range results {
+		if s == nil || s.String() != "true" {
+			t.Errorf("Failed to return shared result %d: got %v", i, s)
+		}
+	}
+}
+
+func TestSet_ReadThroughEviction(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	set := &Set{}
+	set.UseClock(clock)
+	set.ReadThrough(ResolverFunc(func(ctx context.Context, path string) (string, bool, error) {
+		return "", false, nil
+	}), time.Hour, time.Minute)
+
+	rt := set.resolver
+	for i := 0; i < minReadThroughSweep; i++ {
+		set.Get("Missing" + strconv.Itoa(i))
+	}
+
+	clock.Advance(2 * time.Minute)
+	for i := 0; i < minReadThroughSweep; i++ {
+		set.Get("Other" + strconv.Itoa(i))
+	}
+
+	rt.mu.Lock()
+	size := len(rt.expires)
+	rt.mu.Unlock()
+
+	if size > minReadThroughSweep+1 {
+		t.Errorf("Failed to evict expired entries: got %d cached paths", size)
+	}
+}
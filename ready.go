@@ -0,0 +1,98 @@
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+// Ready blocks until every setting at the paths has been set by a provider, file, the environment or Setting.Set since it was registered, even to its default value, or until the ctx is done. Settings that are not registered yet (i.e. mirrored by a WatchClient) are waited for as well. This is a warm-up barrier for services whose startup must wait for asynchronously delivered remote configuration.
+func (s *Set) Ready(ctx context.Context, paths ...string) error {
+	// registrations wake the wait for unknown settings
+	registered := make(chan struct{}, 1)
+	handle := s.Root().Notify(NotifyFunc(func(*Setting) {
+		select {
+		case registered <- struct{}{}:
+		default:
+		}
+	}))
+	defer handle.Close()
+
+	for {
+		var (
+			pending string
+			ready   <-chan struct{}
+		)
+
+		for _, path := range paths {
+			setting := s.lookup(path)
+			if setting == nil {
+				pending = path
+				break
+			}
+
+			if ch := setting.readyChan(); !closed(ch) {
+				pending, ready = path, ch
+				break
+			}
+		}
+
+		if pending == "" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return &SettingError{Path: pending, Err: fmt.Errorf("not ready: %w", ctx.Err())}
+		case <-registered:
+		case <-ready:
+		}
+	}
+}
+
+// IsPopulated returns if the setting has been set since it was registered, see Set.Ready
+func (s *Setting) IsPopulated() bool {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+
+	return s.populated
+}
+
+// populate marks the setting as set, releasing Set.Ready
+func (s *Setting) populate() {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+
+	if s.populated {
+		return
+	}
+
+	s.populated = true
+	if s.ready != nil {
+		close(s.ready)
+	}
+}
+
+// readyChan returns a channel closed once the setting is populated
+func (s *Setting) readyChan() <-chan struct{} {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+
+	if s.ready == nil {
+		s.ready = make(chan struct{})
+		if s.populated {
+			close(s.ready)
+		}
+	}
+
+	return s.ready
+}
+
+// closed returns if the channel is closed without blocking
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
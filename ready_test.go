@@ -0,0 +1,49 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSet_Ready(t *testing.T) {
+	s := &Set{}
+	port := s.Setting("Port", 80, "")
+
+	if port.IsPopulated() {
+		t.Errorf("Failed to start unpopulated")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var settingErr *SettingError
+	if err := s.Ready(ctx, "Port"); !errors.As(err, &settingErr) || settingErr.Path != "Port" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Failed to time out: got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Ready(context.Background(), "Port", "Remote.Token") }()
+
+	// the default value populates the setting as well
+	_ = port.Set("80")
+
+	_, _ = s.Root().registerPath("Remote.Token", new(string), "", nil)
+	select {
+	case err := <-done:
+		t.Fatalf("Failed to wait for unpopulated setting: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	_, _ = s.Update("Remote.Token", "secret")
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Failed to become ready: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Failed to become ready")
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Recorded is a single change captured by a Recorder
+type Recorded struct {
+	// Time the change happened
+	Time time.Time `json:"time"`
+
+	// Path of the setting that changed
+	Path string `json:"path"`
+
+	// Value the setting changed to, ***** for masked settings and redacted for Redacter values with secret parts
+	Value string `json:"value,omitempty"`
+
+	// Masked is set for masked settings and redacted values, which are not reapplied by Set.Replay
+	Masked bool `json:"masked,omitempty"`
+}
+
+// Recorder captures the ordered sequence of changes in a Set as JSON lines, see Set.Record
+type Recorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	handle  *NotifyHandle
+	err     error
+}
+
+// Record writes every change in the Set, and its children, to w until the Recorder is closed. Values of masked settings are written as ***** and Redacter values redacted, so secrets never reach the recording. The recording can be reapplied to a fresh Set with Set.Replay to reproduce an incident locally.
+func (s *Set) Record(w io.Writer) *Recorder {
+	r := &Recorder{encoder: json.NewEncoder(w)}
+	r.handle = s.Notify(NotifyFunc(r.record))
+
+	return r
+}
+
+// record writes the current value of the setting
+func (r *Recorder) record(setting *Setting) {
+	value := setting.format()
+	entry := Recorded{
+		Time:  time.Now(),
+		Path:  setting.Path,
+		Value: setting.display(value),
+	}
+	entry.Masked = setting.Mask || entry.Value != value
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+
+	if err := r.encoder.Encode(entry); err != nil {
+		r.err = fmt.Errorf("unable to record %q: %w", setting.Path, err)
+	}
+}
+
+// Close stops recording, returning the first error writing the recording
+func (r *Recorder) Close() error {
+	_ = r.handle.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// Replay reapplies a recording written by Set.Record to the Set in order. When step is not nil it is called before each change is applied, allowing the replay to be paced or inspected, and replay stops with any error it returns. Masked entries, which hold the value of masked settings and redacted values as displayed, are passed to step but are not applied.
+func (s *Set) Replay(r io.Reader, step func(Recorded) error) error {
+	decoder := json.NewDecoder(r)
+
+	for {
+		var entry Recorded
+		if err := decoder.Decode(&entry); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to decode recording: %w", err)
+		}
+
+		if step != nil {
+			if err := step(entry); err != nil {
+				return err
+			}
+		}
+
+		if entry.Masked {
+			continue
+		}
+
+		found, err := s.Update(entry.Path, entry.Value)
+		if !found {
+			return &SettingError{Path: entry.Path, Err: ErrUnknownSetting}
+		}
+		if err != nil {
+			return &SettingError{Path: entry.Path, Err: err}
+		}
+	}
+}
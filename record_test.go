@@ -0,0 +1,85 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSet_RecordReplay(t *testing.T) {
+	type config struct {
+		Port     int
+		Password string `mask:"true"`
+	}
+
+	var recording bytes.Buffer
+
+	original := config{Port: 80}
+	set := &Set{}
+	set.Subset("HTTP").Bind(&original)
+
+	recorder := set.Record(&recording)
+	_, _ = set.Update("HTTP.Port", "8080")
+	_, _ = set.Update("HTTP.Password", "secret")
+	_, _ = set.Update("HTTP.Port", "9090")
+
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Failed to record: %v", err)
+	}
+	_, _ = set.Update("HTTP.Port", "1")
+
+	if strings.Contains(recording.String(), "secret") {
+		t.Errorf("Failed to mask recorded value: got %s", recording.String())
+	}
+
+	replayed := config{Port: 80}
+	fresh := &Set{}
+	fresh.Subset("HTTP").Bind(&replayed)
+
+	var ports []int
+	err := fresh.Replay(bytes.NewReader(recording.Bytes()), func(r Recorded) error {
+		ports = append(ports, replayed.Port)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+
+	if len(ports) != 3 || ports[1] != 8080 {
+		t.Errorf("Failed to replay in order: got %v", ports)
+	}
+
+	if replayed.Port != 9090 || replayed.Password != "" {
+		t.Errorf("Failed to replay changes: expected %d; got %d (%q)", 9090, replayed.Port, replayed.Password)
+	}
+
+	err = (&Set{}).Replay(bytes.NewReader(recording.Bytes()), nil)
+	if !errors.Is(err, ErrUnknownSetting) {
+		t.Errorf("Failed to report unknown setting: got %v", err)
+	}
+}
+
+func TestSet_RecordRedacted(t *testing.T) {
+	var recording bytes.Buffer
+
+	set := redactedSet(t)
+	recorder := set.Record(&recording)
+	_, _ = set.Update("DB", "postgres://app:hunter4@db:5432/app")
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Failed to record: %v", err)
+	}
+	assertRedacted(t, "recording", recording.String())
+
+	// the redacted value is skipped rather than replayed over the current one
+	target := redactedSet(t)
+	err := target.Replay(bytes.NewReader(recording.Bytes()), func(r Recorded) error {
+		if !r.Masked {
+			t.Errorf("Failed to flag redacted entry: got %+v", r)
+		}
+		return nil
+	})
+	if err != nil || target.Get("DB").format() != "postgres://app:hunter3@db:5432/app" {
+		t.Errorf("Failed to skip redacted entry: got %v with %q", err, target.Get("DB").format())
+	}
+}
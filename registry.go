@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownScheme is returned by OpenProvider for a URL whose scheme has no registered ProviderFactory, and for a SecretRef whose scheme has no registered SecretResolver
+var ErrUnknownScheme = errors.New("unknown provider scheme")
+
+// ProviderFactory creates a Provider from its URL (i.e. vault://secret/app or etcd://host:2379/app)
+type ProviderFactory func(u *url.URL) (Provider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]ProviderFactory{}
+)
+
+func init() {
+	RegisterProvider("file", func(u *url.URL) (Provider, error) {
+		// file:relative.json is opaque, file:///abs.json and file://relative.json are not
+		path := u.Opaque
+		if path == "" {
+			path = u.Host + u.Path
+		}
+		if path == "" {
+			return nil, errors.New("path can not be empty")
+		}
+
+		if digest := u.Query().Get("digest"); digest != "" {
+			return FileDigest(path, digest), nil
+		}
+
+		return File(path), nil
+	})
+
+	for _, scheme := range []string{"http", "https"} {
+		RegisterProvider(scheme, func(u *url.URL) (Provider, error) {
+			// the digest is checked here rather than sent to the server
+			query := u.Query()
+			digest := query.Get("digest")
+			if digest == "" {
+				return HTTP(u.String()), nil
+			}
+
+			fetch := *u
+			query.Del("digest")
+			fetch.RawQuery = query.Encode()
+
+			return HTTPDigest(fetch.String(), digest), nil
+		})
+	}
+}
+
+// RegisterProvider makes the factory available to OpenProvider for URLs with the scheme, so backends can register themselves (i.e. from an init function) and be selected from configuration without changing call sites. Scheme is case insensitive, can not be empty or already registered, factory can not be nil
+func RegisterProvider(scheme string, factory ProviderFactory) {
+	if scheme == "" {
+		panic("scheme can not be empty")
+	}
+	if factory == nil {
+		panic("factory can not be nil")
+	}
+
+	scheme = strings.ToLower(scheme)
+
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if _, found := factories[scheme]; found {
+		panic(fmt.Sprintf("provider scheme %q already registered", scheme))
+	}
+
+	factories[scheme] = factory
+}
+
+// ProviderSchemes returns the sorted schemes registered with RegisterProvider
+func ProviderSchemes() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	schemes := make([]string, 0, len(factories))
+	for scheme := range factories {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+
+	return schemes
+}
+
+// OpenProvider creates a Provider for the URL using the ProviderFactory registered for its scheme. The file, http and https schemes are always registered and accept a digest query parameter, see FileDigest and HTTPDigest.
+func OpenProvider(rawURL string) (Provider, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse provider URL: %w", err)
+	}
+
+	factoriesMu.RLock()
+	factory, found := factories[strings.ToLower(u.Scheme)]
+	factoriesMu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("unable to open %q: %w", u.Redacted(), ErrUnknownScheme)
+	}
+
+	p, err := factory(u)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %q: %w", u.Redacted(), err)
+	}
+
+	return p, nil
+}
+
+// AddProviderURL attaches the Provider opened from the URL with OpenProvider to the current Set, see Set.AddProvider
+func (s *Set) AddProviderURL(name, rawURL string) error {
+	p, err := OpenProvider(rawURL)
+	if err != nil {
+		return err
+	}
+
+	s.AddProvider(name, p)
+
+	return nil
+}
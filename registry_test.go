@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenProvider(t *testing.T) {
+	// registered once, so the test can run repeatedly with -count
+	if !hasScheme("memory") {
+		RegisterProvider("Memory", func(u *url.URL) (Provider, error) {
+			return ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+				return map[string]string{"Name": u.Host}, nil
+			}), nil
+		})
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Failed to panic on duplicate scheme")
+		}
+	}()
+
+	s := &Set{}
+	name := s.Setting("Name", new(string), "")
+
+	if err := s.AddProviderURL("memory", "memory://portcullis"); err != nil {
+		t.Fatalf("Failed to add provider: %v", err)
+	}
+	if err := s.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if name.String() != "portcullis" {
+		t.Errorf("Failed to load from registered provider: expected %q; got %q", "portcullis", name.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"Name": "file"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := OpenProvider("file://" + path)
+	if err != nil {
+		t.Fatalf("Failed to open file provider: %v", err)
+	}
+	if values, err := p.Load(context.Background()); err != nil || values["Name"] != "file" {
+		t.Errorf("Failed to load file provider: got %v with %v", err, values)
+	}
+
+	if _, err := OpenProvider("vault://secret/app"); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("Failed to reject unknown scheme: expected %v; got %v", ErrUnknownScheme, err)
+	}
+
+	RegisterProvider("FILE", func(u *url.URL) (Provider, error) { return nil, nil })
+}
+
+func hasScheme(scheme string) bool {
+	for _, s := range ProviderSchemes() {
+		if s == scheme {
+			return true
+		}
+	}
+
+	return false
+}
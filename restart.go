@@ -0,0 +1,134 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// LabelRestartRequired marks a setting as restart required like Setting.RestartRequired, so bound fields can use `labels:"restart-required"`
+const LabelRestartRequired = "restart-required"
+
+// PendingChange is a change of a restart required setting made after Set.Started that takes effect on the next restart, see Set.PendingRestart
+type PendingChange struct {
+	// Path of the setting
+	Path string `json:"path"`
+
+	// From is the value the process is running with, ***** for masked settings
+	From string `json:"from"`
+
+	// To is the value taking effect on restart, ***** for masked settings
+	To string `json:"to"`
+
+	// Time of the latest change
+	Time time.Time `json:"time"`
+}
+
+// Started marks the startup of the process as finished for the Set tree. Changes of restart required settings (see Setting.RestartRequired) made afterwards, by any source (i.e. Set.Reload, a file watch or an admin surface), are accepted but pending until a restart (see Set.PendingRestart), while every value applied before takes effect as the process starts with it.
+func (s *Set) Started() {
+	atomic.StoreInt32(&s.Root().started, 1)
+}
+
+// hasStarted returns if Set.Started was called for the Set tree
+func (s *Set) hasStarted() bool {
+	return atomic.LoadInt32(&s.Root().started) != 0
+}
+
+type baselineContextKey struct{}
+
+// baseline returns a child context of ctx for values the process would start with anyway (i.e. embedded defaults, see Set.LoadDefaults), which take effect directly rather than waiting for a restart
+func baseline(ctx context.Context) context.Context {
+	return context.WithValue(ctx, baselineContextKey{}, true)
+}
+
+// requiresRestart returns if the setting is only read when the process starts
+func (s *Setting) requiresRestart() bool {
+	return s.RestartRequired || hasLabel(s.Labels, LabelRestartRequired)
+}
+
+// pend records the change of the restart required setting from the value the process is running with, a change back to it is no longer pending
+func (s *Set) pend(setting *Setting, running string) {
+	root := s.Root()
+	key := strings.ToLower(setting.Path)
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	if root.pendingRestart == nil {
+		root.pendingRestart = map[string]*pendingChange{}
+	}
+
+	p := root.pendingRestart[key]
+	if p == nil {
+		p = &pendingChange{setting: setting, running: running}
+		root.pendingRestart[key] = p
+	}
+
+	if setting.format() == p.running {
+		delete(root.pendingRestart, key)
+		return
+	}
+
+	p.time = time.Now()
+}
+
+// pendingChange is the state of a PendingChange
+type pendingChange struct {
+	setting *Setting
+	running string
+	time    time.Time
+}
+
+// PendingRestart returns the changes of the restart required settings within the Set made after Set.Started (see Setting.RestartRequired), sorted by path. The changes are accepted but the process keeps running with the previous value, so operators know a restart is needed for them to take effect.
+func (s *Set) PendingRestart() []PendingChange {
+	root := s.Root()
+
+	root.mu.Lock()
+	var changes []PendingChange
+	for _, p := range root.pendingRestart {
+		if !s.contains(p.setting.Path) {
+			continue
+		}
+
+		change := PendingChange{Path: p.setting.Path, From: p.setting.display(p.running), To: p.setting.display(p.setting.format()), Time: p.time}
+		changes = append(changes, change)
+	}
+	root.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+
+	return changes
+}
+
+// PendingRestartServer exposes the changes of a Set waiting for a restart over HTTP as JSON, see Set.PendingRestart
+type PendingRestartServer struct {
+	// Set being exposed
+	Set *Set
+
+	// Token, when not empty, is required from clients as a bearer token
+	Token string
+}
+
+// ServeHTTP writes the pending changes of the Set, an empty array when no restart is needed
+func (ps *PendingRestartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkBearer(w, r, ps.Token) {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	changes := ps.Set.PendingRestart()
+	if changes == nil {
+		changes = []PendingChange{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(changes)
+}
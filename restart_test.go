@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSet_PendingRestart(t *testing.T) {
+	cfg := struct {
+		Workers int `labels:"restart-required"`
+		Level   string
+	}{Workers: 4, Level: "info"}
+
+	set := &Set{}
+	set.Bind(&cfg)
+	secret := set.Setting("Key", "abc", "")
+	secret.Mask = true
+	secret.RestartRequired = true
+
+	// values supplied at startup are not pending, whatever their source
+	t.Setenv("WORKERS", "6")
+	if err := set.LoadEnv(""); err != nil {
+		t.Fatalf("Failed to load env: %v", err)
+	}
+	_ = set.Get("Workers").Set("8")
+
+	if pending := set.PendingRestart(); len(pending) != 0 {
+		t.Fatalf("Failed to ignore startup values: got %v", pending)
+	}
+
+	set.Started()
+
+	if _, err := set.UpdateContext(context.Background(), "Workers", "16"); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	_ = set.Get("Workers").Set("32")
+	_ = set.Get("Level").Set("debug")
+	_ = secret.Set("xyz")
+
+	pending := set.PendingRestart()
+	if len(pending) != 2 {
+		t.Fatalf("Failed to record pending changes: got %v", pending)
+	}
+
+	if p := pending[1]; p.Path != "Workers" || p.From != "8" || p.To != "32" || p.Time.IsZero() {
+		t.Errorf("Failed to record pending change: got %+v", p)
+	}
+
+	if p := pending[0]; p.Path != "Key" || p.From != "*****" || p.To != "*****" {
+		t.Errorf("Failed to mask pending change: got %+v", p)
+	}
+
+	// changing back to the running value needs no restart
+	_ = set.Get("Workers").Set("8")
+
+	rec := httptest.NewRecorder()
+	(&PendingRestartServer{Set: set}).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	var served []PendingChange
+	if err := json.NewDecoder(rec.Body).Decode(&served); err != nil {
+		t.Fatalf("Failed to decode pending changes: %v", err)
+	}
+
+	if len(served) != 1 || served[0].Path != "Key" {
+		t.Errorf("Failed to serve pending changes: got %v", served)
+	}
+}
+
+func TestSet_PendingRestartLoaded(t *testing.T) {
+	workers := 4
+
+	set := &Set{}
+	set.Setting("Workers", &workers, "").RestartRequired = true
+	set.Started()
+
+	// values loaded after startup wait for a restart like runtime writes
+	t.Setenv("WORKERS", "8")
+	if err := set.LoadEnv(""); err != nil {
+		t.Fatalf("Failed to load env: %v", err)
+	}
+
+	if pending := set.PendingRestart(); len(pending) != 1 || pending[0].From != "4" || pending[0].To != "8" {
+		t.Errorf("Failed to record loaded change: got %+v", pending)
+	}
+}
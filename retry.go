@@ -0,0 +1,98 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryPolicy retries a failing operation with exponential backoff, shared by provider integrations rather than each hardcoding their own behavior
+type RetryPolicy struct {
+	// Attempts is the total number of attempts, defaults to 3
+	Attempts int
+
+	// Backoff before the second attempt, doubling for every attempt after, defaults to 100 milliseconds
+	Backoff time.Duration
+
+	// MaxBackoff caps the backoff, when zero the backoff is not capped
+	MaxBackoff time.Duration
+
+	// Retryable classifies errors that are worth retrying, defaults to DefaultRetryable
+	Retryable func(err error) bool
+
+	// Clock the backoff is waited on, defaults to SystemClock
+	Clock Clock
+}
+
+// DefaultRetryable retries every error except context errors, ErrBreakerOpen and *StaleError as retrying those can not succeed
+func DefaultRetryable(err error) bool {
+	var staleErr *StaleError
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrBreakerOpen) && !errors.As(err, &staleErr)
+}
+
+// Do calls fn until it succeeds, returns an error that is not retryable, the attempts are exhausted or the ctx is done. The last error is returned.
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := p.Attempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+
+		timer := clockOrSystem(p.Clock).NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C():
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
+
+// Retry wraps the Provider retrying failed loads with the policy
+func Retry(p Provider, policy RetryPolicy) Provider {
+	return &retryProvider{provider: p, policy: policy}
+}
+
+// retryProvider is the Provider returned by Retry
+type retryProvider struct {
+	provider Provider
+	policy   RetryPolicy
+}
+
+// Load implements Provider.Load
+func (r *retryProvider) Load(ctx context.Context) (map[string]string, error) {
+	var values map[string]string
+
+	err := r.policy.Do(ctx, func(ctx context.Context) error {
+		var err error
+		values, err = r.provider.Load(ctx)
+		return err
+	})
+
+	return values, err
+}
+
+// Unwrap returns the Provider being retried
+func (r *retryProvider) Unwrap() Provider {
+	return r.provider
+}
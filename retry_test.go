@@ -0,0 +1,41 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	attempts := 0
+	flaky := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("unavailable")
+		}
+		return map[string]string{"Port": "8080"}, nil
+	})
+
+	values, err := Retry(flaky, RetryPolicy{Attempts: 3, Backoff: time.Millisecond}).Load(context.Background())
+	if err != nil || values["Port"] != "8080" || attempts != 3 {
+		t.Errorf("Failed to retry: got %v with %v after %d attempts", err, values, attempts)
+	}
+
+	attempts = 0
+	permanent := errors.New("permanent")
+	failing := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		attempts++
+		return nil, permanent
+	})
+
+	policy := RetryPolicy{Attempts: 5, Backoff: time.Millisecond, Retryable: func(err error) bool { return !errors.Is(err, permanent) }}
+	if _, err := Retry(failing, policy).Load(context.Background()); !errors.Is(err, permanent) || attempts != 1 {
+		t.Errorf("Failed to stop on permanent error: got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	if _, err := Retry(&Breaker{Provider: failing, Threshold: 1}, RetryPolicy{Backoff: time.Millisecond}).Load(context.Background()); !errors.Is(err, ErrBreakerOpen) || attempts != 1 {
+		t.Errorf("Failed to stop on open breaker: got %v after %d attempts", err, attempts)
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrRevisionUnavailable is returned by Set.Rollback when the history of a setting no longer covers the revision
+var ErrRevisionUnavailable = errors.New("revision not in history")
+
+// Rollback restores every setting of the Set that changed after the revision to the value it had at the revision, notifying for every restored setting. The values are taken from the history of the settings, so the Set must keep history (see Set.KeepHistory) covering every change since the revision, otherwise an error wrapping ErrRevisionUnavailable is returned without restoring anything. Undoing the last change is a rollback to Set.Revision minus one.
+//
+// Restored values are runtime changes, written through to a writable provider and saved by Set.Persist. A setting changed again while rolling back fails with ErrRevisionMismatch.
+func (s *Set) Rollback(revision uint64) error {
+	if current := s.Revision(); revision > current {
+		return fmt.Errorf("%w: revision %d is after the current revision %d", ErrRevisionUnavailable, revision, current)
+	}
+
+	type restore struct {
+		setting  *Setting
+		value    string
+		revision uint64
+	}
+
+	// every value is resolved before any is restored, so a gap in the history leaves the Set untouched
+	var restores []restore
+	for _, setting := range s.sorted() {
+		current := setting.Revision()
+		if current <= revision {
+			continue
+		}
+
+		value, err := setting.valueAt(revision)
+		if err != nil {
+			return &SettingError{Path: setting.Path, Err: err}
+		}
+
+		restores = append(restores, restore{setting: setting, value: value, revision: current})
+	}
+
+	for _, r := range restores {
+		r := r
+		err := r.setting.setIf(context.Background(), r.value, "", func() error {
+			if current := r.setting.Revision(); current != r.revision {
+				return fmt.Errorf("%w: expected %d; got %d", ErrRevisionMismatch, r.revision, current)
+			}
+			return nil
+		})
+		if err != nil {
+			return &SettingError{Path: r.setting.Path, Err: err}
+		}
+
+		// runtime changes are saved when the Set is persisted
+		s.persist(r.setting)
+	}
+
+	return nil
+}
+
+// valueAt returns the value the setting had at the revision from its history, which is the value replaced by the first change after it
+func (s *Setting) valueAt(revision uint64) (string, error) {
+	s.history.mu.Lock()
+	defer s.history.mu.Unlock()
+
+	for _, entry := range s.history.ordered() {
+		if entry.Revision <= revision {
+			continue
+		}
+
+		// the change replaced a value from before the revision only when no change in between was dropped
+		if entry.previousRevision > revision {
+			break
+		}
+
+		return entry.previous, nil
+	}
+
+	return "", fmt.Errorf("%w: %d", ErrRevisionUnavailable, revision)
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSet_Rollback(t *testing.T) {
+	set := &Set{}
+	set.KeepHistory(2)
+
+	port := set.Setting("Port", 80, "")
+	host := set.Setting("Host", "localhost", "")
+
+	_ = port.Set("8080")
+	before := set.Revision()
+	_ = host.Set("example.com")
+	_ = port.Set("9090")
+
+	var notified []string
+	set.Notify(NotifyFunc(func(setting *Setting) {
+		notified = append(notified, setting.Path)
+	}))
+
+	if err := set.Rollback(before); err != nil {
+		t.Fatalf("Failed to rollback: %v", err)
+	}
+
+	if port.String() != "8080" || host.String() != "localhost" {
+		t.Errorf("Failed to restore values: expected %q and %q; got %q and %q", "8080", "localhost", port.String(), host.String())
+	}
+
+	if len(notified) != 2 {
+		t.Errorf("Failed to notify restored settings: got %v", notified)
+	}
+
+	// undo the rollback of Port
+	if err := set.Rollback(set.Revision() - 1); err != nil || port.String() != "9090" {
+		t.Errorf("Failed to undo the last change: got %q (%v)", port.String(), err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = host.Set(string(rune('a' + i)))
+	}
+	if err := set.Rollback(before); !errors.Is(err, ErrRevisionUnavailable) || host.String() != "c" {
+		t.Errorf("Failed to reject a revision no longer in history: got %v", err)
+	}
+
+	if err := set.Rollback(set.Revision() + 1); !errors.Is(err, ErrRevisionUnavailable) {
+		t.Errorf("Failed to reject a future revision: got %v", err)
+	}
+}
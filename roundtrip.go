@@ -0,0 +1,224 @@
+package config
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+)
+
+// RoundTripError is a value of a setting that does not survive being set back from its string, see Set.CheckRoundTrip
+type RoundTripError struct {
+	// Path of the setting
+	Path string
+
+	// Value the setting was set to
+	Value string
+
+	// Got is the string the value formatted to when it was set back, empty when setting it back failed
+	Got string
+
+	// Err setting the value back failed with, nil when it was set back to a different value
+	Err error
+}
+
+func (e *RoundTripError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%s does not round trip %q: %v", e.Path, e.Value, e.Err)
+	case e.Got != e.Value:
+		return fmt.Sprintf("%s does not round trip %q: set back as %q", e.Path, e.Value, e.Got)
+	default:
+		return fmt.Sprintf("%s does not round trip %q: not equal to itself, see Equality", e.Path, e.Value)
+	}
+}
+
+// Unwrap returns the error setting the value back failed with
+func (e *RoundTripError) Unwrap() error {
+	return e.Err
+}
+
+// CheckRoundTrip verifies the invariants the loaders rely on for every setting of the Set: setting a setting to its own string (its String when unmasked) succeeds, formats to the same string, and Setting.Equals holds for it. It checks the current and default value of every setting plus up to samples random values per setting, generated from the seed for the types that can be generated (basic types, and structs of exported fields such as most custom Marshaler implementations), so a failure reproduces with the same seed. Generated values that do not parse are skipped. The Set itself is not changed. It is intended to run as a property test of an application's whole Set:
+//
+//	for _, err := range set.CheckRoundTrip(1000, 1) {
+//		t.Error(err)
+//	}
+func (s *Set) CheckRoundTrip(samples int, seed int64) []*RoundTripError {
+	var settings []*Setting
+	s.Range(func(_ string, setting *Setting) bool {
+		settings = append(settings, setting)
+		return true
+	})
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	random := rand.New(rand.NewSource(seed))
+
+	var errs []*RoundTripError
+	for _, setting := range settings {
+		if err := setting.roundTrip(setting.format(), false); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := setting.roundTrip(setting.DefaultValue, true); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		typ := reflect.TypeOf(setting.Value)
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if !generatable(typ, map[reflect.Type]bool{}) {
+			continue
+		}
+
+		for i := 0; i < samples; i++ {
+			generated := generate(typ, random, 3)
+
+			sample := setting.scratch()
+			if rvalue := reflect.ValueOf(sample.Value); rvalue.Kind() == reflect.Ptr {
+				rvalue.Elem().Set(generated)
+			} else {
+				sample.Value = generated.Interface()
+			}
+
+			if err := setting.roundTrip(sample.format(), true); err != nil {
+				errs = append(errs, err)
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// roundTrip sets a scratch copy of the setting to v and back to its own string, returning the invariant broken. When lenient, a v that does not parse is skipped rather than reported.
+func (s *Setting) roundTrip(v string, lenient bool) *RoundTripError {
+	first := s.scratch()
+	if err := first.convert(v); err != nil {
+		if lenient {
+			return nil
+		}
+		return &RoundTripError{Path: s.Path, Value: v, Err: err}
+	}
+	formatted := first.format()
+
+	second := s.scratch()
+	if err := second.convert(formatted); err != nil {
+		return &RoundTripError{Path: s.Path, Value: formatted, Err: err}
+	}
+	if got := second.format(); got != formatted {
+		return &RoundTripError{Path: s.Path, Value: formatted, Got: got}
+	}
+	if !second.Equals(formatted) {
+		return &RoundTripError{Path: s.Path, Value: formatted, Got: formatted}
+	}
+
+	return nil
+}
+
+// scratch returns a Setting holding a new zero value of the type of the Value, to be converted without touching the setting
+func (s *Setting) scratch() *Setting {
+	value := reflect.ValueOf(s.Value)
+	scratch := &Setting{Path: s.Path, set: s.set}
+
+	if value.Kind() == reflect.Ptr {
+		scratch.Value = reflect.New(value.Type().Elem()).Interface()
+	} else {
+		scratch.Value = reflect.Zero(value.Type()).Interface()
+	}
+
+	return scratch
+}
+
+// generatable returns if generate can create values of the type, seen guards recursive types
+func generatable(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if valueTypes[t] || seen[t] {
+		return false
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return generatable(t.Elem(), seen)
+	case reflect.Map:
+		return generatable(t.Key(), seen) && generatable(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath != "" || !generatable(f.Type, seen) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// generate returns a random value of the generatable type, favoring the extreme integers where formatting bugs hide. Size bounds the length of strings and collections.
+func generate(t reflect.Type, random *rand.Rand, size int) reflect.Value {
+	v := reflect.New(t).Elem()
+	if size < 0 {
+		size = 0
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(random.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := t.Bits()
+		switch random.Intn(4) {
+		case 0:
+			v.SetInt(-1 << (bits - 1))
+		case 1:
+			v.SetInt(1<<(bits-1) - 1)
+		default:
+			v.SetInt(int64(random.Uint64()) >> (64 - bits))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(random.Uint64() >> (64 - t.Bits()))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat((random.Float64() - 0.5) * float64(int64(1)<<uint(random.Intn(60))))
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(complex(random.NormFloat64(), random.NormFloat64()))
+	case reflect.String:
+		runes := make([]rune, random.Intn(size*4+1))
+		for i := range runes {
+			runes[i] = rune(random.Intn(0x800))
+		}
+		v.SetString(string(runes))
+	case reflect.Ptr:
+		if random.Intn(4) > 0 {
+			elem := reflect.New(t.Elem())
+			elem.Elem().Set(generate(t.Elem(), random, size-1))
+			v.Set(elem)
+		}
+	case reflect.Slice:
+		n := random.Intn(size + 1)
+		v.Set(reflect.MakeSlice(t, n, n))
+		for i := 0; i < n; i++ {
+			v.Index(i).Set(generate(t.Elem(), random, size-1))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).Set(generate(t.Elem(), random, size-1))
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(t))
+		for i := random.Intn(size + 1); i > 0; i-- {
+			v.SetMapIndex(generate(t.Key(), random, size-1), generate(t.Elem(), random, size-1))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			v.Field(i).Set(generate(t.Field(i).Type, random, size-1))
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripList struct {
+	Items []string
+}
+
+func (l *roundTripList) UnmarshalSetting(v string) error {
+	l.Items = nil
+	if v != "" {
+		l.Items = strings.Split(v, ",")
+	}
+	return nil
+}
+
+func (l *roundTripList) MarshalSetting() string {
+	return strings.Join(l.Items, ", ")
+}
+
+func (l *roundTripList) Equals(v string) bool {
+	return l.MarshalSetting() == v
+}
+
+type roundTripLevel struct {
+	Level int
+}
+
+func (l *roundTripLevel) UnmarshalSetting(v string) error {
+	switch v {
+	case "debug":
+		l.Level = 0
+	case "info":
+		l.Level = 1
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+func (l *roundTripLevel) MarshalSetting() string {
+	return [...]string{"debug", "info", "warn"}[l.Level%3]
+}
+
+func TestSet_CheckRoundTrip(t *testing.T) {
+	cfg := struct {
+		Name    string
+		Port    int16
+		Ratio   float64
+		Timeout time.Duration
+		Enabled bool
+		Share   Percent
+		Where   *time.Location
+		List    roundTripList
+	}{Timeout: time.Second, Where: time.UTC}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	errs := set.CheckRoundTrip(200, 1)
+	if len(errs) != 1 || errs[0].Path != "List" || errs[0].Got == errs[0].Value {
+		t.Fatalf("Failed to find list that does not round trip: got %v", errs)
+	}
+
+	// the failure reproduces with the same seed
+	if again := set.CheckRoundTrip(200, 1); len(again) != 1 || again[0].Value != errs[0].Value {
+		t.Errorf("Failed to reproduce: expected %v; got %v", errs, again)
+	}
+
+	if cfg.Name != "" || cfg.Timeout != time.Second {
+		t.Error("Failed to leave the Set unchanged")
+	}
+
+	level := &roundTripLevel{Level: 2}
+	set = &Set{}
+	set.Setting("Level", level, "")
+
+	errs = set.CheckRoundTrip(0, 1)
+	if len(errs) != 1 || errs[0].Value != "warn" || errs[0].Err == nil {
+		t.Fatalf("Failed to find level that does not parse: got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), `Level does not round trip "warn"`) {
+		t.Errorf("Failed to describe error: got %q", errs[0].Error())
+	}
+}
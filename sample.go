@@ -0,0 +1,148 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SampleFormat is the file format written by Set.WriteSample
+type SampleFormat int
+
+const (
+	// SampleYAML writes a YAML document
+	SampleYAML SampleFormat = iota
+
+	// SampleTOML writes a TOML document
+	SampleTOML
+)
+
+// WriteSample writes a sample file in the format with every setting of the Set commented out at its default value, preceded by its description. This is intended to be generated from the code in CI and shipped as config.example.yaml. Masked settings are written with an empty value, and the secret parts of Redacter values are redacted.
+func (s *Set) WriteSample(w io.Writer, f SampleFormat) error {
+	return s.writeSample(w, f, "# ")
+}
+
+// writeSample writes every setting of the Set in the format with every line starting with the prefix
+func (s *Set) writeSample(w io.Writer, f SampleFormat, prefix string) error {
+	type entry struct {
+		parents []string
+		setting *Setting
+	}
+
+	var entries []entry
+	s.Range(func(_ string, setting *Setting) bool {
+		path := setting.Path
+		if s.path != "" {
+			path = strings.TrimPrefix(path[len(s.path):], ".")
+		}
+
+		segments := strings.Split(path, ".")
+		entries = append(entries, entry{parents: segments[:len(segments)-1], setting: setting})
+		return true
+	})
+
+	// settings of a Set come before its subsets, as required by TOML tables
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].parents, entries[j].parents
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return entries[i].setting.Name < entries[j].setting.Name
+	})
+
+	bw := bufio.NewWriter(w)
+
+	var open []string
+	for _, e := range entries {
+		// number of parents shared with the previous setting
+		common := 0
+		for common < len(open) && common < len(e.parents) && open[common] == e.parents[common] {
+			common++
+		}
+
+		switch f {
+		case SampleYAML:
+			for i := common; i < len(e.parents); i++ {
+				fmt.Fprintf(bw, "%s%s%s:\n", prefix, strings.Repeat("  ", i), e.parents[i])
+			}
+		case SampleTOML:
+			if common != len(open) || common != len(e.parents) {
+				fmt.Fprintf(bw, "\n%s[%s]\n", prefix, strings.Join(e.parents, "."))
+			}
+		default:
+			return fmt.Errorf("unsupported sample format %d", f)
+		}
+		open = e.parents
+
+		indent := ""
+		if f == SampleYAML {
+			indent = strings.Repeat("  ", len(e.parents))
+		}
+
+		for _, line := range sampleComment(e.setting) {
+			fmt.Fprintf(bw, "%s%s# %s\n", prefix, indent, line)
+		}
+
+		separator := ": "
+		if f == SampleTOML {
+			separator = " = "
+		}
+
+		fmt.Fprintf(bw, "%s%s%s%s%s\n", prefix, indent, e.setting.Name, separator, sampleValue(e.setting))
+	}
+
+	return bw.Flush()
+}
+
+// sampleComment returns the comment lines describing the setting
+func sampleComment(setting *Setting) []string {
+	var lines []string
+	if setting.Description != "" {
+		lines = append(lines, strings.Split(setting.Description, "\n")...)
+	}
+
+	var notes []string
+	if setting.Required {
+		notes = append(notes, "required")
+	}
+	if setting.Mask {
+		notes = append(notes, "secret")
+	}
+	if len(notes) > 0 {
+		lines = append(lines, "("+strings.Join(notes, ", ")+")")
+	}
+
+	return lines
+}
+
+// sampleValue returns the default of the setting as a literal, numbers and booleans are bare while everything else is quoted
+func sampleValue(setting *Setting) string {
+	if setting.Mask {
+		return `""`
+	}
+
+	value := setting.display(setting.DefaultValue)
+
+	kind := reflect.Indirect(reflect.ValueOf(setting.Value)).Kind()
+	switch {
+	case kind == reflect.Bool:
+		if _, err := strconv.ParseBool(value); err == nil {
+			return value
+		}
+	case kind >= reflect.Int && kind <= reflect.Float64:
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return value
+		}
+	}
+
+	return strconv.Quote(value)
+}
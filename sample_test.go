@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSet_WriteSample(t *testing.T) {
+	cfg := struct {
+		Name string `description:"name of the service"`
+		HTTP struct {
+			Port    int           `description:"port to listen on" required:"true"`
+			Timeout time.Duration `description:"request timeout"`
+			TLS     struct {
+				Key string `mask:"true"`
+			}
+		}
+		Debug bool
+	}{Name: "api"}
+	cfg.HTTP.Port = 8080
+	cfg.HTTP.Timeout = 5 * time.Second
+	cfg.HTTP.TLS.Key = "secret"
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	tests := []struct {
+		format   SampleFormat
+		expected string
+	}{
+		{
+			format: SampleYAML,
+			expected: `# Debug: false
+# # name of the service
+# Name: "api"
+# HTTP:
+#   # port to listen on
+#   # (required)
+#   Port: 8080
+#   # request timeout
+#   Timeout: "5s"
+#   TLS:
+#     # (secret)
+#     Key: ""
+`,
+		},
+		{
+			format: SampleTOML,
+			expected: `# Debug = false
+# # name of the service
+# Name = "api"
+
+# [HTTP]
+# # port to listen on
+# # (required)
+# Port = 8080
+# # request timeout
+# Timeout = "5s"
+
+# [HTTP.TLS]
+# # (secret)
+# Key = ""
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		if err := set.WriteSample(buf, tt.format); err != nil {
+			t.Fatalf("Failed to write sample: %v", err)
+		}
+
+		if buf.String() != tt.expected {
+			t.Errorf("Failed to write sample:\nexpected:\n%s\ngot:\n%s", tt.expected, buf.String())
+		}
+	}
+}
+
+func TestSet_WriteSampleRedacted(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := redactedSet(t).WriteSample(buf, SampleYAML); err != nil {
+		t.Fatalf("Failed to write sample: %v", err)
+	}
+
+	assertRedacted(t, "sample", buf.String())
+}
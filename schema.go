@@ -0,0 +1,196 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schemaTypes are the Go types recreated by ReadSchema, any other type is read as a string
+var schemaTypes = map[string]func() Value{
+	"string":               func() Value { return new(string) },
+	"bool":                 func() Value { return new(bool) },
+	"int":                  func() Value { return new(int) },
+	"int8":                 func() Value { return new(int8) },
+	"int16":                func() Value { return new(int16) },
+	"int32":                func() Value { return new(int32) },
+	"int64":                func() Value { return new(int64) },
+	"uint":                 func() Value { return new(uint) },
+	"uint8":                func() Value { return new(uint8) },
+	"uint16":               func() Value { return new(uint16) },
+	"uint32":               func() Value { return new(uint32) },
+	"uint64":               func() Value { return new(uint64) },
+	"float32":              func() Value { return new(float32) },
+	"float64":              func() Value { return new(float64) },
+	"complex64":            func() Value { return new(complex64) },
+	"complex128":           func() Value { return new(complex128) },
+	"time.Duration":        func() Value { return new(time.Duration) },
+	"config.Duration":      func() Value { return new(Duration) },
+	"config.DurationRange": func() Value { return new(DurationRange) },
+	"config.Percent":       func() Value { return new(Percent) },
+	"config.Rate":          func() Value { return new(Rate) },
+}
+
+// schema is a JSON Schema document describing a Set
+type schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+	WriteOnly   bool               `json:"writeOnly,omitempty"`
+	Properties  map[string]*schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	GoType      string             `json:"x-go-type,omitempty"`
+	Category    string             `json:"x-category,omitempty"`
+	Annotations map[string]string  `json:"x-annotations,omitempty"`
+}
+
+// WriteSchema writes a JSON Schema describing the documents accepted by Set.LoadFile for the settings of the Set. Subsets are objects, and every setting is a property with its type, default and description. Masked settings are marked writeOnly and their default is omitted, as is a default with secret parts (see Redacter). The schema can be read back with ReadSchema.
+func (s *Set) WriteSchema(w io.Writer) error {
+	root := &schema{
+		Schema: "https://json-schema.org/draft/2020-12/schema",
+		Type:   "object",
+	}
+
+	s.Range(func(_ string, setting *Setting) bool {
+		path := setting.Path
+		if s.path != "" {
+			path = path[len(s.path)+1:]
+		}
+
+		segments := strings.Split(path, ".")
+
+		object := root
+		for _, segment := range segments[:len(segments)-1] {
+			if object.Properties == nil {
+				object.Properties = map[string]*schema{}
+			}
+
+			child, found := object.Properties[segment]
+			if !found {
+				child = &schema{Type: "object"}
+				object.Properties[segment] = child
+			}
+			object = child
+		}
+
+		if object.Properties == nil {
+			object.Properties = map[string]*schema{}
+		}
+
+		name := segments[len(segments)-1]
+		object.Properties[name] = settingSchema(setting)
+		if setting.Required {
+			object.Required = append(object.Required, name)
+			sort.Strings(object.Required)
+		}
+
+		return true
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(root)
+}
+
+// settingSchema describes a single setting
+func settingSchema(setting *Setting) *schema {
+	sc := &schema{
+		Type:        "string",
+		Description: setting.Description,
+		GoType:      setting.Type(),
+		Category:    setting.Category,
+		Annotations: setting.Annotations,
+		WriteOnly:   setting.Mask,
+	}
+
+	var value interface{} = setting.DefaultValue
+
+	switch reflect.Indirect(reflect.ValueOf(setting.Value)).Kind() {
+	case reflect.Bool:
+		sc.Type = "boolean"
+		if b, err := strconv.ParseBool(setting.DefaultValue); err == nil {
+			value = b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseInt(setting.DefaultValue, 10, 64); err == nil {
+			sc.Type = "integer"
+			value = json.Number(setting.DefaultValue)
+		} else if _, err := strconv.ParseUint(setting.DefaultValue, 10, 64); err == nil {
+			sc.Type = "integer"
+			value = json.Number(setting.DefaultValue)
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(setting.DefaultValue, 64); err == nil {
+			sc.Type = "number"
+			value = json.Number(setting.DefaultValue)
+		}
+	}
+
+	if !setting.sensitive() {
+		sc.Default = value
+	}
+
+	return sc
+}
+
+// ReadSchema creates a new Set from a JSON Schema written by Set.WriteSchema. Settings are recreated with their Go type when it is known, and as strings otherwise, so values can be validated without the original program.
+func ReadSchema(r io.Reader) (*Set, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var root schema
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("unable to decode schema: %w", err)
+	}
+
+	set := &Set{}
+	if err := readSchema(set, &root); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+func readSchema(set *Set, object *schema) error {
+	required := map[string]bool{}
+	for _, name := range object.Required {
+		required[name] = true
+	}
+
+	for name, property := range object.Properties {
+		if property.Type == "object" {
+			if err := readSchema(set.Subset(name), property); err != nil {
+				return err
+			}
+			continue
+		}
+
+		newValue, found := schemaTypes[property.GoType]
+		if !found {
+			newValue = schemaTypes["string"]
+		}
+
+		setting := set.Setting(name, newValue(), property.Description)
+		setting.Mask = property.WriteOnly
+		setting.Category = property.Category
+		setting.Annotations = property.Annotations
+		setting.Required = required[name]
+
+		if property.Default != nil {
+			if err := setting.Set(fmt.Sprint(property.Default)); err != nil {
+				return fmt.Errorf("invalid default for %q: %w", setting.Path, err)
+			}
+			setting.DefaultValue = setting.format()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_Schema(t *testing.T) {
+	cfg := struct {
+		HTTP struct {
+			Port    int16         `description:"Port to listen" required:"true"`
+			Timeout time.Duration `category:"Networking"`
+		}
+		Password string `mask:"true"`
+		Ratio    float64
+		Debug    bool
+	}{}
+	cfg.HTTP.Port = 8080
+	cfg.HTTP.Timeout = time.Minute
+	cfg.Password = "secret"
+
+	buf := &bytes.Buffer{}
+	if err := (&Set{}).Bind(&cfg).WriteSchema(buf); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("Failed to omit masked default from schema:\n%s", buf.String())
+	}
+
+	set, err := ReadSchema(buf)
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+
+	port := set.Get("HTTP.Port")
+	if port == nil || port.Type() != "int16" || port.DefaultValue != "8080" || port.Description != "Port to listen" || !port.Required {
+		t.Fatalf("Failed to read setting from schema: got %+v", port)
+	}
+
+	if err := port.Set("70000"); err == nil {
+		t.Errorf("Failed to validate value with the original type")
+	}
+
+	if timeout := set.Get("HTTP.Timeout"); timeout == nil || timeout.DefaultValue != "1m0s" || timeout.Category != "Networking" {
+		t.Errorf("Failed to read duration from schema: got %+v", timeout)
+	}
+
+	if password := set.Get("Password"); password == nil || !password.Mask {
+		t.Errorf("Failed to read masked setting from schema: got %+v", password)
+	}
+
+	for _, path := range []string{"Ratio", "Debug"} {
+		if set.Get(path) == nil {
+			t.Errorf("Failed to read %q from schema", path)
+		}
+	}
+}
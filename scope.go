@@ -0,0 +1,142 @@
+package config
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Scope isolates the settings a plugin contributes to a Set so they can be removed at runtime, see Set.Scope
+type Scope struct {
+	set *Set
+
+	mu      sync.Mutex
+	handles []*NotifyHandle
+	closed  bool
+}
+
+// Scope returns a registration handle for a plugin owning the subset name of the Set. The plugin registers its settings and subsets in Scope.Set and attaches notifiers or hooks elsewhere through Scope.Track, so everything it contributed is removed in one Scope.Close when it is unloaded.
+func (s *Set) Scope(name string) *Scope {
+	return &Scope{set: s.Subset(name)}
+}
+
+// Set returns the subset owned by the Scope, settings must not be registered in it after Scope.Close
+func (sc *Scope) Set() *Set {
+	return sc.set
+}
+
+// Track adopts the handle of a notifier or hook the plugin attached outside the Scope (i.e. on a setting of the host), it is closed with the Scope. The handle is returned for convenience and closed right away when the Scope is already closed.
+func (sc *Scope) Track(h *NotifyHandle) *NotifyHandle {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.closed {
+		_ = h.Close()
+		return h
+	}
+
+	sc.handles = append(sc.handles, h)
+
+	return h
+}
+
+// Close removes every setting, subset, provider and template of the Scope from the Set and stops their notifiers along with the tracked handles, pending approvals and restarts of its settings are dropped. An OpRemove Event is reported for every removed setting. Settings the plugin still holds keep their value but are no longer found or ranged over by the Set, so they must not be used after Close.
+func (sc *Scope) Close() error {
+	sc.mu.Lock()
+	if sc.closed {
+		sc.mu.Unlock()
+		return nil
+	}
+	sc.closed = true
+	handles := sc.handles
+	sc.handles = nil
+	sc.mu.Unlock()
+
+	for _, h := range handles {
+		_ = h.Close()
+	}
+
+	sc.set.remove()
+
+	return nil
+}
+
+// remove the Set, its subsets and their settings from the tree
+func (s *Set) remove() {
+	root := s.Root()
+	start := time.Now()
+
+	var removed []string
+	root.settings.Range(func(key, value interface{}) bool {
+		setting := value.(*Setting)
+		if s.contains(setting.Path) {
+			root.settings.Delete(key)
+			atomic.AddInt32(&root.settingCount, -1)
+			removed = append(removed, setting.Path)
+			setting.notifiers.Range(func(k, _ interface{}) bool {
+				setting.notifiers.Delete(k)
+				return true
+			})
+		}
+		return true
+	})
+
+	root.children.Range(func(key, value interface{}) bool {
+		set := value.(*Set)
+		if s.contains(set.path) {
+			root.children.Delete(key)
+			set.notifiers.Range(func(k, _ interface{}) bool {
+				set.notifiers.Delete(k)
+				return true
+			})
+		}
+		return true
+	})
+
+	root.mu.Lock()
+	// a new slice, as the bindings are ranged over outside the lock
+	var bindings []*mapBinding
+	for _, b := range root.mapBindings {
+		if !s.contains(b.set.path) {
+			bindings = append(bindings, b)
+		}
+	}
+	root.mapBindings = bindings
+
+	// providers, templates and changes held for the removed settings must not write into it later
+	var providers []*registeredProvider
+	for _, rp := range root.providers {
+		if !s.contains(rp.set.path) {
+			providers = append(providers, rp)
+		}
+	}
+	root.providers = providers
+
+	var templates []*settingTemplate
+	for _, t := range root.templates {
+		if !s.contains(t.prototype.Path) {
+			templates = append(templates, t)
+		}
+	}
+	root.templates = templates
+
+	for id, r := range root.approvals {
+		if s.contains(r.setting.Path) {
+			delete(root.approvals, id)
+		}
+	}
+	for key, p := range root.pendingRestart {
+		if s.contains(p.setting.Path) {
+			delete(root.pendingRestart, key)
+		}
+	}
+	root.mu.Unlock()
+
+	sort.Strings(removed)
+	for _, path := range removed {
+		s.observe(OpRemove, path, "", start, nil)
+	}
+
+	s.trace("remove", s.path, "removed subset and its settings")
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSet_Scope(t *testing.T) {
+	set := &Set{}
+	host := set.Setting("Debug", false, "")
+
+	scope := set.Scope("PluginX")
+	scope.Set().Setting("Endpoint", "http://localhost", "")
+	scope.Set().Subset("Retry").Setting("Max", 3, "")
+
+	var pluginNotified, hostNotified int
+	scope.Set().Get("Endpoint").Notify(NotifyFunc(func(*Setting) { pluginNotified++ }))
+	scope.Track(host.Notify(NotifyFunc(func(*Setting) { hostNotified++ })))
+
+	if set.Get("PluginX.Retry.Max") == nil {
+		t.Fatal("Failed to register scoped setting")
+	}
+
+	if err := scope.Close(); err != nil {
+		t.Fatalf("Failed to close scope: %v", err)
+	}
+
+	if set.Get("PluginX.Endpoint") != nil || set.Get("PluginX.Retry.Max") != nil {
+		t.Error("Failed to remove scoped settings")
+	}
+
+	var paths []string
+	set.Range(func(_ string, setting *Setting) bool {
+		paths = append(paths, setting.Path)
+		return true
+	})
+	if len(paths) != 1 || paths[0] != "Debug" {
+		t.Errorf("Failed to range remaining settings: got %v", paths)
+	}
+
+	_ = host.Set("true")
+	if hostNotified != 0 {
+		t.Errorf("Failed to stop tracked notifier: notified %d times", hostNotified)
+	}
+
+	// the scope can be loaded again once closed
+	set.Scope("PluginX").Set().Setting("Endpoint", "http://remote", "")
+	if v := set.Get("PluginX.Endpoint").Value; v != "http://remote" {
+		t.Errorf("Failed to register after close: expected %q; got %q", "http://remote", v)
+	}
+
+	if pluginNotified != 0 {
+		t.Errorf("Failed to remove scoped notifier: notified %d times", pluginNotified)
+	}
+
+	if err := scope.Close(); err != nil {
+		t.Errorf("Failed to close scope twice: %v", err)
+	}
+}
+
+func TestScope_CloseProviders(t *testing.T) {
+	set := &Set{}
+	set.Setting("Debug", false, "")
+
+	scope := set.Scope("PluginX")
+	scope.Set().Template("Queues.*.Size", 10, "")
+	workers := scope.Set().Setting("Workers", 1, "")
+	workers.RestartRequired = true
+	scope.Set().AddProvider("plugin", ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"Queues.jobs.Size": "20", "Workers": "4"}, nil
+	}))
+
+	set.Started()
+	if err := set.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if len(set.PendingRestart()) != 1 {
+		t.Fatalf("Failed to hold restart: got %v", set.PendingRestart())
+	}
+
+	if err := scope.Close(); err != nil {
+		t.Fatalf("Failed to close scope: %v", err)
+	}
+
+	if health := set.Health(); len(health) != 0 {
+		t.Errorf("Failed to remove scoped provider: got %v", health)
+	}
+	if templates := set.Templates(); len(templates) != 0 {
+		t.Errorf("Failed to remove scoped template: got %v", templates)
+	}
+	if pending := set.PendingRestart(); len(pending) != 0 {
+		t.Errorf("Failed to drop pending restart: got %v", pending)
+	}
+
+	if err := set.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload after close: %v", err)
+	}
+	set.Range(func(_ string, setting *Setting) bool {
+		if setting.Path != "Debug" {
+			t.Errorf("Failed to keep reload out of closed scope: got %s", setting.Path)
+		}
+		return true
+	})
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Search returns the settings within the Set matching the query, best matches first. Every word of the query must match the path, name or description of a setting, case insensitive. Substring matches of the path and name rank above matches of the description, and a word whose letters appear in order in the path (i.e. hmc for HTTP.MaxConns) is a fuzzy match ranked last. An empty query matches nothing.
+func (s *Set) Search(query string) []*Setting {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return nil
+	}
+
+	type match struct {
+		setting *Setting
+		score   int
+	}
+
+	var matches []match
+	s.Range(func(_ string, setting *Setting) bool {
+		total := 0
+		for _, word := range words {
+			score := searchScore(setting, word)
+			if score == 0 {
+				return true
+			}
+			total += score
+		}
+
+		matches = append(matches, match{setting: setting, score: total})
+		return true
+	})
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].setting.Path < matches[j].setting.Path
+	})
+
+	settings := make([]*Setting, 0, len(matches))
+	for _, m := range matches {
+		settings = append(settings, m.setting)
+	}
+
+	return settings
+}
+
+// searchScore ranks how well the lower case word matches the setting, 0 when it does not match
+func searchScore(setting *Setting, word string) int {
+	path := strings.ToLower(setting.Path)
+	name := strings.ToLower(setting.Name)
+
+	switch {
+	case path == word || name == word:
+		return 100
+	case strings.HasPrefix(name, word):
+		return 75
+	case strings.Contains(path, word):
+		return 50
+	case strings.Contains(strings.ToLower(setting.Description), word):
+		return 20
+	case subsequence(path, word):
+		return 10
+	}
+
+	return 0
+}
+
+// subsequence returns if the letters of word appear in order in s
+func subsequence(s, word string) bool {
+	for _, r := range word {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+
+	return true
+}
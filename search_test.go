@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSet_Search(t *testing.T) {
+	s := &Set{}
+	http := s.Subset("HTTP")
+	http.Setting("Port", 8080, "Port the server listens on")
+	http.Setting("MaxConns", 100, "Maximum concurrent connections")
+	s.Subset("Database").Setting("Port", 5432, "Port of the database server")
+	s.Setting("Debug", false, "Enable verbose logging")
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "port", expected: []string{"Database.Port", "HTTP.Port"}},
+		{query: "http port", expected: []string{"HTTP.Port"}},
+		{query: "connections", expected: []string{"HTTP.MaxConns"}},
+		{query: "hmc", expected: []string{"HTTP.MaxConns"}},
+		{query: "server", expected: []string{"Database.Port", "HTTP.Port"}},
+		{query: "verbose DEBUG", expected: []string{"Debug"}},
+		{query: "", expected: nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, setting := range s.Search(tt.query) {
+			got = append(got, setting.Path)
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+			t.Errorf("Failed to search %q: expected %v; got %v", tt.query, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSecretSource is returned when a masked setting is supplied by a source the SecretPolicy of the Set does not allow
+var ErrSecretSource = errors.New("secret from disallowed source")
+
+// SecretPolicy decides whether the source may supply the value of a masked setting. The source is SourceEnv for Set.LoadEnv, SourceFlag for command line flags, the provider name for Set.Reload, or the file path for Set.LoadFile. Direct writes by the application (i.e. Setting.Set) are never checked.
+type SecretPolicy interface {
+	AllowSecret(setting *Setting, source string) error
+}
+
+// SecretPolicyFunc defines a function that decides whether the source may supply the masked setting
+type SecretPolicyFunc func(setting *Setting, source string) error
+
+// AllowSecret implements SecretPolicy.AllowSecret
+func (f SecretPolicyFunc) AllowSecret(setting *Setting, source string) error {
+	return f(setting, source)
+}
+
+// EnvOnlySecrets returns a SecretPolicy allowing masked settings to be supplied only by the environment (Set.LoadEnv) and the named providers (i.e. a secret manager), so secrets can never be read from files or passed as command line flags where they leak into shell history and process listings
+func EnvOnlySecrets(providers ...string) SecretPolicy {
+	return SecretPolicyFunc(func(setting *Setting, source string) error {
+		if source == SourceEnv {
+			return nil
+		}
+
+		for _, name := range providers {
+			if strings.EqualFold(name, source) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: %s can not be supplied by %q", ErrSecretSource, setting.Path, source)
+	})
+}
+
+// RestrictSecrets sets the SecretPolicy consulted whenever a masked setting of the Set tree is supplied by a source, and by Set.ValidateFile, such as EnvOnlySecrets. A nil SecretPolicy allows every source.
+func (s *Set) RestrictSecrets(p SecretPolicy) {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	root.secretPolicy = p
+}
+
+// allowSecret returns the error of the SecretPolicy of the Set when the setting is masked and the source is not allowed to supply it
+func (s *Set) allowSecret(setting *Setting, source string) error {
+	if !setting.Mask || source == "" || source == SourceDefault || source == SourceDerived {
+		return nil
+	}
+
+	root := s.Root()
+	root.mu.Lock()
+	policy := root.secretPolicy
+	root.mu.Unlock()
+
+	if policy == nil {
+		return nil
+	}
+
+	return policy.AllowSecret(setting, source)
+}
+
+// flagValue is the flag.Value of a Setting, so values from the command line are supplied with SourceFlag
+type flagValue struct {
+	setting *Setting
+}
+
+// String implements flag.Value.String
+func (f *flagValue) String() string {
+	if f.setting == nil {
+		return ""
+	}
+
+	return f.setting.String()
+}
+
+// Set implements flag.Value.Set
+func (f *flagValue) Set(v string) error {
+	return f.setting.setFrom(context.Background(), v, SourceFlag)
+}
+
+// IsBoolFlag implements the optional boolFlag interface of the flag package
+func (f *flagValue) IsBoolFlag() bool {
+	return f.setting != nil && f.setting.IsBoolFlag()
+}
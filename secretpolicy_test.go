@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet_RestrictSecrets(t *testing.T) {
+	set := &Set{}
+	password := set.Setting("Password", "", "Database password")
+	password.Mask = true
+	host := set.Setting("Host", "localhost", "Database host")
+
+	set.RestrictSecrets(EnvOnlySecrets("vault"))
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"Password": "hunter2", "Host": "db"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := set.LoadFile(path); !errors.Is(err, ErrSecretSource) {
+		t.Errorf("Failed to reject secret from file: got %v", err)
+	}
+
+	var validationErr *ValidationError
+	if err := set.ValidateFile(path); !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 || !errors.Is(validationErr.Errors[0], ErrSecretSource) {
+		t.Errorf("Failed to report secret from file: got %v", err)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	password.Flag("password", fs)
+	host.Flag("host", fs)
+	if err := fs.Parse([]string{"-host", "flagged", "-password", "hunter2"}); err == nil || password.Value != "" {
+		t.Errorf("Failed to reject secret from flag: got %v", err)
+	}
+
+	if host.String() != "flagged" {
+		t.Errorf("Failed to set unmasked setting from flag: expected %q; got %q", "flagged", host.String())
+	}
+
+	t.Setenv("APP_PASSWORD", "from-env")
+	if err := set.LoadEnv("APP"); err != nil {
+		t.Errorf("Failed to load secret from env: %v", err)
+	}
+
+	set.AddProvider("vault", ProviderFunc(func(context.Context) (map[string]string, error) {
+		return map[string]string{"Password": "from-vault"}, nil
+	}))
+	if err := set.Reload(context.Background()); err != nil {
+		t.Errorf("Failed to load secret from allowed provider: %v", err)
+	}
+
+	if err := password.Set("direct"); err != nil {
+		t.Errorf("Failed to set secret directly: %v", err)
+	}
+
+	set.RestrictSecrets(nil)
+	if err := set.LoadFile(path); err != nil {
+		t.Errorf("Failed to load secret from file without policy: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_SelfConfig(t *testing.T) {
+	remote := writeFiles(t, map[string]string{"remote.json": `{"DB": {"Password": "from-remote", "User": "admin"}}`})
+	dir := writeFiles(t, map[string]string{"config.json": `{"Config": {"Providers": [{"Name": "remote", "URL": "file://` + filepath.Join(remote, "remote.json") + `"}], "Mask": "db.*"}}`})
+
+	cfg := struct {
+		DB struct {
+			User     string
+			Password string
+		}
+		Name string
+	}{}
+
+	set := &Set{}
+	set.Bind(&cfg)
+	self := set.SelfConfig()
+
+	if interval := self.Poller().Interval; interval != time.Minute {
+		t.Errorf("Failed to default poll interval: expected %s; got %s", time.Minute, interval)
+	}
+
+	t.Setenv("APP_CONFIG_POLL_INTERVAL", "30s")
+	t.Setenv("APP_CONFIG_SECRET_SOURCES", "vault")
+
+	if err := set.LoadFile(filepath.Join(dir, "config.json")); err != nil {
+		t.Fatalf("Failed to load file: %v", err)
+	}
+	if err := set.LoadEnv("APP"); err != nil {
+		t.Fatalf("Failed to load env: %v", err)
+	}
+
+	if err := set.ApplySelfConfig(self); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+
+	if interval := self.Poller().Interval; interval != 30*time.Second {
+		t.Errorf("Failed to configure poll interval: expected %s; got %s", 30*time.Second, interval)
+	}
+
+	if !set.Get("DB.Password").Mask || !set.Get("DB.User").Mask || set.Get("Name").Mask {
+		t.Error("Failed to mask settings matching Config.Mask")
+	}
+
+	if url := set.Get("Config.Providers.0.URL"); url == nil || !url.Mask {
+		t.Error("Failed to mask provider URL")
+	}
+
+	// masked settings are only allowed from the environment and vault
+	var reloadErr *ReloadError
+	if err := set.Reload(context.Background()); !errors.As(err, &reloadErr) || !errors.Is(reloadErr.Errors[0].Err, ErrSecretSource) {
+		t.Errorf("Failed to restrict secret sources: got %v", err)
+	}
+
+	if err := set.ApplySelfConfig(self); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Failed to reject provider added twice: got %v", err)
+	}
+
+	invalid := &SelfConfig{Providers: []ProviderConfig{{Name: "other", URL: "keyring://app"}}}
+	invalid.Poll.Interval = time.Second
+	if err := set.ApplySelfConfig(invalid); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("Failed to reject unknown scheme: expected %v; got %v", ErrUnknownScheme, err)
+	}
+	if err := set.ApplySelfConfig(&SelfConfig{}); err == nil {
+		t.Error("Failed to reject zero poll interval")
+	}
+}
@@ -1,29 +1,101 @@
 package config
 
 import (
+	"context"
+	"crypto/ed25519"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"reflect"
-	"sort"
 	"strings"
 	"sync"
-	"text/tabwriter"
+	"sync/atomic"
+	"time"
 )
 
 // Set defines a composite collection of configuration
 type Set struct {
+	// accessed atomically, kept first for 64-bit alignment on 32-bit platforms
+	revision uint64
+
 	name      string
 	path      string
 	root      *Set
 	parent    *Set
+	mounted   bool
 	children  sync.Map
 	settings  sync.Map
 	notifiers sync.Map
+	tenants   sync.Map
+
+	mu        sync.Mutex
+	viewMu    sync.RWMutex
+	providers []*registeredProvider
+	tracer    atomic.Value
+	hooks     sync.Map
+	hookCount int32
+
+	settingCount    int32
+	historySize     int32
+	strictFloats    int32
+	trackProvenance int32
+	silenced        int32
+	started         int32
+	limitValues     atomic.Value
+	guardValues     atomic.Value
+	internValue     atomic.Value
+	envPrefix       atomic.Value
+	auditValue      atomic.Value
+	latencyValue    atomic.Value
+	clockValue      atomic.Value
+
+	// guarded by mu
+	signatureKeys  []ed25519.PublicKey
+	authorizer     Authorizer
+	persister      *Persister
+	resolver       *readThrough
+	templates      []*settingTemplate
+	mapBindings    []*mapBinding
+	secretPolicy   SecretPolicy
+	catalog        Catalog
+	pendingRestart map[string]*pendingChange
+	epochID        string
+	loads          []*LoadTiming
+	approvals      map[string]*approvalRequest
+	approvalHook   func(ApprovalRequest)
+
+	// guarded by the mu of the Set rather than the root, see Set.Silence
+	silencedChanges []*Setting
+	silencedAt      time.Time
 }
 
-// Get a setting by name
+// Get a setting by name, the setting is recorded as read (see Setting.Reads and Set.Unread)
 func (s *Set) Get(name string) *Setting {
+	return s.GetContext(context.Background(), name)
+}
+
+// GetContext gets a setting by name like Set.Get, the ctx bounds the lookup of a Resolver (see Set.ReadThrough)
+func (s *Set) GetContext(ctx context.Context, name string) *Setting {
+	start := time.Now()
+
+	setting := s.resolve(ctx, name, s.lookup(name))
+	if setting == nil {
+		s.observe(OpGet, name, "", start, ErrUnknownSetting)
+		return nil
+	}
+
+	atomic.AddUint64(&setting.reads, 1)
+	atomic.StoreInt64(&setting.lastRead, start.UnixNano())
+	setting.auditSecret("Set.Get")
+
+	s.observe(OpGet, setting.Path, "", start, nil)
+
+	return setting
+}
+
+// lookup a setting by name without recording it as read
+func (s *Set) lookup(name string) *Setting {
 	root := s.root
 	if root == nil {
 		root = s
@@ -38,12 +110,53 @@ func (s *Set) Get(name string) *Setting {
 		return setting.(*Setting)
 	}
 
+	// dynamic keyspaces materialize on first use, resolved in the same order
+	if setting := s.materialize(name); setting != nil {
+		return setting
+	}
+	if s.path != "" {
+		if setting := s.materialize(path); setting != nil {
+			return setting
+		}
+	}
+
+	s.trace("miss", name, "setting %q not found in %q", name, s.path)
+
+	return nil
+}
+
+// peek returns the setting by name like lookup, but resolves dynamic keyspaces to a detached setting rather than materializing it, so nothing is bound or registered
+func (s *Set) peek(name string) *Setting {
+	root := s.Root()
+
+	if setting, found := root.settings.Load(strings.ToLower(name)); found {
+		return setting.(*Setting)
+	}
+
+	path := s.pathOf(name)
+	if setting, found := root.settings.Load(strings.ToLower(path)); found {
+		return setting.(*Setting)
+	}
+
+	for _, candidate := range []string{name, path} {
+		if setting, beneath := s.peekMap(candidate); beneath {
+			return setting
+		}
+	}
+
+	if setting := s.peekTemplate(name); setting != nil {
+		return setting
+	}
+	if s.path != "" {
+		return s.peekTemplate(path)
+	}
+
 	return nil
 }
 
 // Update an existing setting by name. This is useful to populate from command line and/or environment, etc...
 func (s *Set) Update(name, value string) (bool, error) {
-	setting := s.Get(name)
+	setting := s.lookup(name)
 	if setting == nil {
 		return false, nil
 	}
@@ -67,6 +180,10 @@ func (s *Set) Subset(name string) *Set {
 		return set.(*Set)
 	}
 
+	if limits := root.limits(); limits.MaxDepth > 0 && s.depth() >= limits.MaxDepth {
+		panic((&LimitError{Limit: "MaxDepth", Path: subsetPath, Max: limits.MaxDepth}).Error())
+	}
+
 	set := &Set{
 		name:   name,
 		path:   subsetPath,
@@ -84,6 +201,20 @@ func (s *Set) Path() string {
 	return s.path
 }
 
+// pathOf returns the full path of the name within the current Set
+func (s *Set) pathOf(name string) string {
+	if s.path == "" {
+		return name
+	}
+
+	return s.path + "." + name
+}
+
+// Revision returns the number of changes applied to the Set tree, incremented for every setting that changes value. Clients of admin surfaces compare revisions to detect missed updates, see Setting.Revision.
+func (s *Set) Revision() uint64 {
+	return atomic.LoadUint64(&s.Root().revision)
+}
+
 // Name of the current set
 func (s *Set) Name() string {
 	return s.name
@@ -109,11 +240,26 @@ func (s *Set) Parent() *Set {
 
 // Setting will create a new setting with the specified name, value, and description in the current Set. Name can not be empty, value can not be nil
 func (s *Set) Setting(name string, value Value, description string) *Setting {
+	setting, err := s.Register(name, value, description)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return setting
+}
+
+// Register creates a new setting like Set.Setting, returning an error rather than panicking when the setting can not be created. This is intended for hosts registering settings on behalf of plugins, where an error wrapping ErrLimitExceeded is returned once the Limits of the Set are reached.
+func (s *Set) Register(name string, value Value, description string) (*Setting, error) {
+	return s.register(name, value, description, nil)
+}
+
+// register the setting like Set.Register, configure is called with the setting before it is visible to anyone else
+func (s *Set) register(name string, value Value, description string, configure func(*Setting)) (*Setting, error) {
 	if name == "" {
-		panic("name can not be empty")
+		return nil, errors.New("name can not be empty")
 	}
 	if value == nil {
-		panic("value can not be nil")
+		return nil, errors.New("value can not be nil")
 	}
 
 	root := s.root
@@ -126,28 +272,60 @@ func (s *Set) Setting(name string, value Value, description string) *Setting {
 		settingPath = name
 	}
 
+	limits := root.limits()
+	if limits.MaxDepth > 0 && s.depth() > limits.MaxDepth {
+		return nil, &LimitError{Limit: "MaxDepth", Path: settingPath, Max: limits.MaxDepth}
+	}
+
 	setting := &Setting{
 		Name:        name,
 		Description: description,
 		Path:        settingPath,
 		Value:       value,
+		set:         s,
 	}
 
 	// cheeky allows the underlying thing to actually map it properly
-	setting.DefaultValue = setting.String()
+	setting.DefaultValue = s.intern(setting.format())
+
+	if configure != nil {
+		configure(setting)
+	}
+
+	if count := atomic.AddInt32(&root.settingCount, 1); limits.MaxSettings > 0 && int(count) > limits.MaxSettings {
+		atomic.AddInt32(&root.settingCount, -1)
+		return nil, &LimitError{Limit: "MaxSettings", Path: settingPath, Max: limits.MaxSettings}
+	}
 
 	_, exists := root.settings.LoadOrStore(strings.ToLower(settingPath), setting)
 	if exists {
-		panic(fmt.Sprintf("setting %q already exists", settingPath))
+		atomic.AddInt32(&root.settingCount, -1)
+		return nil, fmt.Errorf("setting %q already exists", settingPath)
 	}
 
-	// get notified when the setting changes - we won't stop notifications as long as it is a child, and since there is no remove.... we just discard the Close handler
-	_ = setting.Notify(NotifyFunc(s.notifyChanged))
+	s.trace("register", settingPath, "registered %T with default %q", value, setting.display(setting.DefaultValue))
+	s.observe(OpRegister, settingPath, "", time.Now(), nil)
+
+	// changes of the setting are propagated to this Set by Setting.Set
 
 	// notify that we have added something (a change) after returning
 	defer s.notifyChanged(setting)
 
-	return setting
+	return setting, nil
+}
+
+// registerPath registers a setting at the full path, creating the subsets along the way, see Set.register for configure
+func (s *Set) registerPath(path string, value Value, description string, configure func(*Setting)) (*Setting, error) {
+	set := s
+	name := path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		for _, segment := range strings.Split(path[:i], ".") {
+			set = set.Subset(segment)
+		}
+		name = path[i+1:]
+	}
+
+	return set.register(name, value, description, configure)
 }
 
 // Range over the settings in the entire Set
@@ -161,7 +339,7 @@ func (s *Set) Range(fn func(string, *Setting) bool) {
 		key := k.(string)
 		setting := v.(*Setting)
 
-		if !strings.HasPrefix(key, s.path) {
+		if !s.contains(setting.Path) {
 			return true
 		}
 
@@ -169,14 +347,33 @@ func (s *Set) Range(fn func(string, *Setting) bool) {
 	})
 }
 
-// Bind the Pointer to a Struct. This will take all of the fields and attempt to create settings from them. Any child structs will be set in a subset of the parent struct by name. All fields will be passed into the Set.Setting() function as pointers so that the Set.Set() function can write to the underlying value.
+// Bind the Pointer to a Struct. This will take all of the fields and attempt to create settings from them. Any child structs will be set in a subset of the parent struct by name. All fields will be passed into the Set.Setting() function as pointers so that the Set.Set() function can write to the underlying value. Fields of type map[string]Struct and []Struct (or their *Struct forms) are a subset named after the field with a subset per key or index, keys and elements present are bound immediately and others when a path beneath them is first used (i.e. by Set.LoadFile or Set.LoadEnv), allowing one block per named section or list item in a document.
 //
 // Fields names can be overwritten with the `setting` field tag.
 //
-// Descriptions on settings can be set with the `description` field tag.
+// Descriptions on settings can be set with the `description` field tag, and related settings grouped with the `category` field tag.
+//
+// The role required to write the setting through admin surfaces can be set with the `role` field tag, see Set.UpdateContext.
+//
+// A constraint expression the value must satisfy can be set with the `constraint` field tag (i.e. `constraint:"this <= Pool.Max"`), see Setting.Constraint.
+//
+// Annotations for downstream tooling can be set with the `meta` field tag as comma separated key=value pairs (i.e. `meta:"owner=payments,ticket=OPS-42"`).
+//
+// Labels for Set.Select can be set with the `labels` field tag as a comma separated list (i.e. `labels:"restart-required,team:payments"`).
 //
 // You can mask the Stringer of the setting (set it to output *****) by setting the field tag `mask:"true"`. This is really important to do to passwords/tokens/etc... to make sure they don't end up in logs.
-func (s *Set) Bind(value interface{}) *Set {
+//
+// The tag keys can be remapped with the WithTags option.
+//
+// Pointers to values (i.e. proto3 optional fields) are settings that are nil while unset, and nil pointers to structs are allocated to be bound unless the struct contains itself. Generated protobuf messages are bound by their exported fields: XXX_ fields are skipped, the fields of the member set in a oneof are bound in place, and Duration, Timestamp and the wrappers of the well-known types are settings of their value while other well-known types are skipped.
+func (s *Set) Bind(value interface{}, opts ...BindOption) *Set {
+	defer s.observe(OpBind, s.path, "", time.Now(), nil)
+
+	return s.bind(value, newBindOptions(opts))
+}
+
+// bind the pointer to a struct with the resolved options
+func (s *Set) bind(value interface{}, opts *bindOptions) *Set {
 	rvalue := reflect.ValueOf(value)
 
 	if rvalue.Kind() != reflect.Ptr {
@@ -194,97 +391,172 @@ func (s *Set) Bind(value interface{}) *Set {
 		fieldValue := rvalue.Field(i)
 
 		if !fieldValue.CanSet() {
+			s.trace("skip", s.pathOf(fieldType.Name), "field %q of %s is not settable", fieldType.Name, rvalue.Type())
 			continue
 		}
 
-		description := fieldType.Tag.Get("description")
-		name := fieldType.Name
-		masked := fieldType.Tag.Get("mask") == "true"
-		flagName := fieldType.Tag.Get("flag")
+		// generated protobuf messages keep their bookkeeping in exported XXX_ fields
+		if strings.HasPrefix(fieldType.Name, "XXX_") {
+			s.trace("skip", s.pathOf(fieldType.Name), "field %q of %s is internal to protobuf", fieldType.Name, rvalue.Type())
+			continue
+		}
 
-		if tagName := fieldType.Tag.Get("setting"); tagName != "" {
+		name := fieldType.Name
+		if tagName := tagName(fieldType.Tag.Get(opts.tags.Setting)); tagName != "" {
 			name = tagName
+		} else if opts.mapper != nil {
+			name = opts.mapper(fieldType)
 		}
 
 		if name == "-" {
+			s.trace("skip", s.pathOf(fieldType.Name), "field %q of %s is ignored by tag", fieldType.Name, rvalue.Type())
+			continue
+		}
+
+		// maps and slices of structs are subsets per key, materialized from loaded data
+		if kind := fieldValue.Kind(); (kind == reflect.Map || kind == reflect.Slice) && s.bindMap(name, fieldValue, opts) {
 			continue
 		}
 
 		switch rvalue.Field(i).Kind() {
 		case reflect.Invalid, reflect.Chan, reflect.Func:
-			// do nothing
+			s.trace("skip", s.pathOf(name), "field %q of %s has unsupported kind %s", fieldType.Name, rvalue.Type(), fieldValue.Kind())
+			logger().Warn("field not bound", "path", s.pathOf(name), "field", fieldType.Name, "kind", fieldValue.Kind().String())
+
+		case reflect.Interface:
+			// the fields of the member set in a protobuf oneof are bound in place, as they are in the message
+			if member := oneofMember(fieldType, fieldValue); member.IsValid() {
+				s.bind(member.Interface(), opts.nested(rvalue.Type()))
+				break
+			}
+
+			s.trace("skip", s.pathOf(name), "field %q of %s is an interface or an unset oneof", fieldType.Name, rvalue.Type())
+
+		case reflect.Struct:
+			// structs that know how to unmarshal themselves are settings, not children
+			if _, ok := fieldValue.Addr().Interface().(Unmarshaler); !ok && !valueTypes[fieldValue.Type()] {
+				// if the thing is a struct, pass it through as a child
+				s.Subset(name).bind(fieldValue.Addr().Interface(), opts.nested(rvalue.Type()))
+				break
+			}
+
+			fallthrough
 
 		case reflect.Ptr:
 			// if the thing is a pointer, then call this as a child
-			s.Subset(name).Bind(fieldValue.Interface())
+			if fieldValue.Kind() == reflect.Ptr && !valueTypes[fieldValue.Type()] {
+				elem := fieldValue.Type().Elem()
+
+				// pointers to values (i.e. proto3 optional fields) and protobuf well-known types are settings that are nil while unset
+				if value, known := wellKnownValue(fieldValue); known {
+					if value == nil {
+						s.trace("skip", s.pathOf(name), "field %q of %s is an unsupported protobuf well-known type", fieldType.Name, rvalue.Type())
+						break
+					}
+
+					s.bindSetting(name, value, fieldType.Tag, opts)
+					break
+				}
+				if elem.Kind() != reflect.Struct {
+					s.bindSetting(name, newPointerValue(fieldValue), fieldType.Tag, opts)
+					break
+				}
+
+				// nil children (i.e. unset protobuf messages) are allocated to be bound, unless they recurse
+				if fieldValue.IsNil() {
+					if elem == rvalue.Type() || opts.within(elem) {
+						s.trace("skip", s.pathOf(name), "field %q of %s is a nil recursive %s", fieldType.Name, rvalue.Type(), elem)
+						break
+					}
+
+					fieldValue.Set(reflect.New(elem))
+				}
+
+				s.Subset(name).bind(fieldValue.Interface(), opts.nested(rvalue.Type()))
+				break
+			}
 
-		case reflect.Struct:
-			// if the thing is a struct, pass it through as a child
-			s.Subset(name).Bind(fieldValue.Addr().Interface())
+			fallthrough
 
 		default:
 			// all other field types we pass in the pointer to the value as a setting so that it is "bound"
-			setting := s.Setting(name, fieldValue.Addr().Interface(), description)
-			setting.Mask = masked
-
-			// does it have a flag?
-			if flagName != "" {
-				setting.Flag(flagName, flag.CommandLine)
-			}
+			s.bindSetting(name, fieldValue.Addr().Interface(), fieldType.Tag, opts)
 		}
 	}
 
 	return s
 }
 
-// Dump the current settings to the specified io.Writer in a tab separated list
-func (s *Set) Dump(w io.Writer) error {
-	tw := tabwriter.NewWriter(w, 10, 10, 5, ' ', 0)
-
-	settings := []*Setting{}
-	s.Range(func(path string, setting *Setting) bool {
-		settings = append(settings, setting)
-		return true
-	})
-
-	// sort by name
-	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
-
-	// print header
-	fmt.Fprintln(tw, "Path\tType\tValue\tDefault Value\tDescription")
+// bindSetting registers the bound value as a setting configured by the field tags
+func (s *Set) bindSetting(name string, value Value, tag reflect.StructTag, opts *bindOptions) {
+	setting := s.Setting(name, value, tag.Get(opts.tags.Description))
+	setting.Mask = tag.Get(opts.tags.Mask) == "true"
+	setting.Category = tag.Get(opts.tags.Category)
+	setting.Required = tag.Get(opts.tags.Required) == "true"
+	setting.Role = tag.Get(opts.tags.Role)
+	setting.Constraint = tag.Get(opts.tags.Constraint)
+	setting.Annotations = parseAnnotations(tag.Get(opts.tags.Annotations))
+	setting.Labels = parseLabels(tag.Get(opts.tags.Labels))
+
+	// does it have a flag?
+	if flagName := tag.Get(opts.tags.Flag); flagName != "" {
+		setting.Flag(flagName, flag.CommandLine)
+	}
+}
 
-	// print items
-	for _, setting := range settings {
-		if setting.Mask {
-			fmt.Fprintf(tw, "%s\t%T\t%q\t\"*****\"\t%s\n", setting.Path, setting.Value, setting.String(), setting.Description)
-		} else {
-			fmt.Fprintf(tw, "%s\t%T\t%q\t%q\t%s\n", setting.Path, setting.Value, setting.String(), setting.DefaultValue, setting.Description)
-		}
+// Dump the current settings of the Set, for a subset only its descendants, to the specified io.Writer in a tab separated list. The columns, format and masking can be configured with DumpWith.
+func (s *Set) Dump(w io.Writer, opts ...DumpOption) error {
+	options := &dumpOptions{}
+	for _, opt := range opts {
+		opt(options)
 	}
 
-	return tw.Flush()
+	return s.dump(w, options)
 }
 
 // Notify when any of the settings in this set, or any child set is added or changed
-func (s *Set) Notify(n Notifier) *NotifyHandle {
+func (s *Set) Notify(n Notifier, opts ...NotifyOption) *NotifyHandle {
 	if n == nil {
 		return &NotifyHandle{}
 	}
 
+	options := newNotifyOptions(opts)
+	if options.name != "" {
+		n = namedNotifier{Notifier: n, name: options.name}
+	}
+
 	handle := &NotifyHandle{
 		stopFunc: s.notifiers.Delete,
 	}
 
 	s.notifiers.Store(handle, n)
 
+	if options.current {
+		for _, setting := range s.sorted() {
+			s.dispatch(n, setting)
+		}
+	}
+
 	return handle
 }
 
-// notifyChanged is attached to all settings so that we can get notified of when they are added
+// NotifyContext registers the Notifier like Set.Notify until the ctx is done, at which point the handle is closed, tying the subscription to the lifetime of the goroutine owning the ctx
+func (s *Set) NotifyContext(ctx context.Context, n Notifier, opts ...NotifyOption) *NotifyHandle {
+	return notifyContext(ctx, func(n Notifier) *NotifyHandle { return s.Notify(n, opts...) }, n)
+}
+
+// Touch notifies for every setting within the Set without changing their values, see Setting.Touch
+func (s *Set) Touch() {
+	for _, setting := range s.sorted() {
+		setting.Touch()
+	}
+}
+
+// notifyChanged is called by all settings of the Set when they are added or changed
 func (s *Set) notifyChanged(setting *Setting) {
 	s.notifiers.Range(func(k, v interface{}) bool {
 		notifier := v.(Notifier)
-		notifier.Notify(setting)
+		s.dispatch(notifier, setting)
 		return true
 	})
 
@@ -1,11 +1,17 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -24,20 +30,58 @@ type Equality interface {
 	Equals(string) bool
 }
 
+// Redacter is the interface implemented by types that mask only their secret parts (i.e. the password of a DSN), used by Setting.String when the setting is not masked entirely.
+type Redacter interface {
+	Redacted() string
+}
+
 // Value is an interface for interacting with the underlying configuration value
 type Value interface{}
 
 // Setting within the configuration containing a Value
 type Setting struct {
+	// accessed atomically, kept first for 64-bit alignment on 32-bit platforms
+	reads       uint64
+	lastRead    int64
+	lastChanged int64
+	revision    uint64
+
 	// Mask will overwrite the String function to return ***** to protect from logging
 	Mask bool
 
+	// Required settings must be provided rather than left at their default, see Wizard
+	Required bool
+
+	// RestartRequired settings are only read when the process starts, changes after Set.Started are accepted but pending until a restart (see Set.PendingRestart). The label restart-required has the same effect.
+	RestartRequired bool
+
 	// Name of the value
 	Name string
 
 	// Description of this setting, useful for help text
 	Description string
 
+	// Descriptions of this setting localized by locale (i.e. fr or pt-BR), see Setting.Describe
+	Descriptions map[string]string
+
+	// Category groups related settings (i.e. Networking, Observability) in help output independent of their subset
+	Category string
+
+	// Role required to write the setting through admin surfaces, see Set.UpdateContext
+	Role string
+
+	// Constraint is an expression the value must satisfy (i.e. this <= Pool.Max), evaluated by Setting.Set and Set.ValidateFile, see ConstraintError
+	Constraint string
+
+	// Annotations are arbitrary metadata for downstream tooling (i.e. owner, ticket links or UI hints), carried through Set.Dump, Set.WriteSchema and WatchServer
+	Annotations map[string]string
+
+	// NotifyUnchanged notifies on every successful Setting.Set, even when the value is unchanged, for consumers with reapply semantics
+	NotifyUnchanged bool
+
+	// Labels classify the setting (i.e. restart-required or team:payments) for Set.Select
+	Labels []string
+
 	// DefaultValue of the Setting as a string
 	DefaultValue string
 
@@ -47,7 +91,19 @@ type Setting struct {
 	// Value of the setting
 	Value Value
 
-	notifiers sync.Map
+	set          *Set
+	notifiers    sync.Map
+	writeMu      sync.Mutex
+	dependencies []string
+	history      history
+	provenance   provenance
+	source       atomic.Value
+	validator    func(string) error
+	immutable    bool
+
+	readyMu   sync.Mutex
+	ready     chan struct{}
+	populated bool
 }
 
 // IsDefault will return if the value matches the default value specified in Setting.DefaultValue
@@ -55,25 +111,247 @@ func (s *Setting) IsDefault() bool {
 	return s.Equals(s.DefaultValue)
 }
 
+// Reads returns the number of times the setting has been read with Set.Get
+func (s *Setting) Reads() uint64 {
+	return atomic.LoadUint64(&s.reads)
+}
+
+// LastRead returns the time the setting was last read with Set.Get, zero if it has never been read
+func (s *Setting) LastRead() time.Time {
+	nanos := atomic.LoadInt64(&s.lastRead)
+	if nanos == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nanos)
+}
+
+// LastChanged returns the time the value last changed, zero if it has never changed since it was registered
+func (s *Setting) LastChanged() time.Time {
+	nanos := atomic.LoadInt64(&s.lastChanged)
+	if nanos == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nanos)
+}
+
+// Revision returns the Set.Revision of the last change of the value, zero if it has never changed
+func (s *Setting) Revision() uint64 {
+	return atomic.LoadUint64(&s.revision)
+}
+
 // Notify provides a callback interface to when a setting has changed via Setting.Set
-func (s *Setting) Notify(n Notifier) *NotifyHandle {
+func (s *Setting) Notify(n Notifier, opts ...NotifyOption) *NotifyHandle {
 	if n == nil {
 		return &NotifyHandle{}
 	}
 
+	options := newNotifyOptions(opts)
+	if options.name != "" {
+		n = namedNotifier{Notifier: n, name: options.name}
+	}
+
 	handle := &NotifyHandle{
 		stopFunc: s.notifiers.Delete,
 	}
 
 	s.notifiers.Store(handle, n)
 
+	if options.current {
+		if s.set != nil {
+			s.set.dispatch(n, s)
+		} else {
+			n.Notify(s)
+		}
+	}
+
 	return handle
 }
 
+// NotifyContext registers the Notifier like Setting.Notify until the ctx is done, at which point the handle is closed, tying the subscription to the lifetime of the goroutine owning the ctx
+func (s *Setting) NotifyContext(ctx context.Context, n Notifier, opts ...NotifyOption) *NotifyHandle {
+	return notifyContext(ctx, func(n Notifier) *NotifyHandle { return s.Notify(n, opts...) }, n)
+}
+
 // Set the Value from the provided string
 func (s *Setting) Set(v string) error {
+	return s.setFrom(context.Background(), v, "")
+}
+
+// SetContext sets the Value from the provided string like Setting.Set, the ctx bounds the write to a writable provider (see Set.WriteThrough)
+func (s *Setting) SetContext(ctx context.Context, v string) error {
+	return s.setFrom(ctx, v, "")
+}
+
+// setFrom sets the Value from the provided string supplied by the source (i.e. a provider name or file), an empty source is a direct call to Setting.Set which is written through to a writable provider (see Set.WriteThrough)
+func (s *Setting) setFrom(ctx context.Context, v, source string) error {
+	return s.setIf(ctx, v, source, nil)
+}
+
+// setIf sets the Value like setFrom when the precondition, checked while no other write of the setting is in progress, returns nil. The error of a failed precondition is returned as is.
+func (s *Setting) setIf(ctx context.Context, v, source string, precondition func() error) (err error) {
+	if s.set != nil {
+		defer func(start time.Time) { s.set.observe(OpSet, s.Path, source, start, err) }(time.Now())
+		defer func() { s.supplied(v, source, err) }()
+	}
+
+	if s.set != nil {
+		if max := s.set.limits().MaxValueLength; max > 0 && len(v) > max {
+			return &LimitError{Limit: "MaxValueLength", Path: s.Path, Max: max}
+		}
+	}
+
+	if s.set != nil {
+		if err := s.set.allowSecret(s, source); err != nil {
+			return err
+		}
+	}
+
+	changed, err := s.apply(ctx, v, source, precondition)
+
+	// if not changed, then go ahead and exit the function and don't notify
+	if err != nil || (!changed && !s.NotifyUnchanged) {
+		return err
+	}
+
+	s.notify()
+
+	return nil
+}
+
+// Touch notifies every Notifier of the setting, and of the Sets it belongs to, without changing its value. This re-runs side effects (i.e. after a reload) even though the value did not change.
+func (s *Setting) Touch() {
+	s.notify()
+}
+
+// notify the notifiers of the setting and propagate to the Set it belongs to
+func (s *Setting) notify() {
+	// held back while the Set is silenced, see Set.Silence
+	if s.set != nil && s.set.hold(s) {
+		return
+	}
+
+	// notify those of changed value
+	s.notifiers.Range(func(key, val interface{}) bool {
+		f, ok := val.(Notifier)
+		if !ok || f == nil {
+			s.notifiers.Delete(key)
+			return true
+		}
+
+		if s.set != nil {
+			s.set.dispatch(f, s)
+		} else {
+			f.Notify(s)
+		}
+
+		return true
+	})
+
+	// propagate the change up through the Set the setting belongs to
+	if s.set != nil {
+		s.set.notifyChanged(s)
+	}
+}
+
+// apply the string to the Value holding the write lock, so concurrent writes of the setting are serialized while notifications are sent without it. Returns if the value changed.
+func (s *Setting) apply(ctx context.Context, v, source string, precondition func() error) (bool, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if precondition != nil {
+		if err := precondition(); err != nil {
+			return false, err
+		}
+	}
+
 	same := s.Equals(v)
 
+	if !same {
+		if err := s.validate(v); err != nil {
+			return false, err
+		}
+
+		if err := s.constrain(v, documentLookup(ctx)); err != nil {
+			return false, err
+		}
+	}
+
+	// the backend is written before the value is changed, so a conflict leaves the value untouched
+	if !same && source == "" && s.set != nil {
+		if err := s.set.writeThrough(ctx, s, v); err != nil {
+			return false, err
+		}
+	}
+
+	// views see the value either before or after the change, see Set.View
+	if s.set != nil {
+		view := &s.set.Root().viewMu
+		view.RLock()
+		defer view.RUnlock()
+	}
+
+	// the value the process runs with is kept while the change waits for a restart
+	var running string
+	pending := !same && s.set != nil && s.requiresRestart() && s.set.hasStarted() && ctx.Value(baselineContextKey{}) == nil
+	if pending {
+		running = s.format()
+	}
+
+	// the replaced value is only kept when it is retained in the history
+	var previous string
+	previousRevision := s.Revision()
+	if !same && s.historySize() > 0 {
+		previous = s.format()
+	}
+
+	if err := s.convert(v); err != nil {
+		if s.set != nil && s.set.tracing() {
+			// secrets are traced as displayed, without the conversion error quoting them
+			if shown := s.display(v); shown != v {
+				s.set.trace("convert", s.Path, "unable to convert %q to %T", shown, s.Value)
+			} else {
+				s.set.trace("convert", s.Path, "unable to convert %q to %T: %v", v, s.Value, err)
+			}
+		}
+		return false, err
+	}
+
+	if s.set != nil && s.set.tracing() {
+		s.set.trace("convert", s.Path, "converted %q to %T", s.display(v), s.Value)
+	}
+
+	s.populate()
+
+	if same {
+		return false, nil
+	}
+
+	atomic.StoreInt64(&s.lastChanged, time.Now().UnixNano())
+	if s.set != nil {
+		atomic.StoreUint64(&s.revision, atomic.AddUint64(&s.set.Root().revision, 1))
+	}
+
+	s.source.Store(source)
+	s.record(source, previous, previousRevision)
+
+	if pending {
+		s.set.pend(s, running)
+	}
+
+	return true, nil
+}
+
+// convert the string to the Value type and store it
+func (s *Setting) convert(v string) (err error) {
+	// out of range numbers report the path and the range allowed by the type
+	defer func() {
+		if errors.Is(err, strconv.ErrRange) {
+			err = newRangeError(s, v, err)
+		}
+	}()
+
 	if unmarshaler, ok := s.Value.(Unmarshaler); ok {
 		if err := unmarshaler.UnmarshalSetting(v); err != nil {
 			return fmt.Errorf("unable to marshal value to %T: %w", s.Value, err)
@@ -81,9 +359,9 @@ func (s *Setting) Set(v string) error {
 	} else {
 		switch val := s.Value.(type) {
 		case string:
-			s.Value = v
+			s.Value = s.intern(v)
 		case *string:
-			*val = v
+			*val = s.intern(v)
 		case bool:
 			pv, err := strconv.ParseBool(v)
 			if err != nil {
@@ -220,17 +498,17 @@ func (s *Setting) Set(v string) error {
 			*val = pv
 
 		case float32:
-			pv, err := strconv.ParseFloat(v, 32)
+			pv, err := s.parseFloat32(v)
 			if err != nil {
-				return fmt.Errorf("unable to cast value to float32: %w", err)
+				return err
 			}
-			s.Value = float32(pv)
+			s.Value = pv
 		case *float32:
-			pv, err := strconv.ParseFloat(v, 32)
+			pv, err := s.parseFloat32(v)
 			if err != nil {
-				return fmt.Errorf("unable to cast value to float32: %w", err)
+				return err
 			}
-			*val = float32(pv)
+			*val = pv
 		case float64:
 			pv, err := strconv.ParseFloat(v, 64)
 			if err != nil {
@@ -244,6 +522,31 @@ func (s *Setting) Set(v string) error {
 			}
 			*val = pv
 
+		case complex64:
+			pv, err := strconv.ParseComplex(v, 64)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to complex64: %w", err)
+			}
+			s.Value = complex64(pv)
+		case *complex64:
+			pv, err := strconv.ParseComplex(v, 64)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to complex64: %w", err)
+			}
+			*val = complex64(pv)
+		case complex128:
+			pv, err := strconv.ParseComplex(v, 128)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to complex128: %w", err)
+			}
+			s.Value = pv
+		case *complex128:
+			pv, err := strconv.ParseComplex(v, 128)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to complex128: %w", err)
+			}
+			*val = pv
+
 		case time.Duration:
 			pv, err := time.ParseDuration(v)
 			if err != nil {
@@ -257,38 +560,144 @@ func (s *Setting) Set(v string) error {
 			}
 			*val = pv
 
+		case *time.Location:
+			pv, err := time.LoadLocation(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to time.Location: %w", err)
+			}
+			s.Value = pv
+		case **time.Location:
+			pv, err := time.LoadLocation(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to time.Location: %w", err)
+			}
+			*val = pv
+
+		case netip.Addr:
+			pv, err := parseAddr(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to netip.Addr: %w", err)
+			}
+			s.Value = pv
+		case *netip.Addr:
+			pv, err := parseAddr(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to netip.Addr: %w", err)
+			}
+			*val = pv
+
+		case netip.AddrPort:
+			pv, err := parseAddrPort(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to netip.AddrPort: %w", err)
+			}
+			s.Value = pv
+		case *netip.AddrPort:
+			pv, err := parseAddrPort(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to netip.AddrPort: %w", err)
+			}
+			*val = pv
+
+		case *net.TCPAddr:
+			pv, err := parseTCPAddr(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to net.TCPAddr: %w", err)
+			}
+			s.Value = pv
+		case **net.TCPAddr:
+			pv, err := parseTCPAddr(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to net.TCPAddr: %w", err)
+			}
+			*val = pv
+
+		case *mail.Address:
+			pv, err := parseMailAddress(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to mail.Address: %w", err)
+			}
+			s.Value = pv
+		case **mail.Address:
+			pv, err := parseMailAddress(v)
+			if err != nil {
+				return fmt.Errorf("unable to cast value to mail.Address: %w", err)
+			}
+			*val = pv
+
 		default:
 			return fmt.Errorf("type %T not supported", s.Value)
 
 		}
 	}
 
-	// if same, then go ahead and exit the function and don't notify
-	if same {
-		return nil
+	return nil
+}
+
+func (s *Setting) String() string {
+	if s.Mask {
+		return "*****"
 	}
 
-	// notify those of changed value
-	s.notifiers.Range(func(key, val interface{}) bool {
-		f, ok := val.(Notifier)
-		if !ok || f == nil {
-			s.notifiers.Delete(key)
-			return true
-		}
+	if redacter, ok := s.Value.(Redacter); ok {
+		return redacter.Redacted()
+	}
+
+	return s.format()
+}
+
+// display returns the value v of the setting as it may be shown outside the process, like Setting.String: ***** for masked settings, the Redacted form for Redacter values and v otherwise. Values a Redacter can not hold are masked, as they can not be redacted.
+func (s *Setting) display(v string) string {
+	if s.Mask {
+		return "*****"
+	}
+
+	if _, ok := s.Value.(Redacter); !ok || v == "" {
+		return v
+	}
+
+	scratch := s.clone()
+	if err := scratch.convert(v); err != nil {
+		return "*****"
+	}
 
-		f.Notify(s)
+	if redacter, ok := scratch.Value.(Redacter); ok {
+		return redacter.Redacted()
+	}
+
+	return "*****"
+}
 
+// sensitive returns if the current or default value of the setting has secret parts, so exporters handle it like a masked setting
+func (s *Setting) sensitive() bool {
+	if s.Mask {
 		return true
-	})
+	}
 
-	return nil
+	if _, ok := s.Value.(Redacter); !ok {
+		return false
+	}
+
+	current := s.format()
+	return s.display(current) != current || s.display(s.DefaultValue) != s.DefaultValue
 }
 
-func (s *Setting) String() string {
+// redacted returns if the value is the current value of the setting as shown by display (i.e. exported by Set.Export), so it stands for the current value rather than replacing it
+func (s *Setting) redacted(v string) bool {
 	if s.Mask {
-		return "*****"
+		return v == "*****"
 	}
 
+	if _, ok := s.Value.(Redacter); !ok {
+		return false
+	}
+
+	current := s.format()
+	return v != current && v == s.display(current)
+}
+
+// format the Value as a string regardless of Mask
+func (s *Setting) format() string {
 	if marshaler, ok := s.Value.(Marshaler); ok {
 		return marshaler.MarshalSetting()
 	}
@@ -354,6 +763,40 @@ func (s *Setting) String() string {
 	case *float64:
 		return strconv.FormatFloat(*val, 'g', -1, 64)
 
+	case complex64:
+		return strconv.FormatComplex(complex128(val), 'g', -1, 64)
+	case *complex64:
+		return strconv.FormatComplex(complex128(*val), 'g', -1, 64)
+	case complex128:
+		return strconv.FormatComplex(val, 'g', -1, 128)
+	case *complex128:
+		return strconv.FormatComplex(*val, 'g', -1, 128)
+
+	case *time.Location:
+		return val.String()
+	case **time.Location:
+		return (*val).String()
+
+	case netip.Addr:
+		return formatAddr(val)
+	case *netip.Addr:
+		return formatAddr(*val)
+
+	case netip.AddrPort:
+		return formatAddrPort(val)
+	case *netip.AddrPort:
+		return formatAddrPort(*val)
+
+	case *net.TCPAddr:
+		return formatTCPAddr(val)
+	case **net.TCPAddr:
+		return formatTCPAddr(*val)
+
+	case *mail.Address:
+		return formatMailAddress(val)
+	case **mail.Address:
+		return formatMailAddress(*val)
+
 	default:
 		return fmt.Sprintf("%v", val)
 	}
@@ -531,6 +974,31 @@ func (s *Setting) Equals(v string) bool {
 		}
 		return *val == pv
 
+	case complex64:
+		pv, err := strconv.ParseComplex(v, 64)
+		if err != nil {
+			return false
+		}
+		return val == complex64(pv)
+	case *complex64:
+		pv, err := strconv.ParseComplex(v, 64)
+		if err != nil {
+			return false
+		}
+		return *val == complex64(pv)
+	case complex128:
+		pv, err := strconv.ParseComplex(v, 128)
+		if err != nil {
+			return false
+		}
+		return val == pv
+	case *complex128:
+		pv, err := strconv.ParseComplex(v, 128)
+		if err != nil {
+			return false
+		}
+		return *val == pv
+
 	case time.Duration:
 		pv, err := time.ParseDuration(v)
 		if err != nil {
@@ -544,6 +1012,71 @@ func (s *Setting) Equals(v string) bool {
 		}
 		return *val == pv
 
+	case *time.Location:
+		pv, err := time.LoadLocation(v)
+		if err != nil {
+			return false
+		}
+		return val.String() == pv.String()
+	case **time.Location:
+		pv, err := time.LoadLocation(v)
+		if err != nil {
+			return false
+		}
+		return (*val).String() == pv.String()
+
+	case netip.Addr:
+		pv, err := parseAddr(v)
+		if err != nil {
+			return false
+		}
+		return val == pv
+	case *netip.Addr:
+		pv, err := parseAddr(v)
+		if err != nil {
+			return false
+		}
+		return *val == pv
+
+	case netip.AddrPort:
+		pv, err := parseAddrPort(v)
+		if err != nil {
+			return false
+		}
+		return val == pv
+	case *netip.AddrPort:
+		pv, err := parseAddrPort(v)
+		if err != nil {
+			return false
+		}
+		return *val == pv
+
+	case *net.TCPAddr:
+		pv, err := parseTCPAddr(v)
+		if err != nil {
+			return false
+		}
+		return formatTCPAddr(val) == formatTCPAddr(pv)
+	case **net.TCPAddr:
+		pv, err := parseTCPAddr(v)
+		if err != nil {
+			return false
+		}
+		return formatTCPAddr(*val) == formatTCPAddr(pv)
+
+	case *mail.Address:
+		pv, err := parseMailAddress(v)
+		if err != nil {
+			return false
+		}
+		return formatMailAddress(val) == formatMailAddress(pv)
+	case **mail.Address:
+		pv, err := parseMailAddress(v)
+		if err != nil {
+			return false
+		}
+		return formatMailAddress(*val) == formatMailAddress(pv)
+
 	default:
 		return fmt.Sprintf("%v", val) == v
 	}
@@ -565,11 +1098,11 @@ func (s *Setting) IsBoolFlag() bool {
 	}
 }
 
-// Flag will register the current Setting as a command line flag in the supplied flag.FlagSet. When the supplied fs is nill, the flag.CommandLine is used
+// Flag will register the current Setting as a command line flag in the supplied flag.FlagSet. When the supplied fs is nill, the flag.CommandLine is used. Values from the command line are supplied with SourceFlag, see Set.RestrictSecrets
 func (s *Setting) Flag(arg string, fs *flag.FlagSet) {
 	if fs == nil {
 		fs = flag.CommandLine
 	}
 
-	fs.Var(s, arg, s.Description)
+	fs.Var(&flagValue{setting: s}, arg, s.Description)
 }
@@ -2,11 +2,20 @@ package config
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
+
+	// zone data for TestSetting_Location on hosts without it
+	_ "time/tzdata"
 )
 
 type setTest struct {
@@ -55,6 +64,9 @@ func TestSetting_Set(t *testing.T) {
 		newSetTest(float32(23), float32(5), "5"),
 		newSetTest(float64(23), float64(5), "5"),
 
+		newSetTest(complex64(23+2i), complex64(5), "5"),
+		newSetTest(complex128(23-2i), complex128(5+1i), "(5+1i)"),
+
 		// actually treated like a uint8, but we make sure it works
 		newSetTest(byte(6), byte(5), "5"),
 	}
@@ -227,3 +239,246 @@ func TestSetting_FlagCompat(t *testing.T) {
 		t.Errorf("Failed to resolve type; expected %q got %q", "bool", st.Type())
 	}
 }
+
+func TestSetting_Location(t *testing.T) {
+	cfg := struct {
+		Zone *time.Location
+	}{
+		Zone: time.UTC,
+	}
+
+	s := &Set{}
+	s.Bind(&cfg)
+
+	zone := s.Get("Zone")
+	if zone == nil || zone.DefaultValue != "UTC" {
+		t.Fatalf("Failed to bind location: got %v", zone)
+	}
+
+	if err := zone.Set("America/New_York"); err != nil {
+		t.Fatalf("Failed to set location: %v", err)
+	}
+	if cfg.Zone.String() != "America/New_York" || !zone.Equals("America/New_York") {
+		t.Errorf("Failed to set location: expected %q; got %q", "America/New_York", cfg.Zone)
+	}
+
+	if err := zone.Set("Mars/Olympus_Mons"); err == nil {
+		t.Errorf("Failed to reject unknown zone")
+	}
+	if zone.String() != "America/New_York" {
+		t.Errorf("Failed to keep location: expected %q; got %q", "America/New_York", zone.String())
+	}
+}
+
+func TestSetting_NetworkTypes(t *testing.T) {
+	cfg := struct {
+		Addr     netip.Addr
+		AddrPort netip.AddrPort
+		Listen   *net.TCPAddr
+		Alerts   *mail.Address
+	}{}
+
+	s := &Set{}
+	s.Bind(&cfg)
+
+	tests := []struct {
+		path    string
+		value   string
+		invalid string
+	}{
+		{path: "Addr", value: "fe80::1%eth0", invalid: "10.0.0"},
+		{path: "AddrPort", value: "10.0.0.1:8080", invalid: "10.0.0.1"},
+		{path: "Listen", value: ":8080", invalid: "localhost:http"},
+		{path: "Alerts", value: `"Ops" <ops@example.com>`, invalid: "ops"},
+	}
+
+	for _, tt := range tests {
+		setting := s.Get(tt.path)
+		if setting == nil {
+			t.Errorf("Failed to bind %s", tt.path)
+			continue
+		}
+
+		if setting.DefaultValue != "" {
+			t.Errorf("Failed to format zero %s: expected %q; got %q", tt.path, "", setting.DefaultValue)
+		}
+
+		if err := setting.Set(tt.value); err != nil {
+			t.Errorf("Failed to set %s: %v", tt.path, err)
+		}
+		if setting.String() != tt.value || !setting.Equals(tt.value) {
+			t.Errorf("Failed to round trip %s: expected %q; got %q", tt.path, tt.value, setting.String())
+		}
+
+		if err := setting.Set(tt.invalid); err == nil {
+			t.Errorf("Failed to reject %s: %q", tt.path, tt.invalid)
+		}
+	}
+
+	if cfg.Listen.Port != 8080 || cfg.Alerts.Address != "ops@example.com" || cfg.AddrPort.Port() != 8080 {
+		t.Errorf("Failed to bind values: got %v, %v and %v", cfg.Listen, cfg.Alerts, cfg.AddrPort)
+	}
+}
+
+func TestSetting_NotifyCurrent(t *testing.T) {
+	s := &Set{}
+	port := s.Setting("Port", 80, "")
+	s.Subset("HTTP").Setting("Host", "localhost", "")
+
+	var values []string
+	handle := port.Notify(NotifyFunc(func(setting *Setting) { values = append(values, setting.String()) }), NotifyCurrent())
+	defer handle.Close()
+
+	_ = port.Set("8080")
+
+	if fmt.Sprint(values) != "[80 8080]" {
+		t.Errorf("Failed to deliver current value: expected %v; got %v", []string{"80", "8080"}, values)
+	}
+
+	var paths []string
+	s.Notify(NotifyFunc(func(setting *Setting) { paths = append(paths, setting.Path) }), NotifyCurrent()).Close()
+
+	if fmt.Sprint(paths) != "[HTTP.Host Port]" {
+		t.Errorf("Failed to deliver current settings: expected %v; got %v", []string{"HTTP.Host", "Port"}, paths)
+	}
+}
+
+func TestSetting_Touch(t *testing.T) {
+	s := &Set{}
+	port := s.Setting("Port", 80, "")
+
+	var notified, propagated int
+	port.Notify(NotifyFunc(func(*Setting) { notified++ }))
+	s.Notify(NotifyFunc(func(*Setting) { propagated++ }))
+
+	_ = port.Set("80")
+	if notified != 0 {
+		t.Errorf("Failed to suppress unchanged value: got %d notifications", notified)
+	}
+
+	port.Touch()
+	s.Touch()
+	if notified != 2 || propagated != 2 {
+		t.Errorf("Failed to touch: expected %d notifications; got %d and %d", 2, notified, propagated)
+	}
+
+	port.NotifyUnchanged = true
+	_ = port.Set("80")
+	if notified != 3 || port.Revision() != 0 {
+		t.Errorf("Failed to notify unchanged value: got %d notifications at revision %d", notified, port.Revision())
+	}
+}
+
+func TestNotifierGroup(t *testing.T) {
+	set := &Set{}
+	port := set.Setting("Port", 80, "")
+	host := set.Subset("HTTP").Setting("Host", "localhost", "")
+
+	var notified []string
+	record := NotifyFunc(func(setting *Setting) {
+		notified = append(notified, setting.Path)
+	})
+
+	var group NotifierGroup
+	group.Setting(port, record)
+	group.Set(set.Subset("HTTP"), record)
+	group.Add(host.Notify(record))
+
+	_ = port.Set("8080")
+	_ = host.Set("example.com")
+
+	if len(notified) != 3 {
+		t.Fatalf("Failed to notify group: got %v", notified)
+	}
+
+	if err := group.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = port.Set("9090")
+	_ = host.Set("example.org")
+
+	group.Setting(port, record)
+	_ = port.Set("80")
+
+	if len(notified) != 3 {
+		t.Errorf("Failed to close group: got %v", notified)
+	}
+}
+
+func TestSetting_NotifyContext(t *testing.T) {
+	set := &Set{}
+	port := set.Setting("Port", 80, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var settingCalls, setCalls int
+	port.NotifyContext(ctx, NotifyFunc(func(*Setting) { settingCalls++ }))
+	set.NotifyContext(ctx, NotifyFunc(func(*Setting) { setCalls++ }))
+
+	_ = port.Set("8080")
+	if settingCalls != 1 || setCalls != 1 {
+		t.Fatalf("Failed to notify: got %d and %d notifications", settingCalls, setCalls)
+	}
+
+	cancel()
+	_ = port.Set("9090")
+	if settingCalls != 1 || setCalls != 1 {
+		t.Errorf("Failed to stop notifying after cancel: got %d and %d notifications", settingCalls, setCalls)
+	}
+
+	// the handle is closed in the background once the ctx is done
+	count := func() int {
+		n := 0
+		port.notifiers.Range(func(_, _ interface{}) bool { n++; return true })
+		return n
+	}
+	for deadline := time.Now().Add(time.Second); count() > 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if n := count(); n != 0 {
+		t.Errorf("Failed to close handle after cancel: %d notifiers remain", n)
+	}
+
+	if handle := port.NotifyContext(ctx, NotifyFunc(func(*Setting) {})); handle.stopFunc != nil || count() != 0 {
+		t.Errorf("Failed to ignore registration with a done ctx")
+	}
+}
+
+func TestSetting_RangeError(t *testing.T) {
+	set := &Set{}
+	small := set.Subset("Limits").Setting("Small", int8(0), "")
+	unsigned := set.Setting("Unsigned", uint16(0), "")
+
+	var rangeErr *RangeError
+	if err := small.Set("200"); !errors.As(err, &rangeErr) || rangeErr.Path != "Limits.Small" || rangeErr.Min != "-128" || rangeErr.Max != "127" || !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("Failed to describe range: got %v", err)
+	}
+
+	if err := unsigned.Set("70000"); !errors.As(err, &rangeErr) || rangeErr.Input != "70000" || rangeErr.Max != "65535" || rangeErr.Type != "uint16" {
+		t.Errorf("Failed to describe unsigned range: got %v", err)
+	}
+
+	if err := small.Set("abc"); errors.As(err, &rangeErr) {
+		t.Errorf("Failed to keep syntax errors: got %v", err)
+	}
+}
+
+func TestSet_StrictFloats(t *testing.T) {
+	set := &Set{}
+	ratio := float32(0)
+	set.Setting("Ratio", &ratio, "")
+
+	if err := set.Get("Ratio").Set("16777217"); err != nil || ratio != 16777216 {
+		t.Errorf("Failed to round without strict floats: got %v (%v)", ratio, err)
+	}
+
+	set.StrictFloats(true)
+	if err := set.Get("Ratio").Set("16777219"); !errors.Is(err, ErrPrecision) {
+		t.Errorf("Failed to reject lost precision: got %v", err)
+	}
+
+	if err := set.Get("Ratio").Set("0.1"); err != nil || ratio != 0.1 {
+		t.Errorf("Failed to accept representable value: got %v (%v)", ratio, err)
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrInvalidSignature is returned when a config file is not signed by any of the trusted keys
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// SignatureSuffix is appended to the path of a config file to find its detached signature
+const SignatureSuffix = ".minisig"
+
+// RequireSignatures enables signature verification for files read anywhere in the Set tree: by Set.LoadFile, Set.ValidateFile, Set.LoadURL with the file scheme and the File and FileDigest providers loaded by Set.Reload. Every file, including the files it includes, must have a detached signature at its path with SignatureSuffix made by one of the keys, otherwise nothing is applied. Sources whose documents can not be verified are rejected while signatures are required: documents fetched over http(s) and the overrides file of Set.Persist, which the process rewrites.
+//
+// Signatures are either minisign legacy signatures (minisign -S -l) or a base64 encoded Ed25519 signature as written by SignFile. Calling RequireSignatures without keys disables verification.
+func (s *Set) RequireSignatures(keys ...ed25519.PublicKey) {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	root.signatureKeys = keys
+}
+
+// reader returns the fileReader for the Set tree
+func (s *Set) reader() *fileReader {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	r := &fileReader{}
+	if keys := root.signatureKeys; len(keys) > 0 {
+		r.verify = func(path string, data []byte) error {
+			return verifySignature(keys, path, data)
+		}
+	}
+
+	return r
+}
+
+type readerContextKey struct{}
+
+// withReader returns a child context of ctx for loading providers of the Set the reader belongs to, so providers reading files (i.e. File) verify them like Set.LoadFile
+func withReader(ctx context.Context, r *fileReader) context.Context {
+	return context.WithValue(ctx, readerContextKey{}, r)
+}
+
+// readerFrom returns a copy of the fileReader added to the ctx with withReader, a reader without verification when there is none
+func readerFrom(ctx context.Context) *fileReader {
+	if r, ok := ctx.Value(readerContextKey{}).(*fileReader); ok {
+		copied := *r
+		return &copied
+	}
+
+	return &fileReader{}
+}
+
+// signaturesRequired returns if Set.RequireSignatures was called with keys for the Set tree
+func (s *Set) signaturesRequired() bool {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	return len(root.signatureKeys) > 0
+}
+
+// SignedFile returns a Provider reading the document at path in any format of Set.LoadFile like File, verifying the signatures like Set.RequireSignatures
+func SignedFile(path string, keys ...ed25519.PublicKey) Provider {
+	return ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		r := &fileReader{
+			verify: func(path string, data []byte) error {
+				return verifySignature(keys, path, data)
+			},
+		}
+
+		return r.read(path, nil)
+	})
+}
+
+// SignFile writes the base64 encoded Ed25519 signature of the file at path to its detached signature file
+func SignFile(path string, key ed25519.PrivateKey) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read %q: %w", path, err)
+	}
+
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+
+	if err := os.WriteFile(path+SignatureSuffix, []byte(signature+"\n"), 0644); err != nil {
+		return fmt.Errorf("unable to write signature: %w", err)
+	}
+
+	return nil
+}
+
+// ParsePublicKey parses a minisign public key, either the contents of the key file or the base64 line, or a base64 encoded Ed25519 public key
+func ParsePublicKey(text string) (ed25519.PublicKey, error) {
+	lines := signatureLines(text)
+	if len(lines) == 0 {
+		return nil, errors.New("empty public key")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(lines[len(lines)-1])
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode public key: %w", err)
+	}
+
+	switch {
+	case len(data) == ed25519.PublicKeySize:
+		return ed25519.PublicKey(data), nil
+	case len(data) == 2+8+ed25519.PublicKeySize && string(data[:2]) == "Ed":
+		return ed25519.PublicKey(data[10:]), nil
+	default:
+		return nil, errors.New("unsupported public key")
+	}
+}
+
+// verifySignature checks the detached signature of the file at path is made by one of the keys
+func verifySignature(keys []ed25519.PublicKey, path string, data []byte) error {
+	text, err := os.ReadFile(path + SignatureSuffix)
+	if err != nil {
+		return fmt.Errorf("unable to read signature: %w", err)
+	}
+
+	lines := signatureLines(string(text))
+	if len(lines) == 0 {
+		return ErrInvalidSignature
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil {
+		return fmt.Errorf("unable to decode signature: %w", err)
+	}
+
+	var trusted []byte
+	switch {
+	case len(signature) == ed25519.SignatureSize && len(lines) == 1:
+		// bare signature
+
+	case len(signature) == 2+8+ed25519.SignatureSize && string(signature[:2]) == "Ed" && len(lines) == 3:
+		// minisign legacy signature, the global signature covers the signature and trusted comment
+		comment := strings.TrimPrefix(lines[1], "trusted comment: ")
+		global, err := base64.StdEncoding.DecodeString(lines[2])
+		if err != nil {
+			return fmt.Errorf("unable to decode signature: %w", err)
+		}
+
+		signature = signature[10:]
+		trusted = append(append(append([]byte{}, signature...), comment...), global...)
+
+	case len(signature) == 2+8+ed25519.SignatureSize && string(signature[:2]) == "ED":
+		return errors.New("prehashed minisign signatures are not supported, sign with minisign -l")
+
+	default:
+		return errors.New("unsupported signature")
+	}
+
+	for _, key := range keys {
+		if !ed25519.Verify(key, data, signature) {
+			continue
+		}
+
+		if trusted != nil {
+			n := len(trusted) - ed25519.SignatureSize
+			if !ed25519.Verify(key, trusted[:n], trusted[n:]) {
+				continue
+			}
+		}
+
+		return nil
+	}
+
+	return ErrInvalidSignature
+}
+
+// signatureLines returns the lines of a minisign file without the untrusted comment and blank lines
+func signatureLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "untrusted comment:") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
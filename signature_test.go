@@ -0,0 +1,142 @@
+package config
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSet_RequireSignatures(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(nil)
+	_, other, _ := ed25519.GenerateKey(nil)
+
+	dir := writeFiles(t, map[string]string{
+		"config.json": `{"include": "base.json", "Port": 8080}`,
+		"base.json":   `{"Host": "example.com"}`,
+	})
+	config := filepath.Join(dir, "config.json")
+	base := filepath.Join(dir, "base.json")
+
+	var (
+		host = "localhost"
+		port = 80
+	)
+
+	set := &Set{}
+	set.Setting("Host", &host, "")
+	set.Setting("Port", &port, "")
+	set.RequireSignatures(public)
+
+	if err := set.LoadFile(config); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Failed to reject unsigned file: got %v", err)
+	}
+
+	_ = SignFile(config, private)
+	_ = SignFile(base, other)
+
+	if err := set.LoadFile(config); !errors.Is(err, ErrInvalidSignature) || host != "localhost" || port != 80 {
+		t.Errorf("Failed to reject include signed by untrusted key: got %v with %q and %d", err, host, port)
+	}
+
+	_ = SignFile(base, private)
+
+	if err := set.LoadFile(config); err != nil || host != "example.com" || port != 8080 {
+		t.Errorf("Failed to load signed files: got %v with %q and %d", err, host, port)
+	}
+
+	_ = os.WriteFile(config, []byte(`{"Port": 9090}`), 0o644)
+
+	if err := set.LoadFile(config); !errors.Is(err, ErrInvalidSignature) || port != 8080 {
+		t.Errorf("Failed to reject modified file: got %v with %d", err, port)
+	}
+
+	set.RequireSignatures()
+
+	if err := set.LoadFile(config); err != nil || port != 9090 {
+		t.Errorf("Failed to disable verification: got %v with %d", err, port)
+	}
+}
+
+func TestSignedFile_Minisign(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(nil)
+
+	dir := writeFiles(t, map[string]string{"config.json": `{"Port": 8080}`})
+	config := filepath.Join(dir, "config.json")
+	data, _ := os.ReadFile(config)
+
+	keyID := []byte("12345678")
+	encode := base64.StdEncoding.EncodeToString
+
+	key, err := ParsePublicKey("untrusted comment: minisign public key\n" + encode(append(append([]byte("Ed"), keyID...), public...)) + "\n")
+	if err != nil || !key.Equal(public) {
+		t.Fatalf("Failed to parse public key: %v", err)
+	}
+
+	signature := ed25519.Sign(private, data)
+	comment := "timestamp:1600000000"
+	global := ed25519.Sign(private, append(append([]byte{}, signature...), comment...))
+
+	minisig := "untrusted comment: signature\n" +
+		encode(append(append([]byte("Ed"), keyID...), signature...)) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		encode(global) + "\n"
+	_ = os.WriteFile(config+SignatureSuffix, []byte(minisig), 0o644)
+
+	values, err := SignedFile(config, key).Load(context.Background())
+	if err != nil || values["Port"] != "8080" {
+		t.Errorf("Failed to load minisigned file: got %v with %v", err, values)
+	}
+
+	tampered := "untrusted comment: signature\n" +
+		encode(append(append([]byte("Ed"), keyID...), signature...)) + "\n" +
+		"trusted comment: timestamp:1700000000\n" +
+		encode(global) + "\n"
+	_ = os.WriteFile(config+SignatureSuffix, []byte(tampered), 0o644)
+
+	if _, err := SignedFile(config, key).Load(context.Background()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Failed to reject modified trusted comment: got %v", err)
+	}
+}
+
+func TestSet_RequireSignaturesSources(t *testing.T) {
+	public, _, _ := ed25519.GenerateKey(nil)
+
+	dir := writeFiles(t, map[string]string{"config.json": `{"Port": 8080}`})
+	config := filepath.Join(dir, "config.json")
+
+	port := 80
+	set := &Set{}
+	set.Setting("Port", &port, "")
+	set.RequireSignatures(public)
+
+	// files read by providers and URLs are verified like Set.LoadFile
+	set.AddProvider("file", File(config))
+	if err := set.Reload(context.Background()); err == nil || !strings.Contains(err.Error(), "unable to read signature") || port != 80 {
+		t.Errorf("Failed to verify provider: got %v with %d", err, port)
+	}
+
+	if err := set.LoadURL(context.Background(), "file://"+config); !errors.Is(err, os.ErrNotExist) || port != 80 {
+		t.Errorf("Failed to verify file URL: got %v with %d", err, port)
+	}
+
+	// sources that can not be verified are rejected
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Port": 8080}`))
+	}))
+	defer server.Close()
+
+	if err := set.LoadURL(context.Background(), server.URL+"/config.json"); !errors.Is(err, ErrInvalidSignature) || port != 80 {
+		t.Errorf("Failed to reject remote document: got %v with %d", err, port)
+	}
+
+	if _, err := set.Persist(filepath.Join(dir, "overrides.json")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Failed to reject overrides file: expected %v; got %v", ErrInvalidSignature, err)
+	}
+}
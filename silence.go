@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// LoadedNotifier is a Notifier of a Set told once about every setting within the Set that changed while notifications were held back by Set.Silence, rather than once per setting
+type LoadedNotifier interface {
+	Notifier
+
+	// Loaded is called by Set.Resume with the Set the notifier is registered on and the settings within it that changed, in path order
+	Loaded(set *Set, changed []*Setting)
+}
+
+// Silence holds back the notifications of the settings within the Set (i.e. during the initial load of hundreds of values) until Set.Resume, so components do not react to every value as it arrives. Changes are still applied, recorded and visible to Set.Get. Calls nest, notifications are held back until Set.Resume is called as many times as Set.Silence.
+func (s *Set) Silence() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if atomic.AddInt32(&s.silenced, 1) == 1 {
+		s.silencedAt = time.Now()
+	}
+}
+
+// Resume delivers the notifications held back by Set.Silence once the last Silence is resumed: the notifiers of every changed setting are called once with its current value, the notifiers of the Sets containing them are called once per changed setting, or once in total when they implement LoadedNotifier, and every Hook receives an OpLoaded event for the Set. When a parent Set is still silenced the changes are held back until it resumes. Resume panics when the Set is not silenced.
+func (s *Set) Resume() {
+	s.mu.Lock()
+	count := atomic.AddInt32(&s.silenced, -1)
+	if count < 0 {
+		atomic.AddInt32(&s.silenced, 1)
+		s.mu.Unlock()
+		panic(fmt.Sprintf("set %q is not silenced", s.path))
+	}
+	if count > 0 {
+		s.mu.Unlock()
+		return
+	}
+
+	changed, since := s.silencedChanges, s.silencedAt
+	s.silencedChanges = nil
+	s.mu.Unlock()
+
+	// a silenced parent holds the changes back until it resumes
+	var deliver []*Setting
+	for _, setting := range changed {
+		if s.parent == nil || !s.parent.hold(setting) {
+			deliver = append(deliver, setting)
+		}
+	}
+	if len(deliver) > 0 {
+		s.deliver(deliver)
+	}
+
+	s.observe(OpLoaded, s.path, "", since, nil)
+}
+
+// hold records the change of the setting when the Set or one of its parents is silenced, returning false when the notifications should be sent now
+func (s *Set) hold(setting *Setting) bool {
+	for set := s; set != nil; set = set.parent {
+		if atomic.LoadInt32(&set.silenced) == 0 {
+			continue
+		}
+
+		set.mu.Lock()
+		// the count changes under the lock, so a concurrent Resume either sees the change or it is notified now
+		if atomic.LoadInt32(&set.silenced) == 0 {
+			set.mu.Unlock()
+			continue
+		}
+		for _, held := range set.silencedChanges {
+			if held == setting {
+				set.mu.Unlock()
+				return true
+			}
+		}
+		set.silencedChanges = append(set.silencedChanges, setting)
+		set.mu.Unlock()
+
+		return true
+	}
+
+	return false
+}
+
+// deliver the notifications held back for the changed settings, the notifiers of the settings first and then those of the Sets containing them from the innermost outwards
+func (s *Set) deliver(changed []*Setting) {
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Path < changed[j].Path })
+
+	var sets []*Set
+	within := map[*Set][]*Setting{}
+	for _, setting := range changed {
+		setting.notifiers.Range(func(key, val interface{}) bool {
+			if f, ok := val.(Notifier); ok && f != nil {
+				setting.set.dispatch(f, setting)
+			}
+			return true
+		})
+
+		for set := setting.set; set != nil; set = set.parent {
+			if _, found := within[set]; !found {
+				sets = append(sets, set)
+			}
+			within[set] = append(within[set], setting)
+		}
+	}
+
+	// deeper Sets have longer paths, as notifications propagate upward
+	sort.SliceStable(sets, func(i, j int) bool { return len(sets[i].path) > len(sets[j].path) })
+
+	for _, set := range sets {
+		set.notifiers.Range(func(_, v interface{}) bool {
+			if loaded, ok := asLoadedNotifier(v.(Notifier)); ok {
+				set.loaded(loaded, within[set])
+				return true
+			}
+
+			for _, setting := range within[set] {
+				set.dispatch(v.(Notifier), setting)
+			}
+			return true
+		})
+	}
+}
+
+// loaded calls the LoadedNotifier with the changed settings, logging a panic like Set.dispatch
+func (s *Set) loaded(n LoadedNotifier, changed []*Setting) {
+	defer recoverNotifier(changed[0])
+
+	n.Loaded(s, changed)
+}
+
+// asLoadedNotifier returns the LoadedNotifier registered as the Notifier, if any
+func asLoadedNotifier(n Notifier) (LoadedNotifier, bool) {
+	if v, ok := n.(namedNotifier); ok {
+		n = v.Notifier
+	}
+
+	loaded, ok := n.(LoadedNotifier)
+	return loaded, ok
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+// loadedRecorder records the notifications and loads it receives
+type loadedRecorder struct {
+	notified []string
+	loaded   [][]string
+}
+
+func (r *loadedRecorder) Notify(s *Setting) {
+	r.notified = append(r.notified, s.Path)
+}
+
+func (r *loadedRecorder) Loaded(set *Set, changed []*Setting) {
+	var paths []string
+	for _, s := range changed {
+		paths = append(paths, s.Path)
+	}
+	r.loaded = append(r.loaded, paths)
+}
+
+func TestSet_Silence(t *testing.T) {
+	var (
+		port    = 80
+		host    = "localhost"
+		level   = "info"
+		current int
+	)
+
+	set := &Set{}
+	http := set.Subset("HTTP")
+	portSetting := http.Setting("Port", &port, "")
+	http.Setting("Host", &host, "")
+	set.Setting("Level", &level, "")
+
+	var settingCalls int
+	portSetting.Notify(NotifyFunc(func(s *Setting) {
+		settingCalls++
+		current = port
+	}))
+
+	var subsetCalls []string
+	http.Notify(NotifyFunc(func(s *Setting) { subsetCalls = append(subsetCalls, s.Path) }))
+
+	recorder := &loadedRecorder{}
+	set.NotifyContext(context.Background(), recorder)
+
+	var events []Event
+	set.Hook(HookFunc(func(e Event) {
+		if e.Op == OpLoaded {
+			events = append(events, e)
+		}
+	}))
+
+	http.Silence()
+	http.Silence()
+	for _, v := range []string{"8080", "8081", "8082"} {
+		if _, err := set.Update("HTTP.Port", v); err != nil {
+			t.Fatalf("Failed to update: %v", err)
+		}
+	}
+	_, _ = set.Update("HTTP.Host", "example.com")
+
+	// settings outside the silenced subset are notified as usual
+	_, _ = set.Update("Level", "debug")
+
+	if settingCalls != 0 || len(subsetCalls) != 0 || len(recorder.notified) != 1 || port != 8082 {
+		t.Fatalf("Failed to silence: got %d, %v and %v with %d", settingCalls, subsetCalls, recorder.notified, port)
+	}
+
+	http.Resume()
+	if settingCalls != 0 || len(events) != 0 {
+		t.Fatalf("Failed to nest silence: got %d calls and %d events", settingCalls, len(events))
+	}
+
+	http.Resume()
+
+	if settingCalls != 1 || current != 8082 {
+		t.Errorf("Failed to notify setting once: got %d with %d", settingCalls, current)
+	}
+
+	if len(subsetCalls) != 2 || subsetCalls[0] != "HTTP.Host" || subsetCalls[1] != "HTTP.Port" {
+		t.Errorf("Failed to notify subset once per setting: got %v", subsetCalls)
+	}
+
+	if len(recorder.loaded) != 1 || len(recorder.loaded[0]) != 2 || len(recorder.notified) != 1 {
+		t.Errorf("Failed to notify load once: got %v and %v", recorder.loaded, recorder.notified)
+	}
+
+	if len(events) != 1 || events[0].Path != "HTTP" {
+		t.Errorf("Failed to emit loaded event: got %v", events)
+	}
+
+	// a silenced parent holds the changes of a resumed subset
+	set.Silence()
+	http.Silence()
+	_, _ = set.Update("HTTP.Port", "9090")
+	http.Resume()
+
+	if settingCalls != 1 {
+		t.Errorf("Failed to hold changes for parent: got %d", settingCalls)
+	}
+
+	set.Resume()
+
+	if settingCalls != 2 || current != 9090 || len(recorder.loaded) != 2 {
+		t.Errorf("Failed to notify on parent resume: got %d with %d and %v", settingCalls, current, recorder.loaded)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Failed to panic resuming unsilenced set")
+		}
+	}()
+	set.Resume()
+}
@@ -0,0 +1,43 @@
+package config
+
+const (
+	// SourceDefault is the source of a setting that still has the value it was registered with, or was reverted to it with Setting.Clear
+	SourceDefault = "default"
+
+	// SourceRuntime is the source of values written directly, by the application or an admin surface (i.e. Setting.Set or Set.UpdateContext)
+	SourceRuntime = "runtime"
+
+	// SourceEnv is the source of values supplied by the environment with Set.LoadEnv
+	SourceEnv = "env"
+
+	// SourceFlag is the source of values supplied by command line flags registered with Setting.Flag
+	SourceFlag = "flag"
+
+	// SourceRegistry is the source of values supplied by the Windows registry with Set.Bootstrap
+	SourceRegistry = "registry"
+
+	// SourceDerived is the source of values computed by Set.Derive, they are never written through to a writable provider nor treated as runtime changes
+	SourceDerived = "derived"
+)
+
+// Source returns what supplied the current value of the setting: the provider name, the file path, SourceEnv, SourceFlag, SourceDerived, SourceRuntime or SourceDefault when the value never changed
+func (s *Setting) Source() string {
+	source, ok := s.source.Load().(string)
+	switch {
+	case !ok:
+		return SourceDefault
+	case source == "":
+		return SourceRuntime
+	default:
+		return source
+	}
+}
+
+// storedSource returns the source as passed to Setting.setFrom, so the value can be set again as if supplied by the same source: empty for SourceRuntime
+func (s *Setting) storedSource() string {
+	if source := s.Source(); source != SourceRuntime {
+		return source
+	}
+
+	return ""
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// LoadTiming is the time a loader or provider took, see Set.LoadReport
+type LoadTiming struct {
+	// Op is OpLoad for Set.LoadFile, Set.LoadURL and Set.LoadEnv, OpFetch for a Provider loaded by Set.Reload
+	Op Op
+
+	// Path of the Set loaded
+	Path string
+
+	// Source loaded: the file, URL, provider name or SourceEnv
+	Source string
+
+	// Start of the load
+	Start time.Time
+
+	// Duration of the load, the time spent so far while it is running
+	Duration time.Duration
+
+	// Running is set while the load has not completed
+	Running bool
+
+	// Err the load failed with
+	Err error
+}
+
+// LoadReport is the time every loader and provider took to load the Set tree for the first time, see Set.LoadReport
+type LoadReport struct {
+	// Loads in the order they started
+	Loads []LoadTiming
+}
+
+// Elapsed returns the time from the start of the first load to the end of the last, or to now while a load is running
+func (r LoadReport) Elapsed() time.Duration {
+	if len(r.Loads) == 0 {
+		return 0
+	}
+
+	first := r.Loads[0].Start
+	var last time.Time
+	for _, l := range r.Loads {
+		if end := l.Start.Add(l.Duration); end.After(last) {
+			last = end
+		}
+	}
+
+	return last.Sub(first)
+}
+
+// String formats the report with a line per load, the slowest first
+func (r LoadReport) String() string {
+	loads := append([]LoadTiming(nil), r.Loads...)
+	sort.SliceStable(loads, func(i, j int) bool { return loads[i].Duration > loads[j].Duration })
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	for _, l := range loads {
+		result := "ok"
+		switch {
+		case l.Running:
+			result = "still running"
+		case l.Err != nil:
+			result = "failed: " + l.Err.Error()
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", l.Op, l.Source, l.Duration.Round(time.Millisecond), result)
+	}
+	_ = tw.Flush()
+
+	return b.String()
+}
+
+// BudgetError is returned by Set.Startup when the initial load does not complete within its budget, the report tells which loads were slow
+type BudgetError struct {
+	// Budget the load exceeded
+	Budget time.Duration
+
+	// Report of the loads when the budget ran out
+	Report LoadReport
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("startup exceeded its budget of %s:\n%s", e.Budget, e.Report)
+}
+
+// Unwrap returns context.DeadlineExceeded
+func (e *BudgetError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// LoadReport returns the time every loader and provider took to load the Set tree for the first time, including the loads still running. Only the first load of every source is kept, so reloads do not grow the report.
+func (s *Set) LoadReport() LoadReport {
+	root := s.Root()
+	now := time.Now()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	report := LoadReport{Loads: make([]LoadTiming, 0, len(root.loads))}
+	for _, l := range root.loads {
+		timing := *l
+		if timing.Running {
+			timing.Duration = now.Sub(timing.Start)
+		}
+		report.Loads = append(report.Loads, timing)
+	}
+
+	return report
+}
+
+// Startup runs fn to load the Set for the first time (i.e. with Set.LoadFile and Set.Reload using the ctx it is given), failing with a *BudgetError holding the LoadReport when fn does not complete within the budget. Like Set.Reload, fn is abandoned when the ctx is done even if it does not respect the ctx. A budget of zero only bounds fn by the ctx.
+func (s *Set) Startup(ctx context.Context, budget time.Duration, fn func(ctx context.Context) error) error {
+	parent := ctx
+	if budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if budget > 0 && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && err != nil {
+		err = &BudgetError{Budget: budget, Report: s.LoadReport()}
+		logger().Error("startup exceeded its budget", "path", s.path, "budget", budget.String())
+	}
+
+	return err
+}
+
+// timeLoad records the load of the source in the LoadReport, the returned func completes it with the result. Loads of a source that was loaded before are not recorded.
+func (s *Set) timeLoad(op Op, path, source string) func(error) {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	for _, l := range root.loads {
+		if l.Op == op && l.Path == path && l.Source == source {
+			return func(error) {}
+		}
+	}
+
+	timing := &LoadTiming{Op: op, Path: path, Source: source, Start: time.Now(), Running: true}
+	root.loads = append(root.loads, timing)
+
+	return func(err error) {
+		root.mu.Lock()
+		defer root.mu.Unlock()
+
+		timing.Duration = time.Since(timing.Start)
+		timing.Running = false
+		timing.Err = err
+	}
+}
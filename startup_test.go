@@ -0,0 +1,70 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_Startup(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.json": `{"Name": "file"}`})
+
+	set := &Set{}
+	set.Setting("Name", "", "")
+
+	release := make(chan struct{})
+	defer close(release)
+
+	set.AddProvider("fast", ProviderFunc(func(context.Context) (map[string]string, error) {
+		return map[string]string{}, nil
+	}))
+	set.AddProvider("slow", ProviderFunc(func(context.Context) (map[string]string, error) {
+		<-release
+		return nil, nil
+	}))
+
+	err := set.Startup(context.Background(), 50*time.Millisecond, func(ctx context.Context) error {
+		if err := set.LoadFile(filepath.Join(dir, "config.json")); err != nil {
+			return err
+		}
+		if err := set.LoadEnv("APP"); err != nil {
+			return err
+		}
+		return set.Reload(ctx)
+	})
+
+	var budgetErr *BudgetError
+	if !errors.As(err, &budgetErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Failed to exceed budget: got %v", err)
+	}
+
+	sources := map[string]LoadTiming{}
+	for _, l := range budgetErr.Report.Loads {
+		sources[l.Source] = l
+	}
+	if len(sources) != 4 || sources[SourceEnv].Op != OpLoad || sources["fast"].Op != OpFetch || sources["fast"].Running {
+		t.Errorf("Failed to report loads: got %+v", budgetErr.Report.Loads)
+	}
+	if slow := sources["slow"]; !slow.Running && !errors.Is(slow.Err, context.DeadlineExceeded) {
+		t.Errorf("Failed to report slow provider: got %+v", slow)
+	}
+
+	lines := strings.Split(budgetErr.Error(), "\n")
+	if !strings.Contains(lines[0], "budget of 50ms") || !strings.Contains(lines[1], "slow") {
+		t.Errorf("Failed to break down slowest load first: got %q", budgetErr.Error())
+	}
+
+	// reloads are not reported again
+	_ = set.LoadEnv("APP")
+	if loads := set.LoadReport().Loads; len(loads) != 4 || set.LoadReport().Elapsed() < 50*time.Millisecond {
+		t.Errorf("Failed to keep first loads: got %+v", loads)
+	}
+
+	expected := errors.New("boom")
+	if err := set.Startup(context.Background(), 0, func(context.Context) error { return expected }); err != expected {
+		t.Errorf("Failed to return startup error: expected %v; got %v", expected, err)
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Stats describes the size of a Set tree for capacity monitoring
+type Stats struct {
+	// Settings in the Set and its subsets
+	Settings int
+
+	// Subsets beneath the Set
+	Subsets int
+
+	// Notifiers attached to the Set, its subsets and their settings
+	Notifiers int
+
+	// Bytes is the approximate memory used by the settings, their strings and values
+	Bytes int
+
+	// Interned is the number of distinct values shared by the settings of the whole Set tree, see Set.InternValues
+	Interned int
+
+	// InternSaved is the number of bytes of duplicate values written to the whole Set tree that share memory with an interned value rather than being kept, see Set.InternValues
+	InternSaved int
+}
+
+var (
+	settingSize = int(reflect.TypeOf((*Setting)(nil)).Elem().Size())
+	setSize     = int(reflect.TypeOf((*Set)(nil)).Elem().Size())
+)
+
+// Stats returns the number of settings, subsets and notifiers in the Set tree beneath the Set along with an approximation of their memory footprint
+func (s *Set) Stats() Stats {
+	var stats Stats
+
+	s.Range(func(_ string, setting *Setting) bool {
+		stats.Settings++
+		stats.Notifiers += countMap(&setting.notifiers)
+		stats.Bytes += settingSize + len(setting.Name) + len(setting.Path) + len(setting.Description) + len(setting.Category) + len(setting.Role) + len(setting.DefaultValue)
+
+		// the value itself, its string form is a good approximation for variable sized values
+		if rv := reflect.ValueOf(setting.Value); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			stats.Bytes += int(rv.Elem().Type().Size())
+		}
+		stats.Bytes += len(setting.format())
+
+		return true
+	})
+
+	stats.Notifiers += countMap(&s.notifiers)
+	stats.Bytes += setSize + len(s.path)
+
+	s.Root().children.Range(func(_, v interface{}) bool {
+		set := v.(*Set)
+		if set != s && s.contains(set.path) {
+			stats.Subsets++
+			stats.Notifiers += countMap(&set.notifiers)
+			stats.Bytes += setSize + len(set.path) + len(set.name)
+		}
+
+		return true
+	})
+
+	stats.Interned, stats.InternSaved = s.internStats()
+
+	return stats
+}
+
+// countMap returns the number of entries in the map
+func countMap(m *sync.Map) int {
+	count := 0
+	m.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+
+	return count
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestSet_Stats(t *testing.T) {
+	var (
+		host = "localhost"
+		port = 80
+		name = "app"
+	)
+
+	set := &Set{}
+	set.Setting("Name", &name, "")
+	set.Subset("HTTP").Setting("Host", &host, "")
+	set.Subset("HTTP").Subset("TLS").Setting("Port", &port, "").Notify(NotifyFunc(func(*Setting) {}))
+	set.Subset("HTTP").Notify(NotifyFunc(func(*Setting) {}))
+	set.Subset("Other")
+
+	stats := set.Subset("HTTP").Stats()
+	if stats.Settings != 2 || stats.Subsets != 1 || stats.Notifiers != 2 {
+		t.Errorf("Failed to count subset: got %+v", stats)
+	}
+
+	root := set.Stats()
+	if root.Settings != 3 || root.Subsets != 3 || root.Notifiers != 2 {
+		t.Errorf("Failed to count set: got %+v", root)
+	}
+
+	if root.Bytes <= stats.Bytes || stats.Bytes <= 0 {
+		t.Errorf("Failed to approximate memory: got %d and %d", root.Bytes, stats.Bytes)
+	}
+}
+
+func TestSet_InternValues(t *testing.T) {
+	set := &Set{}
+	set.InternValues()
+
+	for i := 0; i < 100; i++ {
+		set.Subset(fmt.Sprintf("Tenant%d", i)).Setting("Region", "us-east-1", "")
+	}
+
+	region := set.Get("Tenant0.Region")
+	for _, tenant := range []string{"a", "b", "c"} {
+		if _, err := set.Tenant(tenant).Update("Tenant0.Region", "eu-west-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = region.Set("eu-west-1")
+
+	stats := set.Stats()
+	if stats.Interned != 2 {
+		t.Errorf("Failed to intern distinct values: expected %d; got %d", 2, stats.Interned)
+	}
+
+	// 99 duplicate defaults and 3 duplicate overrides plus the change
+	if expected := 99*len("us-east-1") + 3*len("eu-west-1"); stats.InternSaved != expected {
+		t.Errorf("Failed to report saved bytes: expected %d; got %d", expected, stats.InternSaved)
+	}
+
+	a := set.Tenant("a").Get("Tenant0.Region").Value.(string)
+	b := region.Value.(string)
+	if (*reflect.StringHeader)(unsafe.Pointer(&a)).Data != (*reflect.StringHeader)(unsafe.Pointer(&b)).Data {
+		t.Errorf("Failed to share interned value")
+	}
+}
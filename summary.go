@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// SummaryEntry is a setting that is not at its default value, see Set.Summary
+type SummaryEntry struct {
+	// Path of the setting
+	Path string
+
+	// Value of the setting, ***** for masked settings
+	Value string
+
+	// Source of the value, see Setting.Source
+	Source string
+}
+
+// Summary returns every setting of the Set that is not at its default value, sorted by path, along with the source that supplied its value
+func (s *Set) Summary() []SummaryEntry {
+	var entries []SummaryEntry
+	for _, setting := range s.sorted() {
+		if setting.IsDefault() {
+			continue
+		}
+
+		entries = append(entries, SummaryEntry{
+			Path:   setting.Path,
+			Value:  setting.String(),
+			Source: setting.Source(),
+		})
+	}
+
+	return entries
+}
+
+// WriteSummary writes the Set.Summary to w as a single block, intended to be logged once at startup so the effective configuration of a service and where each value came from is on record
+func (s *Set) WriteSummary(w io.Writer) error {
+	entries := s.Summary()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "configuration: %d non-default settings\n", len(entries))
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "  %s\t%q\t(%s)\n", entry.Path, entry.Value, entry.Source)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,20 @@
+//go:build go1.21
+
+package config
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogSummary logs the Set.Summary as a single record at info level, with a group per setting holding its value and source
+func (s *Set) LogSummary(ctx context.Context, logger *slog.Logger) {
+	entries := s.Summary()
+
+	attrs := make([]slog.Attr, 0, len(entries))
+	for _, entry := range entries {
+		attrs = append(attrs, slog.Group(entry.Path, slog.String("value", entry.Value), slog.String("source", entry.Source)))
+	}
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "configuration", attrs...)
+}
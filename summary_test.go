@@ -0,0 +1,45 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSet_WriteSummary(t *testing.T) {
+	set := &Set{}
+	port := set.Setting("Port", 80, "")
+	set.Setting("Host", "localhost", "")
+	password := set.Setting("Password", "", "")
+	password.Mask = true
+
+	if source := port.Source(); source != SourceDefault {
+		t.Errorf("Failed to attribute default value: expected %q; got %q", SourceDefault, source)
+	}
+
+	_ = port.Set("8080")
+	t.Setenv("APP_PASSWORD", "hunter2")
+	if err := set.LoadEnv("APP"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := set.Summary()
+	if len(entries) != 2 || entries[0] != (SummaryEntry{Path: "Password", Value: "*****", Source: SourceEnv}) || entries[1] != (SummaryEntry{Path: "Port", Value: "8080", Source: SourceRuntime}) {
+		t.Fatalf("Failed to summarize: got %+v", entries)
+	}
+
+	var buf bytes.Buffer
+	if err := set.WriteSummary(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"2 non-default settings", `"8080"`, "(env)", "(runtime)"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Failed to write summary: expected %q in %q", expected, buf.String())
+		}
+	}
+
+	if strings.Contains(buf.String(), "hunter2") || strings.Contains(buf.String(), "Host") {
+		t.Errorf("Failed to omit secrets and defaults: got %q", buf.String())
+	}
+}
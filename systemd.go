@@ -0,0 +1,64 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SystemdUnit describes the files written by Set.WriteSystemd
+type SystemdUnit struct {
+	// EnvironmentFile the unmasked settings are written to
+	EnvironmentFile string
+
+	// SecretsFile the masked settings are written to with 0600 permissions, only written when there are masked settings
+	SecretsFile string
+
+	// Prefix of the variable names, see EnvName
+	Prefix string
+}
+
+// WriteSystemd writes the current values of the settings of the Set to the environment files of the unit and a drop-in unit snippet referencing them to w (i.e. /etc/systemd/system/app.service.d/config.conf). Variables are named by EnvName and can be read with Set.LoadEnv. Masked settings and values with secret parts (see Redacter) are only written to the SecretsFile.
+func (s *Set) WriteSystemd(w io.Writer, u SystemdUnit) error {
+	var plain, secret bytes.Buffer
+	for _, setting := range s.sorted() {
+		line := fmt.Sprintf("%s=%s\n", setting.EnvName(u.Prefix), systemdQuote(setting.format()))
+		if setting.sensitive() {
+			secret.WriteString(line)
+		} else {
+			plain.WriteString(line)
+		}
+	}
+
+	if err := writeAtomic(u.EnvironmentFile, plain.Bytes()); err != nil {
+		return fmt.Errorf("unable to write %q: %w", u.EnvironmentFile, err)
+	}
+	if err := os.Chmod(u.EnvironmentFile, 0644); err != nil {
+		return fmt.Errorf("unable to write %q: %w", u.EnvironmentFile, err)
+	}
+
+	dropIn := fmt.Sprintf("[Service]\nEnvironmentFile=%s\n", u.EnvironmentFile)
+
+	if secret.Len() > 0 {
+		if u.SecretsFile == "" {
+			return fmt.Errorf("unable to write masked settings: no secrets file")
+		}
+
+		if err := writeAtomic(u.SecretsFile, secret.Bytes()); err != nil {
+			return fmt.Errorf("unable to write %q: %w", u.SecretsFile, err)
+		}
+
+		dropIn += fmt.Sprintf("EnvironmentFile=%s\n", u.SecretsFile)
+	}
+
+	_, err := io.WriteString(w, dropIn)
+	return err
+}
+
+// systemdQuote double quotes the value for an environment file
+func systemdQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet_WriteSystemd(t *testing.T) {
+	cfg := struct {
+		Name     string
+		Password string `mask:"true"`
+	}{Name: `say "hi"`, Password: "secret"}
+
+	set := &Set{}
+	set.Subset("App").Bind(&cfg)
+
+	dir := t.TempDir()
+	unit := SystemdUnit{
+		EnvironmentFile: filepath.Join(dir, "app.env"),
+		SecretsFile:     filepath.Join(dir, "secrets.env"),
+	}
+
+	dropIn := &bytes.Buffer{}
+	if err := set.WriteSystemd(dropIn, unit); err != nil {
+		t.Fatalf("Failed to write systemd: %v", err)
+	}
+
+	expected := "[Service]\nEnvironmentFile=" + unit.EnvironmentFile + "\nEnvironmentFile=" + unit.SecretsFile + "\n"
+	if dropIn.String() != expected {
+		t.Errorf("Failed to write drop-in: expected %q; got %q", expected, dropIn.String())
+	}
+
+	plain, _ := os.ReadFile(unit.EnvironmentFile)
+	if string(plain) != "APP_NAME=\"say \\\"hi\\\"\"\n" {
+		t.Errorf("Failed to write environment file: got %q", plain)
+	}
+
+	secret, _ := os.ReadFile(unit.SecretsFile)
+	if string(secret) != "APP_PASSWORD=\"secret\"\n" {
+		t.Errorf("Failed to write secrets file: got %q", secret)
+	}
+
+	if info, err := os.Stat(unit.SecretsFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Failed to protect secrets file: got %v", info.Mode())
+	}
+}
+
+func TestSet_WriteSystemdRedacted(t *testing.T) {
+	dir := t.TempDir()
+	unit := SystemdUnit{
+		EnvironmentFile: filepath.Join(dir, "app.env"),
+		SecretsFile:     filepath.Join(dir, "secrets.env"),
+	}
+
+	if err := redactedSet(t).WriteSystemd(&bytes.Buffer{}, unit); err != nil {
+		t.Fatalf("Failed to write systemd: %v", err)
+	}
+
+	plain, _ := os.ReadFile(unit.EnvironmentFile)
+	assertRedacted(t, "environment file", string(plain))
+
+	if secret, _ := os.ReadFile(unit.SecretsFile); !bytes.Contains(secret, []byte("hunter3")) {
+		t.Errorf("Failed to write DSN to secrets file: got %q", secret)
+	}
+}
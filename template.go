@@ -0,0 +1,146 @@
+package config
+
+import "strings"
+
+// settingTemplate is a setting registered with Set.Template
+type settingTemplate struct {
+	// segments of the full path pattern, lower case
+	segments  []string
+	prototype *Setting
+}
+
+// Template registers a setting template for a dynamic keyspace that can't be enumerated at compile time (i.e. per-client or per-queue tuning). The pattern is a path relative to the Set where a * matches exactly one segment, such as Clients.*.Timeout. A concrete setting materializes from the template the first time a matching path is used, whether by Set.Get, Set.Update, a file or a provider, with a copy of the value and the description.
+//
+// The returned prototype is not registered, its Mask, Required, Category, Role, Constraint, Annotations, Labels and NotifyUnchanged are copied to every instance materialized after they are set. Pattern must contain a *, value can not be nil.
+func (s *Set) Template(pattern string, value Value, description string) *Setting {
+	if !strings.Contains(pattern, "*") {
+		panic("pattern must contain a *")
+	}
+	if value == nil {
+		panic("value can not be nil")
+	}
+
+	path := s.pathOf(pattern)
+	prototype := &Setting{
+		Description: description,
+		Path:        path,
+		Value:       value,
+	}
+	prototype.DefaultValue = prototype.format()
+
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	root.templates = append(root.templates, &settingTemplate{
+		segments:  strings.Split(strings.ToLower(path), "."),
+		prototype: prototype,
+	})
+
+	return prototype
+}
+
+// materialize registers the setting at the path from the map field or first template matching it, returning nil when none matches
+func (s *Set) materialize(path string) *Setting {
+	root := s.Root()
+
+	// a map field binds a whole struct for the key
+	if s.materializeMap(path) {
+		if setting, found := root.settings.Load(strings.ToLower(path)); found {
+			return setting.(*Setting)
+		}
+	}
+
+	root.mu.Lock()
+	templates := root.templates
+	root.mu.Unlock()
+
+	if len(templates) == 0 {
+		return nil
+	}
+
+	segments := strings.Split(path, ".")
+	for _, t := range templates {
+		if !t.match(segments) {
+			continue
+		}
+
+		setting, err := root.registerPath(path, t.prototype.clone().Value, t.prototype.Description, func(setting *Setting) {
+			setting.Mask = t.prototype.Mask
+			setting.Required = t.prototype.Required
+			setting.Category = t.prototype.Category
+			setting.Role = t.prototype.Role
+			setting.Constraint = t.prototype.Constraint
+			setting.Annotations = t.prototype.Annotations
+			setting.Labels = t.prototype.Labels
+			setting.NotifyUnchanged = t.prototype.NotifyUnchanged
+		})
+		if err != nil {
+			// materialized concurrently, or beyond the Limits of the Set
+			if existing, found := root.settings.Load(strings.ToLower(path)); found {
+				return existing.(*Setting)
+			}
+			s.trace("template", path, "unable to materialize %q: %v", path, err)
+			return nil
+		}
+
+		s.trace("template", path, "materialized from %s", t.prototype.Path)
+		return setting
+	}
+
+	return nil
+}
+
+// peekTemplate returns a detached setting for the path from the first template matching it, like materialize without registering it, returning nil when none matches
+func (s *Set) peekTemplate(path string) *Setting {
+	root := s.Root()
+
+	root.mu.Lock()
+	templates := root.templates
+	root.mu.Unlock()
+
+	segments := strings.Split(path, ".")
+	for _, t := range templates {
+		if t.match(segments) {
+			setting := t.prototype.clone()
+			setting.Name = segments[len(segments)-1]
+			setting.Path = path
+			return setting
+		}
+	}
+
+	return nil
+}
+
+// match returns if the path segments match the template, * matches any single segment
+func (t *settingTemplate) match(segments []string) bool {
+	if len(segments) != len(t.segments) {
+		return false
+	}
+
+	for i, segment := range segments {
+		if t.segments[i] != "*" && t.segments[i] != strings.ToLower(segment) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Templates returns the path patterns of the templates registered with Set.Template within the Set
+func (s *Set) Templates() []string {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	var patterns []string
+	for _, t := range root.templates {
+		if s.contains(t.prototype.Path) {
+			patterns = append(patterns, t.prototype.Path)
+		}
+	}
+
+	return patterns
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSet_Template(t *testing.T) {
+	s := &Set{}
+	clients := s.Subset("Clients")
+	prototype := clients.Template("*.Timeout", 5*time.Second, "Timeout of the client")
+	clients.Template("*.Token", "", "Token of the client").Mask = true
+	prototype.Labels = []string{"per-client"}
+
+	if s.lookup("Clients.Timeout") != nil || s.lookup("Clients.a.b.Timeout") != nil {
+		t.Errorf("Failed to match segments exactly")
+	}
+
+	acme := s.Get("Clients.Acme.Timeout")
+	if acme == nil || acme.String() != "5s" || acme.Description != "Timeout of the client" || len(acme.Labels) != 1 {
+		t.Fatalf("Failed to materialize on Get: got %v", acme)
+	}
+
+	if found, err := clients.Update("Globex.Timeout", "1m"); !found || err != nil {
+		t.Fatalf("Failed to materialize on Update: %v", err)
+	}
+	if acme.String() != "5s" {
+		t.Errorf("Failed to copy the template value: expected %q; got %q", "5s", acme.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"Clients": {"Initech": {"Token": "secret"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LoadFile(path); err != nil {
+		t.Fatalf("Failed to load file: %v", err)
+	}
+
+	token := s.Get("Clients.Initech.Token")
+	if token == nil || !token.Mask || token.format() != "secret" {
+		t.Errorf("Failed to materialize from file: got %v", token)
+	}
+
+	if patterns := s.Templates(); len(patterns) != 2 || patterns[0] != "Clients.*.Timeout" {
+		t.Errorf("Failed to list templates: got %v", patterns)
+	}
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tenant is an overlay of the settings of a Set for a single tenant. A tenant overrides values without changing the Set or any other tenant, and reads fall back to the Set for values it does not override.
+type Tenant struct {
+	name      string
+	set       *Set
+	overrides sync.Map
+	notifiers sync.Map
+}
+
+// Tenant returns the overlay of the Set for the named tenant, creating it on first use. Tenants are scoped to the Set, so only settings within the Set can be overridden.
+func (s *Set) Tenant(name string) *Tenant {
+	if name == "" {
+		panic("tenant name can not be empty")
+	}
+
+	if t, found := s.tenants.Load(strings.ToLower(name)); found {
+		return t.(*Tenant)
+	}
+
+	t := &Tenant{name: name, set: s}
+	if existing, loaded := s.tenants.LoadOrStore(strings.ToLower(name), t); loaded {
+		return existing.(*Tenant)
+	}
+
+	// changes of the Set are seen by the tenant unless it overrides them, tenants live as long as the Set so the handle is discarded
+	_ = s.Notify(NotifyFunc(func(setting *Setting) {
+		if _, overridden := t.overrides.Load(strings.ToLower(setting.Path)); !overridden {
+			t.notify(setting)
+		}
+	}))
+
+	return t
+}
+
+// Tenants returns the names of the tenants of the Set sorted by name
+func (s *Set) Tenants() []string {
+	var names []string
+	s.tenants.Range(func(_, v interface{}) bool {
+		names = append(names, v.(*Tenant).name)
+		return true
+	})
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Name of the tenant
+func (t *Tenant) Name() string {
+	return t.name
+}
+
+// Get a setting by name as seen by the tenant. Overridden settings are private to the tenant, other settings are shared with the Set and must be changed through Tenant.Update rather than Setting.Set to keep the tenant isolated.
+func (t *Tenant) Get(name string) *Setting {
+	setting := t.set.Get(name)
+	if setting == nil || !t.set.contains(setting.Path) {
+		return nil
+	}
+
+	if override, found := t.overrides.Load(strings.ToLower(setting.Path)); found {
+		return override.(*Setting)
+	}
+
+	return setting
+}
+
+// Update the value of an existing setting by name for the tenant only. The value is checked like Setting.Set, against the Limits, the validator, the Constraint (resolving other settings as seen by the tenant) and the Guards of the Set, and immutable settings can not be overridden.
+func (t *Tenant) Update(name, value string) (bool, error) {
+	setting := t.set.lookup(name)
+	if setting == nil || !t.set.contains(setting.Path) {
+		return false, nil
+	}
+
+	override := setting.clone()
+	if existing, found := t.overrides.Load(strings.ToLower(setting.Path)); found {
+		override = existing.(*Setting).clone()
+	}
+
+	// overrides of many tenants mostly share values, see Set.InternValues
+	switch override.Value.(type) {
+	case string, *string:
+		value = t.set.intern(value)
+	}
+
+	if max := t.set.limits().MaxValueLength; max > 0 && len(value) > max {
+		return true, &LimitError{Limit: "MaxValueLength", Path: setting.Path, Max: max}
+	}
+
+	same := override.Equals(value)
+	if !same {
+		if err := setting.validate(value); err != nil {
+			return true, err
+		}
+
+		if err := setting.constrain(value, t.lookup); err != nil {
+			return true, err
+		}
+
+		if err := t.set.guard(context.Background(), setting, value); err != nil {
+			return true, err
+		}
+	}
+
+	if err := override.convert(value); err != nil {
+		return true, err
+	}
+
+	t.overrides.Store(strings.ToLower(setting.Path), override)
+
+	if !same {
+		t.notify(override)
+	}
+
+	return true, nil
+}
+
+// lookup resolves the value of a setting by path as seen by the tenant, for evaluating constraints
+func (t *Tenant) lookup(path string) (string, bool) {
+	override, found := t.overrides.Load(strings.ToLower(path))
+	if !found {
+		return "", false
+	}
+
+	return override.(*Setting).format(), true
+}
+
+// Reset removes the override of the setting by name, so the tenant sees the value of the Set again
+func (t *Tenant) Reset(name string) {
+	setting := t.set.lookup(name)
+	if setting == nil {
+		return
+	}
+
+	if _, loaded := t.overrides.LoadAndDelete(strings.ToLower(setting.Path)); loaded {
+		t.notify(setting)
+	}
+}
+
+// Range over the settings of the tenant, overridden settings replace the settings of the Set
+func (t *Tenant) Range(fn func(string, *Setting) bool) {
+	t.set.Range(func(key string, setting *Setting) bool {
+		if override, found := t.overrides.Load(key); found {
+			setting = override.(*Setting)
+		}
+
+		return fn(key, setting)
+	})
+}
+
+// Notify when any setting changes as seen by the tenant, either by an override of the tenant or by a change of the Set the tenant does not override
+func (t *Tenant) Notify(n Notifier) *NotifyHandle {
+	if n == nil {
+		return &NotifyHandle{}
+	}
+
+	handle := &NotifyHandle{
+		stopFunc: t.notifiers.Delete,
+	}
+
+	t.notifiers.Store(handle, n)
+
+	return handle
+}
+
+// notify the notifiers of the tenant of the setting
+func (t *Tenant) notify(setting *Setting) {
+	t.notifiers.Range(func(_, v interface{}) bool {
+		t.set.dispatch(v.(Notifier), setting)
+		return true
+	})
+}
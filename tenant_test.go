@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSet_Tenant(t *testing.T) {
+	limit := 100
+	set := &Set{}
+	set.Subset("Limits").Setting("Requests", &limit, "")
+
+	acme := set.Subset("Limits").Tenant("acme")
+	globex := set.Subset("Limits").Tenant("globex")
+
+	var changes []string
+	acme.Notify(NotifyFunc(func(s *Setting) {
+		changes = append(changes, s.String())
+	}))
+
+	if found, err := acme.Update("Requests", "500"); !found || err != nil {
+		t.Fatalf("Failed to override setting: %v", err)
+	}
+
+	if _, err := acme.Update("Requests", "lots"); err == nil {
+		t.Errorf("Failed to reject invalid override")
+	}
+
+	if acme.Get("Requests").String() != "500" || globex.Get("Requests").String() != "100" || limit != 100 {
+		t.Errorf("Failed to isolate tenant: got %s, %s and %d", acme.Get("Requests"), globex.Get("Requests"), limit)
+	}
+
+	// changes of the set are hidden by the override
+	_, _ = set.Update("Limits.Requests", "200")
+
+	if acme.Get("Requests").String() != "500" || globex.Get("Requests").String() != "200" {
+		t.Errorf("Failed to overlay set: got %s and %s", acme.Get("Requests"), globex.Get("Requests"))
+	}
+
+	acme.Reset("Requests")
+
+	if acme.Get("Requests").String() != "200" {
+		t.Errorf("Failed to reset override: got %s", acme.Get("Requests"))
+	}
+
+	if strings.Join(changes, ",") != "500,200" {
+		t.Errorf("Failed to notify tenant: expected %q; got %q", "500,200", strings.Join(changes, ","))
+	}
+
+	if tenants := set.Subset("Limits").Tenants(); strings.Join(tenants, ",") != "acme,globex" || len(set.Tenants()) != 0 {
+		t.Errorf("Failed to enumerate tenants: got %v", tenants)
+	}
+}
+
+func TestTenant_UpdateChecked(t *testing.T) {
+	var (
+		min     = 10
+		max     = 100
+		version = "v1"
+	)
+
+	set := &Set{}
+	set.Setting("Min", &min, "")
+	set.Setting("Max", &max, "").Constraint = "this >= Min"
+	set.NewSetting("Version", &version, WithImmutable())
+	set.Limit(Limits{MaxValueLength: 8})
+	set.Guard(Guards{MaxMagnitude: 1000})
+
+	acme := set.Tenant("acme")
+
+	if _, err := acme.Update("Min", "50"); err != nil {
+		t.Fatalf("Failed to override setting: %v", err)
+	}
+
+	// constraints resolve other settings as seen by the tenant
+	var constraintErr *ConstraintError
+	if _, err := acme.Update("Max", "20"); !errors.As(err, &constraintErr) {
+		t.Errorf("Failed to constrain override: got %v", err)
+	}
+	if _, err := set.Tenant("globex").Update("Max", "20"); err != nil {
+		t.Errorf("Failed to constrain against the set: %v", err)
+	}
+
+	if _, err := acme.Update("Version", "v2"); !errors.Is(err, ErrImmutable) {
+		t.Errorf("Failed to reject immutable override: expected %v; got %v", ErrImmutable, err)
+	}
+
+	if _, err := acme.Update("Max", "123456789"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Failed to limit override: expected %v; got %v", ErrLimitExceeded, err)
+	}
+
+	if _, err := acme.Update("Max", "5000"); !errors.Is(err, ErrGuardRejected) {
+		t.Errorf("Failed to guard override: expected %v; got %v", ErrGuardRejected, err)
+	}
+
+	if acme.Get("Max").String() != "100" || acme.Get("Version").String() != "v1" {
+		t.Errorf("Failed to leave rejected overrides out: got %s and %s", acme.Get("Max"), acme.Get("Version"))
+	}
+}
+
+func TestSet_RangeSubset(t *testing.T) {
+	var (
+		httpPort  = 80
+		httpsPort = 443
+	)
+
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", &httpPort, "")
+	set.Subset("HTTPS").Setting("Port", &httpsPort, "")
+
+	var paths []string
+	set.Subset("HTTP").Range(func(_ string, s *Setting) bool {
+		paths = append(paths, s.Path)
+		return true
+	})
+
+	if strings.Join(paths, ",") != "HTTP.Port" {
+		t.Errorf("Failed to range subset: expected %q; got %q", "HTTP.Port", paths)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WriteTerraform writes a variables.tf declaration for every setting of the Set to variables, with its type, default and description, and the current values as terraform.tfvars to tfvars. Variables are named by EnvName in lower case (HTTP.MaxConns is http_max_conns). Masked settings and values with secret parts (see Redacter) are declared sensitive without a default and are left as comments in tfvars.
+func (s *Set) WriteTerraform(variables, tfvars io.Writer) error {
+	vw := bufio.NewWriter(variables)
+	tw := bufio.NewWriter(tfvars)
+
+	for i, setting := range s.sorted() {
+		name := strings.ToLower(EnvName("", setting.Path))
+		kind := terraformType(setting)
+
+		if i > 0 {
+			fmt.Fprintln(vw)
+		}
+
+		fmt.Fprintf(vw, "variable %q {\n  type = %s\n", name, kind)
+		if setting.Description != "" {
+			fmt.Fprintf(vw, "  description = %s\n", terraformString(setting.Description))
+		}
+		sensitive := setting.sensitive()
+		if sensitive {
+			fmt.Fprintf(vw, "  sensitive = true\n")
+		} else {
+			fmt.Fprintf(vw, "  default = %s\n", terraformValue(kind, setting.DefaultValue))
+		}
+		fmt.Fprintln(vw, "}")
+
+		if sensitive {
+			fmt.Fprintf(tw, "# %s is sensitive and must be provided separately\n", name)
+		} else {
+			fmt.Fprintf(tw, "%s = %s\n", name, terraformValue(kind, setting.format()))
+		}
+	}
+
+	if err := vw.Flush(); err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+// terraformType returns the type of the variable for the setting
+func terraformType(setting *Setting) string {
+	if _, ok := setting.Value.(Marshaler); ok {
+		return "string"
+	}
+
+	kind := reflect.Indirect(reflect.ValueOf(setting.Value)).Kind()
+	switch {
+	case kind == reflect.Bool:
+		return "bool"
+	case kind >= reflect.Int && kind <= reflect.Float64:
+		// named numbers such as time.Duration are written in their string form
+		if _, err := strconv.ParseFloat(setting.DefaultValue, 64); err == nil {
+			return "number"
+		}
+	}
+
+	return "string"
+}
+
+// terraformValue returns the value as a literal of the type
+func terraformValue(kind, value string) string {
+	if kind == "string" {
+		return terraformString(value)
+	}
+
+	return value
+}
+
+// terraformString quotes the string, escaping template sequences
+func terraformString(value string) string {
+	value = strings.ReplaceAll(value, "${", "$${")
+	value = strings.ReplaceAll(value, "%{", "%%{")
+
+	return strconv.Quote(value)
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet_WriteTerraform(t *testing.T) {
+	cfg := struct {
+		MaxConns int           `description:"maximum connections"`
+		Timeout  time.Duration `description:"uses ${timeout}"`
+		Debug    bool
+		Token    string `mask:"true"`
+	}{MaxConns: 10, Timeout: time.Second, Token: "secret"}
+
+	set := &Set{}
+	set.Subset("HTTP").Bind(&cfg)
+	_, _ = set.Update("HTTP.MaxConns", "20")
+
+	variables := &bytes.Buffer{}
+	tfvars := &bytes.Buffer{}
+	if err := set.WriteTerraform(variables, tfvars); err != nil {
+		t.Fatalf("Failed to write terraform: %v", err)
+	}
+
+	expectedVariables := `variable "http_debug" {
+  type = bool
+  default = false
+}
+
+variable "http_max_conns" {
+  type = number
+  description = "maximum connections"
+  default = 10
+}
+
+variable "http_timeout" {
+  type = string
+  description = "uses $${timeout}"
+  default = "1s"
+}
+
+variable "http_token" {
+  type = string
+  sensitive = true
+}
+`
+	if variables.String() != expectedVariables {
+		t.Errorf("Failed to write variables:\nexpected:\n%s\ngot:\n%s", expectedVariables, variables.String())
+	}
+
+	expectedTfvars := `http_debug = false
+http_max_conns = 20
+http_timeout = "1s"
+# http_token is sensitive and must be provided separately
+`
+	if tfvars.String() != expectedTfvars {
+		t.Errorf("Failed to write tfvars:\nexpected:\n%s\ngot:\n%s", expectedTfvars, tfvars.String())
+	}
+}
+
+func TestSet_WriteTerraformRedacted(t *testing.T) {
+	variables, tfvars := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := redactedSet(t).WriteTerraform(variables, tfvars); err != nil {
+		t.Fatalf("Failed to write terraform: %v", err)
+	}
+
+	assertRedacted(t, "variables", variables.String())
+	assertRedacted(t, "tfvars", tfvars.String())
+	if !strings.Contains(variables.String(), "sensitive = true") {
+		t.Errorf("Failed to declare DSN sensitive:\n%s", variables.String())
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import "fmt"
+
+// TraceFunc receives diagnostic messages about an operation on a setting path. Operations are "register", "skip", "miss" and "convert".
+type TraceFunc func(op, path, message string)
+
+// Trace sets the function called for every registration, field skipped by Bind, lookup miss and value conversion within the Set tree. Values of masked settings are traced as ***** and Redacter values redacted. This is useful to debug why a field isn't bound or why a value is wrong without adding prints to the library. A nil fn disables tracing.
+func (s *Set) Trace(fn TraceFunc) {
+	s.Root().tracer.Store(&fn)
+}
+
+// trace the operation if a TraceFunc is set on the root
+func (s *Set) trace(op, path, format string, args ...interface{}) {
+	fn, _ := s.Root().tracer.Load().(*TraceFunc)
+	if fn == nil || *fn == nil {
+		return
+	}
+
+	(*fn)(op, path, fmt.Sprintf(format, args...))
+}
+
+// tracing returns if a TraceFunc is set on the root, so messages costly to build can be skipped
+func (s *Set) tracing() bool {
+	fn, _ := s.Root().tracer.Load().(*TraceFunc)
+	return fn != nil && *fn != nil
+}
@@ -0,0 +1,15 @@
+//go:build go1.21
+
+package config
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogTrace returns a TraceFunc writing every trace message to the logger at debug level
+func SlogTrace(logger *slog.Logger) TraceFunc {
+	return func(op, path, message string) {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, message, slog.String("op", op), slog.String("path", path))
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSet_Trace(t *testing.T) {
+	var traces []string
+
+	set := &Set{}
+	set.Trace(func(op, path, message string) {
+		traces = append(traces, op+" "+path)
+	})
+
+	cfg := struct {
+		Port    int
+		private string
+		Ignored string `setting:"-"`
+	}{}
+	set.Subset("HTTP").Bind(&cfg)
+
+	set.Get("HTTP.Missing")
+	_, _ = set.Update("HTTP.Port", "nope")
+	_, _ = set.Update("HTTP.Port", "8080")
+
+	expected := []string{
+		"register HTTP.Port",
+		"skip HTTP.private",
+		"skip HTTP.Ignored",
+		"miss HTTP.Missing",
+		"convert HTTP.Port",
+		"convert HTTP.Port",
+	}
+
+	if strings.Join(traces, ",") != strings.Join(expected, ",") {
+		t.Errorf("Failed to trace operations:\nexpected %v\ngot      %v", expected, traces)
+	}
+
+	set.Trace(nil)
+	set.Get("HTTP.Missing")
+
+	if len(traces) != len(expected) {
+		t.Errorf("Failed to disable tracing: got %v", traces[len(expected):])
+	}
+}
+
+func TestSet_TraceRedacted(t *testing.T) {
+	var traces []string
+
+	set := redactedSet(t)
+	set.Setting("Port", 80, "").Mask = true
+	set.Trace(func(op, path, message string) {
+		traces = append(traces, message)
+	})
+
+	_, _ = set.Update("DB", "postgres://app:hunter4@db:5432/app")
+	_, _ = set.Update("DB", "postgres://app:hunter5@db:5432/app\x00")
+	_, _ = set.Update("Port", "hunter6")
+	_, _ = set.Update("Port", "8080")
+
+	if len(traces) != 4 {
+		t.Errorf("Failed to trace conversions: got %v", traces)
+	}
+	assertRedacted(t, "trace", strings.Join(traces, "\n"))
+	if strings.Contains(strings.Join(traces, "\n"), "8080") {
+		t.Errorf("Failed to mask traced value: got %v", traces)
+	}
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxImportSize is the largest document TransferServer accepts
+const maxImportSize = 8 << 20
+
+// Export writes every setting of the Set as a JSON document keyed by path relative to the Set, readable by Set.LoadFile and Set.Import. Masked settings are written as ***** and Redacter values in their redacted form, which Set.Import leaves untouched, so a snapshot can be moved between environments without carrying secrets.
+func (s *Set) Export(w io.Writer) error {
+	document := map[string]interface{}{}
+	for _, setting := range s.sorted() {
+		nest(document, strings.Split(s.relative(setting.Path), "."), setting.display(setting.format()))
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode export: %w", err)
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// relative returns the path relative to the Set
+func (s *Set) relative(path string) string {
+	if s.path == "" {
+		return path
+	}
+
+	return strings.TrimPrefix(path[len(s.path):], ".")
+}
+
+// importChange is a setting changed by an import and the revision it was planned against
+type importChange struct {
+	setting  *Setting
+	value    string
+	source   string
+	revision uint64
+}
+
+// PlanImport returns the changes Set.Import would make with the document, in any format of Set.LoadFile selected by the extension of the name or the content, without applying anything. Masked values are shown as ***** and Redacter values redacted, like Set.Export. See Set.Import for the checks made.
+func (s *Set) PlanImport(ctx context.Context, name string, data []byte) ([]Change, error) {
+	_, changes, err := s.planImport(ctx, name, data)
+	return changes, err
+}
+
+// Import applies the document on behalf of the caller identified by the ctx as a single transaction, returning the changes made. Before anything is applied, every value is checked like Set.ValidateFile and against the Guards of the Set with every problem returned in a *ValidationError, and every change is authorized like Set.UpdateContext, failing with an error wrapping ErrForbidden when denied. Settings requiring approval (see LabelApprovalRequired) can not be changed by an import. Masked settings written as ***** and Redacter values written in their redacted form (see Set.Export) are left untouched, and documents can not include other documents.
+//
+// When a setting fails to apply, or changed since the changes were planned (see ErrRevisionMismatch), the settings already changed are restored to their previous value and source and the error returned. Notifications are held back until the import completes (see Set.Silence), so notifiers never see part of an import. Imported values are runtime changes saved by Set.Persist.
+func (s *Set) Import(ctx context.Context, name string, data []byte) ([]Change, error) {
+	planned, changes, err := s.planImport(ctx, name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Silence()
+	defer s.Resume()
+
+	for i, c := range planned {
+		c := c
+		previous, source := c.setting.format(), c.setting.storedSource()
+		err := c.setting.setIf(ctx, c.value, "", func() error {
+			if current := c.setting.Revision(); current != c.revision {
+				return fmt.Errorf("%w: expected %d; got %d", ErrRevisionMismatch, c.revision, current)
+			}
+			return nil
+		})
+		if err != nil {
+			s.restore(ctx, planned[:i])
+			return nil, &SettingError{Path: c.setting.Path, Err: err}
+		}
+
+		// the value and source the setting is restored to when a later setting fails
+		planned[i].value, planned[i].source = previous, source
+	}
+
+	// runtime changes are saved when the Set is persisted
+	for _, c := range planned {
+		s.persist(c.setting)
+	}
+
+	return changes, nil
+}
+
+// restore the settings changed by an import to the previous values and sources held by the changes, latest first
+func (s *Set) restore(ctx context.Context, applied []importChange) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		c := applied[i]
+		if err := c.setting.setFrom(ctx, c.value, c.source); err != nil {
+			logger().Error("unable to restore setting after failed import", "path", c.setting.Path, "error", err)
+		}
+	}
+}
+
+// planImport decodes and checks the document, returning the settings it changes in path order
+func (s *Set) planImport(ctx context.Context, name string, data []byte) ([]importChange, []Change, error) {
+	values, err := (&fileReader{}).decode(name, "", data, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	values = s.resolveEnvNames(values)
+
+	// masked settings exported as *****, and Redacter values exported redacted, keep their value
+	for path, v := range values {
+		if setting := s.lookup(path); setting != nil && setting.redacted(v) {
+			delete(values, path)
+		}
+	}
+
+	if err := s.validate(values, ""); err != nil {
+		return nil, nil, err
+	}
+
+	imported := make(map[*Setting]string, len(values))
+	for path, v := range values {
+		imported[s.lookup(path)] = v
+	}
+
+	var planned []importChange
+	var changes []Change
+	var errs []*SettingError
+	for _, setting := range s.sorted() {
+		v, found := imported[setting]
+		if !found || setting.Equals(v) {
+			continue
+		}
+
+		// the document was exported at the revision expected by an If-Match header, see TransferServer
+		if revision, ok := ifMatch(ctx); ok && setting.Revision() > revision {
+			return nil, nil, &SettingError{Path: setting.Path, Err: fmt.Errorf("%w: changed at %d after %d", ErrRevisionMismatch, setting.Revision(), revision)}
+		}
+
+		if err := s.authorize(ctx, setting); err != nil {
+			return nil, nil, &SettingError{Path: setting.Path, Err: err}
+		}
+
+		if setting.requiresApproval() && ctx.Value(trustedContextKey{}) == nil {
+			return nil, nil, &SettingError{Path: setting.Path, Err: fmt.Errorf("%w: requires approval and can not be imported", ErrForbidden)}
+		}
+
+		if err := s.guard(ctx, setting, v); err != nil {
+			errs = append(errs, &SettingError{Path: setting.Path, Err: err})
+			continue
+		}
+
+		// normalize the value through the setting so the preview shows what will be applied
+		to := setting.clone()
+		_ = to.convert(v)
+
+		planned = append(planned, importChange{setting: setting, value: v, revision: setting.Revision()})
+
+		// masked and Redacter values are shown as they are exported
+		changes = append(changes, Change{Path: setting.Path, From: setting.display(setting.format()), To: setting.display(to.format())})
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, &ValidationError{Errors: errs}
+	}
+
+	return planned, changes, nil
+}
+
+// TransferServer exports and imports the settings of a Set over HTTP so configuration can be moved between environments: GET downloads a masked snapshot (see Set.Export), POST uploads a document and responds with the changes it makes as JSON (see WriteDiffJSON), only applying them when the apply query parameter is true (see Set.Import). The Set.Revision of a snapshot is returned as its ETag, an upload with an If-Match header fails with 412 Precondition Failed when a setting it changes was changed after that revision. The format of an upload is selected by the Content-Type, the extension of the URL path or the content. The actor and roles of the caller are read from the request context, so the server is typically wrapped by authentication middleware calling WithRoles.
+type TransferServer struct {
+	// Set being exposed
+	Set *Set
+
+	// Token, when not empty, is required from clients as a bearer token
+	Token string
+}
+
+// ServeHTTP exports, previews or imports the settings of the Set
+func (ts *TransferServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkBearer(w, r, ts.Token) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="config.json"`)
+		w.Header().Set("ETag", etag(ts.Set.Revision()))
+		_ = ts.Set.Export(w)
+
+	case http.MethodPost:
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		ctx, err := withIfMatch(r.Context(), r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, conditional := ifMatch(ctx)
+
+		name := documentName(r.URL, r.Header.Get("Content-Type"))
+
+		var changes []Change
+		if apply, _ := strconv.ParseBool(r.URL.Query().Get("apply")); apply {
+			changes, err = ts.Set.Import(ctx, name, data)
+		} else {
+			changes, err = ts.Set.PlanImport(ctx, name, data)
+		}
+
+		switch {
+		case err == nil:
+			w.Header().Set("Content-Type", "application/json")
+			_ = WriteDiffJSON(w, changes)
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ErrRevisionMismatch) && conditional:
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		case errors.Is(err, ErrRevisionMismatch):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		}
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
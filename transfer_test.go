@@ -0,0 +1,219 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSet_Import(t *testing.T) {
+	type config struct {
+		Password string `mask:"true"`
+		Timeout  time.Duration
+		Server   struct {
+			Host string
+			Port int
+		}
+		Admin string `role:"admin"`
+	}
+
+	source := &Set{}
+	exported := config{Password: "secret", Timeout: time.Minute}
+	exported.Server.Host, exported.Server.Port = "prod.internal", 443
+	source.Bind(&exported)
+
+	buf := &bytes.Buffer{}
+	if err := source.Export(buf); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if strings.Contains(buf.String(), "secret") || !strings.Contains(buf.String(), `"Password": "*****"`) {
+		t.Errorf("Failed to mask export:\n%s", buf.String())
+	}
+
+	target := &Set{}
+	cfg := config{Password: "local", Timeout: time.Second}
+	target.Bind(&cfg)
+
+	changes, err := target.PlanImport(context.Background(), "config.json", buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to plan import: %v", err)
+	}
+	expected := []Change{{Path: "Server.Host", From: "", To: "prod.internal"}, {Path: "Server.Port", From: "0", To: "443"}, {Path: "Timeout", From: "1s", To: "1m0s"}}
+	if len(changes) != len(expected) {
+		t.Fatalf("Failed to plan import: expected %+v; got %+v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Failed to plan import: expected %+v; got %+v", expected[i], changes[i])
+		}
+	}
+	if cfg.Timeout != time.Second {
+		t.Error("Failed to leave Set unchanged by plan")
+	}
+
+	if _, err := target.Import(context.Background(), "config.json", buf.Bytes()); err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	if cfg.Password != "local" || cfg.Timeout != time.Minute || cfg.Server.Port != 443 {
+		t.Errorf("Failed to import: got %+v", cfg)
+	}
+
+	// nothing is applied when a value is invalid
+	_, err = target.Import(context.Background(), "config.yaml", []byte("Timeout: 5m\nServer:\n  Port: many\n"))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || cfg.Timeout != time.Minute {
+		t.Errorf("Failed to reject invalid import: got %v", err)
+	}
+
+	target.Authorize(RoleAuthorizer)
+	if _, err := target.Import(context.Background(), "config.json", []byte(`{"Timeout": "5m", "Admin": "x"}`)); !errors.Is(err, ErrForbidden) || cfg.Timeout != time.Minute {
+		t.Errorf("Failed to forbid import: expected %v; got %v", ErrForbidden, err)
+	}
+
+	// settings already changed are restored with their source when a later setting fails, without notifying
+	if err := target.Get("Server.Port").setFrom(context.Background(), "8443", "prod.json"); err != nil {
+		t.Fatalf("Failed to load port: %v", err)
+	}
+
+	var notified int
+	target.Get("Server.Port").Notify(NotifyFunc(func(setting *Setting) {
+		notified++
+		if cfg.Server.Port != 8443 {
+			t.Errorf("Failed to hold back notification: got port %d", cfg.Server.Port)
+		}
+	}))
+
+	var once sync.Once
+	target.Hook(HookFunc(func(e Event) {
+		if e.Op == OpSet && e.Path == "Server.Port" && e.Err == nil {
+			once.Do(func() { target.Get("Timeout").Set("2m") })
+		}
+	}))
+
+	_, err = target.Import(context.Background(), "config.json", []byte(`{"Timeout": "5m", "Server": {"Port": 9090}}`))
+	if !errors.Is(err, ErrRevisionMismatch) || cfg.Server.Port != 8443 {
+		t.Errorf("Failed to restore failed import: got %v with port %d", err, cfg.Server.Port)
+	}
+	if source := target.Get("Server.Port").Source(); source != "prod.json" {
+		t.Errorf("Failed to restore source: expected %q; got %q", "prod.json", source)
+	}
+	if notified > 1 {
+		t.Errorf("Failed to hold back notifications: notified %d times", notified)
+	}
+}
+
+func TestSet_ImportRedacted(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := redactedSet(t).Export(buf); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	assertRedacted(t, "export", buf.String())
+
+	target := redactedSet(t)
+
+	changes, err := target.PlanImport(context.Background(), "config.json", buf.Bytes())
+	if err != nil || len(changes) != 0 {
+		t.Errorf("Failed to leave redacted value untouched: got %v with %+v", err, changes)
+	}
+
+	if _, err := target.Import(context.Background(), "config.json", buf.Bytes()); err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	if dsn := target.Get("DB").format(); dsn != "postgres://app:hunter3@db:5432/app" {
+		t.Errorf("Failed to keep redacted value: got %q", dsn)
+	}
+}
+
+func TestSet_PlanImportRedacted(t *testing.T) {
+	changes, err := redactedSet(t).PlanImport(context.Background(), "config.json", []byte(`{"DB": "postgres://app:hunter4@db:5432/app"}`))
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("Failed to plan import: got %v with %+v", err, changes)
+	}
+
+	assertRedacted(t, "import preview", fmt.Sprintf("%+v", changes))
+}
+
+func TestTransferServer(t *testing.T) {
+	cfg := struct {
+		Name string
+	}{Name: "old"}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	server := &TransferServer{Set: set}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/config", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"Name": "old"`) || w.Header().Get("Content-Disposition") == "" {
+		t.Errorf("Failed to export: got %d %s", w.Code, w.Body.String())
+	}
+
+	upload := func(query, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/config"+query, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/yaml")
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := upload("", "Name: new\n"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"to":"new"`) || cfg.Name != "old" {
+		t.Errorf("Failed to preview import: got %d %s", w.Code, w.Body.String())
+	}
+	if w := upload("?apply=true", "Name: new\n"); w.Code != http.StatusOK || cfg.Name != "new" {
+		t.Errorf("Failed to apply import: got %d %s", w.Code, w.Body.String())
+	}
+	if w := upload("?apply=true", "Unknown: value\n"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Failed to reject unknown setting: expected %d; got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestTransferServer_IfMatch(t *testing.T) {
+	cfg := struct {
+		Name string
+		Port int
+	}{Name: "old", Port: 80}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	server := &TransferServer{Set: set}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/config", nil))
+	exported := w.Header().Get("ETag")
+	if exported == "" {
+		t.Fatalf("Failed to return ETag of export")
+	}
+
+	upload := func(ifMatch, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/config?apply=true", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/yaml")
+		r.Header.Set("If-Match", ifMatch)
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	// another operator changes the name after the export
+	_ = set.Get("Name").Set("theirs")
+
+	if w := upload(exported, "Name: mine\n"); w.Code != http.StatusPreconditionFailed || cfg.Name != "theirs" {
+		t.Errorf("Failed to reject stale import: expected %d; got %d %s with %q", http.StatusPreconditionFailed, w.Code, w.Body.String(), cfg.Name)
+	}
+	if w := upload(exported, "Port: 8080\n"); w.Code != http.StatusOK || cfg.Port != 8080 {
+		t.Errorf("Failed to import unchanged setting: got %d %s", w.Code, w.Body.String())
+	}
+	if w := upload("nope", "Port: 9090\n"); w.Code != http.StatusBadRequest {
+		t.Errorf("Failed to reject invalid If-Match: expected %d; got %d", http.StatusBadRequest, w.Code)
+	}
+}
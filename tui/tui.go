@@ -0,0 +1,251 @@
+// Package tui provides a terminal browser for a live config.Set that binaries can expose behind a hidden subcommand for debugging on hosts without an HTTP admin interface.
+//
+// The browser is line oriented so it works over any terminal, pipe or remote shell:
+//
+//	ls [path]           list the subsets and settings at the current or supplied path
+//	cd <path>           change the current path, ".." moves to the parent and "/" to the root
+//	get <path>          show a setting with its default and description
+//	set <path> <value>  update a setting, the value is validated by the setting
+//	find <query>        search paths and descriptions
+//	watch               toggle printing of live changes
+//	help                show the commands
+//	quit                exit the browser
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/portcullis/config"
+)
+
+// Browser of a config.Set
+type Browser struct {
+	// Set being browsed
+	Set *config.Set
+
+	// In is read for commands
+	In io.Reader
+
+	// Out is written with the results of commands and live changes
+	Out io.Writer
+
+	// Locale descriptions are shown and searched in, see config.Setting.Describe
+	Locale string
+
+	mu       sync.Mutex
+	path     string
+	watching bool
+}
+
+// Run the browser on the set until quit is entered or in is exhausted
+func Run(set *config.Set, in io.Reader, out io.Writer) error {
+	b := &Browser{Set: set, In: in, Out: out}
+	return b.Run()
+}
+
+// Run the Browser until quit is entered or In is exhausted
+func (b *Browser) Run() error {
+	handle := b.Set.Notify(config.NotifyFunc(b.changed))
+	defer handle.Close()
+
+	scanner := bufio.NewScanner(b.In)
+	for {
+		b.printf("%s> ", b.prompt())
+
+		if !scanner.Scan() {
+			b.printf("\n")
+			return scanner.Err()
+		}
+
+		command, args, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		args = strings.TrimSpace(args)
+
+		switch command {
+		case "":
+		case "ls":
+			b.list(b.resolve(args))
+		case "cd":
+			b.cd(args)
+		case "get":
+			b.get(args)
+		case "set":
+			path, value, _ := strings.Cut(args, " ")
+			b.set(path, strings.TrimSpace(value))
+		case "find":
+			b.find(args)
+		case "watch":
+			b.mu.Lock()
+			b.watching = !b.watching
+			watching := b.watching
+			b.mu.Unlock()
+			b.printf("watching: %v\n", watching)
+		case "help":
+			b.printf("commands: ls [path], cd <path>, get <path>, set <path> <value>, find <query>, watch, help, quit\n")
+		case "quit", "exit":
+			return nil
+		default:
+			b.printf("unknown command %q, try help\n", command)
+		}
+	}
+}
+
+// printf writes to Out, serialized with live change output
+func (b *Browser) printf(format string, args ...interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fmt.Fprintf(b.Out, format, args...)
+}
+
+// changed prints live changes while watching
+func (b *Browser) changed(s *config.Setting) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.watching {
+		fmt.Fprintf(b.Out, "\n* %s = %q\n", s.Path, s.String())
+	}
+}
+
+func (b *Browser) prompt() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.path == "" {
+		return "/"
+	}
+
+	return b.path
+}
+
+// resolve the argument relative to the current path
+func (b *Browser) resolve(arg string) string {
+	b.mu.Lock()
+	current := b.path
+	b.mu.Unlock()
+
+	switch {
+	case arg == "" || arg == ".":
+		return current
+	case arg == "/":
+		return ""
+	case strings.HasPrefix(arg, "/"):
+		return strings.TrimPrefix(arg, "/")
+	case arg == "..":
+		if i := strings.LastIndex(current, "."); i >= 0 {
+			return current[:i]
+		}
+		return ""
+	case current == "":
+		return arg
+	default:
+		return current + "." + arg
+	}
+}
+
+// settings returns every setting sorted by path
+func (b *Browser) settings() []*config.Setting {
+	var settings []*config.Setting
+	b.Set.Range(func(_ string, s *config.Setting) bool {
+		settings = append(settings, s)
+		return true
+	})
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	return settings
+}
+
+// list the immediate children of the path
+func (b *Browser) list(path string) {
+	prefix := strings.ToLower(path)
+	if prefix != "" {
+		prefix += "."
+	}
+
+	seen := map[string]bool{}
+	found := false
+
+	for _, s := range b.settings() {
+		if !strings.HasPrefix(strings.ToLower(s.Path), prefix) {
+			continue
+		}
+		found = true
+
+		rest := s.Path[len(prefix):]
+		if name, _, subset := strings.Cut(rest, "."); subset {
+			if !seen[name] {
+				seen[name] = true
+				b.printf("%s/\n", name)
+			}
+			continue
+		}
+
+		b.printf("%s = %q\n", rest, s.String())
+	}
+
+	if !found {
+		b.printf("nothing found at %q\n", path)
+	}
+}
+
+func (b *Browser) cd(arg string) {
+	path := b.resolve(arg)
+
+	prefix := strings.ToLower(path) + "."
+	for _, s := range b.settings() {
+		if path == "" || strings.HasPrefix(strings.ToLower(s.Path), prefix) {
+			b.mu.Lock()
+			b.path = path
+			b.mu.Unlock()
+			return
+		}
+	}
+
+	b.printf("no subset at %q\n", path)
+}
+
+func (b *Browser) get(arg string) {
+	s := b.Set.Get(b.resolve(arg))
+	if s == nil {
+		b.printf("no setting at %q\n", b.resolve(arg))
+		return
+	}
+
+	defaultValue := s.DefaultValue
+	if s.Mask {
+		defaultValue = "*****"
+	}
+
+	b.printf("%s\n  type:        %s\n  value:       %q\n  default:     %q\n  description: %s\n", s.Path, s.Type(), s.String(), defaultValue, s.Describe(b.Locale))
+}
+
+func (b *Browser) set(arg, value string) {
+	s := b.Set.Get(b.resolve(arg))
+	if s == nil {
+		b.printf("no setting at %q\n", b.resolve(arg))
+		return
+	}
+
+	if err := s.Set(value); err != nil {
+		b.printf("invalid value: %v\n", err)
+		return
+	}
+
+	b.printf("%s = %q\n", s.Path, s.String())
+}
+
+func (b *Browser) find(query string) {
+	query = strings.ToLower(query)
+
+	for _, s := range b.settings() {
+		if strings.Contains(strings.ToLower(s.Path), query) || strings.Contains(strings.ToLower(s.Describe(b.Locale)), query) {
+			b.printf("%s = %q\n", s.Path, s.String())
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/portcullis/config"
+)
+
+func TestRun(t *testing.T) {
+	set := &config.Set{}
+	set.Subset("HTTP").Setting("Port", 80, "Port to listen")
+	set.Subset("HTTP").Setting("Host", "localhost", "Host to listen")
+	set.Setting("Name", "app", "")
+
+	input := strings.Join([]string{
+		"ls",
+		"cd HTTP",
+		"ls",
+		"set Port nope",
+		"watch",
+		"set Port 8080",
+		"find listen",
+		"quit",
+	}, "\n")
+
+	out := &bytes.Buffer{}
+	if err := Run(set, strings.NewReader(input), out); err != nil {
+		t.Fatalf("Failed to run browser: %v", err)
+	}
+
+	for _, expected := range []string{
+		"HTTP/\n",
+		`Name = "app"`,
+		"HTTP> ",
+		`Port = "80"`,
+		"invalid value",
+		`* HTTP.Port = "8080"`,
+		`HTTP.Host = "localhost"`,
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Failed to find %q in output:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Percent is a fraction expressed as a percentage in settings, "75%" is stored as 0.75. The percent sign is required so 75 is never mistaken for the fraction (i.e. 7500%), and the percentage must be within 0 and 100%.
+type Percent float64
+
+// UnmarshalSetting implements Unmarshaler
+func (p *Percent) UnmarshalSetting(v string) error {
+	pv, err := parsePercent(v)
+	if err != nil {
+		return err
+	}
+
+	*p = pv
+	return nil
+}
+
+// MarshalSetting implements Marshaler, the percentage is rounded to 6 decimal places so float error (i.e. 0.07 * 100) does not show
+func (p *Percent) MarshalSetting() string {
+	return strconv.FormatFloat(p.percentage(), 'g', -1, 64) + "%"
+}
+
+// Equals implements Equality, comparing at the precision of MarshalSetting
+func (p *Percent) Equals(v string) bool {
+	pv, err := parsePercent(v)
+	if err != nil {
+		return false
+	}
+
+	return p.percentage() == pv.percentage()
+}
+
+// percentage returns the fraction as a percentage rounded to 6 decimal places
+func (p *Percent) percentage() float64 {
+	return math.Round(float64(*p)*100*1e6) / 1e6
+}
+
+func parsePercent(v string) (Percent, error) {
+	v = strings.TrimSpace(v)
+	if !strings.HasSuffix(v, "%") {
+		return 0, fmt.Errorf("invalid percentage %q: missing %% sign", v)
+	}
+
+	pv, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(v, "%")), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q: %w", v, err)
+	}
+
+	// NaN fails both comparisons
+	if !(pv >= 0 && pv <= 100) {
+		return 0, fmt.Errorf("invalid percentage %q: must be within 0 and 100%%", v)
+	}
+
+	return Percent(pv / 100), nil
+}
+
+// Rate is a count over a period of time expressed as "100/s", "5/m", "1/h" or with any duration as the period ("10/30s"), the zero Rate is expressed as an empty string
+type Rate struct {
+	Count float64
+	Per   time.Duration
+}
+
+// PerSecond returns the rate normalized to one second
+func (r Rate) PerSecond() float64 {
+	if r.Per <= 0 {
+		return 0
+	}
+
+	return r.Count / r.Per.Seconds()
+}
+
+// UnmarshalSetting implements Unmarshaler
+func (r *Rate) UnmarshalSetting(v string) error {
+	rv, err := parseRate(v)
+	if err != nil {
+		return err
+	}
+
+	*r = rv
+	return nil
+}
+
+// MarshalSetting implements Marshaler
+func (r *Rate) MarshalSetting() string {
+	if *r == (Rate{}) {
+		return ""
+	}
+
+	count := strconv.FormatFloat(r.Count, 'g', -1, 64)
+
+	switch r.Per {
+	case time.Second:
+		return count + "/s"
+	case time.Minute:
+		return count + "/m"
+	case time.Hour:
+		return count + "/h"
+	default:
+		return count + "/" + r.Per.String()
+	}
+}
+
+// Equals implements Equality
+func (r *Rate) Equals(v string) bool {
+	rv, err := parseRate(v)
+	if err != nil {
+		return false
+	}
+
+	return r.PerSecond() == rv.PerSecond()
+}
+
+func parseRate(v string) (Rate, error) {
+	if strings.TrimSpace(v) == "" {
+		return Rate{}, nil
+	}
+
+	count, per, found := strings.Cut(strings.TrimSpace(v), "/")
+	if !found {
+		return Rate{}, fmt.Errorf("invalid rate %q: expected <count>/<period>", v)
+	}
+
+	cv, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid rate %q: %w", v, err)
+	}
+
+	if cv < 0 || math.IsNaN(cv) || math.IsInf(cv, 0) {
+		return Rate{}, fmt.Errorf("invalid rate %q: count must be a positive number", v)
+	}
+
+	per = strings.TrimSpace(per)
+
+	// allow the unit on its own (s, m, h) as the period
+	pv, err := time.ParseDuration(per)
+	if err != nil {
+		pv, err = time.ParseDuration("1" + per)
+	}
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid rate %q: %w", v, err)
+	}
+
+	if pv <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: period must be positive", v)
+	}
+
+	return Rate{Count: cv, Per: pv}, nil
+}
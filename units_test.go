@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercent(t *testing.T) {
+	var p Percent
+	st := &Setting{Value: &p}
+
+	if err := st.Set("75%"); err != nil {
+		t.Fatalf("Failed to set percentage: %v", err)
+	}
+
+	if p != 0.75 {
+		t.Errorf("Failed to parse percentage: expected %v; got %v", 0.75, p)
+	}
+
+	if st.String() != "75%" {
+		t.Errorf("Failed to format percentage: expected %q; got %q", "75%", st.String())
+	}
+
+	if !st.Equals(" 75.0 %") {
+		t.Errorf("Failed to equality check percentage")
+	}
+
+	for _, input := range []string{"lots%", "75", "0.75", "101%", "-1%", "NaN%", "Inf%"} {
+		if err := st.Set(input); err == nil {
+			t.Errorf("Failed to reject invalid percentage %q", input)
+		}
+	}
+
+	if p != 0.75 {
+		t.Errorf("Failed to keep percentage: expected %v; got %v", 0.75, p)
+	}
+
+	// fractions that are not exact in binary format without float error
+	for fraction, expected := range map[Percent]string{0.07: "7%", 0.29: "29%", 0.125: "12.5%"} {
+		p := fraction
+		if got := p.MarshalSetting(); got != expected || !p.Equals(got) {
+			t.Errorf("Failed to format percentage %v: expected %q; got %q", float64(fraction), expected, got)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	tests := map[string]Rate{
+		"100/s":   {Count: 100, Per: time.Second},
+		"5/m":     {Count: 5, Per: time.Minute},
+		"10/30s":  {Count: 10, Per: 30 * time.Second},
+		" 1 / h ": {Count: 1, Per: time.Hour},
+		"":        {},
+	}
+
+	for input, expected := range tests {
+		var r Rate
+		if err := r.UnmarshalSetting(input); err != nil {
+			t.Errorf("Failed to parse rate %q: %v", input, err)
+			continue
+		}
+
+		if r != expected {
+			t.Errorf("Failed to parse rate %q: expected %+v; got %+v", input, expected, r)
+		}
+
+		if !r.Equals(r.MarshalSetting()) {
+			t.Errorf("Failed to round trip rate %q: got %q", input, r.MarshalSetting())
+		}
+	}
+
+	for _, input := range []string{"100", "-1/s", "1/0s", "1/fortnight"} {
+		var r Rate
+		if err := r.UnmarshalSetting(input); err == nil {
+			t.Errorf("Failed to reject invalid rate %q", input)
+		}
+	}
+}
+
+func TestSet_BindUnits(t *testing.T) {
+	cfg := struct {
+		Sample Percent
+		Limit  Rate
+	}{Sample: 0.5, Limit: Rate{Count: 10, Per: time.Second}}
+
+	set := (&Set{}).Bind(&cfg)
+
+	if _, err := set.Update("Limit", "20/m"); err != nil {
+		t.Fatalf("Failed to update rate: %v", err)
+	}
+
+	if cfg.Limit != (Rate{Count: 20, Per: time.Minute}) {
+		t.Errorf("Failed to bind rate as a setting: got %+v", cfg.Limit)
+	}
+
+	if set.Get("Sample").String() != "50%" {
+		t.Errorf("Failed to bind percentage: got %q", set.Get("Sample").String())
+	}
+}
@@ -0,0 +1,18 @@
+package config
+
+import "sort"
+
+// Unread returns the settings of the Set that have never been read with Set.Get, sorted by path. This helps pruning dead configuration that keeps accumulating across releases.
+func (s *Set) Unread() []*Setting {
+	var settings []*Setting
+	s.Range(func(_ string, setting *Setting) bool {
+		if setting.Reads() == 0 {
+			settings = append(settings, setting)
+		}
+		return true
+	})
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	return settings
+}
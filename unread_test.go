@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+func TestSet_Unread(t *testing.T) {
+	set := &Set{}
+	set.Setting("Used", 1, "")
+	set.Setting("Unused", 2, "")
+	set.Setting("Updated", 3, "")
+
+	set.Get("used")
+	_, _ = set.Update("Updated", "4")
+
+	unread := set.Unread()
+	if len(unread) != 2 || unread[0].Path != "Unused" || unread[1].Path != "Updated" {
+		t.Errorf("Failed to report unread settings: got %v", unread)
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// LoadURL opens the Provider registered for the scheme of the URL (see OpenProvider), loads it and updates the matching settings in the Set, so a single flag (i.e. -config-url) can point at any supported backend. The file, http and https schemes are always registered, other backends (i.e. s3, vault or etcd) register themselves with RegisterProvider.
+//
+// The ctx bounds loading the Provider, the URL without its password is the source of the updated settings.
+func (s *Set) LoadURL(ctx context.Context, rawURL string) (err error) {
+	source := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		source = u.Redacted()
+	}
+
+	defer func(start time.Time) { s.observe(OpLoad, s.path, source, start, err) }(time.Now())
+	done := s.timeLoad(OpLoad, s.path, source)
+	defer func() { done(err) }()
+
+	p, err := OpenProvider(rawURL)
+	if err != nil {
+		return err
+	}
+
+	values, err := load(withReader(ctx, s.reader()), p)
+	if err != nil {
+		return fmt.Errorf("unable to load %q: %w", source, err)
+	}
+
+	return s.updateRemote(values, source)
+}
+
+// maxRemoteSize is the largest document HTTP fetches
+const maxRemoteSize = 8 << 20
+
+// HTTP returns a Provider fetching the document at the URL with a GET request, in any format of Set.LoadFile selected by the extension of the URL path, the Content-Type of the response or the content. Remote documents can not include other documents or be larger than 8MiB.
+func HTTP(rawURL string) Provider {
+	return HTTPDigest(rawURL, "")
+}
+
+// HTTPDigest returns a Provider fetching the document at the URL like HTTP, failing when the hex encoded SHA-256 digest of the document does not match the expected digest, see FileDigest. An empty digest is not checked.
+func HTTPDigest(rawURL, digest string) Provider {
+	return ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// remote documents have no detached signature to verify
+		if readerFrom(ctx).verify != nil {
+			return nil, fmt.Errorf("unable to fetch %q: %w: remote documents can not be verified while signatures are required", req.URL.Redacted(), ErrInvalidSignature)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unable to fetch %q: unexpected status %s", req.URL.Redacted(), resp.Status)
+		}
+
+		// one byte more than allowed tells a document that is too large from one that fits exactly
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch %q: %w", req.URL.Redacted(), err)
+		}
+		if len(data) > maxRemoteSize {
+			return nil, fmt.Errorf("unable to fetch %q: document is larger than %d bytes", req.URL.Redacted(), maxRemoteSize)
+		}
+
+		if digest != "" {
+			if err := checkDigest(digest, data); err != nil {
+				return nil, fmt.Errorf("unable to fetch %q: %w", req.URL.Redacted(), err)
+			}
+		}
+
+		return (&fileReader{}).decode(documentName(req.URL, resp.Header.Get("Content-Type")), "", data, nil)
+	})
+}
+
+// documentName returns the name of the document at the URL with an extension matching its content type when the path has none, so decodeDocument can select the format
+func documentName(u *url.URL, contentType string) string {
+	name := path.Base(u.Path)
+	if path.Ext(name) != "" {
+		return name
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		return name + ".json"
+	case strings.HasSuffix(mediaType, "yaml"):
+		return name + ".yaml"
+	case strings.HasSuffix(mediaType, "toml"):
+		return name + ".toml"
+	}
+
+	return name
+}
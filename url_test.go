@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSet_LoadURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app.yaml":
+			_, _ = w.Write([]byte("HTTP:\n  Port: 8080\n"))
+		case "/app":
+			w.Header().Set("Content-Type", "application/toml; charset=utf-8")
+			_, _ = w.Write([]byte("[HTTP]\nPort = 8081\n"))
+		case "/include.json":
+			_, _ = w.Write([]byte(`{"include": "/etc/passwd"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	set := &Set{}
+	port := set.Subset("HTTP").Setting("Port", 0, "")
+
+	if err := set.LoadURL(context.Background(), server.URL+"/app.yaml"); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if port.String() != "8080" || port.Source() != server.URL+"/app.yaml" {
+		t.Errorf("Failed to load by extension: got %q from %q", port.String(), port.Source())
+	}
+
+	if err := set.LoadURL(context.Background(), server.URL+"/app"); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if port.String() != "8081" {
+		t.Errorf("Failed to load by content type: expected %q; got %q", "8081", port.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"HTTP": {"Port": 8082}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.LoadURL(context.Background(), "file://"+path); err != nil || port.String() != "8082" {
+		t.Errorf("Failed to load file: got %q with %v", port.String(), err)
+	}
+
+	for _, rawURL := range []string{server.URL + "/missing.json", server.URL + "/include.json"} {
+		if err := set.LoadURL(context.Background(), rawURL); err == nil {
+			t.Errorf("Failed to reject %s", rawURL)
+		}
+	}
+	if port.String() != "8082" {
+		t.Errorf("Failed to leave settings untouched: expected %q; got %q", "8082", port.String())
+	}
+
+	if err := set.LoadURL(context.Background(), "etcd://localhost:2379/app"); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("Failed to reject unknown scheme: expected %v; got %v", ErrUnknownScheme, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := set.LoadURL(ctx, server.URL+"/app.yaml"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Failed to respect ctx: expected %v; got %v", context.Canceled, err)
+	}
+}
+
+func TestSet_LoadURLDigest(t *testing.T) {
+	document := "Port: 8080\n"
+	sum := sha256.Sum256([]byte(document))
+	digest := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the digest is not sent to the server
+		if r.URL.Query().Get("digest") != "" {
+			http.Error(w, "unexpected digest", http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/app.yaml":
+			_, _ = w.Write([]byte(document))
+		case "/large.yaml":
+			_, _ = w.Write([]byte("Port: 8080\n" + strings.Repeat("#", maxRemoteSize)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	port := 80
+	set := &Set{}
+	set.Setting("Port", &port, "")
+
+	other := sha256.Sum256([]byte("Port: 9090\n"))
+	if err := set.LoadURL(context.Background(), server.URL+"/app.yaml?digest=sha256:"+hex.EncodeToString(other[:])); !errors.Is(err, ErrDigestMismatch) || port != 80 {
+		t.Errorf("Failed to reject mismatched digest: got %v with %d", err, port)
+	}
+
+	if err := set.LoadURL(context.Background(), server.URL+"/app.yaml?digest=sha256:"+digest); err != nil || port != 8080 {
+		t.Errorf("Failed to load matching digest: got %v with %d", err, port)
+	}
+
+	port = 80
+	if err := set.LoadURL(context.Background(), server.URL+"/large.yaml"); err == nil || !strings.Contains(err.Error(), "larger than") || port != 80 {
+		t.Errorf("Failed to reject large document: got %v with %d", err, port)
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownSetting is returned for a path that does not resolve to a setting
+var ErrUnknownSetting = errors.New("unknown setting")
+
+// SettingError is a problem with the value for a setting path
+type SettingError struct {
+	Path string
+	Err  error
+}
+
+func (e *SettingError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *SettingError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError contains every problem found while validating values
+type ValidationError struct {
+	Errors []*SettingError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// ValidateFile reads the document at path, like Set.LoadFile, and reports whether every key resolves to a setting and every value is valid for its setting without applying anything. Masked settings the SecretPolicy of the Set does not allow from a file are reported, see Set.RestrictSecrets. All problems are returned in a *ValidationError, allowing a dry-run before Set.Reload.
+func (s *Set) ValidateFile(path string) error {
+	values, err := s.reader().read(path, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.validate(values, path)
+}
+
+// validate the values supplied by the source, keyed by path relative to the Set, without applying them
+func (s *Set) validate(values map[string]string, source string) error {
+	values = s.resolveEnvNames(values)
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// constraints see the other values being validated rather than the current ones
+	pending := make(map[string]string, len(values))
+	for path, v := range values {
+		if setting := s.peek(path); setting != nil {
+			pending[strings.ToLower(setting.Path)] = v
+		}
+	}
+	lookup := func(path string) (string, bool) {
+		v, found := pending[strings.ToLower(path)]
+		return v, found
+	}
+
+	var errs []*SettingError
+	for _, path := range paths {
+		setting := s.peek(path)
+		if setting == nil {
+			errs = append(errs, &SettingError{Path: path, Err: ErrUnknownSetting})
+			continue
+		}
+
+		if err := s.allowSecret(setting, source); err != nil {
+			errs = append(errs, &SettingError{Path: path, Err: err})
+			continue
+		}
+
+		if err := setting.check(values[path]); err != nil {
+			errs = append(errs, &SettingError{Path: path, Err: err})
+			continue
+		}
+
+		if err := setting.constrain(values[path], lookup); err != nil {
+			errs = append(errs, &SettingError{Path: path, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+// check if the string is valid for the setting without changing its Value
+func (s *Setting) check(v string) error {
+	if !s.Equals(v) {
+		if err := s.validate(v); err != nil {
+			return err
+		}
+	}
+
+	return s.clone().convert(v)
+}
+
+// clone returns a detached copy of the setting, pointer values are copied so the original is never written to
+func (s *Setting) clone() *Setting {
+	value := s.Value
+
+	if rv := reflect.ValueOf(s.Value); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		cp := reflect.New(rv.Elem().Type())
+		cp.Elem().Set(rv.Elem())
+		value = cp.Interface()
+	}
+
+	return &Setting{
+		Mask:            s.Mask,
+		Required:        s.Required,
+		RestartRequired: s.RestartRequired,
+		Name:            s.Name,
+		Description:     s.Description,
+		Descriptions:    s.Descriptions,
+		Category:        s.Category,
+		Role:            s.Role,
+		Constraint:      s.Constraint,
+		Annotations:     s.Annotations,
+		Labels:          s.Labels,
+		DefaultValue:    s.DefaultValue,
+		Path:            s.Path,
+		Value:           value,
+		NotifyUnchanged: s.NotifyUnchanged,
+		validator:       s.validator,
+		immutable:       s.immutable,
+	}
+}
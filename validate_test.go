@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSet_ValidateFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"valid.json":   `{"HTTP": {"Port": 8080}}`,
+		"invalid.json": `{"HTTP": {"Port": "eighty", "Host": "localhost"}}`,
+	})
+
+	port := 80
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", &port, "")
+
+	if err := set.ValidateFile(filepath.Join(dir, "valid.json")); err != nil {
+		t.Errorf("Failed to validate file: %v", err)
+	}
+
+	if port != 80 {
+		t.Errorf("Failed to validate without applying: got %d", port)
+	}
+
+	err := set.ValidateFile(filepath.Join(dir, "invalid.json"))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 2 {
+		t.Fatalf("Failed to report problems: got %v", err)
+	}
+
+	if validationErr.Errors[0].Path != "HTTP.Host" || !errors.Is(validationErr.Errors[0], ErrUnknownSetting) {
+		t.Errorf("Failed to report unknown key: got %v", validationErr.Errors[0])
+	}
+
+	if validationErr.Errors[1].Path != "HTTP.Port" {
+		t.Errorf("Failed to report invalid value: got %v", validationErr.Errors[1])
+	}
+}
+
+func TestSet_ValidateFileMap(t *testing.T) {
+	type Upstream struct {
+		Host string
+		Port int
+	}
+
+	dir := writeFiles(t, map[string]string{
+		"valid.json":   `{"Upstreams": {"evil": {"Port": 8080}}, "Replicas": {"3": {"Host": "b"}}}`,
+		"invalid.json": `{"Upstreams": {"evil": {"Port": "eighty", "Missing": 1}}}`,
+	})
+
+	cfg := struct {
+		Upstreams map[string]Upstream
+		Replicas  []Upstream
+	}{
+		Upstreams: map[string]Upstream{},
+	}
+	set := (&Set{}).Bind(&cfg)
+
+	if err := set.ValidateFile(filepath.Join(dir, "valid.json")); err != nil {
+		t.Errorf("Failed to validate map entries: %v", err)
+	}
+
+	err := set.ValidateFile(filepath.Join(dir, "invalid.json"))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 2 || !errors.Is(validationErr.Errors[0], ErrUnknownSetting) {
+		t.Errorf("Failed to report problems of map entries: got %v", err)
+	}
+
+	if len(cfg.Upstreams) != 0 || len(cfg.Replicas) != 0 {
+		t.Errorf("Failed to validate without binding: got %v and %v", cfg.Upstreams, cfg.Replicas)
+	}
+
+	if setting := set.Get("Upstreams.evil.Port"); setting == nil {
+		t.Errorf("Failed to bind map entry after validating")
+	}
+}
+
+func TestSet_ValidateFileTemplate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"valid.json":   `{"Clients": {"acme": {"Timeout": "1s"}}}`,
+		"invalid.json": `{"Clients": {"acme": {"Timeout": "soon"}}}`,
+	})
+
+	set := &Set{}
+	set.Setting("Port", 80, "")
+	set.Template("Clients.*.Timeout", 5*time.Second, "")
+
+	if err := set.ValidateFile(filepath.Join(dir, "valid.json")); err != nil {
+		t.Errorf("Failed to validate templated setting: %v", err)
+	}
+
+	var validationErr *ValidationError
+	if err := set.ValidateFile(filepath.Join(dir, "invalid.json")); !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 || validationErr.Errors[0].Path != "Clients.acme.Timeout" {
+		t.Errorf("Failed to report invalid templated value: got %v", err)
+	}
+
+	if count := atomic.LoadInt32(&set.settingCount); count != 1 {
+		t.Errorf("Failed to validate without materializing: expected %d settings; got %d", 1, count)
+	}
+
+	if _, found := set.settings.Load("clients.acme.timeout"); found {
+		t.Errorf("Failed to validate without registering the templated setting")
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ReadOnlyView is a consistent snapshot of the settings within a Set, see Set.View
+type ReadOnlyView struct {
+	path     string
+	revision uint64
+	entries  map[string]viewEntry
+}
+
+// viewEntry is a setting and its value when the view was taken
+type viewEntry struct {
+	setting *Setting
+	value   string
+}
+
+// View calls fn with a consistent snapshot of the settings within the Set: values are captured while no setting of the tree is changing, so related settings (i.e. a host and its port) are read together, and nothing written while fn runs is visible to it. Values loaded from one source are applied one setting at a time, so a view taken during Set.LoadFile or Set.Reload can hold part of the values.
+//
+// Writers are only held back while the values are copied, not while fn runs, so fn can write settings and take other views.
+func (s *Set) View(fn func(ro ReadOnlyView)) {
+	root := s.Root()
+	view := ReadOnlyView{path: s.path, entries: map[string]viewEntry{}}
+
+	root.viewMu.Lock()
+	view.revision = atomic.LoadUint64(&root.revision)
+	s.Range(func(key string, setting *Setting) bool {
+		view.entries[key] = viewEntry{setting: setting, value: setting.format()}
+		return true
+	})
+	root.viewMu.Unlock()
+
+	fn(view)
+}
+
+// Revision of the Set tree when the view was taken, see Set.Revision
+func (v ReadOnlyView) Revision() uint64 {
+	return v.revision
+}
+
+// Get the value of the setting by name, resolved like Set.Get, and whether it is within the view. The setting is recorded as read (see Setting.Reads), and reads of masked settings are audited (see Set.AuditSecrets).
+func (v ReadOnlyView) Get(name string) (string, bool) {
+	e, found := v.entries[strings.ToLower(name)]
+	if !found && v.path != "" {
+		e, found = v.entries[strings.ToLower(v.path+"."+name)]
+	}
+	if !found {
+		return "", false
+	}
+
+	atomic.AddUint64(&e.setting.reads, 1)
+	atomic.StoreInt64(&e.setting.lastRead, time.Now().UnixNano())
+	e.setting.auditSecret("ReadOnlyView.Get")
+
+	return e.value, true
+}
+
+// Scan parses the value of the setting by name into the target, a pointer to any type supported as a setting value (i.e. *int or *time.Duration)
+func (v ReadOnlyView) Scan(name string, target Value) error {
+	value, found := v.Get(name)
+	if !found {
+		return &SettingError{Path: name, Err: ErrUnknownSetting}
+	}
+
+	if err := (&Setting{Value: target}).convert(value); err != nil {
+		return fmt.Errorf("unable to scan %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// Range over the paths and values of the settings within the view, sorted by path, values of masked settings are ***** and Redacter values are redacted
+func (v ReadOnlyView) Range(fn func(path, value string) bool) {
+	entries := make([]viewEntry, 0, len(v.entries))
+	for _, e := range v.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].setting.Path < entries[j].setting.Path })
+
+	for _, e := range entries {
+		if !fn(e.setting.Path, e.setting.display(e.value)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSet_View(t *testing.T) {
+	set := &Set{}
+	db := set.Subset("DB")
+	host := db.Setting("Host", "a", "")
+	port := db.Setting("Port", 1, "")
+	password := db.Setting("Password", "secret", "")
+	password.Mask = true
+
+	db.View(func(ro ReadOnlyView) {
+		_ = host.Set("b")
+
+		if v, found := ro.Get("Host"); !found || v != "a" {
+			t.Errorf("Failed to read snapshot: expected %q; got %q", "a", v)
+		}
+		if v, found := ro.Get("DB.Host"); !found || v != "a" {
+			t.Errorf("Failed to read snapshot by path: expected %q; got %q", "a", v)
+		}
+
+		var p int
+		if err := ro.Scan("Port", &p); err != nil || p != 1 {
+			t.Errorf("Failed to scan: got %d with %v", p, err)
+		}
+
+		var paths, values []string
+		ro.Range(func(path, value string) bool {
+			paths = append(paths, path)
+			values = append(values, value)
+			return true
+		})
+		if len(paths) != 3 || paths[1] != "DB.Password" || values[1] != "*****" {
+			t.Errorf("Failed to range snapshot: got %v with %v", paths, values)
+		}
+
+		if err := ro.Scan("Missing", &p); !errors.As(err, new(*SettingError)) {
+			t.Errorf("Failed to reject unknown setting: got %v", err)
+		}
+	})
+
+	if host.Reads() != 2 {
+		t.Errorf("Failed to record reads: expected 2; got %d", host.Reads())
+	}
+
+	// the port is written before the host, so a view sees the host equal to the port or one behind
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 2; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			_ = port.Set(strconv.Itoa(i))
+			_ = host.Set(strconv.Itoa(i))
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		set.View(func(ro ReadOnlyView) {
+			h, _ := ro.Get("DB.Host")
+			p, _ := ro.Get("DB.Port")
+			if n, _ := strconv.Atoi(p); h != p && h != strconv.Itoa(n-1) && h != "b" {
+				t.Errorf("Failed to read consistent snapshot: host %q and port %q", h, p)
+			}
+		})
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+func TestSet_ViewRedacted(t *testing.T) {
+	redactedSet(t).View(func(ro ReadOnlyView) {
+		ro.Range(func(path, value string) bool {
+			assertRedacted(t, "view", value)
+			return true
+		})
+	})
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// watchMessage is a line of the stream served by WatchServer
+type watchMessage struct {
+	Path        string `json:"path,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Description string `json:"description,omitempty"`
+	Mask        bool   `json:"mask,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Revision of the change, or of the Set for the sync marker
+	Revision uint64 `json:"revision,omitempty"`
+
+	// Synced marks the end of the initial snapshot
+	Synced bool `json:"synced,omitempty"`
+
+	// Epoch of the Set for the sync marker, revisions are only comparable within an epoch
+	Epoch string `json:"epoch,omitempty"`
+}
+
+// WatchServer exposes a Set over HTTP as a stream of newline delimited JSON, so other processes can mirror it live with a WatchClient. Every setting is sent when a client connects, followed by a sync marker and then every change. Values of masked settings are sent as ***** and Redacter values redacted, flagged with mask, so secrets are never mirrored and must reach other processes from their own source.
+//
+// A client resuming from a revision (the since query parameter) is only sent the settings that changed after it in the snapshot, see Set.Revision. Revisions restart with the process, so the client passes the epoch of the sync marker it resumes from (the epoch query parameter) and is sent the full snapshot when the Set is from another process.
+type WatchServer struct {
+	// Set being exposed
+	Set *Set
+
+	// Token, when not empty, is required from clients as a bearer token
+	Token string
+}
+
+// ServeHTTP streams the Set until the client disconnects
+func (ws *WatchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !checkBearer(w, r, ws.Token) {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// changes are queued from the moment we subscribe so none are lost while sending the snapshot
+	var (
+		mu      sync.Mutex
+		changed []*Setting
+		signal  = make(chan struct{}, 1)
+	)
+
+	handle := ws.Set.Notify(NotifyFunc(func(setting *Setting) {
+		mu.Lock()
+		changed = append(changed, setting)
+		mu.Unlock()
+
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}))
+	defer handle.Close()
+
+	// a revision of another epoch, or ahead of the Set, is from before a restart, so the full snapshot is sent
+	since, _ := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+	if epoch := r.URL.Query().Get("epoch"); since > ws.Set.Revision() || (epoch != "" && epoch != ws.Set.epoch()) {
+		since = 0
+	}
+
+	var settings []*Setting
+	ws.Set.Range(func(_ string, setting *Setting) bool {
+		if since == 0 || setting.Revision() > since {
+			settings = append(settings, setting)
+		}
+		return true
+	})
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+
+	send := func(setting *Setting) error {
+		value := setting.format()
+		shown := setting.display(value)
+
+		return encoder.Encode(watchMessage{
+			Path:        setting.Path,
+			Value:       shown,
+			Description: setting.Description,
+			Mask:        setting.Mask || shown != value,
+			Annotations: setting.Annotations,
+			Revision:    setting.Revision(),
+		})
+	}
+
+	for _, setting := range settings {
+		if err := send(setting); err != nil {
+			return
+		}
+	}
+
+	if err := encoder.Encode(watchMessage{Synced: true, Revision: ws.Set.Revision(), Epoch: ws.Set.epoch()}); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-signal:
+		}
+
+		mu.Lock()
+		pending := changed
+		changed = nil
+		mu.Unlock()
+
+		for _, setting := range pending {
+			if err := send(setting); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// WatchClient mirrors a Set exposed by a WatchServer into a local Set, so sidecar and worker processes can consume the configuration of their parent live without their own providers. Masked and redacted settings are not mirrored.
+//
+// Settings already registered in the local Set (i.e. by Set.Bind) are updated, any other setting is registered as a string so the local Set mirrors the remote one.
+type WatchClient struct {
+	// accessed atomically, kept first for 64-bit alignment on 32-bit platforms
+	revision uint64
+
+	// URL of the WatchServer
+	URL string
+
+	// Set the remote settings are mirrored into
+	Set *Set
+
+	// Token, when not empty, is sent to the server as a bearer token
+	Token string
+
+	// Client used to connect, defaults to http.DefaultClient
+	Client *http.Client
+
+	// OnSync, when not nil, is called every time the initial snapshot has been applied after connecting
+	OnSync func()
+
+	epoch atomic.Value
+}
+
+// Revision returns the revision of the remote Set the local Set is in sync with, zero before the first snapshot
+func (c *WatchClient) Revision() uint64 {
+	return atomic.LoadUint64(&c.revision)
+}
+
+// Run connects to the server and applies the stream until the ctx is done or the connection fails. Run can be passed to Poller.Run to reconnect with backoff, reconnecting resumes from the last revision so only the settings changed in between are sent.
+func (c *WatchClient) Run(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return err
+	}
+	if revision := c.Revision(); revision > 0 {
+		query := req.URL.Query()
+		query.Set("since", strconv.FormatUint(revision, 10))
+		if epoch, _ := c.epoch.Load().(string); epoch != "" {
+			query.Set("epoch", epoch)
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to watch %q: unexpected status %s", c.URL, resp.Status)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return fmt.Errorf("unable to watch %q: %w", c.URL, io.ErrUnexpectedEOF)
+		} else if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("unable to watch %q: %w", c.URL, err)
+		}
+
+		var message watchMessage
+		if err := json.Unmarshal(line, &message); err != nil {
+			return fmt.Errorf("unable to decode watch message: %w", err)
+		}
+
+		if message.Synced {
+			c.epoch.Store(message.Epoch)
+			atomic.StoreUint64(&c.revision, message.Revision)
+			if c.OnSync != nil {
+				c.OnSync()
+			}
+			continue
+		}
+
+		if err := c.apply(message); err != nil {
+			return err
+		}
+
+		// changes after the snapshot advance the revision, the snapshot is complete at the sync marker
+		if revision := c.Revision(); revision > 0 && message.Revision > revision {
+			atomic.StoreUint64(&c.revision, message.Revision)
+		}
+	}
+}
+
+// epoch returns the random identifier of the Set tree created on first use, so revisions of a Set can be told apart from those of the same Set in a previous process
+func (s *Set) epoch() string {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	if root.epochID == "" {
+		id := make([]byte, 8)
+		_, _ = rand.Read(id)
+		root.epochID = hex.EncodeToString(id)
+	}
+
+	return root.epochID
+}
+
+// apply the message to the local Set, registering the setting when it does not exist. Masked values are skipped, as the server only sends them as displayed.
+func (c *WatchClient) apply(message watchMessage) error {
+	if message.Mask {
+		return nil
+	}
+
+	// a setting not registered locally is guarded as a string
+	setting := c.Set.lookup(message.Path)
+	if setting == nil {
+		setting = &Setting{Path: message.Path, Value: message.Value}
+	}
+	if err := c.Set.guard(context.Background(), setting, message.Value); err != nil {
+		return &SettingError{Path: message.Path, Err: err}
+	}
+
+	found, err := c.Set.Update(message.Path, message.Value)
+	if err != nil {
+		return &SettingError{Path: message.Path, Err: err}
+	}
+	if found {
+		return nil
+	}
+
+	value := message.Value
+	_, err = c.Set.Root().registerPath(message.Path, &value, message.Description, func(setting *Setting) {
+		setting.Mask = message.Mask
+		setting.Annotations = message.Annotations
+	})
+	if err != nil {
+		return &SettingError{Path: message.Path, Err: err}
+	}
+
+	return nil
+}
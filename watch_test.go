@@ -0,0 +1,208 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchClient_Run(t *testing.T) {
+	cfg := struct {
+		Port     int
+		Password string `mask:"true"`
+	}{Port: 80, Password: "secret"}
+
+	parent := &Set{}
+	parent.Subset("HTTP").Bind(&cfg)
+
+	server := httptest.NewServer(&WatchServer{Set: parent, Token: "token"})
+	defer server.Close()
+
+	port := 0
+	child := &Set{}
+	child.Subset("HTTP").Setting("Port", &port, "")
+
+	synced := make(chan struct{}, 1)
+	changed := make(chan struct{}, 1)
+	child.Notify(NotifyFunc(func(s *Setting) {
+		if s.Path == "HTTP.Port" && port == 8080 {
+			changed <- struct{}{}
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &WatchClient{URL: server.URL, Set: child, Token: "token", OnSync: func() { synced <- struct{}{} }}
+	done := make(chan error, 1)
+	go func() { done <- client.Run(ctx) }()
+
+	select {
+	case <-synced:
+	case err := <-done:
+		t.Fatalf("Failed to sync: %v", err)
+	case <-time.After(time.Second):
+		t.Fatalf("Failed to sync")
+	}
+
+	if port != 80 {
+		t.Errorf("Failed to mirror bound setting: expected %d; got %d", 80, port)
+	}
+
+	if password := child.Get("HTTP.Password"); password != nil {
+		t.Errorf("Failed to skip masked setting: got %q", password.format())
+	}
+
+	_, _ = parent.Update("HTTP.Port", "8080")
+
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatalf("Failed to mirror change")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Failed to stop watching: expected %v; got %v", context.Canceled, err)
+	}
+}
+
+func TestWatchServer_Since(t *testing.T) {
+	s := &Set{}
+	port := s.Setting("Port", 80, "")
+	host := s.Setting("Host", "localhost", "")
+
+	_ = port.Set("8080")
+	_ = host.Set("example.com")
+	_ = host.Set("example.com")
+
+	if s.Revision() != 2 || port.Revision() != 1 || host.Revision() != 2 {
+		t.Fatalf("Failed to count revisions: got %d, %d and %d", s.Revision(), port.Revision(), host.Revision())
+	}
+
+	tests := []struct {
+		since    string
+		epoch    string
+		expected []string
+	}{
+		{since: "", expected: []string{"Host", "Port"}},
+		{since: "1", expected: []string{"Host"}},
+		{since: "1", epoch: s.epoch(), expected: []string{"Host"}},
+		{since: "2", expected: nil},
+		{since: "100", expected: []string{"Host", "Port"}},
+		// a revision of a previous process is not comparable, even when behind the Set
+		{since: "1", epoch: "restarted", expected: []string{"Host", "Port"}},
+	}
+
+	for _, tt := range tests {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		req := httptest.NewRequest("GET", "/?since="+tt.since+"&epoch="+tt.epoch, nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+
+		done := make(chan struct{})
+		go func() {
+			(&WatchServer{Set: s}).ServeHTTP(rec, req)
+			close(done)
+		}()
+
+		// the snapshot is written before waiting for changes
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		<-done
+
+		var got []string
+		var synced watchMessage
+		for _, line := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n") {
+			var message watchMessage
+			if err := json.Unmarshal([]byte(line), &message); err != nil {
+				t.Fatalf("Failed to decode %q: %v", line, err)
+			}
+			if message.Synced {
+				synced = message
+				continue
+			}
+			got = append(got, message.Path)
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(tt.expected) || synced.Revision != 2 || synced.Epoch != s.epoch() {
+			t.Errorf("Failed to resume since %q: expected %v; got %v at revision %d", tt.since, tt.expected, got, synced.Revision)
+		}
+	}
+}
+
+func TestWatchClient_RunRestarted(t *testing.T) {
+	child := &Set{}
+	client := &WatchClient{Set: child}
+
+	// sync runs the client against the Set until the snapshot is applied
+	sync := func(parent *Set) {
+		server := httptest.NewServer(&WatchServer{Set: parent})
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client.URL = server.URL
+		client.OnSync = cancel
+		if err := client.Run(ctx); err != context.Canceled {
+			t.Fatalf("Failed to sync: %v", err)
+		}
+	}
+
+	parent := &Set{}
+	parent.Setting("Host", "a", "")
+	parent.Setting("Port", 80, "")
+	_, _ = parent.Update("Port", "8080")
+	_, _ = parent.Update("Host", "b")
+	sync(parent)
+
+	// the restarted parent is ahead of the client, but its revisions are of another process
+	restarted := &Set{}
+	restarted.Setting("Host", "c", "")
+	restarted.Setting("Port", 80, "")
+	for _, port := range []string{"8081", "8082", "9090"} {
+		_, _ = restarted.Update("Port", port)
+	}
+	sync(restarted)
+
+	if host, port := child.Get("Host").String(), child.Get("Port").String(); host != "c" || port != "9090" {
+		t.Errorf("Failed to resync after restart: got %s and %s", host, port)
+	}
+}
+
+func TestWatchServer_Redacted(t *testing.T) {
+	s := redactedSet(t)
+	s.Setting("Token", "hunter4", "").Mask = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		(&WatchServer{Set: s}).ServeHTTP(rec, req)
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+
+	assertRedacted(t, "watch stream", rec.Body.String())
+
+	for _, line := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n") {
+		var message watchMessage
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			t.Fatalf("Failed to decode %q: %v", line, err)
+		}
+		if !message.Synced && !message.Mask {
+			t.Errorf("Failed to flag displayed value: got %+v", message)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ChangeEvent describes a change of a setting sent to external systems, masked values are never included
+type ChangeEvent struct {
+	// Path of the setting that changed
+	Path string `json:"path"`
+
+	// Value of the setting after the change, masked settings have the value *****
+	Value string `json:"value"`
+
+	// Time of the change
+	Time time.Time `json:"time"`
+
+	// Revision of the Set after the change, see Set.Revision
+	Revision uint64 `json:"revision,omitempty"`
+}
+
+// EventFormat renders a ChangeEvent into the body of a message and its content type
+type EventFormat func(event ChangeEvent) (data []byte, contentType string, err error)
+
+// format the event, defaulting to JSONFormat
+func (f EventFormat) format(event ChangeEvent) ([]byte, string, error) {
+	if f == nil {
+		f = JSONFormat
+	}
+
+	data, contentType, err := f(event)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to format change of %q: %w", event.Path, err)
+	}
+
+	return data, contentType, nil
+}
+
+// JSONFormat renders the ChangeEvent as JSON
+func JSONFormat(event ChangeEvent) ([]byte, string, error) {
+	data, err := json.Marshal(event)
+
+	return data, "application/json", err
+}
+
+// newChangeEvent for the current value of the setting
+func newChangeEvent(setting *Setting) ChangeEvent {
+	return ChangeEvent{
+		Path:     setting.Path,
+		Value:    setting.String(),
+		Time:     time.Now(),
+		Revision: setting.Revision(),
+	}
+}
+
+// SignatureHeader is the header a Webhook sends the HMAC-SHA256 signature of the body in, formatted as sha256=<hex>
+const SignatureHeader = "X-Config-Signature"
+
+// Webhook is a Notifier POSTing every change as a JSON ChangeEvent to the URLs, so external systems (i.e. chat alerts or a CMDB) learn about runtime changes. Changes are delivered in order from a background goroutine so a slow endpoint never blocks Setting.Set.
+type Webhook struct {
+	// URLs every change is POSTed to
+	URLs []string
+
+	// Secret, when not empty, signs the body with HMAC-SHA256 in the SignatureHeader
+	Secret string
+
+	// Retries of a failed delivery, defaults to 3. Deliveries failing with a 4xx status other than 429 are not retried.
+	Retries int
+
+	// Backoff before the first retry, doubling for every retry after, defaults to 1 second
+	Backoff time.Duration
+
+	// Client used to deliver, defaults to http.DefaultClient
+	Client *http.Client
+
+	// Format of the body, defaults to JSONFormat
+	Format EventFormat
+
+	// OnError, when not nil, is called for every delivery that failed after all retries
+	OnError func(url string, event ChangeEvent, err error)
+
+	queue changeQueue
+}
+
+// Notify implements Notifier.Notify
+func (w *Webhook) Notify(setting *Setting) {
+	w.queue.push(newChangeEvent(setting), w.deliver)
+}
+
+// Close stops accepting changes and waits for the queued changes to be delivered
+func (w *Webhook) Close() error {
+	w.queue.close(w.deliver)
+
+	return nil
+}
+
+// deliver the event to every URL
+func (w *Webhook) deliver(event ChangeEvent) {
+	body, contentType, formatErr := w.Format.format(event)
+
+	for _, url := range w.URLs {
+		// an event that can not be formatted fails for every URL, otherwise each URL fails on its own
+		err := formatErr
+		if err == nil {
+			err = w.post(url, contentType, body)
+		}
+
+		if err != nil && w.OnError != nil {
+			w.OnError(url, event, err)
+		}
+	}
+}
+
+// post the body to the url with retries
+func (w *Webhook) post(url, contentType string, body []byte) error {
+	retries := w.Retries
+	if retries <= 0 {
+		retries = 3
+	}
+
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	// the retry decision depends on the status, so it is carried beside the error
+	var retry bool
+	policy := RetryPolicy{
+		Attempts:  retries + 1,
+		Backoff:   backoff,
+		Retryable: func(error) bool { return retry },
+	}
+
+	return policy.Do(context.Background(), func(context.Context) error {
+		var err error
+		retry, err = w.send(url, contentType, body)
+		return err
+	})
+}
+
+// send the body once, returning if a failure can be retried
+func (w *Webhook) send(url, contentType string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("unexpected status %s", resp.Status)
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+
+	return retry, err
+}
+
+// Sign returns the signature of the body sent by a Webhook in the SignatureHeader, receivers compare it with hmac.Equal
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
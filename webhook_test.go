@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		events   []ChangeEvent
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.Header.Get(SignatureHeader) != Sign("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event ChangeEvent
+		_ = json.Unmarshal(body, &event)
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	cfg := struct {
+		Port     int
+		Password string `mask:"true"`
+	}{}
+
+	set := &Set{}
+	set.Bind(&cfg)
+
+	webhook := &Webhook{URLs: []string{server.URL}, Secret: "secret", Backoff: time.Millisecond}
+	set.Notify(webhook)
+
+	_, _ = set.Update("Port", "8080")
+	_, _ = set.Update("Password", "hunter2")
+	_ = webhook.Close()
+
+	if len(events) != 2 || attempts != 3 {
+		t.Fatalf("Failed to deliver with retry: got %d events in %d attempts", len(events), attempts)
+	}
+
+	if events[0].Path != "Port" || events[0].Value != "8080" {
+		t.Errorf("Failed to deliver change: got %+v", events[0])
+	}
+
+	if events[1].Value != "*****" {
+		t.Errorf("Failed to mask change: got %q", events[1].Value)
+	}
+}
+
+func TestWebhook_NotifyFailingURL(t *testing.T) {
+	var delivered int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+	}))
+	defer working.Close()
+
+	port := 80
+	set := &Set{}
+	set.Setting("Port", &port, "")
+
+	var failed []string
+	webhook := &Webhook{
+		URLs:    []string{failing.URL, working.URL},
+		Backoff: time.Millisecond,
+		OnError: func(url string, event ChangeEvent, err error) { failed = append(failed, url) },
+	}
+	set.Notify(webhook)
+
+	_, _ = set.Update("Port", "8080")
+	_ = webhook.Close()
+
+	// the failure of one URL is not reported for the URLs after it
+	if atomic.LoadInt32(&delivered) != 1 || len(failed) != 1 || failed[0] != failing.URL {
+		t.Errorf("Failed to deliver to every URL: got %d deliveries with failures %v", delivered, failed)
+	}
+}
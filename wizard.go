@@ -0,0 +1,94 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Wizard interactively prompts for the required settings of a Set that are still at their default, which is useful for first-run experiences such as on-prem installers.
+type Wizard struct {
+	// In is read for answers, one per line
+	In io.Reader
+
+	// Out is written with the prompts
+	Out io.Writer
+
+	// ReadSecret reads the answer for masked settings without echoing it, such as term.ReadPassword from golang.org/x/term. When nil answers for masked settings are read from In.
+	ReadSecret func() (string, error)
+
+	// Locale the descriptions of the settings are prompted in, see Setting.Describe
+	Locale string
+
+	// File the answers are written to as a JSON document readable by Set.LoadFile, no file is written when empty
+	File string
+}
+
+// Run prompts for every required setting in the Set that is at its default value, sorted by path. An empty answer keeps the default, an invalid answer is reported and prompted for again.
+func (w *Wizard) Run(s *Set) error {
+	var settings []*Setting
+	s.Range(func(_ string, setting *Setting) bool {
+		if setting.Required && setting.IsDefault() {
+			settings = append(settings, setting)
+		}
+		return true
+	})
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+
+	in := bufio.NewReader(w.In)
+	answers := map[string]string{}
+
+	for _, setting := range settings {
+		for {
+			prompt := setting.Path
+			if description := setting.Describe(w.Locale); description != "" {
+				prompt += " (" + description + ")"
+			}
+			if setting.DefaultValue != "" && !setting.Mask {
+				prompt += " [" + setting.display(setting.DefaultValue) + "]"
+			}
+			fmt.Fprintf(w.Out, "%s: ", prompt)
+
+			var (
+				answer string
+				err    error
+			)
+
+			if setting.Mask && w.ReadSecret != nil {
+				answer, err = w.ReadSecret()
+				fmt.Fprintln(w.Out)
+			} else {
+				answer, err = in.ReadString('\n')
+				if err == io.EOF && answer != "" {
+					err = nil
+				}
+			}
+
+			if err != nil {
+				return fmt.Errorf("unable to read answer for %q: %w", setting.Path, err)
+			}
+
+			answer = strings.TrimSpace(answer)
+			if answer == "" {
+				break
+			}
+
+			if err := setting.Set(answer); err != nil {
+				fmt.Fprintf(w.Out, "invalid value: %v\n", err)
+				continue
+			}
+
+			answers[setting.Path] = answer
+			break
+		}
+	}
+
+	if w.File != "" && len(answers) > 0 {
+		return SaveFile(w.File, answers)
+	}
+
+	return nil
+}
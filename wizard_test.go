@@ -0,0 +1,54 @@
+package config
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWizard(t *testing.T) {
+	cfg := struct {
+		Host     string `required:"true" description:"Host name"`
+		Port     int    `required:"true"`
+		Password string `required:"true" mask:"true"`
+		Optional string
+	}{Port: 80}
+
+	set := (&Set{}).Bind(&cfg)
+	file := filepath.Join(t.TempDir(), "answers.json")
+
+	out := &bytes.Buffer{}
+	wizard := &Wizard{
+		In:         strings.NewReader("example.com\nnope\n8080\n"),
+		Out:        out,
+		ReadSecret: func() (string, error) { return "secret", nil },
+		File:       file,
+	}
+
+	// settings are prompted for by path, so the invalid port is asked for again after the password
+	if err := wizard.Run(set); err != nil {
+		t.Fatalf("Failed to run wizard: %v", err)
+	}
+
+	if cfg.Host != "example.com" || cfg.Password != "secret" || cfg.Port != 8080 {
+		t.Errorf("Failed to apply answers: got %+v", cfg)
+	}
+
+	if !strings.Contains(out.String(), "Host (Host name): ") || !strings.Contains(out.String(), "invalid value") {
+		t.Errorf("Failed to prompt: got %q", out.String())
+	}
+
+	loaded := struct {
+		Host     string
+		Password string
+	}{}
+
+	if err := (&Set{}).Bind(&loaded).LoadFile(file); err != nil {
+		t.Fatalf("Failed to load answers: %v", err)
+	}
+
+	if loaded.Host != "example.com" || loaded.Password != "secret" {
+		t.Errorf("Failed to save answers: got %+v", loaded)
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrConflict is returned by a Writer when the backend was changed since the provider last loaded it
+var ErrConflict = errors.New("conflicting remote write")
+
+// WriteRequest is a change written through to a Writer
+type WriteRequest struct {
+	// Path of the setting relative to the Set the provider is attached to
+	Path string
+
+	// Value being written
+	Value string
+
+	// Previous is the value the provider last supplied for the Path
+	Previous string
+
+	// Exists is set when the provider supplied a value for the Path in its last load
+	Exists bool
+}
+
+// Writer is implemented by providers whose backend can be written to (i.e. SQL, etcd, Consul). To detect concurrent remote writes, Write must fail with an error wrapping ErrConflict when the backend no longer holds the Previous value, or holds any value when the path did not Exist.
+type Writer interface {
+	Write(ctx context.Context, req WriteRequest) error
+}
+
+// WriteThrough enables write-through to the provider added with the name, which must implement Writer or wrap a provider that does (i.e. with Cache or Breaker). Every Setting.Set changing a setting within the Set the provider is attached to writes the new value to the backend first, and fails leaving the setting untouched when the write fails. Values applied by providers, files or the environment are not written through.
+func (s *Set) WriteThrough(name string) {
+	root := s.Root()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	for _, rp := range root.providers {
+		if !strings.EqualFold(rp.name, name) {
+			continue
+		}
+
+		for p := rp.provider; p != nil; p = unwrapProvider(p) {
+			if w, ok := p.(Writer); ok {
+				rp.writer = w
+				return
+			}
+		}
+
+		panic(fmt.Sprintf("provider %q is not writable", name))
+	}
+
+	panic(fmt.Sprintf("provider %q does not exist", name))
+}
+
+// writeThrough writes the value of the setting to the last added writable provider containing it, if any
+func (s *Set) writeThrough(ctx context.Context, setting *Setting, value string) error {
+	root := s.Root()
+
+	root.mu.Lock()
+	var rp *registeredProvider
+	for i := len(root.providers) - 1; i >= 0; i-- {
+		if p := root.providers[i]; p.writer != nil && p.set.contains(setting.Path) {
+			rp = p
+			break
+		}
+	}
+
+	root.mu.Unlock()
+
+	// invalid values are left for Setting.Set to report
+	if rp == nil || setting.check(value) != nil {
+		return nil
+	}
+
+	root.mu.Lock()
+
+	req := WriteRequest{Path: setting.Path, Value: value}
+	if rp.set.path != "" {
+		req.Path = setting.Path[len(rp.set.path)+1:]
+	}
+	// paths are case insensitive, the backend is written under the key it supplied the value with
+	if key, found := foldKey(rp.values, req.Path); found {
+		req.Path = key
+		req.Previous, req.Exists = rp.values[key], true
+	}
+	root.mu.Unlock()
+
+	if err := rp.writer.Write(ctx, req); err != nil {
+		return &ProviderError{Name: rp.name, Err: fmt.Errorf("unable to write %q: %w", req.Path, err)}
+	}
+
+	root.mu.Lock()
+	if rp.values == nil {
+		rp.values = map[string]string{}
+	}
+	rp.values[req.Path] = value
+	root.mu.Unlock()
+
+	return nil
+}
+
+// foldKey returns the key of the values matching the path regardless of case, preferring an exact match
+func foldKey(values map[string]string, path string) (string, bool) {
+	if _, found := values[path]; found {
+		return path, true
+	}
+
+	for key := range values {
+		if strings.EqualFold(key, path) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// memoryBackend is a writable provider with compare-and-set semantics
+type memoryBackend struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (m *memoryBackend) Load(ctx context.Context) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values := map[string]string{}
+	for k, v := range m.values {
+		values[k] = v
+	}
+
+	return values, nil
+}
+
+func (m *memoryBackend) Write(ctx context.Context, req WriteRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, exists := m.values[req.Path]
+	if exists != req.Exists || current != req.Previous {
+		return ErrConflict
+	}
+
+	m.values[req.Path] = req.Value
+	return nil
+}
+
+func TestSet_WriteThrough(t *testing.T) {
+	var (
+		port  = 80
+		level = "info"
+	)
+
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", &port, "")
+	set.Setting("Level", &level, "")
+
+	backend := &memoryBackend{values: map[string]string{"Port": "8080"}}
+	set.Subset("HTTP").AddProvider("backend", &Breaker{Provider: backend})
+	set.WriteThrough("backend")
+
+	if err := set.Reload(context.Background()); err != nil || port != 8080 {
+		t.Fatalf("Failed to load backend: got %v with %d", err, port)
+	}
+
+	if _, err := set.Update("HTTP.Port", "9090"); err != nil || backend.values["Port"] != "9090" {
+		t.Errorf("Failed to write through: got %v with %v", err, backend.values)
+	}
+
+	// settings outside the provider are not written
+	if _, err := set.Update("Level", "debug"); err != nil || len(backend.values) != 1 {
+		t.Errorf("Failed to skip unrelated setting: got %v with %v", err, backend.values)
+	}
+
+	// a concurrent remote write is detected
+	backend.values["Port"] = "7070"
+
+	if _, err := set.Update("HTTP.Port", "6060"); !errors.Is(err, ErrConflict) || port != 9090 || backend.values["Port"] != "7070" {
+		t.Errorf("Failed to detect conflict: got %v with %d and %v", err, port, backend.values)
+	}
+
+	_ = set.Reload(context.Background())
+
+	if _, err := set.Update("HTTP.Port", "6060"); err != nil || port != 6060 {
+		t.Errorf("Failed to write through after reload: got %v with %d", err, port)
+	}
+}
+
+func TestSet_WriteThroughCase(t *testing.T) {
+	port := 80
+
+	set := &Set{}
+	set.Subset("HTTP").Setting("Port", &port, "")
+
+	// keys are supplied in another case than the path of the setting
+	backend := &memoryBackend{values: map[string]string{"http.port": "8080"}}
+	set.AddProvider("backend", backend)
+	set.WriteThrough("backend")
+
+	if err := set.Reload(context.Background()); err != nil || port != 8080 {
+		t.Fatalf("Failed to load backend: got %v with %d", err, port)
+	}
+
+	if _, err := set.Update("HTTP.Port", "9090"); err != nil || backend.values["http.port"] != "9090" || len(backend.values) != 1 {
+		t.Errorf("Failed to write through regardless of case: got %v with %v", err, backend.values)
+	}
+}